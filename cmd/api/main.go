@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
@@ -29,6 +30,8 @@ import (
 	"github.com/vyve/vyve-backend/pkg/ai"
 	"github.com/vyve/vyve-backend/pkg/analytics"
 	"github.com/vyve/vyve-backend/pkg/cache"
+	"github.com/vyve/vyve-backend/pkg/email"
+	"github.com/vyve/vyve-backend/pkg/encryption"
 	"github.com/vyve/vyve-backend/pkg/notifications"
 	"github.com/vyve/vyve-backend/pkg/storage"
 	"gorm.io/driver/postgres"
@@ -97,11 +100,17 @@ func migrateDatabase(db *gorm.DB) error {
 		&models.Interaction{},
 		&models.Reflection{},
 		&models.Nudge{},
+		&models.NudgeRule{},
 		&models.Event{},
 		&models.DailyMetric{},
 		&models.PushToken{},
+		&models.Notification{},
 		&models.RelationshipAnalysis{},
 		&models.AIAnalysisJob{},
+		&models.ImportJob{},
+		&models.ActionToken{},
+		&models.MFARecoveryCode{},
+		&models.Passkey{},
 	)
 }
 
@@ -120,6 +129,13 @@ func main() {
 	log.Printf("Starting Vyve API %s (built %s)", Version, BuildTime)
 	log.Printf("Environment: %s", cfg.Env)
 
+	// Field-level encryption must be configured before any model is read or written
+	fieldEncryptor, err := encryption.New(cfg.Encryption)
+	if err != nil {
+		log.Fatalf("Failed to initialize field encryption: %v", err)
+	}
+	models.SetFieldEncryptor(fieldEncryptor)
+
 	// Initialize database and run migrations
 	db, sqlDB, err := initializeDatabase(cfg)
 	if err != nil {
@@ -145,25 +161,42 @@ func main() {
 	storageService := initializeStorage(cfg)
 	analyticsService := initializeAnalytics(cfg)
 	notificationService := initializeNotifications(cfg)
+	mailer, err := initializeMailer(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize email: %v", err)
+	}
 	aiService := initializeAIService(cfg)
 
 	// Initialize repositories
 	repos := repository.NewRepositories(db)
 
 	// Initialize services
-	authService := services.NewAuthService(repos.User, redisClient, cfg.JWT, cfg, analyticsService)
-	userService := services.NewUserService(repos.User, storageService, analyticsService)
-	personService := services.NewPersonService(repos.Person, analyticsService, storageService)
+	authService := services.NewAuthService(repos.User, repos.AuditLog, redisClient, cfg.JWT, cfg, analyticsService, mailer)
+	userService := services.NewUserService(repos.User, repos.AuditLog, repos.Consent, repos.Notification, redisClient, storageService, analyticsService, notificationService)
+	personService := services.NewPersonService(repos.Person, repos.User, analyticsService, storageService)
 	interactionService := services.NewInteractionService(repos.Interaction, repos.Person, analyticsService)
-	reflectionService := services.NewReflectionService(repos.Reflection)
-	nudgeService := services.NewNudgeService(repos.Nudge, notificationService, analyticsService)
-	gdprService := services.NewGDPRService(repos, cfg.Encryption)
+	reflectionService := services.NewReflectionService(repos.Reflection, repos.User, analyticsService)
+	nudgeService := services.NewNudgeService(repos.Nudge, repos.NudgeRule, repos.Person, repos.User, repos.Consent, repos.Notification, analyticsService)
+	gdprService := services.NewGDPRService(repos, storageService, redisClient, cfg.Encryption)
 	dictionaryService := services.NewDictionaryService(db)
 	analysisService := services.NewAnalysisService(aiService, repos.Analysis, repos.Person, repos.Interaction)
+	importService := services.NewImportService(repos.Import, repos.Person, repos.Interaction, storageService, analyticsService)
+
+	// Start the analysis job queue workers
+	analysisWorkers := services.NewAnalysisWorkerPool(analysisService, repos.Analysis, cfg.AI)
+	analysisWorkers.Start()
+
+	// Start the import job queue worker
+	importWorkers := services.NewImportWorkerPool(importService, repos.Import)
+	importWorkers.Start()
+
+	// Start the notification outbox worker
+	notificationWorkers := services.NewNotificationWorkerPool(repos.Notification, repos.User, notificationService)
+	notificationWorkers.Start()
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(authService)
-	userHandler := handlers.NewUserHandler(userService)
+	userHandler := handlers.NewUserHandler(userService, authService, gdprService)
 	onboardingHandler := handlers.NewOnboardingHandler(userService)
 	personHandler := handlers.NewPersonHandler(personService)
 	interactionHandler := handlers.NewInteractionHandler(interactionService)
@@ -172,24 +205,16 @@ func main() {
 	gdprHandler := handlers.NewGDPRHandler(gdprService)
 	dictionaryHandler := handlers.NewDictionaryHandler(dictionaryService)
 	analysisHandler := handlers.NewAnalysisHandler(analysisService)
+	importHandler := handlers.NewImportHandler(importService)
 
 	// Create Fiber app
-	app := fiber.New(fiber.Config{
-		AppName:               "Vyve API",
-		ServerHeader:          "Vyve",
-		DisableStartupMessage: cfg.Env == "production",
-		ReadTimeout:           15 * time.Second,
-		WriteTimeout:          15 * time.Second,
-		IdleTimeout:           60 * time.Second,
-		BodyLimit:             10 * 1024 * 1024, // 10MB
-		Prefork:               false, // Disabled for containerized deployments (Railway, Docker, etc.)
-	})
+	app := fiber.New(appConfig(cfg))
 
 	// Global middleware
 	setupMiddleware(app, cfg)
 
 	// Initialize realtime hub
-	hub := realtime.NewHub(redisClient)
+	hub := realtime.NewHub(redisClient, userService)
 	go hub.Run()
 
 	// Setup routes
@@ -205,13 +230,14 @@ func main() {
 		Onboarding:  onboardingHandler,
 		Dictionary:  dictionaryHandler,
 		Analysis:    analysisHandler,
+		Import:      importHandler,
 	}, authService, cfg)
 
 	// Start background workers
-	startBackgroundWorkers(cfg, repos, notificationService, analyticsService)
+	startBackgroundWorkers(cfg, repos, analyticsService, gdprService, authService, redisClient)
 
 	// Graceful shutdown
-	go gracefulShutdown(app)
+	go gracefulShutdown(app, analysisWorkers.Stop, importWorkers.Stop, notificationWorkers.Stop)
 
 	// Start server
 	port := cfg.Server.Port
@@ -236,6 +262,43 @@ func setupLogging(cfg *config.Config) {
 	}
 }
 
+// appConfig returns the Fiber settings for the API
+func appConfig(cfg *config.Config) fiber.Config {
+	return fiber.Config{
+		AppName:               "Vyve API",
+		ServerHeader:          "Vyve",
+		DisableStartupMessage: cfg.Env == "production",
+		ReadTimeout:           15 * time.Second,
+		WriteTimeout:          15 * time.Second,
+		IdleTimeout:           60 * time.Second,
+		BodyLimit:             10 * 1024 * 1024, // 10MB
+		Prefork:               false,            // Disabled for containerized deployments (Railway, Docker, etc.)
+		// Behind the platform's load balancer every request comes from a proxy, so
+		// c.IP() reads the client from X-Forwarded-For, but only when the request came
+		// from one of the trusted proxies; anyone else could spoof the header.
+		ProxyHeader:             fiber.HeaderXForwardedFor,
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          cfg.Server.TrustedProxies,
+		EnableIPValidation:      true,
+	}
+}
+
+// rateLimiter throttles each client IP to cfg.RateLimit.Max requests per window
+func rateLimiter(cfg *config.Config) fiber.Handler {
+	return limiter.New(limiter.Config{
+		Max:        cfg.RateLimit.Max,
+		Expiration: time.Duration(cfg.RateLimit.Window) * time.Second,
+		KeyGenerator: func(c *fiber.Ctx) string {
+			return c.IP()
+		},
+		LimitReached: func(c *fiber.Ctx) error {
+			return c.Status(429).JSON(fiber.Map{
+				"error": "Too many requests",
+			})
+		},
+	})
+}
+
 func setupMiddleware(app *fiber.App, cfg *config.Config) {
 	// Request ID
 	app.Use(requestid.New())
@@ -270,18 +333,7 @@ func setupMiddleware(app *fiber.App, cfg *config.Config) {
 
 	// Rate limiting
 	if cfg.Env == "production" {
-		app.Use(limiter.New(limiter.Config{
-			Max:        cfg.RateLimit.Max,
-			Expiration: time.Duration(cfg.RateLimit.Window) * time.Second,
-			KeyGenerator: func(c *fiber.Ctx) string {
-				return c.IP()
-			},
-			LimitReached: func(c *fiber.Ctx) error {
-				return c.Status(429).JSON(fiber.Map{
-					"error": "Too many requests",
-				})
-			},
-		}))
+		app.Use(rateLimiter(cfg))
 	}
 
 	// Compression
@@ -341,6 +393,25 @@ func initializeNotifications(cfg *config.Config) notifications.NotificationServi
 	return notifications.NewMockNotificationService()
 }
 
+// initializeMailer creates the configured mailer. Outside production a broken email
+// configuration falls back to logging emails; in production it is an error, and so are
+// the log and file drivers, since the emails carry live reset and verification links.
+func initializeMailer(cfg *config.Config) (email.Mailer, error) {
+	if cfg.IsProduction() && (cfg.Email.Driver == email.DriverLog || cfg.Email.Driver == email.DriverFile) {
+		return nil, fmt.Errorf("email driver %q cannot be used in production", cfg.Email.Driver)
+	}
+
+	mailer, err := email.NewMailer(cfg.Email)
+	if err != nil {
+		if cfg.IsProduction() {
+			return nil, err
+		}
+		log.Printf("Failed to initialize email (%s): %v, logging emails instead", cfg.Email.Driver, err)
+		return email.NewLogMailer(), nil
+	}
+	return mailer, nil
+}
+
 func initializeAIService(cfg *config.Config) *ai.Service {
 	// Only initialize if AI features are enabled and API keys are configured
 	if !cfg.Features.AIInsights {
@@ -374,8 +445,10 @@ func initializeAIService(cfg *config.Config) *ai.Service {
 func startBackgroundWorkers(
 	cfg *config.Config,
 	repos *repository.Repositories,
-	notificationService notifications.NotificationService,
 	analyticsService analytics.Analytics,
+	gdprService services.GDPRService,
+	authService services.AuthService,
+	redisClient cache.Cache,
 ) {
 	// Daily reminder worker
 	go func() {
@@ -383,7 +456,7 @@ func startBackgroundWorkers(
 		defer ticker.Stop()
 
 		for range ticker.C {
-			services.SendDailyReminders(repos.User, notificationService)
+			services.SendDailyReminders(repos, redisClient)
 		}
 	}()
 
@@ -393,7 +466,7 @@ func startBackgroundWorkers(
 		defer ticker.Stop()
 
 		for range ticker.C {
-			services.GenerateNudges(repos, analyticsService, notificationService)
+			services.GenerateNudges(repos, analyticsService)
 		}
 	}()
 
@@ -406,15 +479,40 @@ func startBackgroundWorkers(
 			services.AggregateMetrics(repos, analyticsService)
 		}
 	}()
+
+	// Expired data export sweeper
+	go func() {
+		ticker := time.NewTicker(6 * time.Hour)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			services.PurgeExpiredExports(gdprService)
+		}
+	}()
+
+	// Stale refresh token sweeper
+	go func() {
+		ticker := time.NewTicker(6 * time.Hour)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			services.PurgeStaleRefreshTokens(authService)
+		}
+	}()
 }
 
-func gracefulShutdown(app *fiber.App) {
+func gracefulShutdown(app *fiber.App, cleanups ...func()) {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	log.Println("Shutting down server...")
 
+	// Stop background workers while the server still blocks main
+	for _, cleanup := range cleanups {
+		cleanup()
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/vyve/vyve-backend/internal/config"
+	"github.com/vyve/vyve-backend/pkg/email"
+)
+
+func TestInitializeMailer(t *testing.T) {
+	smtp := config.EmailConfig{Driver: email.DriverSMTP, Host: "smtp.example.com", From: "Vyve <no-reply@example.com>"}
+
+	tests := []struct {
+		name    string
+		env     string
+		email   config.EmailConfig
+		wantErr bool
+		wantLog bool
+	}{
+		{"production smtp", "production", smtp, false, false},
+		{"production without smtp host", "production", config.EmailConfig{Driver: email.DriverSMTP}, true, false},
+		{"production log driver", "production", config.EmailConfig{Driver: email.DriverLog}, true, false},
+		{"production file driver", "production", config.EmailConfig{Driver: email.DriverFile, OutboxDir: t.TempDir()}, true, false},
+		{"development falls back to logging", "development", config.EmailConfig{Driver: email.DriverSMTP}, false, true},
+		{"development log driver", "development", config.EmailConfig{Driver: email.DriverLog}, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mailer, err := initializeMailer(&config.Config{Env: tt.env, Email: tt.email})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("initializeMailer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if _, isLog := mailer.(*email.LogMailer); isLog != tt.wantLog {
+				t.Errorf("initializeMailer() = %T, want log mailer %v", mailer, tt.wantLog)
+			}
+		})
+	}
+}
+
+func TestRateLimiterKeysOnForwardedClient(t *testing.T) {
+	// app.Test connects from 0.0.0.0, which stands in for the platform's proxy
+	cfg := &config.Config{
+		Server:    config.ServerConfig{TrustedProxies: []string{"0.0.0.0"}},
+		RateLimit: config.RateLimitConfig{Max: 1, Window: 60},
+	}
+	app := fiber.New(appConfig(cfg))
+	app.Use(rateLimiter(cfg))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString(c.IP())
+	})
+
+	tests := []struct {
+		name       string
+		forwarded  string
+		wantStatus int
+	}{
+		{"first client", "203.0.113.7", fiber.StatusOK},
+		{"second client", "198.51.100.20", fiber.StatusOK},
+		{"first client again", "203.0.113.7", fiber.StatusTooManyRequests},
+		{"second client again", "198.51.100.20", fiber.StatusTooManyRequests},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+		req.Header.Set(fiber.HeaderXForwardedFor, tt.forwarded)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != tt.wantStatus {
+			t.Errorf("%s: status = %d, want %d", tt.name, resp.StatusCode, tt.wantStatus)
+		}
+	}
+}
+
+func TestRateLimiterIgnoresUntrustedForwardedFor(t *testing.T) {
+	cfg := &config.Config{
+		Server:    config.ServerConfig{TrustedProxies: []string{"10.0.0.0/8"}},
+		RateLimit: config.RateLimitConfig{Max: 1, Window: 60},
+	}
+	app := fiber.New(appConfig(cfg))
+	app.Use(rateLimiter(cfg))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString(c.IP())
+	})
+
+	// A client that isn't behind a trusted proxy can't dodge the limit by
+	// rotating the header
+	for i, forwarded := range []string{"203.0.113.7", "198.51.100.20"} {
+		req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+		req.Header.Set(fiber.HeaderXForwardedFor, forwarded)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []int{fiber.StatusOK, fiber.StatusTooManyRequests}[i]; resp.StatusCode != want {
+			t.Errorf("request %d: status = %d, want %d", i+1, resp.StatusCode, want)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+// Command grantrole grants or revokes a user role from the command line.
+//
+// It is the bootstrap path for the first admin: once one admin exists, further
+// changes can go through the admin API. Changes are recorded in the audit log
+// without an acting user, and the user's sessions are ended when Redis is reachable
+// so the next token refresh carries the new roles.
+//
+//	go run ./cmd/grantrole -email admin@example.com
+//	go run ./cmd/grantrole -email admin@example.com -role admin -revoke
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/joho/godotenv"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/vyve/vyve-backend/internal/config"
+	"github.com/vyve/vyve-backend/internal/models"
+	"github.com/vyve/vyve-backend/internal/repository"
+	"github.com/vyve/vyve-backend/pkg/cache"
+)
+
+func main() {
+	email := flag.String("email", "", "email of the user")
+	role := flag.String("role", models.RoleAdmin, "role to grant or revoke")
+	revoke := flag.Bool("revoke", false, "revoke the role instead of granting it")
+	flag.Parse()
+
+	if *email == "" {
+		log.Fatal("-email is required")
+	}
+	if !models.IsValidRole(*role) {
+		log.Fatalf("Unknown role %q (valid roles: %v)", *role, models.ValidRoles)
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+	cfg := config.Load()
+
+	db, err := gorm.Open(postgres.Open(cfg.GetDatabaseURL()), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Warn),
+	})
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	ctx := context.Background()
+	userRepo := repository.NewUserRepository(db)
+	auditRepo := repository.NewAuditLogRepository(db)
+
+	user, err := userRepo.FindByEmail(ctx, *email)
+	if err != nil {
+		log.Fatalf("Failed to find user %s: %v", *email, err)
+	}
+
+	action := "role_granted"
+	var changed bool
+	if *revoke {
+		action = "role_revoked"
+		// Refuses to remove the last admin
+		changed, err = userRepo.RemoveRole(ctx, user.ID, *role)
+	} else {
+		changed, err = userRepo.AddRole(ctx, user.ID, *role)
+	}
+	if err != nil {
+		log.Fatalf("Failed to update roles: %v", err)
+	}
+
+	if !changed {
+		log.Printf("Nothing to do: %s already has roles %v", user.Email, user.Roles)
+		return
+	}
+
+	if user, err = userRepo.FindByID(ctx, user.ID); err != nil {
+		log.Fatalf("Failed to reload user: %v", err)
+	}
+
+	entry := &models.AuditLog{
+		Action:     action,
+		EntityType: "user",
+		EntityID:   user.ID.String(),
+		Changes: models.JSONB{
+			"role":   *role,
+			"roles":  user.Roles,
+			"source": "cli",
+		},
+		Result: "success",
+	}
+	if err := auditRepo.Create(ctx, entry); err != nil {
+		log.Printf("Warning: failed to write audit log: %v", err)
+	}
+
+	if redisClient, err := cache.NewRedisClient(cfg.Redis); err != nil {
+		log.Printf("Warning: Redis unavailable, existing sessions keep their old roles until they expire: %v", err)
+	} else {
+		defer redisClient.Close()
+		if err := redisClient.DeletePattern(ctx, fmt.Sprintf("session:%s:*", user.ID)); err != nil {
+			log.Printf("Warning: failed to end sessions: %v", err)
+		}
+	}
+
+	log.Printf("%s now has roles %v", user.Email, user.Roles)
+}
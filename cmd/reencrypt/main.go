@@ -0,0 +1,191 @@
+// Command reencrypt rewrites encrypted columns with the active encryption key.
+//
+// Run it after enabling DB_ENCRYPTION to encrypt existing plaintext rows, or after a
+// key rotation: set ENCRYPTION_KEY/ENCRYPTION_KEY_VERSION to the new key and list the
+// old one in ENCRYPTION_PREVIOUS_KEYS ("1:<key>"). Once the command reports nothing
+// left to rotate, the previous key can be removed.
+//
+//	go run ./cmd/reencrypt -dry-run
+//	go run ./cmd/reencrypt -tables people,interactions -batch 200
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/joho/godotenv"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/vyve/vyve-backend/internal/config"
+	"github.com/vyve/vyve-backend/internal/models"
+	"github.com/vyve/vyve-backend/pkg/encryption"
+)
+
+// encryptedColumn is a column written through the "encrypted" serializer
+type encryptedColumn struct {
+	table  string
+	column string
+	array  bool // text[] with one encrypted value per element
+}
+
+var encryptedColumns = []encryptedColumn{
+	{table: "people", column: "notes"},
+	{table: "interactions", column: "notes"},
+	{table: "reflections", column: "responses", array: true},
+	{table: "users", column: "totp_secret"},
+}
+
+type stats struct {
+	scanned, rotated, failed int
+}
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "report rows that need re-encryption without writing them")
+	batchSize := flag.Int("batch", 500, "rows per batch")
+	tables := flag.String("tables", "", "comma separated tables to process (default: all)")
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+	cfg := config.Load()
+
+	if cfg.Encryption.Key == "" {
+		log.Fatal("ENCRYPTION_KEY is not set")
+	}
+	if !cfg.Encryption.Enabled {
+		log.Println("Warning: DB_ENCRYPTION is disabled, the API will not be able to read the rows written by this command")
+	}
+	keyring, err := encryption.NewKeyringFromConfig(cfg.Encryption)
+	if err != nil {
+		log.Fatalf("Failed to load encryption keys: %v", err)
+	}
+
+	db, err := gorm.Open(postgres.Open(cfg.GetDatabaseURL()), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Warn),
+	})
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	selected := make(map[string]bool)
+	for _, t := range strings.Split(*tables, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			selected[t] = true
+		}
+	}
+
+	log.Printf("Re-encrypting with key version %d (dry run: %v)", keyring.ActiveVersion(), *dryRun)
+
+	failed := false
+	for _, col := range encryptedColumns {
+		if len(selected) > 0 && !selected[col.table] {
+			continue
+		}
+		s, err := reencryptColumn(db, keyring, col, *batchSize, *dryRun)
+		if err != nil {
+			log.Fatalf("%s.%s: %v", col.table, col.column, err)
+		}
+		log.Printf("%s.%s: %d rows scanned, %d re-encrypted, %d failed", col.table, col.column, s.scanned, s.rotated, s.failed)
+		if s.failed > 0 {
+			failed = true
+		}
+	}
+
+	if failed {
+		log.Fatal("Some rows could not be decrypted; check that every previous key is configured")
+	}
+}
+
+// reencryptColumn walks the table by primary key and rewrites values that are
+// plaintext or encrypted with an old key. Soft-deleted rows are included.
+func reencryptColumn(db *gorm.DB, keyring *encryption.Keyring, col encryptedColumn, batchSize int, dryRun bool) (stats, error) {
+	var s stats
+	lastID := uuid.Nil
+
+	for {
+		var rows []struct {
+			ID    uuid.UUID
+			Value *string
+		}
+		err := db.Table(col.table).
+			Select(fmt.Sprintf("id, %s::text AS value", col.column)).
+			Where("id > ?", lastID).
+			Order("id").
+			Limit(batchSize).
+			Scan(&rows).Error
+		if err != nil {
+			return s, err
+		}
+		if len(rows) == 0 {
+			return s, nil
+		}
+		lastID = rows[len(rows)-1].ID
+
+		for _, row := range rows {
+			s.scanned++
+			if row.Value == nil {
+				continue
+			}
+
+			value, changed, err := rotateValue(keyring, *row.Value, col.array)
+			if err != nil {
+				s.failed++
+				log.Printf("%s %s: %v", col.table, row.ID, err)
+				continue
+			}
+			if !changed {
+				continue
+			}
+
+			s.rotated++
+			if dryRun {
+				continue
+			}
+			// UpdateColumn skips hooks and leaves updated_at untouched
+			if err := db.Table(col.table).Where("id = ?", row.ID).UpdateColumn(col.column, value).Error; err != nil {
+				return s, err
+			}
+		}
+	}
+}
+
+// rotateValue re-encrypts a raw column value. Array columns are handled element by element.
+func rotateValue(keyring *encryption.Keyring, raw string, array bool) (interface{}, bool, error) {
+	if !array {
+		if !keyring.NeedsRotation(raw) {
+			return raw, false, nil
+		}
+		value, err := keyring.Rotate(raw)
+		return value, err == nil, err
+	}
+
+	var items models.StringArray
+	if err := items.Scan(raw); err != nil {
+		return nil, false, err
+	}
+
+	changed := false
+	for i, item := range items {
+		if !keyring.NeedsRotation(item) {
+			continue
+		}
+		value, err := keyring.Rotate(item)
+		if err != nil {
+			return nil, false, err
+		}
+		items[i] = value
+		changed = true
+	}
+	if !changed {
+		return raw, false, nil
+	}
+
+	value, err := items.Value()
+	return value, err == nil, err
+}
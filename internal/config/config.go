@@ -18,6 +18,7 @@ type Config struct {
 	AWS        AWSConfig
 	Storage    StorageConfig
 	OAuth      OAuthConfig
+	Passkey    PasskeyConfig
 	Email      EmailConfig
 	FCM        FCMConfig
 	Analytics  AnalyticsConfig
@@ -34,6 +35,9 @@ type ServerConfig struct {
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
 	IdleTimeout  time.Duration
+	// TrustedProxies are the IPs and CIDRs of the platform's proxies, whose
+	// X-Forwarded-For header names the client
+	TrustedProxies []string
 }
 
 type DatabaseConfig struct {
@@ -84,6 +88,8 @@ type OAuthConfig struct {
 	Google   OAuthProvider
 	LinkedIn OAuthProvider
 	Apple    AppleOAuthProvider
+	// AppRedirectURLs are the deep links a social login may hand its result back to
+	AppRedirectURLs []string
 }
 
 type OAuthProvider struct {
@@ -100,13 +106,24 @@ type AppleOAuthProvider struct {
 	RedirectURL string
 }
 
+// PasskeyConfig identifies the site passkeys are registered for
+type PasskeyConfig struct {
+	RPID    string   // relying party ID, the domain passkeys are scoped to
+	RPName  string   // shown by the browser when creating a passkey
+	Origins []string // web and app origins passkey ceremonies may come from
+}
+
 type EmailConfig struct {
-	Host     string
-	Port     int
-	User     string
-	Password string
-	From     string
-	UseTLS   bool
+	Driver           string // smtp, log, file
+	Host             string
+	Port             int
+	User             string
+	Password         string
+	From             string
+	UseTLS           bool
+	OutboxDir        string // Directory the file driver writes .eml files to
+	AppURL           string // Public base URL of the API, used in verification links
+	PasswordResetURL string // Page that receives ?token= from password reset emails
 }
 
 type FCMConfig struct {
@@ -122,6 +139,10 @@ type AnalyticsConfig struct {
 type EncryptionConfig struct {
 	Enabled bool
 	Key     string
+	// KeyVersion identifies Key in stored values; bump it when rotating keys
+	KeyVersion int
+	// PreviousKeys are "version:key" pairs still accepted for decryption
+	PreviousKeys []string
 }
 
 type CORSConfig struct {
@@ -159,6 +180,11 @@ type AIConfig struct {
 	CacheEnabled     bool
 	CacheTTL         time.Duration
 	RateLimitPerUser int
+
+	// Analysis job queue
+	JobWorkers      int
+	JobPollInterval time.Duration
+	JobStaleAfter   time.Duration
 }
 
 // Load loads configuration from environment variables
@@ -171,6 +197,9 @@ func Load() *Config {
 			ReadTimeout:  getDuration("SERVER_READ_TIMEOUT", 15*time.Second),
 			WriteTimeout: getDuration("SERVER_WRITE_TIMEOUT", 15*time.Second),
 			IdleTimeout:  getDuration("SERVER_IDLE_TIMEOUT", 60*time.Second),
+			// Private and carrier-grade NAT ranges, which platform load balancers reach
+			// containers from
+			TrustedProxies: getEnvAsSlice("TRUSTED_PROXIES", []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "100.64.0.0/10", "fc00::/7"}),
 		},
 		
 		Database: DatabaseConfig{
@@ -235,15 +264,26 @@ func Load() *Config {
 				PrivateKey:  getEnv("APPLE_PRIVATE_KEY", ""),
 				RedirectURL: getEnv("APPLE_REDIRECT_URL", ""),
 			},
+			AppRedirectURLs: getEnvAsSlice("OAUTH_APP_REDIRECT_URLS", []string{}),
+		},
+
+		Passkey: PasskeyConfig{
+			RPID:    getEnv("PASSKEY_RP_ID", ""),
+			RPName:  getEnv("PASSKEY_RP_NAME", "Vyve"),
+			Origins: getEnvAsSlice("PASSKEY_ORIGINS", []string{}),
 		},
 		
 		Email: EmailConfig{
-			Host:     getEnv("SMTP_HOST", "localhost"),
-			Port:     getEnvAsInt("SMTP_PORT", 587),
-			User:     getEnv("SMTP_USER", ""),
-			Password: getEnv("SMTP_PASSWORD", ""),
-			From:     getEnv("SMTP_FROM", "noreply@vyve.app"),
-			UseTLS:   getEnvAsBool("SMTP_USE_TLS", true),
+			Driver:           getEnv("EMAIL_DRIVER", "smtp"),
+			Host:             getEnv("SMTP_HOST", "localhost"),
+			Port:             getEnvAsInt("SMTP_PORT", 587),
+			User:             getEnv("SMTP_USER", ""),
+			Password:         getEnv("SMTP_PASSWORD", ""),
+			From:             getEnv("SMTP_FROM", "noreply@vyve.app"),
+			UseTLS:           getEnvAsBool("SMTP_USE_TLS", true),
+			OutboxDir:        getEnv("EMAIL_OUTBOX_DIR", "./tmp/emails"),
+			AppURL:           getEnv("APP_URL", "http://localhost:8080"),
+			PasswordResetURL: getEnv("PASSWORD_RESET_URL", "http://localhost:3000/reset-password"),
 		},
 		
 		FCM: FCMConfig{
@@ -257,8 +297,10 @@ func Load() *Config {
 		},
 		
 		Encryption: EncryptionConfig{
-			Enabled: getEnvAsBool("DB_ENCRYPTION", false),
-			Key:     getEnv("ENCRYPTION_KEY", ""),
+			Enabled:      getEnvAsBool("DB_ENCRYPTION", false),
+			Key:          getEnv("ENCRYPTION_KEY", ""),
+			KeyVersion:   getEnvAsInt("ENCRYPTION_KEY_VERSION", 1),
+			PreviousKeys: getEnvAsSlice("ENCRYPTION_PREVIOUS_KEYS", nil),
 		},
 		
 		CORS: CORSConfig{
@@ -296,6 +338,9 @@ func Load() *Config {
 			CacheEnabled:     getEnvAsBool("AI_CACHE_ENABLED", true),
 			CacheTTL:         getDuration("AI_CACHE_TTL", 24*time.Hour),
 			RateLimitPerUser: getEnvAsInt("AI_RATE_LIMIT_PER_USER", 10),
+			JobWorkers:       getEnvAsInt("AI_JOB_WORKERS", 2),
+			JobPollInterval:  getDuration("AI_JOB_POLL_INTERVAL", 5*time.Second),
+			JobStaleAfter:    getDuration("AI_JOB_STALE_AFTER", 2*time.Minute),
 		},
 	}
 }
@@ -393,7 +438,11 @@ func getDuration(key string, defaultValue time.Duration) time.Duration {
 
 func getEnvAsSlice(key string, defaultValue []string) []string {
 	if value := os.Getenv(key); value != "" {
-		return strings.Split(value, ",")
+		values := strings.Split(value, ",")
+		for i := range values {
+			values[i] = strings.TrimSpace(values[i])
+		}
+		return values
 	}
 	return defaultValue
 }
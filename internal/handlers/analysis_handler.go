@@ -1,11 +1,13 @@
 package handlers
 
 import (
+	"errors"
 	"log"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"github.com/vyve/vyve-backend/internal/middleware"
+	"github.com/vyve/vyve-backend/internal/repository"
 	"github.com/vyve/vyve-backend/internal/services"
 )
 
@@ -24,6 +26,7 @@ type AnalysisHandler interface {
 	// Batch operations
 	BatchAnalyze(c *fiber.Ctx) error
 	GetJobStatus(c *fiber.Ctx) error
+	CancelJob(c *fiber.Ctx) error
 
 	// Overall insights
 	GetOverallInsights(c *fiber.Ctx) error
@@ -375,3 +378,45 @@ func (h *analysisHandler) GetOverallInsights(c *fiber.Ctx) error {
 		"recommendations": recommendations,
 	})
 }
+
+// CancelJob cancels a pending or running batch analysis job
+// POST /api/v1/analytics/jobs/:id/cancel
+func (h *analysisHandler) CancelJob(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	jobID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid job ID",
+		})
+	}
+
+	job, err := h.analysisService.CancelJob(c.Context(), userID, jobID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrJobNotCancellable):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Job has already finished",
+			})
+		case repository.IsNotFound(err):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Job not found",
+			})
+		case repository.IsForbidden(err):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Access denied",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to cancel job",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"job":     job,
+		"message": "Job cancelled",
+	})
+}
@@ -2,12 +2,23 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/go-webauthn/webauthn/protocol"
 	"github.com/gofiber/fiber/v2"
+	"golang.org/x/oauth2"
 	"github.com/vyve/vyve-backend/internal/middleware"
 	"github.com/vyve/vyve-backend/internal/repository"
 	"github.com/vyve/vyve-backend/internal/services"
+	"github.com/vyve/vyve-backend/pkg/oidc"
+	"github.com/vyve/vyve-backend/pkg/utils"
 )
 
 // AuthHandler defines authentication handler interface
@@ -19,6 +30,14 @@ type AuthHandler interface {
 	ForgotPassword(c *fiber.Ctx) error
 	ResetPassword(c *fiber.Ctx) error
 	VerifyEmail(c *fiber.Ctx) error
+	ResendVerificationEmail(c *fiber.Ctx) error
+	VerifyMFA(c *fiber.Ctx) error
+
+	// Passkey handlers
+	BeginPasskeyRegistration(c *fiber.Ctx) error
+	FinishPasskeyRegistration(c *fiber.Ctx) error
+	BeginPasskeyLogin(c *fiber.Ctx) error
+	FinishPasskeyLogin(c *fiber.Ctx) error
 
 	// OAuth handlers
 	GoogleAuth(c *fiber.Ctx) error
@@ -27,6 +46,7 @@ type AuthHandler interface {
 	LinkedInCallback(c *fiber.Ctx) error
 	AppleAuth(c *fiber.Ctx) error
 	AppleCallback(c *fiber.Ctx) error
+	ExchangeOAuthCode(c *fiber.Ctx) error
 
 	// Token validation (used by middleware)
 	ValidateToken(ctx context.Context, token string) (*services.Claims, error)
@@ -60,6 +80,7 @@ func (h *authHandler) Register(c *fiber.Ctx) error {
 	}
 
 	// Register user
+	req.Session = sessionMetadata(c)
 	response, err := h.authService.Register(c.Context(), req)
 	if err != nil {
 		return err
@@ -78,6 +99,7 @@ func (h *authHandler) Login(c *fiber.Ctx) error {
 	}
 
 	// Login user
+	req.Session = sessionMetadata(c)
 	response, err := h.authService.Login(c.Context(), req)
 	if err != nil {
 		if err == repository.ErrInvalidCredentials {
@@ -85,6 +107,26 @@ func (h *authHandler) Login(c *fiber.Ctx) error {
 				"error": "Invalid email or password",
 			})
 		}
+		if errors.Is(err, repository.ErrAccountSuspended) {
+			return accountSuspended(c)
+		}
+		var throttled *services.LoginThrottledError
+		if errors.As(err, &throttled) {
+			retryAfter := int(throttled.RetryAfter.Seconds() + 0.5)
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+			if errors.Is(err, repository.ErrAccountLocked) {
+				return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+					"error":       "Too many failed attempts; signing in with a password is paused for this account",
+					"code":        "account_locked",
+					"retry_after": retryAfter,
+				})
+			}
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"error":       "Too many failed attempts; please wait before trying again",
+				"code":        "too_many_attempts",
+				"retry_after": retryAfter,
+			})
+		}
 		// Log the actual error for debugging
 		fmt.Printf("Login error: %v\n", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -92,9 +134,32 @@ func (h *authHandler) Login(c *fiber.Ctx) error {
 		})
 	}
 
-	// Store user activity
-	c.Locals("user_id", response.User.ID)
+	// Store user activity; a two-factor challenge has no user yet
+	if response.User != nil {
+		c.Locals("user_id", response.User.ID)
+	}
+
+	return c.JSON(response)
+}
+
+// VerifyMFA completes a sign-in with the second factor
+func (h *authHandler) VerifyMFA(c *fiber.Ctx) error {
+	var req struct {
+		MFAToken string `json:"mfa_token"`
+		Code     string `json:"code"`
+	}
+	if err := c.BodyParser(&req); err != nil || req.MFAToken == "" || strings.TrimSpace(req.Code) == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "MFA token and code are required",
+		})
+	}
 
+	response, err := h.authService.VerifyMFA(c.Context(), req.MFAToken, strings.TrimSpace(req.Code), sessionMetadata(c))
+	if err != nil {
+		return mfaError(c, err)
+	}
+
+	c.Locals("user_id", response.User.ID)
 	return c.JSON(response)
 }
 
@@ -111,8 +176,16 @@ func (h *authHandler) RefreshToken(c *fiber.Ctx) error {
 	}
 
 	// Refresh token
-	response, err := h.authService.RefreshToken(c.Context(), req.RefreshToken)
+	response, err := h.authService.RefreshToken(c.Context(), req.RefreshToken, sessionMetadata(c))
 	if err != nil {
+		if errors.Is(err, repository.ErrAccountSuspended) {
+			return accountSuspended(c)
+		}
+		if repository.IsUnauthorized(err) {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Invalid or expired refresh token",
+			})
+		}
 		return err
 	}
 
@@ -162,7 +235,12 @@ func (h *authHandler) ForgotPassword(c *fiber.Ctx) error {
 	}
 
 	// Send password reset email
-	if err := h.authService.ForgotPassword(c.Context(), req.Email); err != nil {
+	if err := h.authService.ForgotPassword(c.Context(), req.Email, c.IP()); err != nil {
+		if errors.Is(err, repository.ErrTooManyRequests) {
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"error": "Too many password reset requests, please try again later",
+			})
+		}
 		// Don't reveal if email exists or not
 		return c.JSON(fiber.Map{
 			"message": "If the email exists, a password reset link has been sent",
@@ -187,9 +265,23 @@ func (h *authHandler) ResetPassword(c *fiber.Ctx) error {
 		})
 	}
 
+	if req.Token == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid or expired reset link",
+		})
+	}
+
 	// Reset password
 	if err := h.authService.ResetPassword(c.Context(), req.Token, req.NewPassword); err != nil {
-		return err
+		switch {
+		case errors.Is(err, repository.ErrInvalidInput):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, repository.ErrTokenInvalid):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid or expired reset link"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to reset password",
+		})
 	}
 
 	return c.JSON(fiber.Map{
@@ -208,7 +300,14 @@ func (h *authHandler) VerifyEmail(c *fiber.Ctx) error {
 
 	// Verify email
 	if err := h.authService.VerifyEmail(c.Context(), token); err != nil {
-		return err
+		if errors.Is(err, repository.ErrTokenInvalid) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid or expired verification link",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to verify email",
+		})
 	}
 
 	return c.JSON(fiber.Map{
@@ -216,83 +315,302 @@ func (h *authHandler) VerifyEmail(c *fiber.Ctx) error {
 	})
 }
 
-// OAuth handlers
+// ResendVerificationEmail sends a new verification link to the current user
+func (h *authHandler) ResendVerificationEmail(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
 
-// GoogleAuth initiates Google OAuth flow
-func (h *authHandler) GoogleAuth(c *fiber.Ctx) error {
-    // Create state value (could be stored/validated if needed)
-    state := c.Query("state")
-    if state == "" {
-        state = fmt.Sprintf("%s", c.Locals("requestid"))
-    }
-    url := h.authService.GetGoogleAuthURL(state)
-    if url == "" {
-        return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "Google OAuth not configured"})
-    }
-    return c.Redirect(url)
+	if err := h.authService.ResendVerificationEmail(c.Context(), userID); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrEmailAlreadyVerified):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Email is already verified",
+				"code":  "email_already_verified",
+			})
+		case errors.Is(err, repository.ErrTooManyRequests):
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"error": "Please wait a minute before requesting another email",
+			})
+		case repository.IsNotFound(err):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "User not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to send verification email",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Verification email sent",
+	})
 }
 
-// GoogleCallback handles Google OAuth callback
-func (h *authHandler) GoogleCallback(c *fiber.Ctx) error {
-	code := c.Query("code")
-	if code == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Missing authorization code",
-		})
+// Passkey handlers
+
+// BeginPasskeyRegistration returns the options to pass to navigator.credentials.create().
+// With two-factor authentication on, the body carries a code or the user's password.
+func (h *authHandler) BeginPasskeyRegistration(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var req struct {
+		Code     string `json:"code"`
+		Password string `json:"password"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		}
 	}
 
-	// Handle Google authentication
-	response, err := h.authService.HandleGoogleAuth(c.Context(), code)
+	options, err := h.authService.BeginPasskeyRegistration(c.Context(), userID, req.Code, req.Password, sessionMetadata(c))
 	if err != nil {
-		return err
+		var throttled *services.LoginThrottledError
+		switch {
+		case errors.As(err, &throttled):
+			retryAfter := int(throttled.RetryAfter.Seconds() + 0.5)
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"error":       "Too many failed attempts; please wait before trying again",
+				"code":        "too_many_attempts",
+				"retry_after": retryAfter,
+			})
+		case errors.Is(err, repository.ErrInvalidCredentials):
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Incorrect password"})
+		}
+		return passkeyError(c, err)
 	}
+	return c.JSON(options)
+}
 
-	return c.JSON(response)
+// FinishPasskeyRegistration stores the passkey the browser created
+func (h *authHandler) FinishPasskeyRegistration(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	// The credential's fields sit next to the name
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid passkey credential"})
+	}
+	credential, err := protocol.ParseCredentialCreationResponseBytes(c.Body())
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid passkey credential"})
+	}
+
+	passkey, err := h.authService.FinishPasskeyRegistration(c.Context(), userID, req.Name, credential, sessionMetadata(c))
+	if err != nil {
+		return passkeyError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"data":    passkey,
+	})
 }
 
-// LinkedInAuth initiates LinkedIn OAuth flow
-func (h *authHandler) LinkedInAuth(c *fiber.Ctx) error {
-	// TODO: Implement LinkedIn OAuth flow initiation
-	return c.Redirect("https://www.linkedin.com/oauth/v2/authorization?...")
+// BeginPasskeyLogin returns the options to pass to navigator.credentials.get()
+func (h *authHandler) BeginPasskeyLogin(c *fiber.Ctx) error {
+	options, err := h.authService.BeginPasskeyLogin(c.Context())
+	if err != nil {
+		return passkeyError(c, err)
+	}
+	return c.JSON(options)
 }
 
-// LinkedInCallback handles LinkedIn OAuth callback
-func (h *authHandler) LinkedInCallback(c *fiber.Ctx) error {
-	code := c.Query("code")
-	if code == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Missing authorization code",
-		})
+// FinishPasskeyLogin signs in with the passkey the browser returned
+func (h *authHandler) FinishPasskeyLogin(c *fiber.Ctx) error {
+	credential, err := protocol.ParseCredentialRequestResponseBytes(c.Body())
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid passkey credential"})
 	}
 
-	// Handle LinkedIn authentication
-	response, err := h.authService.HandleLinkedInAuth(c.Context(), code)
+	response, err := h.authService.FinishPasskeyLogin(c.Context(), credential, sessionMetadata(c))
 	if err != nil {
-		return err
+		return passkeyError(c, err)
 	}
 
+	c.Locals("user_id", response.User.ID)
 	return c.JSON(response)
 }
 
+// OAuth handlers
+
+// GoogleAuth initiates Google OAuth flow
+func (h *authHandler) GoogleAuth(c *fiber.Ctx) error {
+	return h.startOAuth(c, services.OAuthProviderGoogle)
+}
+
+// GoogleCallback handles Google OAuth callback
+func (h *authHandler) GoogleCallback(c *fiber.Ctx) error {
+	return h.completeOAuth(c, services.OAuthProviderGoogle, c.Query("state"), c.Query("code"), c.Query("error"),
+		func(ctx context.Context, codeVerifier string) (*services.AuthResponse, error) {
+			return h.authService.HandleGoogleAuth(ctx, c.Query("code"), codeVerifier, sessionMetadata(c))
+		})
+}
+
+// LinkedInAuth initiates LinkedIn OAuth flow
+func (h *authHandler) LinkedInAuth(c *fiber.Ctx) error {
+	return h.startOAuth(c, services.OAuthProviderLinkedIn)
+}
+
+// LinkedInCallback handles LinkedIn OAuth callback
+func (h *authHandler) LinkedInCallback(c *fiber.Ctx) error {
+	return h.completeOAuth(c, services.OAuthProviderLinkedIn, c.Query("state"), c.Query("code"), c.Query("error"),
+		func(ctx context.Context, codeVerifier string) (*services.AuthResponse, error) {
+			return h.authService.HandleLinkedInAuth(ctx, c.Query("code"), codeVerifier, sessionMetadata(c))
+		})
+}
+
 // AppleAuth initiates Apple OAuth flow
 func (h *authHandler) AppleAuth(c *fiber.Ctx) error {
-	// TODO: Implement Apple OAuth flow initiation
-	return c.Redirect("https://appleid.apple.com/auth/authorize?...")
+	return h.startOAuth(c, services.OAuthProviderApple)
 }
 
 // AppleCallback handles Apple OAuth callback
 func (h *authHandler) AppleCallback(c *fiber.Ctx) error {
 	// Apple sends a POST request with form data
 	code := c.FormValue("code")
-	if code == "" {
+	return h.completeOAuth(c, services.OAuthProviderApple, c.FormValue("state"), code, c.FormValue("error"),
+		func(ctx context.Context, codeVerifier string) (*services.AuthResponse, error) {
+			// Apple includes the user's name, as JSON, on the first sign-in only
+			var appleUser struct {
+				Name struct {
+					FirstName string `json:"firstName"`
+					LastName  string `json:"lastName"`
+				} `json:"name"`
+			}
+			if user := c.FormValue("user"); user != "" {
+				if err := json.Unmarshal([]byte(user), &appleUser); err != nil {
+					return nil, fmt.Errorf("%w: invalid user data", repository.ErrInvalidInput)
+				}
+			}
+			name := strings.TrimSpace(appleUser.Name.FirstName + " " + appleUser.Name.LastName)
+
+			return h.authService.HandleAppleAuth(ctx, code, codeVerifier, name, sessionMetadata(c))
+		})
+}
+
+// startOAuth redirects to the provider's sign-in page. The mobile app passes
+// redirect_to to get the sign-in result back on one of its allowlisted deep links.
+func (h *authHandler) startOAuth(c *fiber.Ctx, provider string) error {
+	authURL, nonce, err := h.authService.StartOAuth(c.Context(), provider, c.Query("redirect_to"))
+	if err != nil {
+		return oauthError(c, err)
+	}
+	c.Cookie(oauthStateCookie(c, provider, nonce))
+	return c.Redirect(authURL)
+}
+
+// oauthStateCookie ties a sign-in flow to the browser that started it. The cookie is
+// scoped to the provider's start path, which its callback path sits under. Apple posts
+// its callback from its own site, and browsers hold back SameSite=Lax cookies from
+// cross-site POSTs, so Apple's cookie has to be SameSite=None.
+func oauthStateCookie(c *fiber.Ctx, provider, nonce string) *fiber.Cookie {
+	sameSite := fiber.CookieSameSiteLaxMode
+	if provider == services.OAuthProviderApple {
+		sameSite = fiber.CookieSameSiteNoneMode
+	}
+	cookie := &fiber.Cookie{
+		Name:     "oauth_state_" + provider,
+		Value:    nonce,
+		Path:     strings.TrimSuffix(c.Path(), "/callback"),
+		HTTPOnly: true,
+		Secure:   true,
+		SameSite: sameSite,
+	}
+	if nonce == "" {
+		cookie.Expires = time.Unix(0, 0)
+	}
+	return cookie
+}
+
+// completeOAuth checks the callback's state and cookie before signing in with its
+// code. Flows the app started end with a redirect to its deep link, carrying a
+// one-time code to exchange for the sign-in result, or an error, in the fragment; the
+// rest answer with JSON.
+func (h *authHandler) completeOAuth(c *fiber.Ctx, provider, state, code, providerErr string, signIn func(ctx context.Context, codeVerifier string) (*services.AuthResponse, error)) error {
+	nonce := c.Cookies("oauth_state_" + provider)
+	c.Cookie(oauthStateCookie(c, provider, ""))
+
+	flow, err := h.authService.CompleteOAuth(c.Context(), provider, state, nonce)
+	if err != nil {
+		return oauthError(c, err)
+	}
+
+	var response *services.AuthResponse
+	switch {
+	case providerErr != "":
+		// The user cancelled or the provider refused
+		err = fmt.Errorf("%w: %s", repository.ErrInvalidInput, providerErr)
+	case code == "":
+		err = fmt.Errorf("%w: missing authorization code", repository.ErrInvalidInput)
+	default:
+		response, err = signIn(c.Context(), flow.CodeVerifier)
+	}
+
+	if flow.AppRedirectURL != "" {
+		var exchangeCode string
+		if err == nil {
+			exchangeCode, err = h.authService.IssueOAuthCode(c.Context(), response)
+		}
+
+		fragment := url.Values{}
+		switch {
+		case providerErr != "":
+			fragment.Set("error", providerErr)
+		case err != nil && repository.IsAlreadyExists(err):
+			fragment.Set("error", "account_exists")
+		case errors.Is(err, repository.ErrAccountSuspended):
+			fragment.Set("error", "account_suspended")
+		case err != nil:
+			fragment.Set("error", "sign_in_failed")
+		default:
+			fragment.Set("code", exchangeCode)
+		}
+		if err != nil {
+			log.Printf("[AUTH] %s sign-in for app redirect failed: %v", provider, err)
+		}
+		return c.Redirect(flow.AppRedirectURL + "#" + fragment.Encode())
+	}
+
+	if err != nil {
+		return oauthError(c, err)
+	}
+	return c.JSON(response)
+}
+
+// ExchangeOAuthCode trades the one-time code an app deep link received for the result
+// of the sign-in: tokens, or an MFA challenge
+func (h *authHandler) ExchangeOAuthCode(c *fiber.Ctx) error {
+	var req struct {
+		Code string `json:"code" validate:"required"`
+	}
+
+	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Missing authorization code",
+			"error": "Invalid request body",
 		})
 	}
 
-	// Handle Apple authentication
-	response, err := h.authService.HandleAppleAuth(c.Context(), code)
+	response, err := h.authService.ExchangeOAuthCode(c.Context(), req.Code)
 	if err != nil {
+		if repository.IsUnauthorized(err) {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Invalid or expired code",
+			})
+		}
 		return err
 	}
 
@@ -306,6 +624,80 @@ func (h *authHandler) ValidateToken(ctx context.Context, token string) (*service
 
 // Helper functions
 
+// oauthError maps errors of OAuth sign-in and account linking to responses
+func oauthError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrAccountSuspended):
+		return accountSuspended(c)
+	case errors.Is(err, repository.ErrProviderNotConfigured):
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, repository.ErrInvalidOAuthState):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid or expired sign-in state; start signing in again"})
+	case errors.Is(err, repository.ErrInvalidInput):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case repository.IsAlreadyExists(err):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, oidc.ErrInvalidToken):
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Identity provider returned an invalid token"})
+	}
+	var retrieveErr *oauth2.RetrieveError
+	if errors.As(err, &retrieveErr) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid or expired authorization code"})
+	}
+	log.Printf("[AUTH] OAuth sign-in failed: %v", err)
+	return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{"error": "Sign-in with the identity provider failed"})
+}
+
+// passkeyError maps errors of passkey registration and sign-in to responses
+func passkeyError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrAccountSuspended):
+		return accountSuspended(c)
+	case errors.Is(err, repository.ErrProviderNotConfigured):
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "Passkeys are not available"})
+	case errors.Is(err, repository.ErrInvalidInput):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, repository.ErrLastAuthMethod):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "Set a password, link an account or add another passkey before removing your last login method"})
+	case repository.IsAlreadyExists(err):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, repository.ErrReauthRequired):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Confirm with a two-factor code, a recovery code or your password to add a passkey",
+			"code":  "reauth_required",
+		})
+	case errors.Is(err, repository.ErrInvalidMFACode):
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid two-factor authentication code"})
+	case errors.Is(err, repository.ErrTokenInvalid):
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Passkey sign-in expired; try again"})
+	case repository.IsUnauthorized(err):
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Passkey not recognized"})
+	case repository.IsNotFound(err):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Passkey not found"})
+	}
+	log.Printf("[AUTH] Passkey request failed: %v", err)
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Passkey request failed"})
+}
+
+// accountSuspended answers a sign-in by a user an admin has suspended
+func accountSuspended(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+		"error": "This account has been suspended",
+		"code":  "account_suspended",
+	})
+}
+
+// sessionMetadata describes the device making the request. Apps may name the device
+// with the X-Device-ID and X-Device-Name headers.
+func sessionMetadata(c *fiber.Ctx) services.SessionMetadata {
+	return services.SessionMetadata{
+		IPAddress:  c.IP(),
+		UserAgent:  c.Get(fiber.HeaderUserAgent),
+		DeviceID:   utils.TruncateString(c.Get("X-Device-ID"), 255),
+		DeviceName: utils.TruncateString(c.Get("X-Device-Name"), 100),
+	}
+}
+
 func validateRegisterRequest(req services.RegisterRequest) error {
 	// TODO: Add validation logic
 	return nil
@@ -9,6 +9,15 @@ type UserHandler interface {
 	UpdateProfile(c *fiber.Ctx) error
 	DeleteAccount(c *fiber.Ctx) error
 	ChangePassword(c *fiber.Ctx) error
+	ListSessions(c *fiber.Ctx) error
+	RevokeSession(c *fiber.Ctx) error
+	GetMFAStatus(c *fiber.Ctx) error
+	EnrollMFA(c *fiber.Ctx) error
+	ConfirmMFA(c *fiber.Ctx) error
+	DisableMFA(c *fiber.Ctx) error
+	RegenerateRecoveryCodes(c *fiber.Ctx) error
+	ListPasskeys(c *fiber.Ctx) error
+	DeletePasskey(c *fiber.Ctx) error
 	UploadAvatar(c *fiber.Ctx) error
 	GetStats(c *fiber.Ctx) error
 	GetSettings(c *fiber.Ctx) error
@@ -38,6 +47,9 @@ type UserHandler interface {
 	GetNotificationPreferences(c *fiber.Ctx) error
 	UpdateNotificationPreferences(c *fiber.Ctx) error
 	SendTestNotification(c *fiber.Ctx) error
+	ListNotifications(c *fiber.Ctx) error
+	MarkNotificationRead(c *fiber.Ctx) error
+	MarkAllNotificationsRead(c *fiber.Ctx) error
 	
 	// Admin
 	AdminListUsers(c *fiber.Ctx) error
@@ -46,6 +58,9 @@ type UserHandler interface {
 	AdminDeleteUser(c *fiber.Ctx) error
 	AdminSuspendUser(c *fiber.Ctx) error
 	AdminUnsuspendUser(c *fiber.Ctx) error
+	AdminUnlockUser(c *fiber.Ctx) error
+	AdminGrantRole(c *fiber.Ctx) error
+	AdminRevokeRole(c *fiber.Ctx) error
 	
 	// System
 	GetSystemStats(c *fiber.Ctx) error
@@ -76,6 +91,9 @@ type PersonHandler interface {
 	GetInteractions(c *fiber.Ctx) error
 	GetHealthScore(c *fiber.Ctx) error
 	UpdateReminder(c *fiber.Ctx) error
+	ClearReminder(c *fiber.Ctx) error
+	SnoozeReminder(c *fiber.Ctx) error
+	SkipReminder(c *fiber.Ctx) error
 	Search(c *fiber.Ctx) error
 	GetCategories(c *fiber.Ctx) error
 	UploadAvatar(c *fiber.Ctx) error
@@ -120,6 +138,19 @@ type NudgeHandler interface {
 	GetActive(c *fiber.Ctx) error
 	GetHistory(c *fiber.Ctx) error
 	GenerateNudges(c *fiber.Ctx) error
+	ListRules(c *fiber.Ctx) error
+	GetRule(c *fiber.Ctx) error
+	CreateRule(c *fiber.Ctx) error
+	UpdateRule(c *fiber.Ctx) error
+	DeleteRule(c *fiber.Ctx) error
+	DryRunRule(c *fiber.Ctx) error
+
+	// Global rules (admin)
+	AdminListRules(c *fiber.Ctx) error
+	AdminGetRule(c *fiber.Ctx) error
+	AdminCreateRule(c *fiber.Ctx) error
+	AdminUpdateRule(c *fiber.Ctx) error
+	AdminDeleteRule(c *fiber.Ctx) error
 }
 
 // GDPRHandler defines GDPR handler interface
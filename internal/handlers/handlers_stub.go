@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"errors"
+	"time"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
+	"github.com/vyve/vyve-backend/internal/middleware"
 	"github.com/vyve/vyve-backend/internal/repository"
 	"github.com/vyve/vyve-backend/internal/services"
 )
@@ -29,12 +33,26 @@ func (h *reflectionHandler) List(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
 	}
 
-	// TODO: Implement list with pagination
-	_ = userID
+	opts := services.ListOptions{
+		Page:  c.QueryInt("page", 1),
+		Limit: c.QueryInt("limit", 20),
+	}
+	if opts.StartDate, err = parseDateQuery(c, "start_date", false); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid start_date, expected RFC3339 or YYYY-MM-DD"})
+	}
+	if opts.EndDate, err = parseDateQuery(c, "end_date", true); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid end_date, expected RFC3339 or YYYY-MM-DD"})
+	}
+
+	reflections, pagination, err := h.reflectionService.List(c.Context(), userID, opts)
+	if err != nil {
+		return reflectionError(c, err, "Failed to get reflections")
+	}
+
 	return c.JSON(fiber.Map{
-		"success": true,
-		"data":    []interface{}{},
-		"message": "List reflections - to be fully implemented",
+		"success":    true,
+		"data":       reflections,
+		"pagination": pagination,
 	})
 }
 
@@ -51,7 +69,7 @@ func (h *reflectionHandler) Create(c *fiber.Ctx) error {
 
 	reflection, err := h.reflectionService.Create(c.Context(), userID, req)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create reflection"})
+		return reflectionError(c, err, "Failed to create reflection")
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
@@ -66,11 +84,19 @@ func (h *reflectionHandler) Get(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
 	}
 
-	// TODO: Implement get by ID
-	_ = userID
+	reflectionID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid reflection ID"})
+	}
+
+	reflection, err := h.reflectionService.GetByID(c.Context(), userID, reflectionID)
+	if err != nil {
+		return reflectionError(c, err, "Failed to get reflection")
+	}
+
 	return c.JSON(fiber.Map{
 		"success": true,
-		"message": "Get reflection by ID - to be fully implemented",
+		"data":    reflection,
 	})
 }
 
@@ -80,11 +106,24 @@ func (h *reflectionHandler) Update(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
 	}
 
-	// TODO: Implement update
-	_ = userID
+	reflectionID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid reflection ID"})
+	}
+
+	var req services.UpdateReflectionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	reflection, err := h.reflectionService.Update(c.Context(), userID, reflectionID, req)
+	if err != nil {
+		return reflectionError(c, err, "Failed to update reflection")
+	}
+
 	return c.JSON(fiber.Map{
 		"success": true,
-		"message": "Update reflection - to be fully implemented",
+		"data":    reflection,
 	})
 }
 
@@ -94,12 +133,16 @@ func (h *reflectionHandler) Delete(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
 	}
 
-	// TODO: Implement delete
-	_ = userID
-	return c.JSON(fiber.Map{
-		"success": true,
-		"message": "Delete reflection - to be fully implemented",
-	})
+	reflectionID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid reflection ID"})
+	}
+
+	if err := h.reflectionService.Delete(c.Context(), userID, reflectionID); err != nil {
+		return reflectionError(c, err, "Failed to delete reflection")
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
 }
 
 func (h *reflectionHandler) GetToday(c *fiber.Ctx) error {
@@ -131,14 +174,14 @@ func (h *reflectionHandler) GetStreak(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
 	}
 
-	// TODO: Implement streak calculation
-	_ = userID
+	streak, err := h.reflectionService.GetStreak(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to get streak"})
+	}
+
 	return c.JSON(fiber.Map{
 		"success": true,
-		"data": fiber.Map{
-			"streak": 0,
-			"message": "Streak calculation - to be fully implemented",
-		},
+		"data":    streak,
 	})
 }
 
@@ -164,14 +207,23 @@ func (h *reflectionHandler) GetMoodTrends(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
 	}
 
-	// TODO: Implement mood trends analysis
-	_ = userID
+	opts := services.MoodTrendOptions{
+		Period: c.Query("period", services.TrendPeriodDaily),
+		Days:   c.QueryInt("days", 0),
+		Window: c.QueryInt("window", 0),
+	}
+
+	trends, err := h.reflectionService.GetMoodTrends(c.Context(), userID, opts)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidInput) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to get mood trends"})
+	}
+
 	return c.JSON(fiber.Map{
 		"success": true,
-		"data": fiber.Map{
-			"trends":  []interface{}{},
-			"message": "Mood trends analysis - to be fully implemented",
-		},
+		"data":    trends,
 	})
 }
 
@@ -237,8 +289,21 @@ func (h *gdprHandler) RequestDataExport(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
 	}
 
-	export, err := h.gdprService.ExportUserData(c.Context(), userID)
+	// The body is optional; format defaults to json
+	var req struct {
+		Format string `json:"format"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		}
+	}
+
+	export, err := h.gdprService.ExportUserData(c.Context(), userID, req.Format)
 	if err != nil {
+		if errors.Is(err, repository.ErrInvalidInput) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create export request"})
 	}
 
@@ -296,24 +361,24 @@ func (h *gdprHandler) DownloadExport(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid export ID"})
 	}
 
-	export, err := h.gdprService.GetExport(c.Context(), userID, exportID)
+	url, err := h.gdprService.GetExportDownloadURL(c.Context(), userID, exportID)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Export not found"})
-	}
-
-	if export.Status != "completed" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error":  "Export not ready",
-			"status": export.Status,
-		})
+		switch {
+		case errors.Is(err, repository.ErrExportNotReady):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Export not ready"})
+		case errors.Is(err, repository.ErrExportExpired):
+			return c.Status(fiber.StatusGone).JSON(fiber.Map{"error": "Export has expired, please request a new one"})
+		case repository.IsNotFound(err), errors.Is(err, repository.ErrForbidden):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Export not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create download link"})
 	}
 
-	// In a real implementation, this would download from S3 or similar
-	// For now, return export data as JSON
 	return c.JSON(fiber.Map{
 		"success": true,
-		"message": "In production, this would download the export file",
-		"export":  export,
+		"data": fiber.Map{
+			"url": url,
+		},
 	})
 }
 
@@ -335,6 +400,9 @@ func (h *gdprHandler) DeleteAllData(c *fiber.Ctx) error {
 	}
 
 	if err := h.gdprService.DeleteAllUserData(c.Context(), userID); err != nil {
+		if errors.Is(err, repository.ErrLastAdmin) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "Make another user an admin before deleting your account"})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to delete user data"})
 	}
 
@@ -377,17 +445,45 @@ func (h *gdprHandler) GetAuditLog(c *fiber.Ctx) error {
 	})
 }
 
-// Helper function to get user ID from context
-func getUserID(c *fiber.Ctx) (uuid.UUID, error) {
-	userIDStr := c.Locals("user_id")
-	if userIDStr == nil {
-		return uuid.Nil, fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+// reflectionError maps reflection service errors to HTTP responses
+func reflectionError(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, repository.ErrInvalidInput):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, repository.ErrReflectionAlreadyExists):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "A reflection already exists for this day",
+			"hint":  "Set allow_multiple to true to add another one",
+		})
+	case repository.IsNotFound(err):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Reflection not found"})
+	case repository.IsForbidden(err):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Access denied"})
 	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": message})
+}
 
-	userID, err := uuid.Parse(userIDStr.(string))
+// parseDateQuery parses an optional RFC3339 or YYYY-MM-DD query parameter. With
+// endOfDay, a plain date covers the whole day.
+func parseDateQuery(c *fiber.Ctx, key string, endOfDay bool) (*time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
 	if err != nil {
-		return uuid.Nil, err
+		return nil, err
 	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return &t, nil
+}
 
-	return userID, nil
+// Helper function to get user ID from context
+func getUserID(c *fiber.Ctx) (uuid.UUID, error) {
+	return middleware.GetUserID(c)
 }
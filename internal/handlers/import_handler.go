@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/vyve/vyve-backend/internal/middleware"
+	"github.com/vyve/vyve-backend/internal/repository"
+	"github.com/vyve/vyve-backend/internal/services"
+)
+
+// ImportHandler handles data import endpoints
+type ImportHandler interface {
+	Create(c *fiber.Ctx) error
+	List(c *fiber.Ctx) error
+	Get(c *fiber.Ctx) error
+	Confirm(c *fiber.Ctx) error
+}
+
+type importHandler struct {
+	importService services.ImportService
+}
+
+// NewImportHandler creates a new import handler
+func NewImportHandler(importService services.ImportService) ImportHandler {
+	return &importHandler{
+		importService: importService,
+	}
+}
+
+// Create uploads a file and queues its import
+// POST /api/v1/imports (multipart: file, source, dry_run)
+func (h *importHandler) Create(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "No file provided"})
+	}
+	fileHandle, err := file.Open()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to read file"})
+	}
+	defer fileHandle.Close()
+
+	data, err := io.ReadAll(fileHandle)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to read file data"})
+	}
+
+	dryRun := false
+	if v := c.FormValue("dry_run"); v != "" {
+		if dryRun, err = strconv.ParseBool(v); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "dry_run must be true or false"})
+		}
+	}
+
+	job, err := h.importService.Create(c.Context(), userID, services.ImportRequest{
+		FileName: file.Filename,
+		Data:     data,
+		Source:   c.FormValue("source"),
+		DryRun:   dryRun,
+	})
+	if err != nil {
+		return importError(c, err)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"success": true,
+		"data":    job,
+	})
+}
+
+// List lists the user's recent imports
+// GET /api/v1/imports
+func (h *importHandler) List(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	jobs, err := h.importService.List(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to list imports"})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    jobs,
+	})
+}
+
+// Get returns the status, progress and preview of an import
+// GET /api/v1/imports/:id
+func (h *importHandler) Get(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	jobID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid import ID"})
+	}
+
+	job, err := h.importService.Get(c.Context(), userID, jobID)
+	if err != nil {
+		return importError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    job,
+	})
+}
+
+// Confirm runs a finished dry run for real
+// POST /api/v1/imports/:id/confirm
+func (h *importHandler) Confirm(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	jobID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid import ID"})
+	}
+
+	job, err := h.importService.Confirm(c.Context(), userID, jobID)
+	if err != nil {
+		return importError(c, err)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"success": true,
+		"data":    job,
+	})
+}
+
+// importError maps import service errors to responses
+func importError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrInvalidInput):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, repository.ErrImportNotDryRun):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "Only a completed dry run can be confirmed"})
+	case errors.Is(err, services.ErrStorageUnavailable):
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "Imports are not available right now"})
+	case repository.IsNotFound(err), errors.Is(err, repository.ErrForbidden):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Import not found"})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to process import"})
+}
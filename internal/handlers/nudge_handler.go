@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"github.com/vyve/vyve-backend/internal/middleware"
+	"github.com/vyve/vyve-backend/internal/nudgerules"
+	"github.com/vyve/vyve-backend/internal/repository"
 	"github.com/vyve/vyve-backend/internal/services"
 )
 
@@ -270,3 +273,258 @@ func (h *nudgeHandler) GenerateNudges(c *fiber.Ctx) error {
 		"message": "System nudges generated successfully",
 	})
 }
+
+// ListRules handles GET /nudges/rules
+func (h *nudgeHandler) ListRules(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	rules, err := h.nudgeService.ListRules(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to get nudge rules"})
+	}
+
+	return c.JSON(fiber.Map{
+		"rules": rules,
+		"count": len(rules),
+	})
+}
+
+// GetRule handles GET /nudges/rules/:id
+func (h *nudgeHandler) GetRule(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	ruleID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid rule ID"})
+	}
+
+	rule, err := h.nudgeService.GetRule(c.Context(), userID, ruleID)
+	if err != nil {
+		return ruleError(c, err, "Failed to get nudge rule")
+	}
+
+	return c.JSON(fiber.Map{
+		"rule": rule,
+	})
+}
+
+// CreateRule handles POST /nudges/rules
+func (h *nudgeHandler) CreateRule(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var req services.NudgeRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	rule, err := h.nudgeService.CreateRule(c.Context(), userID, req)
+	if err != nil {
+		return ruleError(c, err, "Failed to create nudge rule")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"rule": rule,
+	})
+}
+
+// UpdateRule handles PUT /nudges/rules/:id
+func (h *nudgeHandler) UpdateRule(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	ruleID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid rule ID"})
+	}
+
+	var req services.NudgeRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	rule, err := h.nudgeService.UpdateRule(c.Context(), userID, ruleID, req)
+	if err != nil {
+		return ruleError(c, err, "Failed to update nudge rule")
+	}
+
+	return c.JSON(fiber.Map{
+		"rule": rule,
+	})
+}
+
+// DeleteRule handles DELETE /nudges/rules/:id
+func (h *nudgeHandler) DeleteRule(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	ruleID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid rule ID"})
+	}
+
+	if err := h.nudgeService.DeleteRule(c.Context(), userID, ruleID); err != nil {
+		return ruleError(c, err, "Failed to delete nudge rule")
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Nudge rule deleted",
+	})
+}
+
+// DryRunRule handles POST /nudges/rules/dry-run and POST /nudges/rules/:id/dry-run.
+// It previews which people a rule would fire for without creating any nudges.
+func (h *nudgeHandler) DryRunRule(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var expression string
+	if id := c.Params("id"); id != "" {
+		ruleID, err := uuid.Parse(id)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid rule ID"})
+		}
+		rule, err := h.nudgeService.GetRule(c.Context(), userID, ruleID)
+		if err != nil {
+			return ruleError(c, err, "Failed to get nudge rule")
+		}
+		expression = rule.Expression
+	} else {
+		var req struct {
+			Expression string `json:"expression"`
+		}
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		}
+		expression = req.Expression
+	}
+
+	matches, err := h.nudgeService.DryRunRule(c.Context(), userID, expression)
+	if err != nil {
+		return ruleError(c, err, "Failed to evaluate nudge rule")
+	}
+
+	return c.JSON(fiber.Map{
+		"expression": expression,
+		"matches":    matches,
+		"count":      len(matches),
+	})
+}
+
+// AdminListRules handles GET /admin/nudge-rules
+func (h *nudgeHandler) AdminListRules(c *fiber.Ctx) error {
+	rules, err := h.nudgeService.ListGlobalRules(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to get nudge rules"})
+	}
+
+	return c.JSON(fiber.Map{
+		"rules": rules,
+		"count": len(rules),
+	})
+}
+
+// AdminGetRule handles GET /admin/nudge-rules/:id
+func (h *nudgeHandler) AdminGetRule(c *fiber.Ctx) error {
+	ruleID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid rule ID"})
+	}
+
+	rule, err := h.nudgeService.GetGlobalRule(c.Context(), ruleID)
+	if err != nil {
+		return ruleError(c, err, "Failed to get nudge rule")
+	}
+
+	return c.JSON(fiber.Map{
+		"rule": rule,
+	})
+}
+
+// AdminCreateRule handles POST /admin/nudge-rules
+func (h *nudgeHandler) AdminCreateRule(c *fiber.Ctx) error {
+	var req services.NudgeRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	rule, err := h.nudgeService.CreateGlobalRule(c.Context(), req)
+	if err != nil {
+		return ruleError(c, err, "Failed to create nudge rule")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"rule": rule,
+	})
+}
+
+// AdminUpdateRule handles PUT /admin/nudge-rules/:id
+func (h *nudgeHandler) AdminUpdateRule(c *fiber.Ctx) error {
+	ruleID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid rule ID"})
+	}
+
+	var req services.NudgeRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	rule, err := h.nudgeService.UpdateGlobalRule(c.Context(), ruleID, req)
+	if err != nil {
+		return ruleError(c, err, "Failed to update nudge rule")
+	}
+
+	return c.JSON(fiber.Map{
+		"rule": rule,
+	})
+}
+
+// AdminDeleteRule handles DELETE /admin/nudge-rules/:id
+func (h *nudgeHandler) AdminDeleteRule(c *fiber.Ctx) error {
+	ruleID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid rule ID"})
+	}
+
+	if err := h.nudgeService.DeleteGlobalRule(c.Context(), ruleID); err != nil {
+		return ruleError(c, err, "Failed to delete nudge rule")
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Nudge rule deleted",
+	})
+}
+
+// ruleError maps nudge rule errors to HTTP responses
+func ruleError(c *fiber.Ctx, err error, message string) error {
+	var parseErr *nudgerules.ParseError
+	switch {
+	case errors.As(err, &parseErr):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid rule expression",
+			"details": parseErr.Error(),
+		})
+	case errors.Is(err, repository.ErrInvalidInput):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case repository.IsNotFound(err):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Nudge rule not found"})
+	case repository.IsForbidden(err):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Access denied"})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": message})
+}
@@ -1,12 +1,16 @@
 package handlers
 
 import (
+	"errors"
+	"log"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 
 	"github.com/vyve/vyve-backend/internal/middleware"
+	"github.com/vyve/vyve-backend/internal/models"
+	"github.com/vyve/vyve-backend/internal/repository"
 	"github.com/vyve/vyve-backend/internal/services"
 )
 
@@ -287,6 +291,121 @@ func (h *personHandler) UploadAvatar(c *fiber.Ctx) error {
 	})
 }
 
+// UpdateReminder handles PUT /people/:id/reminder
+func (h *personHandler) UpdateReminder(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	personID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid person ID"})
+	}
+
+	var schedule models.ReminderSchedule
+	if err := c.BodyParser(&schedule); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	person, err := h.personService.SetReminder(c.Context(), userID, personID, schedule)
+	if err != nil {
+		return reminderError(c, err, "Failed to update reminder")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    person,
+	})
+}
+
+// ClearReminder handles DELETE /people/:id/reminder
+func (h *personHandler) ClearReminder(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	personID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid person ID"})
+	}
+
+	person, err := h.personService.ClearReminder(c.Context(), userID, personID)
+	if err != nil {
+		return reminderError(c, err, "Failed to clear reminder")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    person,
+	})
+}
+
+// SnoozeReminder handles POST /people/:id/reminder/snooze
+func (h *personHandler) SnoozeReminder(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	personID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid person ID"})
+	}
+
+	var req services.SnoozeReminderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	person, err := h.personService.SnoozeReminder(c.Context(), userID, personID, req)
+	if err != nil {
+		return reminderError(c, err, "Failed to snooze reminder")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    person,
+	})
+}
+
+// SkipReminder handles POST /people/:id/reminder/skip
+func (h *personHandler) SkipReminder(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	personID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid person ID"})
+	}
+
+	person, err := h.personService.SkipNextReminder(c.Context(), userID, personID)
+	if err != nil {
+		return reminderError(c, err, "Failed to skip reminder")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    person,
+	})
+}
+
+// reminderError maps errors of reminder schedules to responses. People of other users
+// are reported as not found, like in Get.
+func reminderError(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, repository.ErrInvalidInput):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case repository.IsNotFound(err), errors.Is(err, repository.ErrForbidden):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Person not found"})
+	}
+	log.Printf("[PERSON] %s: %v", message, err)
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": message})
+}
+
 // Stub implementations for remaining methods
 func (h *personHandler) Restore(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{"error": "Not implemented yet"})
@@ -299,7 +418,3 @@ func (h *personHandler) GetInteractions(c *fiber.Ctx) error {
 func (h *personHandler) GetHealthScore(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{"error": "Not implemented yet"})
 }
-
-func (h *personHandler) UpdateReminder(c *fiber.Ctx) error {
-	return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{"error": "Not implemented yet"})
-}
@@ -1,22 +1,32 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
+	"log"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"github.com/vyve/vyve-backend/internal/middleware"
+	"github.com/vyve/vyve-backend/internal/repository"
 	"github.com/vyve/vyve-backend/internal/services"
+	"github.com/vyve/vyve-backend/pkg/utils"
 )
 
 type userHandler struct {
 	userService services.UserService
+	authService services.AuthService
+	gdprService services.GDPRService
 }
 
 // NewUserHandler creates a new user handler
-func NewUserHandler(userService services.UserService) UserHandler {
+func NewUserHandler(userService services.UserService, authService services.AuthService, gdprService services.GDPRService) UserHandler {
 	return &userHandler{
 		userService: userService,
+		authService: authService,
+		gdprService: gdprService,
 	}
 }
 
@@ -35,6 +45,7 @@ type UserProfileResponse struct {
 	LastActivityAt      *time.Time             `json:"last_activity_at,omitempty"`
 	StreakCount         int                    `json:"streak_count"`
 	LastReflectionAt    *time.Time             `json:"last_reflection_at,omitempty"`
+	Roles               []string               `json:"roles"`
 	Settings            map[string]interface{} `json:"settings"`
 	OnboardingCompleted bool                   `json:"onboarding_completed"`
 	CreatedAt           time.Time              `json:"created_at"`
@@ -80,6 +91,7 @@ func (h *userHandler) GetProfile(c *fiber.Ctx) error {
 		LastActivityAt:      user.LastActivityAt,
 		StreakCount:         user.StreakCount,
 		LastReflectionAt:    user.LastReflectionAt,
+		Roles:               user.Roles,
 		Settings:            user.Settings,
 		OnboardingCompleted: user.OnboardingCompleted,
 		CreatedAt:           user.CreatedAt,
@@ -123,6 +135,7 @@ func (h *userHandler) UpdateProfile(c *fiber.Ctx) error {
 		LastActivityAt:      user.LastActivityAt,
 		StreakCount:         user.StreakCount,
 		LastReflectionAt:    user.LastReflectionAt,
+		Roles:               user.Roles,
 		Settings:            user.Settings,
 		OnboardingCompleted: user.OnboardingCompleted,
 		CreatedAt:           user.CreatedAt,
@@ -215,7 +228,14 @@ func (h *userHandler) DeleteAccount(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
 	}
 
-	if err := h.userService.Delete(c.Context(), userID); err != nil {
+	// Account deletion is a GDPR erasure: remove all data, not just the user row
+	if err := h.gdprService.DeleteAllUserData(c.Context(), userID); err != nil {
+		if errors.Is(err, repository.ErrLastAdmin) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "Make another user an admin before deleting your account"})
+		}
+		if repository.IsNotFound(err) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "User not found"})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to delete account"})
 	}
 
@@ -225,9 +245,180 @@ func (h *userHandler) DeleteAccount(c *fiber.Ctx) error {
 	})
 }
 
-// ChangePassword handles POST /users/me/change-password
+// ChangePassword handles POST /users/me/change-password. Other sessions are signed out
+// and the response carries new tokens for this one.
 func (h *userHandler) ChangePassword(c *fiber.Ctx) error {
-	return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{"error": "Change password not implemented yet"})
+	claims, err := middleware.GetClaims(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var req struct {
+		OldPassword string `json:"old_password"`
+		NewPassword string `json:"new_password"`
+	}
+	if err := c.BodyParser(&req); err != nil || req.OldPassword == "" || req.NewPassword == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Old and new password are required"})
+	}
+
+	response, err := h.authService.ChangePassword(c.Context(), claims.UserID, claims.SessionID, req.OldPassword, req.NewPassword, sessionMetadata(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrInvalidCredentials):
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Current password is incorrect"})
+		case errors.Is(err, repository.ErrInvalidInput):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		case repository.IsNotFound(err):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "User not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to change password"})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    response,
+	})
+}
+
+// ListSessions handles GET /users/me/sessions
+func (h *userHandler) ListSessions(c *fiber.Ctx) error {
+	claims, err := middleware.GetClaims(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	sessions, err := h.authService.ListSessions(c.Context(), claims.UserID, claims.SessionID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to list sessions"})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    sessions,
+	})
+}
+
+// RevokeSession handles DELETE /users/me/sessions/:id
+func (h *userHandler) RevokeSession(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	sessionID := c.Params("id")
+	if sessionID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Session ID is required"})
+	}
+
+	if err := h.authService.RevokeSession(c.Context(), userID, sessionID); err != nil {
+		if repository.IsNotFound(err) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Session not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to sign out session"})
+	}
+
+	return c.JSON(fiber.Map{"message": "Session signed out"})
+}
+
+// GetMFAStatus handles GET /users/me/mfa
+func (h *userHandler) GetMFAStatus(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	status, err := h.authService.GetMFAStatus(c.Context(), userID)
+	if err != nil {
+		return mfaError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    status,
+	})
+}
+
+// EnrollMFA handles POST /users/me/mfa/enroll
+func (h *userHandler) EnrollMFA(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	enrollment, err := h.authService.EnrollMFA(c.Context(), userID)
+	if err != nil {
+		return mfaError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    enrollment,
+	})
+}
+
+// ConfirmMFA handles POST /users/me/mfa/confirm
+func (h *userHandler) ConfirmMFA(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	code := mfaCode(c)
+	if code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Code is required"})
+	}
+
+	recoveryCodes, err := h.authService.ConfirmMFA(c.Context(), userID, code, sessionMetadata(c))
+	if err != nil {
+		return mfaError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    fiber.Map{"recovery_codes": recoveryCodes},
+	})
+}
+
+// DisableMFA handles POST /users/me/mfa/disable
+func (h *userHandler) DisableMFA(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	code := mfaCode(c)
+	if code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Code is required"})
+	}
+
+	if err := h.authService.DisableMFA(c.Context(), userID, code, sessionMetadata(c)); err != nil {
+		return mfaError(c, err)
+	}
+
+	return c.JSON(fiber.Map{"message": "Two-factor authentication disabled"})
+}
+
+// RegenerateRecoveryCodes handles POST /users/me/mfa/recovery-codes
+func (h *userHandler) RegenerateRecoveryCodes(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	code := mfaCode(c)
+	if code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Code is required"})
+	}
+
+	recoveryCodes, err := h.authService.RegenerateRecoveryCodes(c.Context(), userID, code, sessionMetadata(c))
+	if err != nil {
+		return mfaError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    fiber.Map{"recovery_codes": recoveryCodes},
+	})
 }
 
 // UploadAvatar handles POST /users/me/upload-avatar
@@ -285,6 +476,7 @@ func (h *userHandler) UploadAvatar(c *fiber.Ctx) error {
 			LastActivityAt:      user.LastActivityAt,
 			StreakCount:         user.StreakCount,
 			LastReflectionAt:    user.LastReflectionAt,
+			Roles:               user.Roles,
 			Settings:            user.Settings,
 			OnboardingCompleted: user.OnboardingCompleted,
 			CreatedAt:           user.CreatedAt,
@@ -333,6 +525,7 @@ func (h *userHandler) UploadAvatar(c *fiber.Ctx) error {
 		LastActivityAt:      user.LastActivityAt,
 		StreakCount:         user.StreakCount,
 		LastReflectionAt:    user.LastReflectionAt,
+		Roles:               user.Roles,
 		Settings:            user.Settings,
 		OnboardingCompleted: user.OnboardingCompleted,
 		CreatedAt:           user.CreatedAt,
@@ -345,20 +538,129 @@ func (h *userHandler) UploadAvatar(c *fiber.Ctx) error {
 }
 
 // Implement the remaining UserHandler interface methods with stubs
+// LinkOAuthAccount handles POST /users/me/oauth/link/:provider with an authorization
+// code obtained from the provider
 func (h *userHandler) LinkOAuthAccount(c *fiber.Ctx) error {
-	return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{"error": "OAuth linking not implemented yet"})
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var req struct {
+		Code string `json:"code"`
+	}
+	if err := c.BodyParser(&req); err != nil || req.Code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Authorization code is required"})
+	}
+
+	if err := h.authService.LinkOAuthAccount(c.Context(), userID, c.Params("provider"), req.Code); err != nil {
+		return oauthError(c, err)
+	}
+
+	return c.JSON(fiber.Map{"message": "Account linked"})
 }
 
+// UnlinkOAuthAccount handles DELETE /users/me/oauth/unlink/:provider
 func (h *userHandler) UnlinkOAuthAccount(c *fiber.Ctx) error {
-	return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{"error": "OAuth unlinking not implemented yet"})
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	if err := h.authService.UnlinkOAuthAccount(c.Context(), userID, c.Params("provider")); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrLastAuthMethod):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "Set a password, link another account or add a passkey before removing your last login method"})
+		case repository.IsNotFound(err):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Account is not linked"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to unlink account"})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListPasskeys handles GET /users/me/passkeys
+func (h *userHandler) ListPasskeys(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	passkeys, err := h.authService.ListPasskeys(c.Context(), userID)
+	if err != nil {
+		return passkeyError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    passkeys,
+	})
+}
+
+// DeletePasskey handles DELETE /users/me/passkeys/:id
+func (h *userHandler) DeletePasskey(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	passkeyID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid passkey ID"})
+	}
+
+	if err := h.authService.DeletePasskey(c.Context(), userID, passkeyID, sessionMetadata(c)); err != nil {
+		return passkeyError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
 }
 
+// RegisterPushToken handles POST /users/me/push-token. Clients that leave device_id out
+// of the body may send it in the X-Device-ID header instead.
 func (h *userHandler) RegisterPushToken(c *fiber.Ctx) error {
-	return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{"error": "Push token registration not implemented yet"})
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var req services.RegisterPushTokenRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if req.DeviceID == "" {
+		req.DeviceID = c.Get("X-Device-ID")
+	}
+	req.DeviceID = utils.TruncateString(req.DeviceID, 255)
+
+	pushToken, err := h.userService.RegisterPushToken(c.Context(), userID, req)
+	if err != nil {
+		return notificationError(c, err, "Failed to register push token")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    pushToken,
+	})
 }
 
+// DeactivatePushToken handles DELETE /users/me/push-token/:token
 func (h *userHandler) DeactivatePushToken(c *fiber.Ctx) error {
-	return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{"error": "Push token deactivation not implemented yet"})
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	err = h.userService.DeactivatePushToken(c.Context(), userID, c.Params("token"))
+	if repository.IsNotFound(err) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Push token not found"})
+	}
+	if err != nil {
+		return notificationError(c, err, "Failed to deactivate push token")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
 }
 
 // Analytics methods
@@ -519,41 +821,477 @@ func (h *userHandler) GetSearchSuggestions(c *fiber.Ctx) error {
 }
 
 // Notification methods
+
+// GetNotificationPreferences handles GET /notifications/preferences
 func (h *userHandler) GetNotificationPreferences(c *fiber.Ctx) error {
-	return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{"error": "Notification preferences not implemented yet"})
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	prefs, err := h.userService.GetNotificationPreferences(c.Context(), userID)
+	if err != nil {
+		return notificationError(c, err, "Failed to get notification preferences")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    prefs,
+	})
 }
 
+// UpdateNotificationPreferences handles PUT /notifications/preferences. Only the types
+// and fields given are changed.
 func (h *userHandler) UpdateNotificationPreferences(c *fiber.Ctx) error {
-	return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{"error": "Update notification preferences not implemented yet"})
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var req services.NotificationPreferencesUpdate
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	prefs, err := h.userService.UpdateNotificationPreferences(c.Context(), userID, req)
+	if err != nil {
+		return notificationError(c, err, "Failed to update notification preferences")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    prefs,
+	})
 }
 
+// SendTestNotification handles POST /notifications/test
 func (h *userHandler) SendTestNotification(c *fiber.Ctx) error {
-	return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{"error": "Test notification not implemented yet"})
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	devices, err := h.userService.SendTestNotification(c.Context(), userID)
+	if err != nil {
+		return notificationError(c, err, "Failed to send test notification")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    fiber.Map{"devices": devices},
+	})
+}
+
+// notificationListMaxLimit caps the page size of the notification inbox
+const notificationListMaxLimit = 100
+
+// ListNotifications handles GET /notifications, the in-app inbox
+func (h *userHandler) ListNotifications(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	page := c.QueryInt("page", 1)
+	limit := min(c.QueryInt("limit", 20), notificationListMaxLimit)
+	items, pagination, err := h.userService.ListNotifications(c.Context(), userID, c.QueryBool("unread"), page, limit)
+	if err != nil {
+		return notificationError(c, err, "Failed to get notifications")
+	}
+	unread, err := h.userService.CountUnreadNotifications(c.Context(), userID)
+	if err != nil {
+		return notificationError(c, err, "Failed to get notifications")
+	}
+
+	return c.JSON(fiber.Map{
+		"success":      true,
+		"data":         items,
+		"pagination":   pagination,
+		"unread_count": unread,
+	})
+}
+
+// MarkNotificationRead handles POST /notifications/:id/read
+func (h *userHandler) MarkNotificationRead(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	notificationID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid notification ID"})
+	}
+
+	notification, err := h.userService.MarkNotificationRead(c.Context(), userID, notificationID)
+	if errors.Is(err, repository.ErrNotificationNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Notification not found"})
+	}
+	if err != nil {
+		return notificationError(c, err, "Failed to mark notification as read")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    notification,
+	})
+}
+
+// MarkAllNotificationsRead handles POST /notifications/read-all
+func (h *userHandler) MarkAllNotificationsRead(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	marked, err := h.userService.MarkAllNotificationsRead(c.Context(), userID)
+	if err != nil {
+		return notificationError(c, err, "Failed to mark notifications as read")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    fiber.Map{"marked": marked},
+	})
 }
 
 // Admin methods
+
+// adminUserListMaxLimit caps the page size of the admin user list
+const adminUserListMaxLimit = 100
+
+// AdminListUsers handles GET /users. Users can be searched and filtered by signup
+// date, last activity, sign-in method, role and suspension.
 func (h *userHandler) AdminListUsers(c *fiber.Ctx) error {
-	return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{"error": "Admin list users not implemented yet"})
+	actorID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	opts := repository.UserFilterOptions{
+		FilterOptions: repository.FilterOptions{
+			Search:  strings.TrimSpace(c.Query("search")),
+			OrderBy: c.Query("order_by"),
+			Desc:    c.QueryBool("desc"),
+			Page:    c.QueryInt("page", 1),
+			Limit:   min(c.QueryInt("limit", 20), adminUserListMaxLimit),
+		},
+		Provider: c.Query("provider"),
+		Role:     c.Query("role"),
+	}
+	dates := []struct {
+		key      string
+		endOfDay bool
+		target   **time.Time
+	}{
+		{"signed_up_after", false, &opts.StartDate},
+		{"signed_up_before", true, &opts.EndDate},
+		{"active_after", false, &opts.ActiveAfter},
+		{"active_before", true, &opts.ActiveBefore},
+	}
+	for _, date := range dates {
+		if *date.target, err = parseDateQuery(c, date.key, date.endOfDay); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("Invalid %s, expected RFC3339 or YYYY-MM-DD", date.key)})
+		}
+	}
+	if c.Query("suspended") != "" {
+		suspended := c.QueryBool("suspended")
+		opts.Suspended = &suspended
+	}
+
+	users, pagination, err := h.userService.ListUsers(c.Context(), actorID, opts)
+	if err != nil {
+		return adminUserError(c, err, "Failed to list users")
+	}
+
+	return c.JSON(fiber.Map{
+		"success":    true,
+		"data":       users,
+		"pagination": pagination,
+	})
 }
 
+// AdminGetUser handles GET /users/:id
 func (h *userHandler) AdminGetUser(c *fiber.Ctx) error {
-	return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{"error": "Admin get user not implemented yet"})
+	actorID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid user ID"})
+	}
+
+	details, err := h.userService.GetUserDetails(c.Context(), actorID, userID)
+	if err != nil {
+		return adminUserError(c, err, "Failed to get user")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    details,
+	})
 }
 
+// AdminUpdateUser handles PUT /users/:id
 func (h *userHandler) AdminUpdateUser(c *fiber.Ctx) error {
-	return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{"error": "Admin update user not implemented yet"})
+	actorID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid user ID"})
+	}
+
+	var req services.AdminUserUpdate
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	user, err := h.userService.AdminUpdateUser(c.Context(), actorID, userID, req)
+	if err != nil {
+		return adminUserError(c, err, "Failed to update user")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    user,
+	})
 }
 
+// AdminDeleteUser handles DELETE /users/:id, erasing the user's account and data
+// as if they had deleted it themselves
 func (h *userHandler) AdminDeleteUser(c *fiber.Ctx) error {
-	return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{"error": "Admin delete user not implemented yet"})
+	actorID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid user ID"})
+	}
+
+	if err := h.gdprService.DeleteUserDataAsAdmin(c.Context(), actorID, userID); err != nil {
+		return adminUserError(c, err, "Failed to delete user")
+	}
+
+	return c.JSON(fiber.Map{"message": "User deleted"})
 }
 
+// AdminSuspendUser handles POST /users/:id/suspend. The user is signed out
+// everywhere and can't sign in until the suspension is lifted.
 func (h *userHandler) AdminSuspendUser(c *fiber.Ctx) error {
-	return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{"error": "Admin suspend user not implemented yet"})
+	actorID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid user ID"})
+	}
+
+	// The reason is optional, so an empty body is fine
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		}
+	}
+
+	user, err := h.userService.SuspendUser(c.Context(), actorID, userID, req.Reason)
+	if err != nil {
+		return adminUserError(c, err, "Failed to suspend user")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    user,
+	})
 }
 
+// AdminUnsuspendUser handles POST /users/:id/unsuspend
 func (h *userHandler) AdminUnsuspendUser(c *fiber.Ctx) error {
-	return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{"error": "Admin unsuspend user not implemented yet"})
+	actorID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid user ID"})
+	}
+
+	user, err := h.userService.UnsuspendUser(c.Context(), actorID, userID)
+	if err != nil {
+		return adminUserError(c, err, "Failed to unsuspend user")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    user,
+	})
+}
+
+// AdminUnlockUser handles POST /users/:id/unlock, lifting a lockout caused by failed
+// sign-ins
+func (h *userHandler) AdminUnlockUser(c *fiber.Ctx) error {
+	actorID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid user ID"})
+	}
+
+	if err := h.authService.UnlockAccount(c.Context(), actorID, userID); err != nil {
+		if repository.IsNotFound(err) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "User not found"})
+		}
+		log.Printf("[AUTH] Failed to unlock user %s: %v", userID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to unlock account"})
+	}
+
+	return c.JSON(fiber.Map{"message": "Account unlocked"})
+}
+
+// AdminGrantRole handles POST /users/:id/roles
+func (h *userHandler) AdminGrantRole(c *fiber.Ctx) error {
+	actorID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid user ID"})
+	}
+
+	var req struct {
+		Role string `json:"role"`
+	}
+	if err := c.BodyParser(&req); err != nil || req.Role == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Role is required"})
+	}
+
+	user, err := h.userService.GrantRole(c.Context(), actorID, userID, req.Role)
+	if err != nil {
+		return roleError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    user,
+	})
+}
+
+// AdminRevokeRole handles DELETE /users/:id/roles/:role
+func (h *userHandler) AdminRevokeRole(c *fiber.Ctx) error {
+	actorID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid user ID"})
+	}
+
+	user, err := h.userService.RevokeRole(c.Context(), actorID, userID, c.Params("role"))
+	if err != nil {
+		return roleError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    user,
+	})
+}
+
+// mfaCode reads the authenticator or recovery code from the request body
+func mfaCode(c *fiber.Ctx) string {
+	var req struct {
+		Code string `json:"code"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(req.Code)
+}
+
+// mfaError maps two-factor authentication errors to responses
+func mfaError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrAccountSuspended):
+		return accountSuspended(c)
+	case errors.Is(err, repository.ErrInvalidMFACode):
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid two-factor authentication code"})
+	case errors.Is(err, repository.ErrMFAAlreadyEnabled),
+		errors.Is(err, repository.ErrMFANotEnabled),
+		errors.Is(err, repository.ErrMFANotEnrolled):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, repository.ErrTooManyRequests):
+		return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{"error": "Too many attempts; sign in again"})
+	case repository.IsUnauthorized(err):
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid or expired two-factor challenge"})
+	case repository.IsNotFound(err):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "User not found"})
+	}
+	log.Printf("[AUTH] Two-factor authentication request failed: %v", err)
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Two-factor authentication request failed"})
+}
+
+// roleError maps role management errors to responses
+func roleError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrInvalidInput):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, repository.ErrLastAdmin):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case repository.IsNotFound(err):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "User not found"})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update roles"})
+}
+
+// notificationError maps errors of notification preferences to responses
+func notificationError(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, repository.ErrInvalidInput):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, repository.ErrConsentRequired):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error(), "code": "consent_required"})
+	case errors.Is(err, repository.ErrProviderNotConfigured):
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "Push notifications are not available"})
+	case repository.IsNotFound(err):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "User not found"})
+	}
+	log.Printf("[USER] %s: %v", message, err)
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": message})
+}
+
+// adminUserError maps errors of admin user management to responses
+func adminUserError(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, repository.ErrInvalidInput):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case repository.IsAlreadyExists(err):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, repository.ErrLastAdmin):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "The last admin cannot be deleted"})
+	case repository.IsNotFound(err):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "User not found"})
+	}
+	log.Printf("[USER] %s: %v", message, err)
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": message})
 }
 
 // System methods
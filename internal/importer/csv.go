@@ -0,0 +1,258 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Column names accepted for each field, after normalizing headers to lower_snake_case
+var (
+	nameColumns         = []string{"name", "full_name", "person", "person_name", "display_name", "contact"}
+	emailColumns        = []string{"email", "e_mail", "email_address"}
+	phoneColumns        = []string{"phone", "phone_number", "mobile", "tel"}
+	relationshipColumns = []string{"relationship", "relation"}
+	personNotesColumns  = []string{"notes", "person_notes"}
+	personContextCols   = []string{"context", "tags", "categories"}
+	dateColumns         = []string{"interaction_at", "interaction_date", "date", "last_contact"}
+	energyColumns       = []string{"energy_impact", "energy"}
+)
+
+// customFieldColumns are copied into Person.CustomFields
+var customFieldColumns = map[string]string{
+	"company":      "organization",
+	"organization": "organization",
+	"title":        "title",
+	"job_title":    "title",
+	"birthday":     "birthday",
+}
+
+type csvRow map[string]string
+
+// get returns the first non-empty value among the given columns
+func (r csvRow) get(columns ...string) string {
+	for _, c := range columns {
+		if v := strings.TrimSpace(r[c]); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// ParseCSV reads a spreadsheet with one row per person or per interaction. Rows that
+// name the same person (by email or name) are merged, and rows with an interaction
+// date also add an interaction with that person.
+//
+// Recognized columns: name (or first_name and last_name), email, phone, relationship,
+// notes, context, company, title, birthday, and for interactions interaction_at (or
+// date), energy_impact, quality, duration, location, special_tags, interaction_notes
+// and interaction_context.
+func ParseCSV(data []byte) (*Batch, error) {
+	rows, err := readCSV(data)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := &Batch{}
+	matcher := NewMatcher()
+	byRef := make(map[string]*Person)
+
+	for i, row := range rows {
+		line := i + 2 // header is line 1
+		person := personFromRow(row)
+		if person.Name == "" {
+			batch.addError("row %d: missing name", line)
+			continue
+		}
+
+		ref, ok := matcher.Match(person.Name, person.Email)
+		if ok {
+			mergePerson(byRef[ref], person)
+		} else {
+			ref = fmt.Sprintf("row-%d", line)
+			person.Ref = ref
+			matcher.Add(ref, person.Name, person.Email)
+			byRef[ref] = person
+			batch.People = append(batch.People, person)
+		}
+
+		interaction, err := interactionFromRow(row, ref, "interaction_notes", "interaction_context")
+		if err != nil {
+			batch.addError("row %d: %v", line, err)
+			continue
+		}
+		if interaction != nil {
+			batch.Interactions = append(batch.Interactions, interaction)
+		}
+	}
+
+	return batch, nil
+}
+
+// readCSV reads a CSV file into rows keyed by normalized header
+func readCSV(data []byte) ([]csvRow, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // Excel adds a BOM
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, ErrEmpty
+		}
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	for i, h := range header {
+		header[i] = normalizeHeader(h)
+	}
+
+	var rows []csvRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+
+		row := make(csvRow, len(header))
+		for i, value := range record {
+			if i < len(header) {
+				row[header[i]] = value
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func normalizeHeader(h string) string {
+	h = strings.ToLower(strings.TrimSpace(h))
+	h = strings.NewReplacer(" ", "_", "-", "_", ".", "_").Replace(h)
+	return h
+}
+
+// personFromRow reads the person columns of a row
+func personFromRow(row csvRow) *Person {
+	name := row.get(nameColumns...)
+	if name == "" {
+		name = strings.TrimSpace(row.get("first_name", "given_name") + " " + row.get("last_name", "family_name", "surname"))
+	}
+
+	person := &Person{
+		Name:         name,
+		Email:        row.get(emailColumns...),
+		Phone:        row.get(phoneColumns...),
+		Relationship: row.get(relationshipColumns...),
+		Notes:        row.get(personNotesColumns...),
+		Context:      parseList(row.get(personContextCols...)),
+	}
+
+	if raw := row.get("custom_fields"); raw != "" {
+		var fields map[string]interface{}
+		if json.Unmarshal([]byte(raw), &fields) == nil {
+			person.CustomFields = fields
+		}
+	}
+	for column, field := range customFieldColumns {
+		if v := row.get(column); v != "" {
+			if person.CustomFields == nil {
+				person.CustomFields = make(map[string]interface{})
+			}
+			person.CustomFields[field] = v
+		}
+	}
+	if person.Email == "" {
+		person.Email = customString(person.CustomFields, "email")
+	}
+	if person.Phone == "" {
+		person.Phone = customString(person.CustomFields, "phone")
+	}
+
+	return person
+}
+
+// interactionFromRow reads the interaction columns of a row. It returns nil when the
+// row has no interaction date.
+func interactionFromRow(row csvRow, personRef, notesColumn, contextColumn string) (*Interaction, error) {
+	date := row.get(dateColumns...)
+	if date == "" {
+		return nil, nil
+	}
+	at, err := parseDate(date)
+	if err != nil {
+		return nil, err
+	}
+
+	energy, err := normalizeEnergyImpact(row.get(energyColumns...))
+	if err != nil {
+		return nil, err
+	}
+
+	interaction := &Interaction{
+		PersonRef:     personRef,
+		InteractionAt: at,
+		EnergyImpact:  energy,
+		Notes:         row.get(notesColumn),
+		Location:      row.get("location"),
+		Context:       parseList(row.get(contextColumn)),
+		SpecialTags:   parseList(row.get("special_tags")),
+	}
+	if interaction.Quality, err = parseInt(row.get("quality"), 0, 5); err != nil {
+		return nil, fmt.Errorf("quality: %w", err)
+	}
+	if interaction.Duration, err = parseInt(row.get("duration"), 0, 24*60); err != nil {
+		return nil, fmt.Errorf("duration: %w", err)
+	}
+	return interaction, nil
+}
+
+// parseList reads a JSON array or a list separated by semicolons, commas or pipes
+func parseList(value string) []string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	if strings.HasPrefix(value, "[") {
+		var items []string
+		if json.Unmarshal([]byte(value), &items) == nil {
+			return items
+		}
+	}
+
+	var items []string
+	for _, item := range strings.FieldsFunc(value, func(r rune) bool { return r == ';' || r == ',' || r == '|' }) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseInt parses an optional integer within [min, max]
+func parseInt(value string, min, max int) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", value)
+	}
+	if n < min || n > max {
+		return 0, fmt.Errorf("%d is out of range %d-%d", n, min, max)
+	}
+	return n, nil
+}
+
+func customString(fields map[string]interface{}, key string) string {
+	s, _ := fields[key].(string)
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,197 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+// maxArchiveFileSize caps the uncompressed size of a file read from an archive
+const maxArchiveFileSize = 100 << 20
+
+// ErrNotVyveExport is returned for archives without a people file
+var ErrNotVyveExport = errors.New("archive is not a Vyve data export")
+
+// exportPerson is a person as written to people.json by the data export
+type exportPerson struct {
+	ID           string                 `json:"id"`
+	Name         string                 `json:"name"`
+	Relationship string                 `json:"relationship"`
+	Notes        string                 `json:"notes"`
+	Context      []string               `json:"context"`
+	CustomFields map[string]interface{} `json:"custom_fields"`
+}
+
+// exportInteraction is an interaction as written to interactions.json by the data export
+type exportInteraction struct {
+	PersonID      string    `json:"person_id"`
+	EnergyImpact  string    `json:"energy_impact"`
+	Context       []string  `json:"context"`
+	Duration      int       `json:"duration"`
+	Quality       int       `json:"quality"`
+	Notes         string    `json:"notes"`
+	Location      string    `json:"location"`
+	SpecialTags   []string  `json:"special_tags"`
+	InteractionAt time.Time `json:"interaction_at"`
+}
+
+// ParseVyveExport reads the people and interactions of a data export archive in
+// either the JSON or the CSV format. Interactions refer to people by their id in the
+// export.
+func ParseVyveExport(data []byte) (*Batch, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotVyveExport, err)
+	}
+
+	files := make(map[string]*zip.File)
+	for _, f := range archive.File {
+		files[path.Base(f.Name)] = f
+	}
+
+	batch := &Batch{}
+	switch {
+	case files["people.json"] != nil:
+		if err := readExportJSON(batch, files["people.json"], files["interactions.json"]); err != nil {
+			return nil, err
+		}
+	case files["people.csv"] != nil:
+		if err := readExportCSV(batch, files["people.csv"], files["interactions.csv"]); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrNotVyveExport
+	}
+	return batch, nil
+}
+
+func readExportJSON(batch *Batch, peopleFile, interactionsFile *zip.File) error {
+	var people []exportPerson
+	if err := readArchiveJSON(peopleFile, &people); err != nil {
+		return err
+	}
+	for i, p := range people {
+		if strings.TrimSpace(p.Name) == "" {
+			batch.addError("people.json item %d: missing name", i+1)
+			continue
+		}
+		batch.People = append(batch.People, &Person{
+			Ref:          p.ID,
+			Name:         strings.TrimSpace(p.Name),
+			Email:        customString(p.CustomFields, "email"),
+			Phone:        customString(p.CustomFields, "phone"),
+			Relationship: p.Relationship,
+			Notes:        p.Notes,
+			Context:      p.Context,
+			CustomFields: p.CustomFields,
+		})
+	}
+
+	if interactionsFile == nil {
+		return nil
+	}
+	var interactions []exportInteraction
+	if err := readArchiveJSON(interactionsFile, &interactions); err != nil {
+		return err
+	}
+	for i, in := range interactions {
+		energy, err := normalizeEnergyImpact(in.EnergyImpact)
+		if err != nil {
+			batch.addError("interactions.json item %d: %v", i+1, err)
+			continue
+		}
+		if in.InteractionAt.IsZero() {
+			batch.addError("interactions.json item %d: missing interaction_at", i+1)
+			continue
+		}
+		batch.Interactions = append(batch.Interactions, &Interaction{
+			PersonRef:     in.PersonID,
+			InteractionAt: in.InteractionAt,
+			EnergyImpact:  energy,
+			Quality:       in.Quality,
+			Duration:      in.Duration,
+			Notes:         in.Notes,
+			Location:      in.Location,
+			Context:       in.Context,
+			SpecialTags:   in.SpecialTags,
+		})
+	}
+	return nil
+}
+
+func readExportCSV(batch *Batch, peopleFile, interactionsFile *zip.File) error {
+	data, err := readArchiveFile(peopleFile)
+	if err != nil {
+		return err
+	}
+	rows, err := readCSV(data)
+	if err != nil && err != ErrEmpty {
+		return err
+	}
+	for i, row := range rows {
+		person := personFromRow(row)
+		if person.Name == "" {
+			batch.addError("people.csv row %d: missing name", i+2)
+			continue
+		}
+		person.Ref = row.get("id")
+		batch.People = append(batch.People, person)
+	}
+
+	if interactionsFile == nil {
+		return nil
+	}
+	if data, err = readArchiveFile(interactionsFile); err != nil {
+		return err
+	}
+	if rows, err = readCSV(data); err != nil && err != ErrEmpty {
+		return err
+	}
+	for i, row := range rows {
+		interaction, err := interactionFromRow(row, row.get("person_id"), "notes", "context")
+		if err != nil {
+			batch.addError("interactions.csv row %d: %v", i+2, err)
+			continue
+		}
+		if interaction == nil {
+			batch.addError("interactions.csv row %d: missing interaction_at", i+2)
+			continue
+		}
+		batch.Interactions = append(batch.Interactions, interaction)
+	}
+	return nil
+}
+
+func readArchiveJSON(f *zip.File, dest interface{}) error {
+	data, err := readArchiveFile(f)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		return fmt.Errorf("%s: %w", f.Name, err)
+	}
+	return nil
+}
+
+func readArchiveFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, maxArchiveFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", f.Name, err)
+	}
+	if len(data) > maxArchiveFileSize {
+		return nil, fmt.Errorf("%s is too large", f.Name)
+	}
+	return data, nil
+}
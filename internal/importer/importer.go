@@ -0,0 +1,226 @@
+// Package importer reads people and interaction history from files users bring with
+// them: a Vyve data export archive, a CSV spreadsheet or vCard contacts.
+//
+// Parsers only turn a file into a Batch. Matching the batch against the user's existing
+// people and writing it is left to the caller, with Matcher providing the name/email
+// deduplication both sides share.
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Import sources
+const (
+	SourceVyveExport = "vyve_export"
+	SourceCSV        = "csv"
+	SourceVCard      = "vcard"
+)
+
+// Energy impacts accepted for interactions
+var energyImpacts = map[string]bool{
+	"energizing": true,
+	"neutral":    true,
+	"draining":   true,
+}
+
+var (
+	// ErrUnknownSource is returned for files whose source cannot be determined
+	ErrUnknownSource = errors.New("unsupported import file, expected a Vyve export (.zip), .csv or .vcf")
+	// ErrEmpty is returned when a file contains no people
+	ErrEmpty = errors.New("no people found in file")
+)
+
+// Person is a person read from an import file
+type Person struct {
+	Ref          string // identifier interactions use to refer to this person
+	Name         string
+	Email        string
+	Phone        string
+	Relationship string
+	Notes        string
+	Context      []string
+	CustomFields map[string]interface{}
+}
+
+// Interaction is an interaction read from an import file
+type Interaction struct {
+	PersonRef     string
+	InteractionAt time.Time
+	EnergyImpact  string
+	Quality       int
+	Duration      int
+	Notes         string
+	Location      string
+	Context       []string
+	SpecialTags   []string
+}
+
+// Batch is the content of an import file
+type Batch struct {
+	People       []*Person
+	Interactions []*Interaction
+	Errors       []string // rows that were skipped, with the reason
+}
+
+func (b *Batch) addError(format string, args ...interface{}) {
+	b.Errors = append(b.Errors, fmt.Sprintf(format, args...))
+}
+
+// DetectSource returns the source of a file from its name
+func DetectSource(filename string) (string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".zip":
+		return SourceVyveExport, nil
+	case ".csv":
+		return SourceCSV, nil
+	case ".vcf", ".vcard":
+		return SourceVCard, nil
+	}
+	return "", ErrUnknownSource
+}
+
+// Parse reads a file of the given source
+func Parse(source string, data []byte) (*Batch, error) {
+	var (
+		batch *Batch
+		err   error
+	)
+	switch source {
+	case SourceVyveExport:
+		batch, err = ParseVyveExport(data)
+	case SourceCSV:
+		batch, err = ParseCSV(data)
+	case SourceVCard:
+		batch, err = ParseVCard(data)
+	default:
+		return nil, ErrUnknownSource
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(batch.People) == 0 {
+		return nil, ErrEmpty
+	}
+	return batch, nil
+}
+
+// NormalizeName lowercases a name and collapses whitespace so that "Sam  Lee" and
+// "sam lee" are the same person
+func NormalizeName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// NormalizeEmail lowercases and trims an email address
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// Matcher finds people by email, falling back to the normalized name
+type Matcher struct {
+	byEmail map[string]string
+	byName  map[string]string
+}
+
+// NewMatcher creates an empty matcher
+func NewMatcher() *Matcher {
+	return &Matcher{
+		byEmail: make(map[string]string),
+		byName:  make(map[string]string),
+	}
+}
+
+// Add registers a person under id. The first person added for a name or email wins.
+func (m *Matcher) Add(id, name, email string) {
+	if email = NormalizeEmail(email); email != "" {
+		if _, ok := m.byEmail[email]; !ok {
+			m.byEmail[email] = id
+		}
+	}
+	if name = NormalizeName(name); name != "" {
+		if _, ok := m.byName[name]; !ok {
+			m.byName[name] = id
+		}
+	}
+}
+
+// Match returns the id of the person with the same email or, failing that, the same name
+func (m *Matcher) Match(name, email string) (string, bool) {
+	if email = NormalizeEmail(email); email != "" {
+		if id, ok := m.byEmail[email]; ok {
+			return id, true
+		}
+	}
+	id, ok := m.byName[NormalizeName(name)]
+	return id, ok
+}
+
+// mergePerson fills fields of dst that are empty with values from src
+func mergePerson(dst, src *Person) {
+	if dst.Email == "" {
+		dst.Email = src.Email
+	}
+	if dst.Phone == "" {
+		dst.Phone = src.Phone
+	}
+	if dst.Relationship == "" {
+		dst.Relationship = src.Relationship
+	}
+	if dst.Notes == "" {
+		dst.Notes = src.Notes
+	}
+	if len(dst.Context) == 0 {
+		dst.Context = src.Context
+	}
+	for k, v := range src.CustomFields {
+		if dst.CustomFields == nil {
+			dst.CustomFields = make(map[string]interface{})
+		}
+		if _, ok := dst.CustomFields[k]; !ok {
+			dst.CustomFields[k] = v
+		}
+	}
+}
+
+// normalizeEnergyImpact maps an energy impact to one of the accepted values, defaulting
+// to neutral
+func normalizeEnergyImpact(value string) (string, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	switch value {
+	case "":
+		return "neutral", nil
+	case "energising", "positive", "+":
+		return "energizing", nil
+	case "negative", "-":
+		return "draining", nil
+	}
+	if !energyImpacts[value] {
+		return "", fmt.Errorf("unknown energy impact %q", value)
+	}
+	return value, nil
+}
+
+// dateLayouts are the date formats accepted in import files
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"01/02/2006",
+	"20060102",
+}
+
+// parseDate parses a date in one of the accepted layouts, as UTC when no zone is given
+func parseDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
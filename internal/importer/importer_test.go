@@ -0,0 +1,118 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestParseCSVMergesRowsAndReadsInteractions(t *testing.T) {
+	data := []byte("\xef\xbb\xbfName,Email,Relationship,Interaction Date,Energy,Quality,Interaction Notes\n" +
+		"Sam Lee,sam@example.com,friend,2024-03-01,energizing,4,Coffee\n" +
+		"sam  lee,,,2024-03-08,,,Walk\n" +
+		",nobody@example.com,,,,,\n" +
+		"Alex,,,,,,\n" +
+		"Kim,,,yesterday,,,\n")
+
+	batch, err := ParseCSV(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(batch.People) != 3 {
+		t.Fatalf("expected 3 people, got %d", len(batch.People))
+	}
+	sam := batch.People[0]
+	if sam.Email != "sam@example.com" || sam.Relationship != "friend" {
+		t.Fatalf("unexpected person %+v", sam)
+	}
+
+	if len(batch.Interactions) != 2 {
+		t.Fatalf("expected 2 interactions, got %d", len(batch.Interactions))
+	}
+	for _, in := range batch.Interactions {
+		if in.PersonRef != sam.Ref {
+			t.Fatalf("interaction not linked to Sam: %+v", in)
+		}
+	}
+	if in := batch.Interactions[1]; in.EnergyImpact != "neutral" || in.Notes != "Walk" ||
+		!in.InteractionAt.Equal(time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected interaction %+v", in)
+	}
+
+	// Missing name and the invalid date are reported
+	if len(batch.Errors) != 2 {
+		t.Fatalf("expected 2 errors, got %q", batch.Errors)
+	}
+}
+
+func TestParseVCard(t *testing.T) {
+	data := []byte("BEGIN:VCARD\r\nVERSION:3.0\r\nN:Lee;Sam;;;\r\nEMAIL;TYPE=home:sam@example.com\r\n" +
+		"TEL;TYPE=cell:+1 555 0100\r\nNOTE:Met at the climbing gym\\, loves\r\n  coffee\r\nORG:Acme;Research\r\nEND:VCARD\r\n" +
+		"BEGIN:VCARD\r\nVERSION:4.0\r\nFN:Sam Lee\r\nitem1.EMAIL:SAM@example.com\r\nTITLE:Engineer\r\nEND:VCARD\r\n" +
+		"BEGIN:VCARD\r\nVERSION:4.0\r\nEMAIL:anon@example.com\r\nEND:VCARD\r\n")
+
+	batch, err := ParseVCard(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batch.People) != 1 || len(batch.Errors) != 1 {
+		t.Fatalf("expected 1 person and 1 error, got %d and %q", len(batch.People), batch.Errors)
+	}
+
+	sam := batch.People[0]
+	if sam.Name != "Sam Lee" || sam.Phone != "+1 555 0100" || sam.Notes != "Met at the climbing gym, loves coffee" {
+		t.Fatalf("unexpected person %+v", sam)
+	}
+	if sam.CustomFields["organization"] != "Acme Research" || sam.CustomFields["title"] != "Engineer" {
+		t.Fatalf("unexpected custom fields %v", sam.CustomFields)
+	}
+}
+
+func TestParseVyveExport(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	files := map[string]string{
+		"people.json": `[{"id":"p1","name":"Sam","relationship":"friend","context":["personal"],"custom_fields":{"email":"sam@example.com"}}]`,
+		"interactions.json": `[{"person_id":"p1","energy_impact":"draining","quality":2,"interaction_at":"2024-05-01T18:00:00Z"},
+			{"person_id":"p1","energy_impact":"bored","interaction_at":"2024-05-02T18:00:00Z"}]`,
+		"manifest.json": `{}`,
+	}
+	for name, content := range files {
+		w, _ := zw.Create(name)
+		w.Write([]byte(content))
+	}
+	zw.Close()
+
+	batch, err := Parse(SourceVyveExport, buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batch.People) != 1 || batch.People[0].Ref != "p1" || batch.People[0].Email != "sam@example.com" {
+		t.Fatalf("unexpected people %+v", batch.People)
+	}
+	if len(batch.Interactions) != 1 || batch.Interactions[0].PersonRef != "p1" || len(batch.Errors) != 1 {
+		t.Fatalf("unexpected interactions %+v, errors %q", batch.Interactions, batch.Errors)
+	}
+
+	if _, err := Parse(SourceVyveExport, []byte("not a zip")); err == nil {
+		t.Fatal("expected an error for an invalid archive")
+	}
+}
+
+func TestMatcher(t *testing.T) {
+	m := NewMatcher()
+	m.Add("1", "Sam Lee", "sam@example.com")
+	m.Add("2", "Alex", "")
+
+	if id, ok := m.Match("Samuel", "SAM@example.com "); !ok || id != "1" {
+		t.Fatalf("expected email match, got %q %v", id, ok)
+	}
+	if id, ok := m.Match(" alex ", "alex@example.com"); !ok || id != "2" {
+		t.Fatalf("expected name match, got %q %v", id, ok)
+	}
+	if _, ok := m.Match("Kim", ""); ok {
+		t.Fatal("unexpected match")
+	}
+}
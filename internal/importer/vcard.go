@@ -0,0 +1,187 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+)
+
+// vcardProperty is one unfolded content line of a vCard
+type vcardProperty struct {
+	name   string
+	params string
+	value  string
+}
+
+// ParseVCard reads contacts from a vCard file (versions 2.1, 3.0 and 4.0). The
+// formatted name, first email and phone, note, organization, title, birthday and
+// categories are kept.
+func ParseVCard(data []byte) (*Batch, error) {
+	batch := &Batch{}
+	matcher := NewMatcher()
+	byRef := make(map[string]*Person)
+
+	var card []vcardProperty
+	inCard := false
+	index := 0
+
+	for _, line := range unfoldVCard(data) {
+		prop, ok := parseVCardLine(line)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VCARD"):
+			inCard = true
+			card = card[:0]
+		case prop.name == "END" && strings.EqualFold(prop.value, "VCARD"):
+			if !inCard {
+				continue
+			}
+			inCard = false
+			index++
+
+			person := personFromVCard(card)
+			if person.Name == "" {
+				batch.addError("contact %d: missing name", index)
+				continue
+			}
+			if ref, ok := matcher.Match(person.Name, person.Email); ok {
+				mergePerson(byRef[ref], person)
+				continue
+			}
+			person.Ref = person.Name
+			if person.Email != "" {
+				person.Ref = person.Email
+			}
+			matcher.Add(person.Ref, person.Name, person.Email)
+			byRef[person.Ref] = person
+			batch.People = append(batch.People, person)
+		case inCard:
+			card = append(card, prop)
+		}
+	}
+
+	return batch, nil
+}
+
+// unfoldVCard splits a vCard file into logical lines, joining continuation lines that
+// start with a space or tab
+func unfoldVCard(data []byte) []string {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// parseVCardLine splits "item1.EMAIL;TYPE=home:sam@example.com" into its name
+// (without group), parameters and value
+func parseVCardLine(line string) (vcardProperty, bool) {
+	key, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return vcardProperty{}, false
+	}
+	name, params, _ := strings.Cut(key, ";")
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return vcardProperty{
+		name:   strings.ToUpper(strings.TrimSpace(name)),
+		params: strings.ToUpper(params),
+		value:  value,
+	}, true
+}
+
+func personFromVCard(card []vcardProperty) *Person {
+	person := &Person{}
+	var structuredName string
+
+	for _, prop := range card {
+		value := unescapeVCard(prop.value)
+		switch prop.name {
+		case "FN":
+			person.Name = strings.TrimSpace(value)
+		case "N":
+			// Family;Given;Additional;Prefix;Suffix
+			parts := splitVCardValue(prop.value)
+			var given, family string
+			if len(parts) > 1 {
+				given = parts[1]
+			}
+			if len(parts) > 0 {
+				family = parts[0]
+			}
+			structuredName = strings.TrimSpace(given + " " + family)
+		case "EMAIL":
+			if person.Email == "" || strings.Contains(prop.params, "PREF") {
+				person.Email = strings.TrimSpace(value)
+			}
+		case "TEL":
+			if person.Phone == "" || strings.Contains(prop.params, "PREF") {
+				person.Phone = strings.TrimSpace(strings.TrimPrefix(value, "tel:"))
+			}
+		case "NOTE":
+			person.Notes = value
+		case "ORG":
+			setCustomField(person, "organization", strings.Trim(strings.Join(splitVCardValue(prop.value), " "), " "))
+		case "TITLE":
+			setCustomField(person, "title", value)
+		case "BDAY":
+			setCustomField(person, "birthday", value)
+		case "CATEGORIES":
+			person.Context = parseList(value)
+		}
+	}
+
+	if person.Name == "" {
+		person.Name = structuredName
+	}
+	return person
+}
+
+func setCustomField(person *Person, key, value string) {
+	if value = strings.TrimSpace(value); value == "" {
+		return
+	}
+	if person.CustomFields == nil {
+		person.CustomFields = make(map[string]interface{})
+	}
+	person.CustomFields[key] = value
+}
+
+// splitVCardValue splits a structured value on unescaped semicolons
+func splitVCardValue(value string) []string {
+	var parts []string
+	var current strings.Builder
+	escaped := false
+	for _, r := range value {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			current.WriteRune(r)
+			escaped = true
+		case r == ';':
+			parts = append(parts, unescapeVCard(current.String()))
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	return append(parts, unescapeVCard(current.String()))
+}
+
+func unescapeVCard(value string) string {
+	return strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(value)
+}
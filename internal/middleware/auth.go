@@ -26,6 +26,13 @@ const (
 // TokenValidator defines the function signature for token validation
 type TokenValidator func(ctx context.Context, token string) (*services.Claims, error)
 
+// EmailVerifiedChecker looks up whether a user's email is verified
+type EmailVerifiedChecker func(ctx context.Context, userID uuid.UUID) (bool, error)
+
+// ErrCodeEmailNotVerified is the "code" of the 403 returned when a route requires a
+// verified email, so clients can prompt the user to verify
+const ErrCodeEmailNotVerified = "email_not_verified"
+
 // AuthMiddleware validates JWT tokens
 func AuthMiddleware(validateToken TokenValidator) fiber.Handler {
 	return func(c *fiber.Ctx) error {
@@ -96,7 +103,7 @@ func OptionalAuthMiddleware(validateToken TokenValidator) fiber.Handler {
 	}
 }
 
-// RequireRole checks if user has required role
+// RequireRole checks if user has any of the required roles
 func RequireRole(roles ...string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		claims, ok := c.Locals("claims").(*services.Claims)
@@ -106,21 +113,44 @@ func RequireRole(roles ...string) fiber.Handler {
 			})
 		}
 
-		// Check if user has any of the required roles
-		// TODO: Add role checking logic based on your requirements
-		// For now, we'll skip this check
-		_ = claims
-		_ = roles
+		for _, role := range roles {
+			if claims.HasRole(role) {
+				return c.Next()
+			}
+		}
 
-		return c.Next()
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Insufficient permissions",
+		})
 	}
 }
 
-// RequireEmailVerified checks if user's email is verified
-func RequireEmailVerified() fiber.Handler {
+// RequireEmailVerified checks if user's email is verified. The user is always looked
+// up rather than trusting the token claim, since they may have verified their email or
+// changed it to an unverified one after the token was issued.
+func RequireEmailVerified(isVerified EmailVerifiedChecker) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		// TODO: Check if user's email is verified
-		// For now, we'll skip this check
+		claims, ok := c.Locals("claims").(*services.Claims)
+		if !ok {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Access denied",
+			})
+		}
+
+		verified, err := isVerified(c.Context(), claims.UserID)
+		if err != nil {
+			log.Printf("Failed to check email verification for user %s: %v", claims.UserID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to check email verification",
+			})
+		}
+		if !verified {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Please verify your email address to use this feature",
+				"code":  ErrCodeEmailNotVerified,
+			})
+		}
+
 		return c.Next()
 	}
 }
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/vyve/vyve-backend/internal/services"
+)
+
+// newTestApp serves GET / behind handler with the given claims in the context
+func newTestApp(claims *services.Claims, handler fiber.Handler) *fiber.App {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		if claims != nil {
+			c.Locals("claims", claims)
+		}
+		return c.Next()
+	})
+	app.Get("/", handler, func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	return app
+}
+
+func TestRequireRole(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims *services.Claims
+		want   int
+	}{
+		{"no claims", nil, fiber.StatusForbidden},
+		{"no roles", &services.Claims{}, fiber.StatusForbidden},
+		{"other role", &services.Claims{Roles: []string{"support"}}, fiber.StatusForbidden},
+		{"admin", &services.Claims{Roles: []string{"admin"}}, fiber.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(tt.claims, RequireRole("admin"))
+			resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequireEmailVerified(t *testing.T) {
+	userID := uuid.New()
+	lookups := 0
+	checker := func(verified bool) EmailVerifiedChecker {
+		return func(ctx context.Context, id uuid.UUID) (bool, error) {
+			lookups++
+			if id != userID {
+				t.Errorf("looked up user %s, want %s", id, userID)
+			}
+			return verified, nil
+		}
+	}
+
+	// The email was changed to an unverified one after the token was issued
+	app := newTestApp(&services.Claims{UserID: userID, EmailVerified: true}, RequireEmailVerified(checker(false)))
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusForbidden || lookups != 1 {
+		t.Errorf("stale verified claim: status = %d, lookups = %d", resp.StatusCode, lookups)
+	}
+
+	// Verified since the token was issued
+	app = newTestApp(&services.Claims{UserID: userID}, RequireEmailVerified(checker(true)))
+	resp, err = app.Test(httptest.NewRequest("GET", "/", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusOK || lookups != 2 {
+		t.Errorf("verified user: status = %d, lookups = %d", resp.StatusCode, lookups)
+	}
+
+	// Not verified
+	app = newTestApp(&services.Claims{UserID: userID}, RequireEmailVerified(checker(false)))
+	resp, err = app.Test(httptest.NewRequest("GET", "/", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusForbidden {
+		t.Fatalf("unverified user: status = %d, want %d", resp.StatusCode, fiber.StatusForbidden)
+	}
+	var body map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body["code"] != ErrCodeEmailNotVerified {
+		t.Errorf("code = %q, want %q", body["code"], ErrCodeEmailNotVerified)
+	}
+}
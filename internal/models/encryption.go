@@ -0,0 +1,106 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/vyve/vyve-backend/pkg/encryption"
+	"gorm.io/gorm/schema"
+)
+
+// fieldEncryptor is used by the "encrypted" serializer. When nil, values are
+// written as plaintext.
+var fieldEncryptor encryption.Encryptor
+
+// ErrNoEncryptionKey is returned when an encrypted value is read without a configured key
+var ErrNoEncryptionKey = errors.New("encrypted field found but encryption is not configured")
+
+func init() {
+	schema.RegisterSerializer("encrypted", EncryptedSerializer{})
+}
+
+// SetFieldEncryptor sets the encryptor used for fields tagged with serializer:encrypted
+func SetFieldEncryptor(e encryption.Encryptor) {
+	fieldEncryptor = e
+}
+
+// EncryptedSerializer encrypts string and StringArray fields at rest.
+// Each array element is encrypted separately so the column keeps its text[] type.
+type EncryptedSerializer struct{}
+
+// Scan implements schema.SerializerInterface
+func (EncryptedSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	fieldValue := reflect.New(field.FieldType)
+
+	switch target := fieldValue.Interface().(type) {
+	case *StringArray:
+		if err := target.Scan(dbValue); err != nil {
+			return err
+		}
+		for i, item := range *target {
+			plaintext, err := decryptField(item)
+			if err != nil {
+				return fmt.Errorf("%s: %w", field.Name, err)
+			}
+			(*target)[i] = plaintext
+		}
+	case *string:
+		switch v := dbValue.(type) {
+		case nil:
+		case string:
+			*target = v
+		case []byte:
+			*target = string(v)
+		default:
+			return fmt.Errorf("cannot scan %T into encrypted %s", dbValue, field.Name)
+		}
+		plaintext, err := decryptField(*target)
+		if err != nil {
+			return fmt.Errorf("%s: %w", field.Name, err)
+		}
+		*target = plaintext
+	default:
+		return fmt.Errorf("encrypted serializer does not support %s", field.FieldType)
+	}
+
+	field.ReflectValueOf(ctx, dst).Set(fieldValue.Elem())
+	return nil
+}
+
+// Value implements schema.SerializerValuerInterface
+func (EncryptedSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	switch v := fieldValue.(type) {
+	case StringArray:
+		encrypted := make(StringArray, len(v))
+		for i, item := range v {
+			value, err := encryptField(item)
+			if err != nil {
+				return nil, err
+			}
+			encrypted[i] = value
+		}
+		return encrypted.Value()
+	case string:
+		return encryptField(v)
+	}
+	return nil, fmt.Errorf("encrypted serializer does not support %T", fieldValue)
+}
+
+func encryptField(value string) (string, error) {
+	if fieldEncryptor == nil {
+		return value, nil
+	}
+	return fieldEncryptor.Encrypt(value)
+}
+
+func decryptField(value string) (string, error) {
+	if fieldEncryptor == nil {
+		if encryption.IsEncrypted(value) {
+			return "", ErrNoEncryptionKey
+		}
+		return value, nil
+	}
+	return fieldEncryptor.Decrypt(value)
+}
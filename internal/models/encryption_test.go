@@ -0,0 +1,52 @@
+package models
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/vyve/vyve-backend/pkg/encryption"
+	"gorm.io/gorm/schema"
+)
+
+func TestEncryptedSerializerRoundTrip(t *testing.T) {
+	e, err := encryption.NewAESGCM(strings.Repeat("ab", 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetFieldEncryptor(e)
+	defer SetFieldEncryptor(nil)
+
+	s, err := schema.Parse(&Reflection{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	field := s.LookUpField("responses")
+	ctx := context.Background()
+
+	value, err := EncryptedSerializer{}.Value(ctx, field, reflect.Value{}, StringArray{"first answer", "a \"quoted\", answer"})
+	if err != nil {
+		t.Fatalf("Value: %v", err)
+	}
+	stored := value.(string)
+	if strings.Contains(stored, "answer") || !strings.Contains(stored, "enc:1:") {
+		t.Fatalf("responses stored in plaintext: %s", stored)
+	}
+
+	var reflection Reflection
+	if err := (EncryptedSerializer{}).Scan(ctx, field, reflect.ValueOf(&reflection), stored); err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	want := StringArray{"first answer", "a \"quoted\", answer"}
+	if !reflect.DeepEqual(reflection.Responses, want) {
+		t.Fatalf("Responses = %q, want %q", reflection.Responses, want)
+	}
+
+	// Without a key, encrypted rows must not be returned as ciphertext
+	SetFieldEncryptor(nil)
+	if err := (EncryptedSerializer{}).Scan(ctx, field, reflect.ValueOf(&reflection), stored); err == nil {
+		t.Fatal("expected an error reading encrypted responses without a key")
+	}
+}
@@ -218,22 +218,37 @@ func (o *OnboardingSteps) Scan(value interface{}) error {
 // User represents a user in the system
 type User struct {
 	Base
-	Username         string     `gorm:"uniqueIndex;not null" json:"username"`
-	Email            string     `gorm:"uniqueIndex;not null" json:"email"`
-	EmailVerified    bool       `gorm:"default:false" json:"email_verified"`
-	PasswordHash     string     `json:"-"`
-	AvatarURL        string     `json:"avatar_url"`
-	DisplayName      string     `json:"display_name"`
-	Bio              string     `json:"bio"`
-	Timezone         string     `gorm:"default:'UTC'" json:"timezone"`
-	Locale           string     `gorm:"default:'en'" json:"locale"`
-	LastLoginAt      *time.Time `json:"last_login_at"`
-	LastActivityAt   *time.Time `json:"last_activity_at"`
-	StreakCount      int        `gorm:"default:0" json:"streak_count"`
-	LastReflectionAt *time.Time `json:"last_reflection_at"`
-	Settings         JSONB      `gorm:"type:jsonb" json:"settings"`
-	Metadata         JSONB      `gorm:"type:jsonb" json:"metadata"`
-	DataResidency    string     `gorm:"default:'us'" json:"data_residency"` // us, eu, etc.
+	Username         string      `gorm:"uniqueIndex;not null" json:"username"`
+	Email            string      `gorm:"uniqueIndex;not null" json:"email"`
+	EmailVerified    bool        `gorm:"default:false" json:"email_verified"`
+	PasswordHash     string      `json:"-"`
+	AvatarURL        string      `json:"avatar_url"`
+	DisplayName      string      `json:"display_name"`
+	Bio              string      `json:"bio"`
+	Timezone         string      `gorm:"default:'UTC'" json:"timezone"`
+	Locale           string      `gorm:"default:'en'" json:"locale"`
+	LastLoginAt      *time.Time  `json:"last_login_at"`
+	LastActivityAt   *time.Time  `json:"last_activity_at"`
+	StreakCount      int         `gorm:"default:0" json:"streak_count"`
+	LongestStreak    int         `gorm:"default:0" json:"longest_streak"`
+	LastReflectionAt *time.Time  `json:"last_reflection_at"`
+	Settings         JSONB       `gorm:"type:jsonb" json:"settings"`
+	Metadata         JSONB       `gorm:"type:jsonb" json:"metadata"`
+	DataResidency    string      `gorm:"default:'us'" json:"data_residency"` // us, eu, etc.
+	Roles            StringArray `gorm:"type:text[];not null;default:'{}'" json:"roles"`
+
+	// Two-factor authentication. TOTPSecret is stored on enrollment but only asked for
+	// at sign-in once MFAEnabledAt is set.
+	TOTPSecret   string     `gorm:"type:text;serializer:encrypted" json:"-"`
+	MFAEnabledAt *time.Time `json:"mfa_enabled_at,omitempty"`
+
+	// Suspension by an admin. A suspended user can't sign in and their sessions are
+	// ended when the suspension starts.
+	SuspendedAt      *time.Time `json:"suspended_at,omitempty"`
+	SuspensionReason string     `json:"suspension_reason,omitempty"`
+
+	// Notification preferences; nil until the user saves theirs
+	NotificationPreferences *NotificationPreferences `gorm:"type:jsonb;serializer:json" json:"-"`
 
 	// Onboarding fields with proper types:
 	OnboardingCompleted bool            `gorm:"default:false" json:"onboarding_completed"`
@@ -251,6 +266,44 @@ type User struct {
 	AuditLogs     []AuditLog     `json:"-"`
 }
 
+// User roles
+const (
+	RoleAdmin = "admin"
+)
+
+// ValidRoles lists the roles that can be granted to a user
+var ValidRoles = []string{RoleAdmin}
+
+// IsValidRole reports whether role can be granted
+func IsValidRole(role string) bool {
+	for _, r := range ValidRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// HasRole reports whether the user has been granted role
+func (u *User) HasRole(role string) bool {
+	for _, r := range u.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// MFAEnabled reports whether the user signs in with a second factor
+func (u *User) MFAEnabled() bool {
+	return u.MFAEnabledAt != nil
+}
+
+// Suspended reports whether an admin has suspended the user
+func (u *User) Suspended() bool {
+	return u.SuspendedAt != nil
+}
+
 // AuthProvider represents an OAuth provider linked to a user
 type AuthProvider struct {
 	Base
@@ -287,10 +340,11 @@ type Person struct {
 	IntentionID            *uuid.UUID           `gorm:"index" json:"intention_id,omitempty"`
 	IntentionRef           *Intention           `gorm:"foreignKey:IntentionID" json:"-"`
 	Context                StringArray          `gorm:"type:text[]" json:"context"` // work, personal, community, etc.
-	Notes                  string               `gorm:"encrypted" json:"notes"`     // Will be encrypted if enabled
+	Notes                  string               `gorm:"type:text;serializer:encrypted" json:"notes"` // Encrypted when DB_ENCRYPTION is enabled
 	CustomFields           JSONB                `gorm:"type:jsonb" json:"custom_fields"`
 	ReminderFrequency      string               `json:"reminder_frequency"` // daily, weekly, monthly, custom
 	NextReminderAt         *time.Time           `json:"next_reminder_at"`
+	ReminderSchedule       *ReminderSchedule    `gorm:"type:jsonb;serializer:json" json:"reminder_schedule,omitempty"`
 
 	// Relations
 	User         User          `gorm:"foreignKey:UserID" json:"-"`
@@ -318,7 +372,7 @@ type Interaction struct {
 	Context       StringArray `gorm:"type:text[]" json:"context"`
 	Duration      int         `json:"duration"` // in minutes
 	Quality       int         `json:"quality"`  // 1-5 scale
-	Notes         string      `gorm:"encrypted" json:"notes"`
+	Notes         string      `gorm:"type:text;serializer:encrypted" json:"notes"`
 	Location      string      `json:"location"`
 	SpecialTags   StringArray `gorm:"type:text[]" json:"special_tags"`
 	InteractionAt time.Time   `gorm:"not null;default:now()" json:"interaction_at"`
@@ -334,7 +388,7 @@ type Reflection struct {
 	Base
 	UserID      uuid.UUID   `gorm:"not null;index" json:"user_id"`
 	Prompt      string      `json:"prompt"`
-	Responses   StringArray `gorm:"type:text[];encrypted" json:"responses"`
+	Responses   StringArray `gorm:"type:text[];serializer:encrypted" json:"responses"`
 	Mood        string      `json:"mood"`
 	EnergyLevel int         `json:"energy_level"` // 1-10 scale
 	Insights    StringArray `gorm:"type:text[]" json:"insights"`
@@ -393,6 +447,20 @@ type Nudge struct {
 	Analysis *RelationshipAnalysis `gorm:"foreignKey:AnalysisID" json:"-"`
 }
 
+// NudgeRule is a rule written in the nudge rule DSL. Rules without a user are global
+// rules managed by admins and evaluated for every user after their own rules.
+type NudgeRule struct {
+	Base
+	UserID      *uuid.UUID `gorm:"index" json:"user_id,omitempty"`
+	Name        string     `gorm:"not null" json:"name"`
+	Expression  string     `gorm:"not null;type:text" json:"expression"` // e.g. "if category=family and days_since_last_interaction > 14 -> reach_out, priority high"
+	Enabled     bool       `gorm:"default:true" json:"enabled"`
+	LastFiredAt *time.Time `json:"last_fired_at,omitempty"`
+
+	// Relations
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
+
 // Event represents an analytics event
 type Event struct {
 	Base
@@ -430,7 +498,7 @@ type PushToken struct {
 	Base
 	UserID     uuid.UUID  `gorm:"not null;index" json:"user_id"`
 	Token      string     `gorm:"not null;uniqueIndex" json:"token"`
-	Platform   string     `gorm:"not null" json:"platform"` // ios, android
+	Platform   string     `gorm:"not null" json:"platform"` // ios, android, web
 	DeviceID   string     `json:"device_id"`
 	DeviceInfo JSONB      `gorm:"type:jsonb" json:"device_info"`
 	Active     bool       `gorm:"default:true" json:"active"`
@@ -444,13 +512,73 @@ type PushToken struct {
 type RefreshToken struct {
 	Base
 	UserID    uuid.UUID  `gorm:"not null;index" json:"user_id"`
-	Token     string     `gorm:"not null;uniqueIndex" json:"token"`
+	TokenHash string     `gorm:"not null;uniqueIndex" json:"-"` // hex SHA-256; the token is only ever sent to the client
 	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
 	Revoked   bool       `gorm:"default:false" json:"revoked"`
 	RevokedAt *time.Time `json:"revoked_at"`
+	UsedAt    *time.Time `json:"used_at"` // set when the token is exchanged for a new one
 	IPAddress string     `json:"ip_address"`
 	UserAgent string     `json:"user_agent"`
 
+	// Session the token belongs to. Every refresh rotates the token within the session,
+	// so the session ID also identifies the token family.
+	SessionID  string    `gorm:"index" json:"session_id"`
+	DeviceID   string    `json:"device_id"`
+	DeviceName string    `json:"device_name"`
+	SignedInAt time.Time `json:"signed_in_at"`
+
+	// Relations
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// ActionToken purposes
+const (
+	TokenPurposeVerifyEmail   = "verify_email"
+	TokenPurposePasswordReset = "password_reset"
+)
+
+// ActionToken is a single-use token sent by email, e.g. to verify an address or reset a
+// password. Only a hash of the token is stored.
+type ActionToken struct {
+	Base
+	UserID    uuid.UUID  `gorm:"not null;index" json:"user_id"`
+	Purpose   string     `gorm:"not null" json:"purpose"` // verify_email, password_reset
+	TokenHash string     `gorm:"not null;uniqueIndex" json:"-"`
+	Email     string     `json:"email"` // Address the token was sent to
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+
+	// Relations
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// MFARecoveryCode is a one-time code that can stand in for the authenticator app. Only a
+// hash of the code is stored.
+type MFARecoveryCode struct {
+	Base
+	UserID   uuid.UUID  `gorm:"not null;index" json:"user_id"`
+	CodeHash string     `gorm:"not null" json:"-"`
+	UsedAt   *time.Time `json:"used_at,omitempty"`
+
+	// Relations
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// Passkey is a WebAuthn credential a user signs in with. A user may register one per
+// device or password manager.
+type Passkey struct {
+	Base
+	UserID         uuid.UUID   `gorm:"not null;index" json:"-"`
+	CredentialID   string      `gorm:"not null;uniqueIndex" json:"credential_id"` // base64url
+	PublicKey      []byte      `gorm:"type:bytea;not null" json:"-"`              // COSE_Key
+	SignCount      int64       `gorm:"not null;default:0" json:"-"`
+	AAGUID         string      `gorm:"column:aaguid" json:"aaguid,omitempty"` // authenticator model
+	Transports     StringArray `gorm:"type:text[]" json:"transports,omitempty"`
+	Name           string      `json:"name"`
+	BackupEligible bool        `gorm:"default:false" json:"backup_eligible"`
+	BackedUp       bool        `gorm:"default:false" json:"backed_up"`
+	LastUsedAt     *time.Time  `json:"last_used_at,omitempty"`
+
 	// Relations
 	User User `gorm:"foreignKey:UserID" json:"-"`
 }
@@ -551,7 +679,7 @@ type AIAnalysisJob struct {
 	
 	// Job details
 	JobType     string      `gorm:"not null" json:"job_type"` // single_person, batch_analysis, recommendations
-	Status      string      `gorm:"not null;default:'pending'" json:"status"` // pending, processing, completed, failed
+	Status      string      `gorm:"not null;default:'pending'" json:"status"` // pending, processing, completed, failed, cancelled
 	Priority    int         `gorm:"default:5" json:"priority"` // 1-10, higher = more priority
 	
 	// Target
@@ -571,6 +699,14 @@ type AIAnalysisJob struct {
 	TotalTokensUsed int     `json:"total_tokens_used"`
 	EstimatedCost   float64 `json:"estimated_cost"`
 	
+	// Queue state
+	Attempts    int        `gorm:"default:0" json:"attempts"`
+	MaxAttempts int        `gorm:"default:3" json:"max_attempts"`
+	NextRunAt   *time.Time `json:"next_run_at,omitempty"` // Earliest time a retry may be claimed
+	WorkerID    string     `json:"-"`                     // Worker currently holding the job
+	HeartbeatAt *time.Time `json:"heartbeat_at,omitempty"`
+	CancelledAt *time.Time `json:"cancelled_at,omitempty"`
+
 	// Timing
 	StartedAt   *time.Time `json:"started_at,omitempty"`
 	CompletedAt *time.Time `json:"completed_at,omitempty"`
@@ -578,3 +714,39 @@ type AIAnalysisJob struct {
 	// Relations
 	User User `gorm:"foreignKey:UserID" json:"-"`
 }
+
+// ImportJob is a background job importing people and interactions from a file
+type ImportJob struct {
+	Base
+	UserID   uuid.UUID `gorm:"not null;index" json:"user_id"`
+	Source   string    `gorm:"not null" json:"source"` // vyve_export, csv, vcard
+	FileName string    `json:"file_name"`
+	FileKey  string    `json:"-"` // Storage key of the uploaded file
+	DryRun   bool      `gorm:"default:false" json:"dry_run"`
+	Status   string    `gorm:"not null;default:'pending'" json:"status"` // pending, processing, completed, failed
+
+	// Progress tracking
+	TotalItems     int     `gorm:"default:0" json:"total_items"`
+	ProcessedItems int     `gorm:"default:0" json:"processed_items"`
+	CreatedItems   int     `gorm:"default:0" json:"created_items"`
+	SkippedItems   int     `gorm:"default:0" json:"skipped_items"` // Duplicates of existing records
+	FailedItems    int     `gorm:"default:0" json:"failed_items"`
+	Progress       float64 `gorm:"default:0" json:"progress"` // 0-100
+
+	// Results
+	ResultData JSONB  `gorm:"type:jsonb" json:"result_data,omitempty"` // Summary, preview and row errors
+	Error      string `gorm:"type:text" json:"error,omitempty"`
+
+	// Queue state
+	Attempts    int        `gorm:"default:0" json:"attempts"`
+	MaxAttempts int        `gorm:"default:3" json:"max_attempts"`
+	WorkerID    string     `json:"-"`
+	HeartbeatAt *time.Time `json:"heartbeat_at,omitempty"`
+
+	// Timing
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+
+	// Relations
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
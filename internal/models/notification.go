@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notification delivery statuses
+const (
+	NotificationStatusPending = "pending"
+	NotificationStatusSent    = "sent"
+	NotificationStatusFailed  = "failed"
+)
+
+// Notification is a notification to a user on one channel. Push notifications wait as
+// pending until the notification worker delivers them, so the table is both the outbox
+// and the record of what was sent. In-app notifications are sent as soon as they are
+// created and make up the user's inbox.
+type Notification struct {
+	Base
+	UserID  uuid.UUID           `gorm:"not null;index" json:"-"`
+	Channel string              `gorm:"not null" json:"channel"` // push, email, in_app
+	Type    string              `gorm:"not null" json:"type"`    // nudge, reminder, streak, insight, marketing
+	Payload NotificationPayload `gorm:"type:jsonb;serializer:json" json:"payload"`
+	Status  string              `gorm:"not null;default:'pending'" json:"status"` // pending, sent, failed
+
+	// Delivery
+	Attempts      int        `gorm:"default:0" json:"attempts"`
+	MaxAttempts   int        `gorm:"default:5" json:"-"`
+	NextAttemptAt *time.Time `json:"-"`
+	Error         string     `gorm:"type:text" json:"error,omitempty"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+	OpenedAt      *time.Time `json:"opened_at,omitempty"`
+
+	// Relations
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// NotificationPayload is what a notification shows and the data the app acts on
+type NotificationPayload struct {
+	Title    string            `json:"title"`
+	Body     string            `json:"body"`
+	Data     map[string]string `json:"data,omitempty"`
+	Priority string            `json:"priority,omitempty"` // high, normal
+}
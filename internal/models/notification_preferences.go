@@ -0,0 +1,169 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Notification channels
+const (
+	ChannelPush  = "push"
+	ChannelEmail = "email"
+	ChannelInApp = "in_app"
+)
+
+// Notification types
+const (
+	NotificationNudge     = "nudge"
+	NotificationReminder  = "reminder"
+	NotificationStreak    = "streak"
+	NotificationInsight   = "insight"
+	NotificationMarketing = "marketing"
+)
+
+// Digest frequencies of the reminder digest
+const (
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+// NotificationPreferences are the notifications a user wants, per channel and type.
+// Marketing notifications also need the user's marketing consent.
+type NotificationPreferences struct {
+	Push            ChannelPreferences `json:"push"`
+	Email           ChannelPreferences `json:"email"`
+	InApp           ChannelPreferences `json:"in_app"`
+	QuietHours      QuietHours         `json:"quiet_hours"`
+	DigestFrequency string             `json:"digest_frequency"` // daily, weekly
+}
+
+// Value stores the preferences as JSON when they are written as a single column, which
+// skips the field's serializer
+func (p NotificationPreferences) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
+
+// ChannelPreferences are the notification types a user wants on one channel
+type ChannelPreferences struct {
+	Nudge     bool `json:"nudge"`
+	Reminder  bool `json:"reminder"`
+	Streak    bool `json:"streak"`
+	Insight   bool `json:"insight"`
+	Marketing bool `json:"marketing"`
+}
+
+// QuietHours delay push notifications between two local hours, which may span midnight,
+// such as 22 to 7, until they end. In-app notifications aren't delayed.
+type QuietHours struct {
+	Enabled bool `json:"enabled"`
+	Start   int  `json:"start"` // 0-23
+	End     int  `json:"end"`   // 0-23
+}
+
+// DefaultNotificationPreferences returns the preferences of users who haven't set any:
+// everything but marketing, on every channel
+func DefaultNotificationPreferences() NotificationPreferences {
+	all := ChannelPreferences{Nudge: true, Reminder: true, Streak: true, Insight: true}
+	return NotificationPreferences{
+		Push:            all,
+		Email:           all,
+		InApp:           all,
+		DigestFrequency: DigestDaily,
+	}
+}
+
+// Channel returns the preferences of a channel, or nil for an unknown one
+func (p *NotificationPreferences) Channel(channel string) *ChannelPreferences {
+	switch channel {
+	case ChannelPush:
+		return &p.Push
+	case ChannelEmail:
+		return &p.Email
+	case ChannelInApp:
+		return &p.InApp
+	}
+	return nil
+}
+
+// Allows reports whether the user wants notifications of a type on a channel
+func (p *NotificationPreferences) Allows(channel, notificationType string) bool {
+	prefs := p.Channel(channel)
+	if prefs == nil {
+		return false
+	}
+	wanted := prefs.field(notificationType)
+	return wanted != nil && *wanted
+}
+
+// Set turns a notification type on or off for a channel
+func (p *NotificationPreferences) Set(channel, notificationType string, enabled bool) error {
+	prefs := p.Channel(channel)
+	if prefs == nil {
+		return fmt.Errorf("unknown notification channel %q", channel)
+	}
+	wanted := prefs.field(notificationType)
+	if wanted == nil {
+		return fmt.Errorf("unknown notification type %q", notificationType)
+	}
+	*wanted = enabled
+	return nil
+}
+
+// Validate checks the quiet hours and digest frequency
+func (p *NotificationPreferences) Validate() error {
+	if p.QuietHours.Enabled {
+		if p.QuietHours.Start < 0 || p.QuietHours.Start > 23 || p.QuietHours.End < 0 || p.QuietHours.End > 23 {
+			return errors.New("quiet hours must be between 0 and 23")
+		}
+		if p.QuietHours.Start == p.QuietHours.End {
+			return errors.New("quiet hours must start and end at different hours")
+		}
+	}
+	if p.DigestFrequency != DigestDaily && p.DigestFrequency != DigestWeekly {
+		return fmt.Errorf("digest frequency must be %s or %s", DigestDaily, DigestWeekly)
+	}
+	return nil
+}
+
+// Contains reports whether a local hour is inside the quiet hours
+func (q QuietHours) Contains(hour int) bool {
+	if !q.Enabled || q.Start == q.End {
+		return false
+	}
+	if q.Start < q.End {
+		return hour >= q.Start && hour < q.End
+	}
+	return hour >= q.Start || hour < q.End
+}
+
+// Until returns when the quiet hours around a time end, in the time's location, or false
+// when the time isn't inside them
+func (q QuietHours) Until(t time.Time) (time.Time, bool) {
+	if !q.Contains(t.Hour()) {
+		return time.Time{}, false
+	}
+	end := time.Date(t.Year(), t.Month(), t.Day(), q.End, 0, 0, 0, t.Location())
+	if !end.After(t) {
+		end = time.Date(t.Year(), t.Month(), t.Day()+1, q.End, 0, 0, 0, t.Location())
+	}
+	return end, true
+}
+
+func (c *ChannelPreferences) field(notificationType string) *bool {
+	switch notificationType {
+	case NotificationNudge:
+		return &c.Nudge
+	case NotificationReminder:
+		return &c.Reminder
+	case NotificationStreak:
+		return &c.Streak
+	case NotificationInsight:
+		return &c.Insight
+	case NotificationMarketing:
+		return &c.Marketing
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNotificationPreferencesDefaults(t *testing.T) {
+	prefs := DefaultNotificationPreferences()
+
+	for _, channel := range []string{ChannelPush, ChannelEmail, ChannelInApp} {
+		for _, notificationType := range []string{NotificationNudge, NotificationReminder, NotificationStreak, NotificationInsight} {
+			if !prefs.Allows(channel, notificationType) {
+				t.Errorf("defaults should allow %s on %s", notificationType, channel)
+			}
+		}
+		if prefs.Allows(channel, NotificationMarketing) {
+			t.Errorf("defaults should not allow marketing on %s", channel)
+		}
+	}
+	if err := prefs.Validate(); err != nil {
+		t.Errorf("defaults are invalid: %v", err)
+	}
+}
+
+func TestNotificationPreferencesSet(t *testing.T) {
+	prefs := DefaultNotificationPreferences()
+
+	if err := prefs.Set(ChannelPush, NotificationNudge, false); err != nil {
+		t.Fatal(err)
+	}
+	if prefs.Allows(ChannelPush, NotificationNudge) {
+		t.Error("nudges should be off on push")
+	}
+	if !prefs.Allows(ChannelInApp, NotificationNudge) {
+		t.Error("turning nudges off on push should leave in-app alone")
+	}
+
+	if err := prefs.Set("sms", NotificationNudge, true); err == nil {
+		t.Error("Set should reject an unknown channel")
+	}
+	if err := prefs.Set(ChannelPush, "gossip", true); err == nil {
+		t.Error("Set should reject an unknown type")
+	}
+	if prefs.Allows(ChannelPush, "gossip") || prefs.Allows("sms", NotificationNudge) {
+		t.Error("unknown channels and types should not be allowed")
+	}
+}
+
+func TestQuietHoursContains(t *testing.T) {
+	tests := []struct {
+		quiet QuietHours
+		hour  int
+		want  bool
+	}{
+		{QuietHours{Enabled: true, Start: 22, End: 7}, 23, true},
+		{QuietHours{Enabled: true, Start: 22, End: 7}, 3, true},
+		{QuietHours{Enabled: true, Start: 22, End: 7}, 7, false},
+		{QuietHours{Enabled: true, Start: 22, End: 7}, 12, false},
+		{QuietHours{Enabled: true, Start: 13, End: 15}, 14, true},
+		{QuietHours{Enabled: true, Start: 13, End: 15}, 15, false},
+		{QuietHours{Enabled: false, Start: 0, End: 23}, 12, false},
+	}
+	for _, tt := range tests {
+		if got := tt.quiet.Contains(tt.hour); got != tt.want {
+			t.Errorf("%+v.Contains(%d) = %v, want %v", tt.quiet, tt.hour, got, tt.want)
+		}
+	}
+}
+
+func TestQuietHoursUntil(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 10, day, hour, minute, 0, 0, loc)
+	}
+	overnight := QuietHours{Enabled: true, Start: 22, End: 7}
+
+	tests := []struct {
+		quiet  QuietHours
+		t      time.Time
+		want   time.Time
+		wantOK bool
+	}{
+		{overnight, at(14, 23, 30), at(15, 7, 0), true},
+		{overnight, at(15, 6, 59), at(15, 7, 0), true},
+		{overnight, at(15, 7, 0), time.Time{}, false},
+		{QuietHours{Enabled: true, Start: 13, End: 15}, at(14, 13, 0), at(14, 15, 0), true},
+		// Quiet hours across the end of daylight saving time still end at 7 local time
+		{overnight, at(24, 23, 0), at(25, 7, 0), true},
+	}
+	for _, tt := range tests {
+		got, ok := tt.quiet.Until(tt.t)
+		if ok != tt.wantOK || !got.Equal(tt.want) {
+			t.Errorf("%+v.Until(%s) = %s, %v, want %s, %v", tt.quiet, tt.t, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestNotificationPreferencesValidate(t *testing.T) {
+	invalid := []NotificationPreferences{
+		{DigestFrequency: "hourly"},
+		{DigestFrequency: DigestDaily, QuietHours: QuietHours{Enabled: true, Start: 22, End: 24}},
+		{DigestFrequency: DigestDaily, QuietHours: QuietHours{Enabled: true, Start: 8, End: 8}},
+	}
+	for _, prefs := range invalid {
+		if err := prefs.Validate(); err == nil {
+			t.Errorf("Validate(%+v) = nil, want an error", prefs)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vyve/vyve-backend/pkg/recurrence"
+)
+
+// Reminder schedule types
+const (
+	ScheduleDaily    = "daily"
+	ScheduleWeekly   = "weekly"
+	ScheduleMonthly  = "monthly"
+	ScheduleInterval = "interval"
+	ScheduleRRule    = "rrule"
+)
+
+// maxScheduleEveryDays bounds the gap of an interval schedule
+const maxScheduleEveryDays = 365
+
+// scheduleWeekdays maps the weekday names of weekly schedules to RRULE codes
+var scheduleWeekdays = map[string]string{
+	"mon": "MO",
+	"tue": "TU",
+	"wed": "WE",
+	"thu": "TH",
+	"fri": "FR",
+	"sat": "SA",
+	"sun": "SU",
+}
+
+// ReminderSchedule is when a person's reminders recur. Reminders fall on days in the
+// user's timezone, counted from StartDate.
+type ReminderSchedule struct {
+	Type       string   `json:"type"`                   // daily, weekly, monthly, interval, rrule
+	Weekdays   []string `json:"weekdays,omitempty"`     // weekly: mon..sun
+	DayOfMonth int      `json:"day_of_month,omitempty"` // monthly: 1-31, or -1 for the last day
+	EveryDays  int      `json:"every_days,omitempty"`   // interval: days between reminders
+	RRule      string   `json:"rrule,omitempty"`        // rrule: an RFC 5545 RRULE
+	StartDate  string   `json:"start_date"`             // YYYY-MM-DD
+}
+
+// Rule returns the recurrence rule of the schedule
+func (s *ReminderSchedule) Rule() (*recurrence.Rule, error) {
+	switch s.Type {
+	case ScheduleDaily:
+		return recurrence.Parse("FREQ=DAILY")
+	case ScheduleWeekly:
+		if len(s.Weekdays) == 0 {
+			return nil, errors.New("weekly schedules need at least one weekday")
+		}
+		codes := make([]string, len(s.Weekdays))
+		for i, day := range s.Weekdays {
+			code, ok := scheduleWeekdays[strings.ToLower(day)]
+			if !ok {
+				return nil, fmt.Errorf("unknown weekday %q", day)
+			}
+			codes[i] = code
+		}
+		return recurrence.Parse("FREQ=WEEKLY;BYDAY=" + strings.Join(codes, ","))
+	case ScheduleMonthly:
+		switch d := s.DayOfMonth; {
+		case d == -1:
+			return recurrence.Parse("FREQ=MONTHLY;BYMONTHDAY=-1")
+		case d >= 1 && d <= 28:
+			return recurrence.Parse(fmt.Sprintf("FREQ=MONTHLY;BYMONTHDAY=%d", d))
+		case d > 28 && d <= 31:
+			// Shorter months get their last day instead
+			days := make([]string, 0, d-27)
+			for day := 28; day <= d; day++ {
+				days = append(days, fmt.Sprint(day))
+			}
+			return recurrence.Parse("FREQ=MONTHLY;BYMONTHDAY=" + strings.Join(days, ",") + ";BYSETPOS=-1")
+		}
+		return nil, errors.New("day_of_month must be between 1 and 31, or -1")
+	case ScheduleInterval:
+		if s.EveryDays < 1 || s.EveryDays > maxScheduleEveryDays {
+			return nil, fmt.Errorf("every_days must be between 1 and %d", maxScheduleEveryDays)
+		}
+		return recurrence.Parse(fmt.Sprintf("FREQ=DAILY;INTERVAL=%d", s.EveryDays))
+	case ScheduleRRule:
+		return recurrence.Parse(s.RRule)
+	}
+	return nil, fmt.Errorf("unknown schedule type %q", s.Type)
+}
+
+// Validate checks the schedule is complete and well formed
+func (s *ReminderSchedule) Validate() error {
+	if _, err := s.Rule(); err != nil {
+		return err
+	}
+	if _, err := s.start(); err != nil {
+		return errors.New("start_date must be a date, YYYY-MM-DD")
+	}
+	return nil
+}
+
+// Next returns when the first reminder after the day of after is due: midnight of that
+// day in loc. It returns false when the schedule has ended.
+func (s *ReminderSchedule) Next(after time.Time, loc *time.Location) (time.Time, bool) {
+	rule, err := s.Rule()
+	if err != nil {
+		return time.Time{}, false
+	}
+	start, err := s.start()
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	day, ok := rule.Next(start, recurrence.Day(after.In(loc)))
+	if !ok {
+		return time.Time{}, false
+	}
+	// Building the time in loc keeps it at local midnight whatever the UTC offset that day
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc), true
+}
+
+// Frequency approximates the schedule by a reminder frequency, which nudges use to
+// judge how long is too long without contact
+func (s *ReminderSchedule) Frequency() string {
+	switch s.Type {
+	case ScheduleDaily:
+		return "daily"
+	case ScheduleWeekly:
+		return "weekly"
+	case ScheduleMonthly:
+		return "monthly"
+	case ScheduleInterval:
+		switch s.EveryDays {
+		case 1:
+			return "daily"
+		case 7:
+			return "weekly"
+		case 14:
+			return "biweekly"
+		case 30:
+			return "monthly"
+		case 90:
+			return "quarterly"
+		}
+	}
+	return "custom"
+}
+
+func (s *ReminderSchedule) start() (time.Time, error) {
+	return time.Parse("2006-01-02", s.StartDate)
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReminderScheduleNext(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("timezone database not available")
+	}
+
+	tests := []struct {
+		name     string
+		schedule ReminderSchedule
+		after    time.Time
+		want     time.Time
+	}{
+		{
+			name:     "daily",
+			schedule: ReminderSchedule{Type: ScheduleDaily, StartDate: "2024-01-01"},
+			after:    time.Date(2024, 3, 5, 23, 0, 0, 0, newYork),
+			want:     time.Date(2024, 3, 6, 0, 0, 0, 0, newYork),
+		},
+		{
+			// 2024-03-10 is when New York springs forward
+			name:     "weekly across DST",
+			schedule: ReminderSchedule{Type: ScheduleWeekly, Weekdays: []string{"mon"}, StartDate: "2024-01-01"},
+			after:    time.Date(2024, 3, 4, 12, 0, 0, 0, newYork),
+			want:     time.Date(2024, 3, 11, 0, 0, 0, 0, newYork),
+		},
+		{
+			name:     "monthly on the 31st",
+			schedule: ReminderSchedule{Type: ScheduleMonthly, DayOfMonth: 31, StartDate: "2024-01-01"},
+			after:    time.Date(2024, 1, 31, 9, 0, 0, 0, newYork),
+			want:     time.Date(2024, 2, 29, 0, 0, 0, 0, newYork),
+		},
+		{
+			name:     "every 10 days",
+			schedule: ReminderSchedule{Type: ScheduleInterval, EveryDays: 10, StartDate: "2024-10-30"},
+			after:    time.Date(2024, 10, 30, 9, 0, 0, 0, newYork),
+			want:     time.Date(2024, 11, 9, 0, 0, 0, 0, newYork),
+		},
+		{
+			name:     "rrule",
+			schedule: ReminderSchedule{Type: ScheduleRRule, RRule: "FREQ=MONTHLY;BYDAY=1SU", StartDate: "2024-01-01"},
+			after:    time.Date(2024, 10, 7, 9, 0, 0, 0, newYork),
+			want:     time.Date(2024, 11, 3, 0, 0, 0, 0, newYork),
+		},
+		{
+			// The UTC instant is already the next day, but the reminder follows the local day
+			name:     "local day",
+			schedule: ReminderSchedule{Type: ScheduleDaily, StartDate: "2024-01-01"},
+			after:    time.Date(2024, 6, 2, 2, 0, 0, 0, time.UTC),
+			want:     time.Date(2024, 6, 2, 0, 0, 0, 0, newYork),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.schedule.Next(tt.after, newYork)
+			if !ok || !got.Equal(tt.want) {
+				t.Errorf("Next() = %v, %v; want %v", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestReminderScheduleValidate(t *testing.T) {
+	invalid := []ReminderSchedule{
+		{Type: "hourly", StartDate: "2024-01-01"},
+		{Type: ScheduleDaily},
+		{Type: ScheduleWeekly, StartDate: "2024-01-01"},
+		{Type: ScheduleWeekly, Weekdays: []string{"someday"}, StartDate: "2024-01-01"},
+		{Type: ScheduleMonthly, DayOfMonth: 0, StartDate: "2024-01-01"},
+		{Type: ScheduleInterval, EveryDays: 0, StartDate: "2024-01-01"},
+		{Type: ScheduleRRule, RRule: "FREQ=HOURLY", StartDate: "2024-01-01"},
+	}
+	for _, schedule := range invalid {
+		if err := schedule.Validate(); err == nil {
+			t.Errorf("Validate(%+v) = nil, want an error", schedule)
+		}
+	}
+
+	valid := ReminderSchedule{Type: ScheduleWeekly, Weekdays: []string{"Mon", "fri"}, StartDate: "2024-01-01"}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate(%+v) = %v", valid, err)
+	}
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUserHasRole(t *testing.T) {
+	user := User{Roles: StringArray{RoleAdmin}}
+	if !user.HasRole(RoleAdmin) {
+		t.Errorf("expected user to have role %q", RoleAdmin)
+	}
+	if user.HasRole("support") {
+		t.Error("expected user not to have role \"support\"")
+	}
+	if (&User{}).HasRole(RoleAdmin) {
+		t.Error("expected user without roles not to have a role")
+	}
+}
+
+func TestIsValidRole(t *testing.T) {
+	if !IsValidRole(RoleAdmin) {
+		t.Errorf("expected %q to be a valid role", RoleAdmin)
+	}
+	if IsValidRole("") || IsValidRole("root") {
+		t.Error("expected unknown roles to be rejected")
+	}
+}
+
+func TestUserSuspended(t *testing.T) {
+	user := User{}
+	if user.Suspended() {
+		t.Error("expected user not to be suspended")
+	}
+	now := time.Now()
+	user.SuspendedAt = &now
+	if !user.Suspended() {
+		t.Error("expected user to be suspended")
+	}
+}
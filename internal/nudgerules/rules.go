@@ -0,0 +1,430 @@
+// Package nudgerules implements the small rule language users write to create their own nudges.
+//
+// A rule is a list of conditions joined by "and", followed by an arrow and a nudge action:
+//
+//	if category=family and days_since_last_interaction > 14 and last energy_impact != draining → reach_out, priority high
+//
+// The leading "if" is optional, the arrow may be written as "→", "->" or "then", and the
+// priority defaults to medium. String comparisons are case-insensitive.
+package nudgerules
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Field types
+const (
+	fieldString = iota
+	fieldNumber
+)
+
+// fields lists every fact a condition can reference and its type
+var fields = map[string]int{
+	"category":                    fieldString,
+	"relationship":                fieldString,
+	"reminder_frequency":          fieldString,
+	"last_energy_impact":          fieldString,
+	"days_since_last_interaction": fieldNumber,
+	"health_score":                fieldNumber,
+	"health_trend":                fieldNumber,
+	"interaction_count":           fieldNumber,
+	"energizing_count":            fieldNumber,
+	"draining_count":              fieldNumber,
+}
+
+// Actions are the nudge types a rule may produce
+var Actions = []string{"reach_out", "schedule_call", "set_boundary", "celebrate", "check_in"}
+
+// Priorities are the accepted nudge priorities
+var Priorities = []string{"high", "medium", "low"}
+
+// Facts are the per-person values a rule is evaluated against
+type Facts struct {
+	Category                 string  `json:"category"`
+	Relationship             string  `json:"relationship"`
+	ReminderFrequency        string  `json:"reminder_frequency"`
+	LastEnergyImpact         string  `json:"last_energy_impact"`
+	DaysSinceLastInteraction int     `json:"days_since_last_interaction"`
+	HealthScore              float64 `json:"health_score"`
+	HealthTrend              float64 `json:"health_trend"`
+	InteractionCount         int     `json:"interaction_count"`
+	EnergizingCount          int     `json:"energizing_count"`
+	DrainingCount            int     `json:"draining_count"`
+}
+
+// Condition is a single "field op value" comparison
+type Condition struct {
+	Field  string  `json:"field"`
+	Op     string  `json:"op"`
+	Text   string  `json:"text,omitempty"`
+	Number float64 `json:"number,omitempty"`
+}
+
+// Rule is a parsed rule expression
+type Rule struct {
+	Conditions []Condition `json:"conditions"`
+	Action     string      `json:"action"`
+	Priority   string      `json:"priority"`
+}
+
+// ParseError describes an invalid rule expression
+type ParseError struct {
+	Pos int
+	Msg string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("invalid rule at position %d: %s", e.Pos, e.Msg)
+}
+
+// Parse parses a rule expression
+func Parse(expr string) (*Rule, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens, end: len(expr)}
+	return p.parse()
+}
+
+// Match reports whether all conditions of the rule hold for the given facts
+func (r *Rule) Match(f Facts) bool {
+	for _, c := range r.Conditions {
+		if !c.match(f) {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the canonical form of the rule
+func (r *Rule) String() string {
+	parts := make([]string, 0, len(r.Conditions))
+	for _, c := range r.Conditions {
+		value := c.Text
+		if fields[c.Field] == fieldNumber {
+			value = strconv.FormatFloat(c.Number, 'f', -1, 64)
+		} else if strings.ContainsAny(value, " \t") {
+			value = strconv.Quote(value)
+		}
+		parts = append(parts, fmt.Sprintf("%s %s %s", c.Field, c.Op, value))
+	}
+	return fmt.Sprintf("if %s -> %s, priority %s", strings.Join(parts, " and "), r.Action, r.Priority)
+}
+
+func (c Condition) match(f Facts) bool {
+	if fields[c.Field] == fieldString {
+		var actual string
+		switch c.Field {
+		case "category":
+			actual = f.Category
+		case "relationship":
+			actual = f.Relationship
+		case "reminder_frequency":
+			actual = f.ReminderFrequency
+		case "last_energy_impact":
+			actual = f.LastEnergyImpact
+		}
+		equal := strings.EqualFold(actual, c.Text)
+		if c.Op == "!=" {
+			return !equal
+		}
+		return equal
+	}
+
+	var actual float64
+	switch c.Field {
+	case "days_since_last_interaction":
+		actual = float64(f.DaysSinceLastInteraction)
+	case "health_score":
+		actual = f.HealthScore
+	case "health_trend":
+		actual = f.HealthTrend
+	case "interaction_count":
+		actual = float64(f.InteractionCount)
+	case "energizing_count":
+		actual = float64(f.EnergizingCount)
+	case "draining_count":
+		actual = float64(f.DrainingCount)
+	}
+
+	switch c.Op {
+	case "=":
+		return actual == c.Number
+	case "!=":
+		return actual != c.Number
+	case ">":
+		return actual > c.Number
+	case ">=":
+		return actual >= c.Number
+	case "<":
+		return actual < c.Number
+	case "<=":
+		return actual <= c.Number
+	}
+	return false
+}
+
+// Tokenizer
+
+const (
+	tokWord = iota
+	tokNumber
+	tokString
+	tokOp
+	tokArrow
+	tokComma
+)
+
+type token struct {
+	kind int
+	text string
+	pos  int
+}
+
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	runes := []rune(expr)
+	// byte offsets keep error positions meaningful for non-ASCII input such as the arrow
+	offsets := make([]int, len(runes)+1)
+	off := 0
+	for i, r := range runes {
+		offsets[i] = off
+		off += len(string(r))
+	}
+	offsets[len(runes)] = off
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		pos := offsets[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '→':
+			tokens = append(tokens, token{tokArrow, "->", pos})
+			i++
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '>':
+			tokens = append(tokens, token{tokArrow, "->", pos})
+			i += 2
+		case r == ',':
+			tokens = append(tokens, token{tokComma, ",", pos})
+			i++
+		case r == '=' || r == '!' || r == '<' || r == '>':
+			op := string(r)
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				op += "="
+				i++
+			}
+			i++
+			if op == "==" {
+				op = "="
+			}
+			if op == "!" {
+				return nil, &ParseError{pos, "expected '!='"}
+			}
+			tokens = append(tokens, token{tokOp, op, pos})
+		case r == '"' || r == '\'':
+			j := i + 1
+			for j < len(runes) && runes[j] != r {
+				j++
+			}
+			if j >= len(runes) {
+				return nil, &ParseError{pos, "unterminated string"}
+			}
+			tokens = append(tokens, token{tokString, string(runes[i+1 : j]), pos})
+			i = j + 1
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			j := i + 1
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{tokNumber, string(runes[i:j]), pos})
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			j := i + 1
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_' ||
+				(runes[j] == '-' && !(j+1 < len(runes) && runes[j+1] == '>'))) {
+				j++
+			}
+			tokens = append(tokens, token{tokWord, strings.ToLower(string(runes[i:j])), pos})
+			i = j
+		default:
+			return nil, &ParseError{pos, fmt.Sprintf("unexpected character %q", r)}
+		}
+	}
+
+	return tokens, nil
+}
+
+// Parser
+
+type parser struct {
+	tokens []token
+	i      int
+	end    int
+}
+
+func (p *parser) peek() *token {
+	if p.i < len(p.tokens) {
+		return &p.tokens[p.i]
+	}
+	return nil
+}
+
+func (p *parser) next() *token {
+	t := p.peek()
+	if t != nil {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	pos := p.end
+	if t := p.peek(); t != nil {
+		pos = t.pos
+	}
+	return &ParseError{pos, fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) parse() (*Rule, error) {
+	if t := p.peek(); t != nil && t.kind == tokWord && t.text == "if" {
+		p.next()
+	}
+
+	rule := &Rule{Priority: "medium"}
+	for {
+		cond, err := p.parseCondition()
+		if err != nil {
+			return nil, err
+		}
+		rule.Conditions = append(rule.Conditions, cond)
+
+		t := p.peek()
+		if t != nil && t.kind == tokWord && t.text == "and" {
+			p.next()
+			continue
+		}
+		break
+	}
+
+	t := p.next()
+	if t == nil || !(t.kind == tokArrow || (t.kind == tokWord && t.text == "then")) {
+		if t != nil {
+			p.i--
+		}
+		return nil, p.errorf("expected '->' followed by an action")
+	}
+
+	t = p.next()
+	if t == nil || t.kind != tokWord {
+		return nil, p.errorf("expected an action (%s)", strings.Join(Actions, ", "))
+	}
+	if !contains(Actions, t.text) {
+		p.i--
+		return nil, p.errorf("unknown action %q (expected one of %s)", t.text, strings.Join(Actions, ", "))
+	}
+	rule.Action = t.text
+
+	if t := p.peek(); t != nil && t.kind == tokComma {
+		p.next()
+	}
+	if t := p.peek(); t != nil && t.kind == tokWord && t.text == "priority" {
+		p.next()
+		if t := p.peek(); t != nil && t.kind == tokOp && t.text == "=" {
+			p.next()
+		}
+		t := p.next()
+		if t == nil || t.kind != tokWord || !contains(Priorities, t.text) {
+			if t != nil {
+				p.i--
+			}
+			return nil, p.errorf("expected a priority (%s)", strings.Join(Priorities, ", "))
+		}
+		rule.Priority = t.text
+	}
+
+	if p.peek() != nil {
+		return nil, p.errorf("unexpected %q after action", p.peek().text)
+	}
+
+	return rule, nil
+}
+
+func (p *parser) parseCondition() (Condition, error) {
+	t := p.next()
+	if t == nil || t.kind != tokWord {
+		if t != nil {
+			p.i--
+		}
+		return Condition{}, p.errorf("expected a field name")
+	}
+
+	field := t.text
+	// "last energy_impact" is accepted as a more readable form of last_energy_impact
+	if field == "last" {
+		nt := p.next()
+		if nt == nil || nt.kind != tokWord {
+			p.i--
+			return Condition{}, p.errorf("expected a field name after 'last'")
+		}
+		field = "last_" + nt.text
+	}
+
+	kind, ok := fields[field]
+	if !ok {
+		p.i--
+		return Condition{}, p.errorf("unknown field %q", field)
+	}
+
+	op := p.next()
+	if op == nil || op.kind != tokOp {
+		if op != nil {
+			p.i--
+		}
+		return Condition{}, p.errorf("expected a comparison operator after %q", field)
+	}
+
+	value := p.next()
+	if value == nil || value.kind == tokArrow || value.kind == tokComma || value.kind == tokOp {
+		if value != nil {
+			p.i--
+		}
+		return Condition{}, p.errorf("expected a value after %q", op.text)
+	}
+
+	cond := Condition{Field: field, Op: op.text}
+	if kind == fieldNumber {
+		if value.kind != tokNumber {
+			p.i--
+			return Condition{}, p.errorf("%s expects a number", field)
+		}
+		n, err := strconv.ParseFloat(value.text, 64)
+		if err != nil {
+			p.i--
+			return Condition{}, p.errorf("invalid number %q", value.text)
+		}
+		cond.Number = n
+		return cond, nil
+	}
+
+	if op.text != "=" && op.text != "!=" {
+		p.i -= 2
+		return Condition{}, p.errorf("%s only supports = and !=", field)
+	}
+	cond.Text = value.text
+	return cond, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package nudgerules
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseExample(t *testing.T) {
+	rule, err := Parse("if category=family and days_since_last_interaction > 14 and last energy_impact != draining → reach_out, priority high")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if rule.Action != "reach_out" || rule.Priority != "high" {
+		t.Fatalf("got action %q priority %q", rule.Action, rule.Priority)
+	}
+	if len(rule.Conditions) != 3 {
+		t.Fatalf("expected 3 conditions, got %d", len(rule.Conditions))
+	}
+	if c := rule.Conditions[2]; c.Field != "last_energy_impact" || c.Op != "!=" || c.Text != "draining" {
+		t.Fatalf("unexpected third condition: %+v", c)
+	}
+
+	want := "if category = family and days_since_last_interaction > 14 and last_energy_impact != draining -> reach_out, priority high"
+	if got := rule.String(); got != want {
+		t.Fatalf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	rule, err := Parse("category = Family and days_since_last_interaction > 14 and last energy_impact != draining -> reach_out")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rule.Priority != "medium" {
+		t.Fatalf("expected default priority medium, got %q", rule.Priority)
+	}
+	if _, err := Parse("category=co-worker->check_in"); err != nil {
+		t.Fatalf("unexpected error for compact expression: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		facts Facts
+		want  bool
+	}{
+		{"matches", Facts{Category: "family", DaysSinceLastInteraction: 20, LastEnergyImpact: "neutral"}, true},
+		{"too recent", Facts{Category: "family", DaysSinceLastInteraction: 14, LastEnergyImpact: "neutral"}, false},
+		{"draining", Facts{Category: "family", DaysSinceLastInteraction: 20, LastEnergyImpact: "draining"}, false},
+		{"other category", Facts{Category: "work", DaysSinceLastInteraction: 20}, false},
+	}
+
+	for _, tt := range tests {
+		if got := rule.Match(tt.facts); got != tt.want {
+			t.Errorf("%s: Match() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"category = family",
+		"mood = happy -> reach_out",
+		"category > family -> reach_out",
+		"health_score > high -> celebrate",
+		"health_score > 80 -> party",
+		"health_score > 80 -> celebrate, priority urgent",
+		"health_score > 80 -> celebrate extra",
+		"category = \"family -> reach_out",
+	}
+
+	for _, expr := range tests {
+		_, err := Parse(expr)
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("Parse(%q) error = %v, want *ParseError", expr, err)
+		}
+	}
+}
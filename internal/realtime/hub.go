@@ -11,6 +11,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/websocket/v2"
 	"github.com/google/uuid"
+	"github.com/vyve/vyve-backend/internal/models"
 	"github.com/vyve/vyve-backend/pkg/cache"
 	"bufio"
 )
@@ -35,10 +36,18 @@ type Hub struct {
 	// Redis client for pub/sub
 	cache cache.Cache
 
+	// Notification preferences of users, consulted before sending them notifications
+	preferences PreferenceChecker
+
 	// Mutex for concurrent access
 	mu sync.RWMutex
 }
 
+// PreferenceChecker decides whether a user wants a type of notification on a channel
+type PreferenceChecker interface {
+	NotificationAllowed(ctx context.Context, userID uuid.UUID, channel, notificationType string) (bool, error)
+}
+
 // Client represents a connected user
 type Client struct {
 	ID     uuid.UUID
@@ -70,7 +79,7 @@ const (
 )
 
 // NewHub creates a new real-time hub
-func NewHub(cache cache.Cache) *Hub {
+func NewHub(cache cache.Cache, preferences PreferenceChecker) *Hub {
 	return &Hub{
 		clients:     make(map[*Client]bool),
 		userClients: make(map[uuid.UUID][]*Client),
@@ -78,6 +87,7 @@ func NewHub(cache cache.Cache) *Hub {
 		register:    make(chan *Client),
 		unregister:  make(chan *Client),
 		cache:       cache,
+		preferences: preferences,
 	}
 }
 
@@ -181,8 +191,20 @@ func (h *Hub) broadcastMessage(message Message) {
 	h.publishToRedis(message)
 }
 
-// SendToUser sends a message to a specific user
+// SendToUser sends a message to a specific user. Notifications the user has turned off
+// for in-app delivery are dropped.
 func (h *Hub) SendToUser(userID uuid.UUID, messageType string, data map[string]interface{}) {
+	if notificationType := notificationTypeOf(messageType, data); notificationType != "" && h.preferences != nil {
+		allowed, err := h.preferences.NotificationAllowed(context.Background(), userID, models.ChannelInApp, notificationType)
+		if err != nil {
+			log.Printf("Error checking notification preferences of user %s: %v", userID, err)
+			return
+		}
+		if !allowed {
+			return
+		}
+	}
+
 	message := Message{
 		Type:      messageType,
 		UserID:    userID,
@@ -192,7 +214,24 @@ func (h *Hub) SendToUser(userID uuid.UUID, messageType string, data map[string]i
 	h.broadcast <- message
 }
 
-// SendToAll sends a message to all connected clients
+// notificationTypeOf returns the notification type a message is, or "" for messages that
+// only keep the app's data up to date
+func notificationTypeOf(messageType string, data map[string]interface{}) string {
+	switch messageType {
+	case EventTypeNudge:
+		return models.NotificationNudge
+	case EventTypeStreakUpdate:
+		return models.NotificationStreak
+	case EventTypeNotification:
+		if notificationType, ok := data["type"].(string); ok && notificationType != "" {
+			return notificationType
+		}
+	}
+	return ""
+}
+
+// SendToAll sends a message to all connected clients. It skips notification
+// preferences, so notifications go through SendToUser instead.
 func (h *Hub) SendToAll(messageType string, data map[string]interface{}) {
 	message := Message{
 		Type:      messageType,
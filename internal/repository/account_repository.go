@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/vyve/vyve-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+//go:generate mockgen -source=account_repository.go -destination=mock_account_repository.go -package=repository
+
+// AccountRepository handles operations that span all of a user's data
+type AccountRepository interface {
+	// DeleteAllData permanently removes the user and every row they own in a single
+	// transaction and returns the number of rows deleted per table. It returns
+	// ErrLastAdmin rather than delete the last admin.
+	DeleteAllData(ctx context.Context, userID uuid.UUID) (map[string]int64, error)
+	// FindInBatches loads the user's rows of dest's model into dest, a pointer to a
+	// slice, batchSize rows at a time and calls fn after each batch
+	FindInBatches(ctx context.Context, userID uuid.UUID, dest interface{}, batchSize int, fn func() error) error
+}
+
+type accountRepository struct {
+	BaseRepository
+}
+
+// NewAccountRepository creates a new account repository
+func NewAccountRepository(db *gorm.DB) AccountRepository {
+	return &accountRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// userOwnedTables lists every table scoped by user_id, children before parents so
+// the deletes never depend on ON DELETE CASCADE
+var userOwnedTables = []struct {
+	name  string
+	model interface{}
+}{
+	{"ai_analysis_jobs", &models.AIAnalysisJob{}},
+	{"nudges", &models.Nudge{}},
+	{"notifications", &models.Notification{}},
+	{"relationship_analyses", &models.RelationshipAnalysis{}},
+	{"nudge_rules", &models.NudgeRule{}},
+	{"interactions", &models.Interaction{}},
+	{"people", &models.Person{}},
+	{"categories", &models.Category{}},
+	{"reflections", &models.Reflection{}},
+	{"events", &models.Event{}},
+	{"daily_metrics", &models.DailyMetric{}},
+	{"user_consents", &models.UserConsent{}},
+	{"data_exports", &models.DataExport{}},
+	{"import_jobs", &models.ImportJob{}},
+	{"audit_logs", &models.AuditLog{}},
+	{"refresh_tokens", &models.RefreshToken{}},
+	{"action_tokens", &models.ActionToken{}},
+	{"mfa_recovery_codes", &models.MFARecoveryCode{}},
+	{"passkeys", &models.Passkey{}},
+	{"push_tokens", &models.PushToken{}},
+	{"auth_providers", &models.AuthProvider{}},
+}
+
+// DeleteAllData permanently removes the user and all of their data
+func (r *accountRepository) DeleteAllData(ctx context.Context, userID uuid.UUID) (map[string]int64, error) {
+	deleted := make(map[string]int64, len(userOwnedTables)+1)
+
+	err := r.Transaction(ctx, func(tx *gorm.DB) error {
+		// The admins are locked as in RemoveRole, so concurrent erasures and
+		// demotions cannot remove every admin
+		if err := ensureNotLastAdmin(tx, userID); err != nil {
+			return err
+		}
+
+		for _, t := range userOwnedTables {
+			result := tx.Unscoped().Where("user_id = ?", userID).Delete(t.model)
+			if result.Error != nil {
+				return result.Error
+			}
+			deleted[t.name] = result.RowsAffected
+		}
+
+		result := tx.Unscoped().Delete(&models.User{}, "id = ?", userID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrUserNotFound
+		}
+		deleted["users"] = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deleted, nil
+}
+
+// FindInBatches walks the user's rows in primary key order
+func (r *accountRepository) FindInBatches(ctx context.Context, userID uuid.UUID, dest interface{}, batchSize int, fn func() error) error {
+	return r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("id").
+		FindInBatches(dest, batchSize, func(tx *gorm.DB, batch int) error {
+			return fn()
+		}).Error
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+)
+
+func TestDeleteAllData(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name    string
+		admins  []uuid.UUID
+		wantErr error
+	}{
+		{"last admin", []uuid.UUID{userID}, ErrLastAdmin},
+		{"another admin left", []uuid.UUID{userID, uuid.New()}, nil},
+		{"not an admin", []uuid.UUID{uuid.New()}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			repo := NewAccountRepository(db)
+
+			rows := sqlmock.NewRows([]string{"id"})
+			for _, id := range tt.admins {
+				rows.AddRow(id)
+			}
+			mock.ExpectBegin()
+			mock.ExpectQuery(`SELECT "id" FROM "users" WHERE .*ANY\(roles\).* FOR UPDATE`).
+				WillReturnRows(rows)
+			if tt.wantErr != nil {
+				mock.ExpectRollback()
+			} else {
+				for range userOwnedTables {
+					mock.ExpectExec(`DELETE FROM .* WHERE user_id = \$1`).
+						WithArgs(userID).
+						WillReturnResult(sqlmock.NewResult(0, 2))
+				}
+				mock.ExpectExec(`DELETE FROM "users" WHERE id = \$1`).
+					WithArgs(userID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			}
+
+			deleted, err := repo.DeleteAllData(context.Background(), userID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (deleted["users"] != 1 || deleted["people"] != 2) {
+				t.Errorf("deleted = %v", deleted)
+			}
+		})
+	}
+}
@@ -7,8 +7,11 @@ import (
 	"github.com/google/uuid"
 	"github.com/vyve/vyve-backend/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+//go:generate mockgen -source=analysis_repository.go -destination=mock_analysis_repository.go -package=repository
+
 // AnalysisRepository handles relationship analysis data access
 type AnalysisRepository interface {
 	// RelationshipAnalysis operations
@@ -32,6 +35,15 @@ type AnalysisRepository interface {
 	UpdateJob(ctx context.Context, job *models.AIAnalysisJob) error
 	ListPendingJobs(ctx context.Context, limit int) ([]*models.AIAnalysisJob, error)
 	GetUserJobs(ctx context.Context, userID uuid.UUID, limit int) ([]*models.AIAnalysisJob, error)
+
+	// Job queue operations
+	ClaimNextJob(ctx context.Context, workerID string) (*models.AIAnalysisJob, error)
+	HeartbeatJob(ctx context.Context, jobID uuid.UUID, workerID string) (bool, error)
+	SaveJobProgress(ctx context.Context, job *models.AIAnalysisJob, workerID string) (bool, error)
+	FinishJob(ctx context.Context, job *models.AIAnalysisJob, workerID string) (bool, error)
+	ReleaseJob(ctx context.Context, jobID uuid.UUID, workerID string) error
+	ReclaimStaleJobs(ctx context.Context, staleBefore time.Time) (int64, error)
+	CancelJob(ctx context.Context, jobID uuid.UUID) (bool, error)
 }
 
 type analysisRepository struct {
@@ -269,11 +281,11 @@ func (r *analysisRepository) UpdateJob(ctx context.Context, job *models.AIAnalys
 	return r.db.WithContext(ctx).Save(job).Error
 }
 
-// ListPendingJobs lists pending jobs ordered by priority
+// ListPendingJobs lists pending jobs that are due to run, ordered by priority
 func (r *analysisRepository) ListPendingJobs(ctx context.Context, limit int) ([]*models.AIAnalysisJob, error) {
 	var jobs []*models.AIAnalysisJob
 	err := r.db.WithContext(ctx).
-		Where("status = ?", "pending").
+		Where("status = ? AND (next_run_at IS NULL OR next_run_at <= ?)", "pending", time.Now()).
 		Order("priority DESC, created_at ASC").
 		Limit(limit).
 		Find(&jobs).Error
@@ -292,3 +304,161 @@ func (r *analysisRepository) GetUserJobs(ctx context.Context, userID uuid.UUID,
 	
 	return jobs, err
 }
+
+// ClaimNextJob atomically claims the highest priority due job for a worker.
+// Concurrent workers skip rows locked by each other, so a job is never claimed twice.
+// Returns ErrNotFound when the queue is empty.
+func (r *analysisRepository) ClaimNextJob(ctx context.Context, workerID string) (*models.AIAnalysisJob, error) {
+	var job models.AIAnalysisJob
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND (next_run_at IS NULL OR next_run_at <= ?)", "pending", now).
+			Order("priority DESC, created_at ASC").
+			First(&job).Error
+		if err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{
+			"status":       "processing",
+			"worker_id":    workerID,
+			"heartbeat_at": now,
+			"attempts":     gorm.Expr("attempts + 1"),
+		}
+		if job.StartedAt == nil {
+			updates["started_at"] = now
+		}
+		if err := tx.Model(&job).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		// Reload so the caller sees the incremented attempt counter
+		return tx.First(&job, "id = ?", job.ID).Error
+	})
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// HeartbeatJob refreshes the heartbeat of a job held by a worker.
+// It returns false when the worker no longer owns the job (cancelled or reclaimed).
+func (r *analysisRepository) HeartbeatJob(ctx context.Context, jobID uuid.UUID, workerID string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.AIAnalysisJob{}).
+		Where("id = ? AND worker_id = ? AND status = ?", jobID, workerID, "processing").
+		Update("heartbeat_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+// SaveJobProgress stores progress and per-item results of a job held by a worker.
+// It returns false when the worker no longer owns the job.
+func (r *analysisRepository) SaveJobProgress(ctx context.Context, job *models.AIAnalysisJob, workerID string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.AIAnalysisJob{}).
+		Where("id = ? AND worker_id = ? AND status = ?", job.ID, workerID, "processing").
+		Updates(map[string]interface{}{
+			"processed_items":   job.ProcessedItems,
+			"failed_items":      job.FailedItems,
+			"progress":          job.Progress,
+			"result_data":       job.ResultData,
+			"total_tokens_used": job.TotalTokensUsed,
+			"estimated_cost":    job.EstimatedCost,
+			"heartbeat_at":      time.Now(),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// FinishJob moves a job held by a worker out of processing, either to a final status
+// or back to pending for a retry. It returns false when the worker no longer owns the job.
+func (r *analysisRepository) FinishJob(ctx context.Context, job *models.AIAnalysisJob, workerID string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.AIAnalysisJob{}).
+		Where("id = ? AND worker_id = ? AND status = ?", job.ID, workerID, "processing").
+		Updates(map[string]interface{}{
+			"status":            job.Status,
+			"worker_id":         "",
+			"processed_items":   job.ProcessedItems,
+			"failed_items":      job.FailedItems,
+			"progress":          job.Progress,
+			"result_data":       job.ResultData,
+			"error":             job.Error,
+			"total_tokens_used": job.TotalTokensUsed,
+			"estimated_cost":    job.EstimatedCost,
+			"next_run_at":       job.NextRunAt,
+			"completed_at":      job.CompletedAt,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ReleaseJob hands a job back to the queue without counting the attempt, used on shutdown
+func (r *analysisRepository) ReleaseJob(ctx context.Context, jobID uuid.UUID, workerID string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.AIAnalysisJob{}).
+		Where("id = ? AND worker_id = ? AND status = ?", jobID, workerID, "processing").
+		Updates(map[string]interface{}{
+			"status":    "pending",
+			"worker_id": "",
+			"attempts":  gorm.Expr("GREATEST(attempts - 1, 0)"),
+		}).Error
+}
+
+// ReclaimStaleJobs returns processing jobs whose worker stopped heartbeating to the queue,
+// or fails them if they have used up their attempts. Jobs that were already processing
+// before heartbeats were introduced have none and are judged by their last update.
+func (r *analysisRepository) ReclaimStaleJobs(ctx context.Context, staleBefore time.Time) (int64, error) {
+	var reclaimed int64
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		failed := tx.Model(&models.AIAnalysisJob{}).
+			Where("status = ? AND COALESCE(heartbeat_at, updated_at) < ? AND attempts >= max_attempts", "processing", staleBefore).
+			Updates(map[string]interface{}{
+				"status":       "failed",
+				"worker_id":    "",
+				"error":        "worker stopped responding",
+				"completed_at": now,
+			})
+		if failed.Error != nil {
+			return failed.Error
+		}
+
+		requeued := tx.Model(&models.AIAnalysisJob{}).
+			Where("status = ? AND COALESCE(heartbeat_at, updated_at) < ?", "processing", staleBefore).
+			Updates(map[string]interface{}{
+				"status":      "pending",
+				"worker_id":   "",
+				"next_run_at": now,
+			})
+		if requeued.Error != nil {
+			return requeued.Error
+		}
+
+		reclaimed = failed.RowsAffected + requeued.RowsAffected
+		return nil
+	})
+
+	return reclaimed, err
+}
+
+// CancelJob cancels a pending or processing job. It returns false if the job had already finished.
+func (r *analysisRepository) CancelJob(ctx context.Context, jobID uuid.UUID) (bool, error) {
+	now := time.Now()
+	result := r.db.WithContext(ctx).
+		Model(&models.AIAnalysisJob{}).
+		Where("id = ? AND status IN (?, ?)", jobID, "pending", "processing").
+		Updates(map[string]interface{}{
+			"status":       "cancelled",
+			"worker_id":    "",
+			"cancelled_at": now,
+			"completed_at": now,
+		})
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestReclaimStaleJobs(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewAnalysisRepository(db)
+	staleBefore := time.Now().Add(-5 * time.Minute)
+
+	// Jobs claimed before heartbeats existed have none and fall back to updated_at
+	stale := `status = \$\d+ AND COALESCE\(heartbeat_at, updated_at\) < \$\d+`
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "ai_analysis_jobs" SET .* WHERE \(`+stale+` AND attempts >= max_attempts\)`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "processing", staleBefore).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE "ai_analysis_jobs" SET .* WHERE \(`+stale+`\)`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "processing", staleBefore).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	n, err := repo.ReclaimStaleJobs(context.Background(), staleBefore)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("reclaimed %d jobs, want 3", n)
+	}
+}
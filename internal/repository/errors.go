@@ -9,7 +9,19 @@ var (
 	ErrUserAlreadyExists = errors.New("user already exists")
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrEmailNotVerified   = errors.New("email not verified")
+	ErrEmailAlreadyVerified = errors.New("email already verified")
 	ErrLastAuthMethod     = errors.New("cannot remove last authentication method")
+	ErrAccountLocked      = errors.New("account is temporarily locked")
+	ErrAccountSuspended   = errors.New("account is suspended")
+	ErrProviderNotConfigured = errors.New("login provider is not configured")
+	ErrInvalidOAuthState  = errors.New("invalid or expired sign-in state")
+	ErrMFAAlreadyEnabled  = errors.New("two-factor authentication is already enabled")
+	ErrMFANotEnabled      = errors.New("two-factor authentication is not enabled")
+	ErrMFANotEnrolled     = errors.New("no authenticator enrollment to confirm")
+	ErrInvalidMFACode     = errors.New("invalid two-factor authentication code")
+	ErrReauthRequired     = errors.New("confirm with a two-factor code or your password")
+	ErrPasskeyNotFound    = errors.New("passkey not found")
+	ErrLastAdmin          = errors.New("cannot revoke the role of the last admin")
 	
 	// Person errors
 	ErrPersonNotFound = errors.New("person not found")
@@ -25,12 +37,20 @@ var (
 	// Nudge errors
 	ErrNudgeNotFound = errors.New("nudge not found")
 	ErrNudgeExpired  = errors.New("nudge has expired")
+	ErrNudgeRuleNotFound = errors.New("nudge rule not found")
+	
+	// Analysis job errors
+	ErrJobNotCancellable = errors.New("job has already finished")
 	
 	// Token errors
 	ErrTokenNotFound = errors.New("token not found")
 	ErrTokenExpired  = errors.New("token has expired")
 	ErrTokenRevoked  = errors.New("token has been revoked")
 	ErrTokenInvalid  = errors.New("token is invalid")
+	ErrTokenReused   = errors.New("token has already been used")
+	
+	// Session errors
+	ErrSessionNotFound = errors.New("session not found")
 	
 	// Consent errors
 	ErrConsentNotFound = errors.New("consent not found")
@@ -40,6 +60,14 @@ var (
 	ErrExportNotFound  = errors.New("export not found")
 	ErrExportPending   = errors.New("export already pending")
 	ErrExportExpired   = errors.New("export has expired")
+	ErrExportNotReady  = errors.New("export is not ready")
+	
+	// Import errors
+	ErrImportNotFound   = errors.New("import not found")
+	ErrImportNotDryRun  = errors.New("import is not a finished dry run")
+	
+	// Notification errors
+	ErrNotificationNotFound = errors.New("notification not found")
 	
 	// General errors
 	ErrNotFound         = errors.New("record not found")
@@ -47,6 +75,7 @@ var (
 	ErrInvalidInput     = errors.New("invalid input")
 	ErrUnauthorized     = errors.New("unauthorized")
 	ErrForbidden        = errors.New("forbidden")
+	ErrTooManyRequests  = errors.New("too many requests")
 	ErrDatabaseError    = errors.New("database error")
 	ErrTransactionError = errors.New("transaction error")
 )
@@ -55,13 +84,18 @@ var (
 func IsNotFound(err error) bool {
 	return errors.Is(err, ErrNotFound) ||
 		errors.Is(err, ErrUserNotFound) ||
+		errors.Is(err, ErrPasskeyNotFound) ||
 		errors.Is(err, ErrPersonNotFound) ||
 		errors.Is(err, ErrInteractionNotFound) ||
 		errors.Is(err, ErrReflectionNotFound) ||
 		errors.Is(err, ErrNudgeNotFound) ||
+		errors.Is(err, ErrNudgeRuleNotFound) ||
 		errors.Is(err, ErrTokenNotFound) ||
+		errors.Is(err, ErrSessionNotFound) ||
 		errors.Is(err, ErrConsentNotFound) ||
-		errors.Is(err, ErrExportNotFound)
+		errors.Is(err, ErrExportNotFound) ||
+		errors.Is(err, ErrImportNotFound) ||
+		errors.Is(err, ErrNotificationNotFound)
 }
 
 // IsAlreadyExists checks if error is an already exists error
@@ -79,12 +113,15 @@ func IsUnauthorized(err error) bool {
 		errors.Is(err, ErrInvalidCredentials) ||
 		errors.Is(err, ErrTokenInvalid) ||
 		errors.Is(err, ErrTokenExpired) ||
-		errors.Is(err, ErrTokenRevoked)
+		errors.Is(err, ErrTokenRevoked) ||
+		errors.Is(err, ErrTokenReused) ||
+		errors.Is(err, ErrInvalidMFACode)
 }
 
 // IsForbidden checks if error is a forbidden error
 func IsForbidden(err error) bool {
 	return errors.Is(err, ErrForbidden) ||
 		errors.Is(err, ErrEmailNotVerified) ||
+		errors.Is(err, ErrAccountSuspended) ||
 		errors.Is(err, ErrConsentRequired)
 }
\ No newline at end of file
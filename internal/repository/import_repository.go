@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vyve/vyve-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ImportRepository handles import job data access and the import job queue
+type ImportRepository interface {
+	Create(ctx context.Context, job *models.ImportJob) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.ImportJob, error)
+	ListByUser(ctx context.Context, userID uuid.UUID, limit int) ([]*models.ImportJob, error)
+
+	// Queue operations
+	ClaimNext(ctx context.Context, workerID string) (*models.ImportJob, error)
+	SaveProgress(ctx context.Context, job *models.ImportJob, workerID string) (bool, error)
+	Finish(ctx context.Context, job *models.ImportJob, workerID string) (bool, error)
+	Release(ctx context.Context, jobID uuid.UUID, workerID string) error
+	ReclaimStale(ctx context.Context, staleBefore time.Time) (int64, error)
+	QueueDryRun(ctx context.Context, jobID uuid.UUID) (bool, error)
+}
+
+type importRepository struct {
+	BaseRepository
+}
+
+// NewImportRepository creates a new import repository
+func NewImportRepository(db *gorm.DB) ImportRepository {
+	return &importRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Create creates a new import job
+func (r *importRepository) Create(ctx context.Context, job *models.ImportJob) error {
+	return r.db.WithContext(ctx).Create(job).Error
+}
+
+// FindByID finds an import job by ID
+func (r *importRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.ImportJob, error) {
+	var job models.ImportJob
+	err := r.db.WithContext(ctx).First(&job, "id = ?", id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrImportNotFound
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// ListByUser lists the most recent import jobs of a user
+func (r *importRepository) ListByUser(ctx context.Context, userID uuid.UUID, limit int) ([]*models.ImportJob, error) {
+	var jobs []*models.ImportJob
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&jobs).Error
+	return jobs, err
+}
+
+// ClaimNext atomically claims the oldest pending import for a worker.
+// Returns ErrNotFound when the queue is empty.
+func (r *importRepository) ClaimNext(ctx context.Context, workerID string) (*models.ImportJob, error) {
+	var job models.ImportJob
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ?", "pending").
+			Order("created_at ASC").
+			First(&job).Error
+		if err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{
+			"status":       "processing",
+			"worker_id":    workerID,
+			"heartbeat_at": now,
+			"attempts":     gorm.Expr("attempts + 1"),
+		}
+		if job.StartedAt == nil {
+			updates["started_at"] = now
+		}
+		if err := tx.Model(&job).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		return tx.First(&job, "id = ?", job.ID).Error
+	})
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// SaveProgress stores the counters of an import held by a worker and refreshes its
+// heartbeat. It returns false when the worker no longer owns the job.
+func (r *importRepository) SaveProgress(ctx context.Context, job *models.ImportJob, workerID string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.ImportJob{}).
+		Where("id = ? AND worker_id = ? AND status = ?", job.ID, workerID, "processing").
+		Updates(map[string]interface{}{
+			"total_items":     job.TotalItems,
+			"processed_items": job.ProcessedItems,
+			"created_items":   job.CreatedItems,
+			"skipped_items":   job.SkippedItems,
+			"failed_items":    job.FailedItems,
+			"progress":        job.Progress,
+			"heartbeat_at":    time.Now(),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Finish records the final state of an import held by a worker. It returns false when
+// the worker no longer owns the job.
+func (r *importRepository) Finish(ctx context.Context, job *models.ImportJob, workerID string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.ImportJob{}).
+		Where("id = ? AND worker_id = ? AND status = ?", job.ID, workerID, "processing").
+		Updates(map[string]interface{}{
+			"status":          job.Status,
+			"worker_id":       "",
+			"file_key":        job.FileKey,
+			"total_items":     job.TotalItems,
+			"processed_items": job.ProcessedItems,
+			"created_items":   job.CreatedItems,
+			"skipped_items":   job.SkippedItems,
+			"failed_items":    job.FailedItems,
+			"progress":        job.Progress,
+			"result_data":     job.ResultData,
+			"error":           job.Error,
+			"completed_at":    job.CompletedAt,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Release hands an import back to the queue without counting the attempt, used on shutdown
+func (r *importRepository) Release(ctx context.Context, jobID uuid.UUID, workerID string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.ImportJob{}).
+		Where("id = ? AND worker_id = ? AND status = ?", jobID, workerID, "processing").
+		Updates(map[string]interface{}{
+			"status":    "pending",
+			"worker_id": "",
+			"attempts":  gorm.Expr("GREATEST(attempts - 1, 0)"),
+		}).Error
+}
+
+// ReclaimStale returns imports whose worker stopped heartbeating to the queue, or fails
+// them if they have used up their attempts
+func (r *importRepository) ReclaimStale(ctx context.Context, staleBefore time.Time) (int64, error) {
+	var reclaimed int64
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		failed := tx.Model(&models.ImportJob{}).
+			Where("status = ? AND heartbeat_at < ? AND attempts >= max_attempts", "processing", staleBefore).
+			Updates(map[string]interface{}{
+				"status":       "failed",
+				"worker_id":    "",
+				"error":        "import stopped responding",
+				"completed_at": time.Now(),
+			})
+		if failed.Error != nil {
+			return failed.Error
+		}
+
+		requeued := tx.Model(&models.ImportJob{}).
+			Where("status = ? AND heartbeat_at < ?", "processing", staleBefore).
+			Updates(map[string]interface{}{
+				"status":    "pending",
+				"worker_id": "",
+			})
+		if requeued.Error != nil {
+			return requeued.Error
+		}
+
+		reclaimed = failed.RowsAffected + requeued.RowsAffected
+		return nil
+	})
+
+	return reclaimed, err
+}
+
+// QueueDryRun turns a completed dry run into a real import and puts it back in the
+// queue. It returns false when the job is not a completed dry run.
+func (r *importRepository) QueueDryRun(ctx context.Context, jobID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.ImportJob{}).
+		Where("id = ? AND dry_run = ? AND status = ?", jobID, true, "completed").
+		Updates(map[string]interface{}{
+			"dry_run":         false,
+			"status":          "pending",
+			"attempts":        0,
+			"processed_items": 0,
+			"created_items":   0,
+			"skipped_items":   0,
+			"failed_items":    0,
+			"progress":        0,
+			"error":           "",
+			"started_at":      nil,
+			"completed_at":    nil,
+		})
+	return result.RowsAffected > 0, result.Error
+}
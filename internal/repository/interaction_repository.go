@@ -25,6 +25,7 @@ type InteractionRepository interface {
 	GetDailyCount(ctx context.Context, userID uuid.UUID, date time.Time) (int64, error)
 	GetAverageQuality(ctx context.Context, userID uuid.UUID) (float64, error)
 	BulkCreate(ctx context.Context, interactions []*models.Interaction) error
+	ExistsAt(ctx context.Context, personID uuid.UUID, at time.Time) (bool, error)
 }
 
 type interactionRepository struct {
@@ -127,6 +128,16 @@ func (r *interactionRepository) GetByPerson(ctx context.Context, personID uuid.U
 	return interactions, err
 }
 
+// ExistsAt reports whether the person already has an interaction at the given time
+func (r *interactionRepository) ExistsAt(ctx context.Context, personID uuid.UUID, at time.Time) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Interaction{}).
+		Where("person_id = ? AND interaction_at = ?", personID, at).
+		Count(&count).Error
+	return count > 0, err
+}
+
 // GetByDateRange gets interactions within a date range
 func (r *interactionRepository) GetByDateRange(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) ([]*models.Interaction, error) {
 	var interactions []*models.Interaction
@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/account_repository.go
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockAccountRepository is a mock of AccountRepository interface.
+type MockAccountRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountRepositoryMockRecorder
+}
+
+// MockAccountRepositoryMockRecorder is the mock recorder for MockAccountRepository.
+type MockAccountRepositoryMockRecorder struct {
+	mock *MockAccountRepository
+}
+
+// NewMockAccountRepository creates a new mock instance.
+func NewMockAccountRepository(ctrl *gomock.Controller) *MockAccountRepository {
+	mock := &MockAccountRepository{ctrl: ctrl}
+	mock.recorder = &MockAccountRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountRepository) EXPECT() *MockAccountRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteAllData mocks base method.
+func (m *MockAccountRepository) DeleteAllData(ctx context.Context, userID uuid.UUID) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAllData", ctx, userID)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAllData indicates an expected call of DeleteAllData.
+func (mr *MockAccountRepositoryMockRecorder) DeleteAllData(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAllData", reflect.TypeOf((*MockAccountRepository)(nil).DeleteAllData), ctx, userID)
+}
+
+// FindInBatches mocks base method.
+func (m *MockAccountRepository) FindInBatches(ctx context.Context, userID uuid.UUID, dest interface{}, batchSize int, fn func() error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindInBatches", ctx, userID, dest, batchSize, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FindInBatches indicates an expected call of FindInBatches.
+func (mr *MockAccountRepositoryMockRecorder) FindInBatches(ctx, userID, dest, batchSize, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindInBatches", reflect.TypeOf((*MockAccountRepository)(nil).FindInBatches), ctx, userID, dest, batchSize, fn)
+}
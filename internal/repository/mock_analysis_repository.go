@@ -0,0 +1,379 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/analysis_repository.go
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	models "github.com/vyve/vyve-backend/internal/models"
+)
+
+// MockAnalysisRepository is a mock of AnalysisRepository interface.
+type MockAnalysisRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAnalysisRepositoryMockRecorder
+}
+
+// MockAnalysisRepositoryMockRecorder is the mock recorder for MockAnalysisRepository.
+type MockAnalysisRepositoryMockRecorder struct {
+	mock *MockAnalysisRepository
+}
+
+// NewMockAnalysisRepository creates a new mock instance.
+func NewMockAnalysisRepository(ctrl *gomock.Controller) *MockAnalysisRepository {
+	mock := &MockAnalysisRepository{ctrl: ctrl}
+	mock.recorder = &MockAnalysisRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAnalysisRepository) EXPECT() *MockAnalysisRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CancelJob mocks base method.
+func (m *MockAnalysisRepository) CancelJob(ctx context.Context, jobID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelJob", ctx, jobID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelJob indicates an expected call of CancelJob.
+func (mr *MockAnalysisRepositoryMockRecorder) CancelJob(ctx, jobID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelJob", reflect.TypeOf((*MockAnalysisRepository)(nil).CancelJob), ctx, jobID)
+}
+
+// ClaimNextJob mocks base method.
+func (m *MockAnalysisRepository) ClaimNextJob(ctx context.Context, workerID string) (*models.AIAnalysisJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimNextJob", ctx, workerID)
+	ret0, _ := ret[0].(*models.AIAnalysisJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimNextJob indicates an expected call of ClaimNextJob.
+func (mr *MockAnalysisRepositoryMockRecorder) ClaimNextJob(ctx, workerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimNextJob", reflect.TypeOf((*MockAnalysisRepository)(nil).ClaimNextJob), ctx, workerID)
+}
+
+// CreateAnalysis mocks base method.
+func (m *MockAnalysisRepository) CreateAnalysis(ctx context.Context, analysis *models.RelationshipAnalysis) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAnalysis", ctx, analysis)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAnalysis indicates an expected call of CreateAnalysis.
+func (mr *MockAnalysisRepositoryMockRecorder) CreateAnalysis(ctx, analysis interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAnalysis", reflect.TypeOf((*MockAnalysisRepository)(nil).CreateAnalysis), ctx, analysis)
+}
+
+// CreateJob mocks base method.
+func (m *MockAnalysisRepository) CreateJob(ctx context.Context, job *models.AIAnalysisJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateJob", ctx, job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateJob indicates an expected call of CreateJob.
+func (mr *MockAnalysisRepositoryMockRecorder) CreateJob(ctx, job interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateJob", reflect.TypeOf((*MockAnalysisRepository)(nil).CreateJob), ctx, job)
+}
+
+// CreateRecommendation mocks base method.
+func (m *MockAnalysisRepository) CreateRecommendation(ctx context.Context, recommendation *models.Nudge) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRecommendation", ctx, recommendation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRecommendation indicates an expected call of CreateRecommendation.
+func (mr *MockAnalysisRepositoryMockRecorder) CreateRecommendation(ctx, recommendation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRecommendation", reflect.TypeOf((*MockAnalysisRepository)(nil).CreateRecommendation), ctx, recommendation)
+}
+
+// FinishJob mocks base method.
+func (m *MockAnalysisRepository) FinishJob(ctx context.Context, job *models.AIAnalysisJob, workerID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishJob", ctx, job, workerID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishJob indicates an expected call of FinishJob.
+func (mr *MockAnalysisRepositoryMockRecorder) FinishJob(ctx, job, workerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishJob", reflect.TypeOf((*MockAnalysisRepository)(nil).FinishJob), ctx, job, workerID)
+}
+
+// GetActiveRecommendations mocks base method.
+func (m *MockAnalysisRepository) GetActiveRecommendations(ctx context.Context, userID uuid.UUID) ([]*models.Nudge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveRecommendations", ctx, userID)
+	ret0, _ := ret[0].([]*models.Nudge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveRecommendations indicates an expected call of GetActiveRecommendations.
+func (mr *MockAnalysisRepositoryMockRecorder) GetActiveRecommendations(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveRecommendations", reflect.TypeOf((*MockAnalysisRepository)(nil).GetActiveRecommendations), ctx, userID)
+}
+
+// GetAnalysisByID mocks base method.
+func (m *MockAnalysisRepository) GetAnalysisByID(ctx context.Context, analysisID uuid.UUID) (*models.RelationshipAnalysis, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAnalysisByID", ctx, analysisID)
+	ret0, _ := ret[0].(*models.RelationshipAnalysis)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAnalysisByID indicates an expected call of GetAnalysisByID.
+func (mr *MockAnalysisRepositoryMockRecorder) GetAnalysisByID(ctx, analysisID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAnalysisByID", reflect.TypeOf((*MockAnalysisRepository)(nil).GetAnalysisByID), ctx, analysisID)
+}
+
+// GetAnalysisHistory mocks base method.
+func (m *MockAnalysisRepository) GetAnalysisHistory(ctx context.Context, userID, personID uuid.UUID, limit int) ([]*models.RelationshipAnalysis, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAnalysisHistory", ctx, userID, personID, limit)
+	ret0, _ := ret[0].([]*models.RelationshipAnalysis)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAnalysisHistory indicates an expected call of GetAnalysisHistory.
+func (mr *MockAnalysisRepositoryMockRecorder) GetAnalysisHistory(ctx, userID, personID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAnalysisHistory", reflect.TypeOf((*MockAnalysisRepository)(nil).GetAnalysisHistory), ctx, userID, personID, limit)
+}
+
+// GetJobByID mocks base method.
+func (m *MockAnalysisRepository) GetJobByID(ctx context.Context, jobID uuid.UUID) (*models.AIAnalysisJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJobByID", ctx, jobID)
+	ret0, _ := ret[0].(*models.AIAnalysisJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJobByID indicates an expected call of GetJobByID.
+func (mr *MockAnalysisRepositoryMockRecorder) GetJobByID(ctx, jobID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobByID", reflect.TypeOf((*MockAnalysisRepository)(nil).GetJobByID), ctx, jobID)
+}
+
+// GetLatestAnalysis mocks base method.
+func (m *MockAnalysisRepository) GetLatestAnalysis(ctx context.Context, userID, personID uuid.UUID) (*models.RelationshipAnalysis, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestAnalysis", ctx, userID, personID)
+	ret0, _ := ret[0].(*models.RelationshipAnalysis)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestAnalysis indicates an expected call of GetLatestAnalysis.
+func (mr *MockAnalysisRepositoryMockRecorder) GetLatestAnalysis(ctx, userID, personID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestAnalysis", reflect.TypeOf((*MockAnalysisRepository)(nil).GetLatestAnalysis), ctx, userID, personID)
+}
+
+// GetRecommendationByID mocks base method.
+func (m *MockAnalysisRepository) GetRecommendationByID(ctx context.Context, recommendationID uuid.UUID) (*models.Nudge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecommendationByID", ctx, recommendationID)
+	ret0, _ := ret[0].(*models.Nudge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecommendationByID indicates an expected call of GetRecommendationByID.
+func (mr *MockAnalysisRepositoryMockRecorder) GetRecommendationByID(ctx, recommendationID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecommendationByID", reflect.TypeOf((*MockAnalysisRepository)(nil).GetRecommendationByID), ctx, recommendationID)
+}
+
+// GetRecommendationsForPerson mocks base method.
+func (m *MockAnalysisRepository) GetRecommendationsForPerson(ctx context.Context, userID, personID uuid.UUID) ([]*models.Nudge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecommendationsForPerson", ctx, userID, personID)
+	ret0, _ := ret[0].([]*models.Nudge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecommendationsForPerson indicates an expected call of GetRecommendationsForPerson.
+func (mr *MockAnalysisRepositoryMockRecorder) GetRecommendationsForPerson(ctx, userID, personID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecommendationsForPerson", reflect.TypeOf((*MockAnalysisRepository)(nil).GetRecommendationsForPerson), ctx, userID, personID)
+}
+
+// GetUserJobs mocks base method.
+func (m *MockAnalysisRepository) GetUserJobs(ctx context.Context, userID uuid.UUID, limit int) ([]*models.AIAnalysisJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserJobs", ctx, userID, limit)
+	ret0, _ := ret[0].([]*models.AIAnalysisJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserJobs indicates an expected call of GetUserJobs.
+func (mr *MockAnalysisRepositoryMockRecorder) GetUserJobs(ctx, userID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserJobs", reflect.TypeOf((*MockAnalysisRepository)(nil).GetUserJobs), ctx, userID, limit)
+}
+
+// HeartbeatJob mocks base method.
+func (m *MockAnalysisRepository) HeartbeatJob(ctx context.Context, jobID uuid.UUID, workerID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HeartbeatJob", ctx, jobID, workerID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HeartbeatJob indicates an expected call of HeartbeatJob.
+func (mr *MockAnalysisRepositoryMockRecorder) HeartbeatJob(ctx, jobID, workerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HeartbeatJob", reflect.TypeOf((*MockAnalysisRepository)(nil).HeartbeatJob), ctx, jobID, workerID)
+}
+
+// ListAnalyses mocks base method.
+func (m *MockAnalysisRepository) ListAnalyses(ctx context.Context, userID uuid.UUID, opts FilterOptions) ([]*models.RelationshipAnalysis, *PaginationResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAnalyses", ctx, userID, opts)
+	ret0, _ := ret[0].([]*models.RelationshipAnalysis)
+	ret1, _ := ret[1].(*PaginationResult)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListAnalyses indicates an expected call of ListAnalyses.
+func (mr *MockAnalysisRepositoryMockRecorder) ListAnalyses(ctx, userID, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAnalyses", reflect.TypeOf((*MockAnalysisRepository)(nil).ListAnalyses), ctx, userID, opts)
+}
+
+// ListPendingJobs mocks base method.
+func (m *MockAnalysisRepository) ListPendingJobs(ctx context.Context, limit int) ([]*models.AIAnalysisJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingJobs", ctx, limit)
+	ret0, _ := ret[0].([]*models.AIAnalysisJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingJobs indicates an expected call of ListPendingJobs.
+func (mr *MockAnalysisRepositoryMockRecorder) ListPendingJobs(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingJobs", reflect.TypeOf((*MockAnalysisRepository)(nil).ListPendingJobs), ctx, limit)
+}
+
+// ListRecommendations mocks base method.
+func (m *MockAnalysisRepository) ListRecommendations(ctx context.Context, userID uuid.UUID, opts FilterOptions) ([]*models.Nudge, *PaginationResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRecommendations", ctx, userID, opts)
+	ret0, _ := ret[0].([]*models.Nudge)
+	ret1, _ := ret[1].(*PaginationResult)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListRecommendations indicates an expected call of ListRecommendations.
+func (mr *MockAnalysisRepositoryMockRecorder) ListRecommendations(ctx, userID, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRecommendations", reflect.TypeOf((*MockAnalysisRepository)(nil).ListRecommendations), ctx, userID, opts)
+}
+
+// ReclaimStaleJobs mocks base method.
+func (m *MockAnalysisRepository) ReclaimStaleJobs(ctx context.Context, staleBefore time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReclaimStaleJobs", ctx, staleBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReclaimStaleJobs indicates an expected call of ReclaimStaleJobs.
+func (mr *MockAnalysisRepositoryMockRecorder) ReclaimStaleJobs(ctx, staleBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReclaimStaleJobs", reflect.TypeOf((*MockAnalysisRepository)(nil).ReclaimStaleJobs), ctx, staleBefore)
+}
+
+// ReleaseJob mocks base method.
+func (m *MockAnalysisRepository) ReleaseJob(ctx context.Context, jobID uuid.UUID, workerID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseJob", ctx, jobID, workerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseJob indicates an expected call of ReleaseJob.
+func (mr *MockAnalysisRepositoryMockRecorder) ReleaseJob(ctx, jobID, workerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseJob", reflect.TypeOf((*MockAnalysisRepository)(nil).ReleaseJob), ctx, jobID, workerID)
+}
+
+// SaveJobProgress mocks base method.
+func (m *MockAnalysisRepository) SaveJobProgress(ctx context.Context, job *models.AIAnalysisJob, workerID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveJobProgress", ctx, job, workerID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveJobProgress indicates an expected call of SaveJobProgress.
+func (mr *MockAnalysisRepositoryMockRecorder) SaveJobProgress(ctx, job, workerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveJobProgress", reflect.TypeOf((*MockAnalysisRepository)(nil).SaveJobProgress), ctx, job, workerID)
+}
+
+// UpdateJob mocks base method.
+func (m *MockAnalysisRepository) UpdateJob(ctx context.Context, job *models.AIAnalysisJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateJob", ctx, job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateJob indicates an expected call of UpdateJob.
+func (mr *MockAnalysisRepositoryMockRecorder) UpdateJob(ctx, job interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateJob", reflect.TypeOf((*MockAnalysisRepository)(nil).UpdateJob), ctx, job)
+}
+
+// UpdateRecommendationStatus mocks base method.
+func (m *MockAnalysisRepository) UpdateRecommendationStatus(ctx context.Context, recommendationID uuid.UUID, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRecommendationStatus", ctx, recommendationID, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRecommendationStatus indicates an expected call of UpdateRecommendationStatus.
+func (mr *MockAnalysisRepositoryMockRecorder) UpdateRecommendationStatus(ctx, recommendationID, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRecommendationStatus", reflect.TypeOf((*MockAnalysisRepository)(nil).UpdateRecommendationStatus), ctx, recommendationID, status)
+}
@@ -0,0 +1,153 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/nudge_rule_repository.go
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	models "github.com/vyve/vyve-backend/internal/models"
+)
+
+// MockNudgeRuleRepository is a mock of NudgeRuleRepository interface.
+type MockNudgeRuleRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockNudgeRuleRepositoryMockRecorder
+}
+
+// MockNudgeRuleRepositoryMockRecorder is the mock recorder for MockNudgeRuleRepository.
+type MockNudgeRuleRepositoryMockRecorder struct {
+	mock *MockNudgeRuleRepository
+}
+
+// NewMockNudgeRuleRepository creates a new mock instance.
+func NewMockNudgeRuleRepository(ctrl *gomock.Controller) *MockNudgeRuleRepository {
+	mock := &MockNudgeRuleRepository{ctrl: ctrl}
+	mock.recorder = &MockNudgeRuleRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNudgeRuleRepository) EXPECT() *MockNudgeRuleRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockNudgeRuleRepository) Create(ctx context.Context, rule *models.NudgeRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockNudgeRuleRepositoryMockRecorder) Create(ctx, rule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockNudgeRuleRepository)(nil).Create), ctx, rule)
+}
+
+// Delete mocks base method.
+func (m *MockNudgeRuleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockNudgeRuleRepositoryMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockNudgeRuleRepository)(nil).Delete), ctx, id)
+}
+
+// FindByID mocks base method.
+func (m *MockNudgeRuleRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.NudgeRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(*models.NudgeRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockNudgeRuleRepositoryMockRecorder) FindByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockNudgeRuleRepository)(nil).FindByID), ctx, id)
+}
+
+// GetEnabled mocks base method.
+func (m *MockNudgeRuleRepository) GetEnabled(ctx context.Context, userID uuid.UUID) ([]*models.NudgeRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEnabled", ctx, userID)
+	ret0, _ := ret[0].([]*models.NudgeRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEnabled indicates an expected call of GetEnabled.
+func (mr *MockNudgeRuleRepositoryMockRecorder) GetEnabled(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEnabled", reflect.TypeOf((*MockNudgeRuleRepository)(nil).GetEnabled), ctx, userID)
+}
+
+// ListByUser mocks base method.
+func (m *MockNudgeRuleRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.NudgeRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", ctx, userID)
+	ret0, _ := ret[0].([]*models.NudgeRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockNudgeRuleRepositoryMockRecorder) ListByUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockNudgeRuleRepository)(nil).ListByUser), ctx, userID)
+}
+
+// ListGlobal mocks base method.
+func (m *MockNudgeRuleRepository) ListGlobal(ctx context.Context) ([]*models.NudgeRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListGlobal", ctx)
+	ret0, _ := ret[0].([]*models.NudgeRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListGlobal indicates an expected call of ListGlobal.
+func (mr *MockNudgeRuleRepositoryMockRecorder) ListGlobal(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGlobal", reflect.TypeOf((*MockNudgeRuleRepository)(nil).ListGlobal), ctx)
+}
+
+// MarkFired mocks base method.
+func (m *MockNudgeRuleRepository) MarkFired(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkFired", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkFired indicates an expected call of MarkFired.
+func (mr *MockNudgeRuleRepositoryMockRecorder) MarkFired(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkFired", reflect.TypeOf((*MockNudgeRuleRepository)(nil).MarkFired), ctx, id)
+}
+
+// Update mocks base method.
+func (m *MockNudgeRuleRepository) Update(ctx context.Context, rule *models.NudgeRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockNudgeRuleRepositoryMockRecorder) Update(ctx, rule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockNudgeRuleRepository)(nil).Update), ctx, rule)
+}
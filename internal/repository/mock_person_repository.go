@@ -0,0 +1,301 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/person_repository.go
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	models "github.com/vyve/vyve-backend/internal/models"
+)
+
+// MockPersonRepository is a mock of PersonRepository interface.
+type MockPersonRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPersonRepositoryMockRecorder
+}
+
+// MockPersonRepositoryMockRecorder is the mock recorder for MockPersonRepository.
+type MockPersonRepositoryMockRecorder struct {
+	mock *MockPersonRepository
+}
+
+// NewMockPersonRepository creates a new mock instance.
+func NewMockPersonRepository(ctrl *gomock.Controller) *MockPersonRepository {
+	mock := &MockPersonRepository{ctrl: ctrl}
+	mock.recorder = &MockPersonRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPersonRepository) EXPECT() *MockPersonRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPersonRepository) Create(ctx context.Context, person *models.Person) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, person)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPersonRepositoryMockRecorder) Create(ctx, person interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPersonRepository)(nil).Create), ctx, person)
+}
+
+// Delete mocks base method.
+func (m *MockPersonRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockPersonRepositoryMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPersonRepository)(nil).Delete), ctx, id)
+}
+
+// FindByID mocks base method.
+func (m *MockPersonRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Person, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(*models.Person)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockPersonRepositoryMockRecorder) FindByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockPersonRepository)(nil).FindByID), ctx, id)
+}
+
+// FindByUserID mocks base method.
+func (m *MockPersonRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*models.Person, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUserID", ctx, userID)
+	ret0, _ := ret[0].([]*models.Person)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUserID indicates an expected call of FindByUserID.
+func (mr *MockPersonRepositoryMockRecorder) FindByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUserID", reflect.TypeOf((*MockPersonRepository)(nil).FindByUserID), ctx, userID)
+}
+
+// GetByCategory mocks base method.
+func (m *MockPersonRepository) GetByCategory(ctx context.Context, userID uuid.UUID, category string) ([]*models.Person, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByCategory", ctx, userID, category)
+	ret0, _ := ret[0].([]*models.Person)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByCategory indicates an expected call of GetByCategory.
+func (mr *MockPersonRepositoryMockRecorder) GetByCategory(ctx, userID, category interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByCategory", reflect.TypeOf((*MockPersonRepository)(nil).GetByCategory), ctx, userID, category)
+}
+
+// GetCategories mocks base method.
+func (m *MockPersonRepository) GetCategories(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCategories", ctx, userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCategories indicates an expected call of GetCategories.
+func (mr *MockPersonRepositoryMockRecorder) GetCategories(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategories", reflect.TypeOf((*MockPersonRepository)(nil).GetCategories), ctx, userID)
+}
+
+// GetCategoryNames mocks base method.
+func (m *MockPersonRepository) GetCategoryNames(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCategoryNames", ctx, userID)
+	ret0, _ := ret[0].(map[uuid.UUID]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCategoryNames indicates an expected call of GetCategoryNames.
+func (mr *MockPersonRepositoryMockRecorder) GetCategoryNames(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategoryNames", reflect.TypeOf((*MockPersonRepository)(nil).GetCategoryNames), ctx, userID)
+}
+
+// GetPeopleForReminders mocks base method.
+func (m *MockPersonRepository) GetPeopleForReminders(ctx context.Context, userID uuid.UUID) ([]*models.Person, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPeopleForReminders", ctx, userID)
+	ret0, _ := ret[0].([]*models.Person)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPeopleForReminders indicates an expected call of GetPeopleForReminders.
+func (mr *MockPersonRepositoryMockRecorder) GetPeopleForReminders(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPeopleForReminders", reflect.TypeOf((*MockPersonRepository)(nil).GetPeopleForReminders), ctx, userID)
+}
+
+// GetPeopleNeedingAttention mocks base method.
+func (m *MockPersonRepository) GetPeopleNeedingAttention(ctx context.Context, userID uuid.UUID) ([]*models.Person, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPeopleNeedingAttention", ctx, userID)
+	ret0, _ := ret[0].([]*models.Person)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPeopleNeedingAttention indicates an expected call of GetPeopleNeedingAttention.
+func (mr *MockPersonRepositoryMockRecorder) GetPeopleNeedingAttention(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPeopleNeedingAttention", reflect.TypeOf((*MockPersonRepository)(nil).GetPeopleNeedingAttention), ctx, userID)
+}
+
+// GetRecentInteractions mocks base method.
+func (m *MockPersonRepository) GetRecentInteractions(ctx context.Context, personID uuid.UUID, limit int) ([]*models.Interaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecentInteractions", ctx, personID, limit)
+	ret0, _ := ret[0].([]*models.Interaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecentInteractions indicates an expected call of GetRecentInteractions.
+func (mr *MockPersonRepositoryMockRecorder) GetRecentInteractions(ctx, personID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecentInteractions", reflect.TypeOf((*MockPersonRepository)(nil).GetRecentInteractions), ctx, personID, limit)
+}
+
+// IncrementInteractionCount mocks base method.
+func (m *MockPersonRepository) IncrementInteractionCount(ctx context.Context, personID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementInteractionCount", ctx, personID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrementInteractionCount indicates an expected call of IncrementInteractionCount.
+func (mr *MockPersonRepositoryMockRecorder) IncrementInteractionCount(ctx, personID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementInteractionCount", reflect.TypeOf((*MockPersonRepository)(nil).IncrementInteractionCount), ctx, personID)
+}
+
+// List mocks base method.
+func (m *MockPersonRepository) List(ctx context.Context, opts FilterOptions) ([]*models.Person, *PaginationResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, opts)
+	ret0, _ := ret[0].([]*models.Person)
+	ret1, _ := ret[1].(*PaginationResult)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockPersonRepositoryMockRecorder) List(ctx, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockPersonRepository)(nil).List), ctx, opts)
+}
+
+// RecalculateInteractionStats mocks base method.
+func (m *MockPersonRepository) RecalculateInteractionStats(ctx context.Context, personID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecalculateInteractionStats", ctx, personID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecalculateInteractionStats indicates an expected call of RecalculateInteractionStats.
+func (mr *MockPersonRepositoryMockRecorder) RecalculateInteractionStats(ctx, personID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecalculateInteractionStats", reflect.TypeOf((*MockPersonRepository)(nil).RecalculateInteractionStats), ctx, personID)
+}
+
+// Search mocks base method.
+func (m *MockPersonRepository) Search(ctx context.Context, userID uuid.UUID, query string, limit int) ([]*models.Person, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, userID, query, limit)
+	ret0, _ := ret[0].([]*models.Person)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockPersonRepositoryMockRecorder) Search(ctx, userID, query, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockPersonRepository)(nil).Search), ctx, userID, query, limit)
+}
+
+// SetNextReminder mocks base method.
+func (m *MockPersonRepository) SetNextReminder(ctx context.Context, personID uuid.UUID, at *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNextReminder", ctx, personID, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetNextReminder indicates an expected call of SetNextReminder.
+func (mr *MockPersonRepositoryMockRecorder) SetNextReminder(ctx, personID, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNextReminder", reflect.TypeOf((*MockPersonRepository)(nil).SetNextReminder), ctx, personID, at)
+}
+
+// Update mocks base method.
+func (m *MockPersonRepository) Update(ctx context.Context, person *models.Person) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, person)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockPersonRepositoryMockRecorder) Update(ctx, person interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPersonRepository)(nil).Update), ctx, person)
+}
+
+// UpdateHealthScore mocks base method.
+func (m *MockPersonRepository) UpdateHealthScore(ctx context.Context, personID uuid.UUID, score float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateHealthScore", ctx, personID, score)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateHealthScore indicates an expected call of UpdateHealthScore.
+func (mr *MockPersonRepositoryMockRecorder) UpdateHealthScore(ctx, personID, score interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateHealthScore", reflect.TypeOf((*MockPersonRepository)(nil).UpdateHealthScore), ctx, personID, score)
+}
+
+// UpdateLastInteraction mocks base method.
+func (m *MockPersonRepository) UpdateLastInteraction(ctx context.Context, personID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLastInteraction", ctx, personID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLastInteraction indicates an expected call of UpdateLastInteraction.
+func (mr *MockPersonRepositoryMockRecorder) UpdateLastInteraction(ctx, personID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLastInteraction", reflect.TypeOf((*MockPersonRepository)(nil).UpdateLastInteraction), ctx, personID)
+}
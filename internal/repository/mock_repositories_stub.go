@@ -0,0 +1,638 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/repositories_stub.go
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	models "github.com/vyve/vyve-backend/internal/models"
+)
+
+// MockReflectionRepository is a mock of ReflectionRepository interface.
+type MockReflectionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockReflectionRepositoryMockRecorder
+}
+
+// MockReflectionRepositoryMockRecorder is the mock recorder for MockReflectionRepository.
+type MockReflectionRepositoryMockRecorder struct {
+	mock *MockReflectionRepository
+}
+
+// NewMockReflectionRepository creates a new mock instance.
+func NewMockReflectionRepository(ctrl *gomock.Controller) *MockReflectionRepository {
+	mock := &MockReflectionRepository{ctrl: ctrl}
+	mock.recorder = &MockReflectionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReflectionRepository) EXPECT() *MockReflectionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockReflectionRepository) Create(ctx context.Context, reflection *models.Reflection) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, reflection)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockReflectionRepositoryMockRecorder) Create(ctx, reflection interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockReflectionRepository)(nil).Create), ctx, reflection)
+}
+
+// Delete mocks base method.
+func (m *MockReflectionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockReflectionRepositoryMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockReflectionRepository)(nil).Delete), ctx, id)
+}
+
+// FindByID mocks base method.
+func (m *MockReflectionRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Reflection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(*models.Reflection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockReflectionRepositoryMockRecorder) FindByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockReflectionRepository)(nil).FindByID), ctx, id)
+}
+
+// FindForDay mocks base method.
+func (m *MockReflectionRepository) FindForDay(ctx context.Context, userID uuid.UUID, dayStart, dayEnd time.Time) (*models.Reflection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindForDay", ctx, userID, dayStart, dayEnd)
+	ret0, _ := ret[0].(*models.Reflection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindForDay indicates an expected call of FindForDay.
+func (mr *MockReflectionRepositoryMockRecorder) FindForDay(ctx, userID, dayStart, dayEnd interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindForDay", reflect.TypeOf((*MockReflectionRepository)(nil).FindForDay), ctx, userID, dayStart, dayEnd)
+}
+
+// GetCompletionTimes mocks base method.
+func (m *MockReflectionRepository) GetCompletionTimes(ctx context.Context, userID uuid.UUID, since time.Time) ([]time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCompletionTimes", ctx, userID, since)
+	ret0, _ := ret[0].([]time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCompletionTimes indicates an expected call of GetCompletionTimes.
+func (mr *MockReflectionRepositoryMockRecorder) GetCompletionTimes(ctx, userID, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCompletionTimes", reflect.TypeOf((*MockReflectionRepository)(nil).GetCompletionTimes), ctx, userID, since)
+}
+
+// List mocks base method.
+func (m *MockReflectionRepository) List(ctx context.Context, opts FilterOptions) ([]*models.Reflection, *PaginationResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, opts)
+	ret0, _ := ret[0].([]*models.Reflection)
+	ret1, _ := ret[1].(*PaginationResult)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockReflectionRepositoryMockRecorder) List(ctx, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockReflectionRepository)(nil).List), ctx, opts)
+}
+
+// ListBetween mocks base method.
+func (m *MockReflectionRepository) ListBetween(ctx context.Context, userID uuid.UUID, start, end time.Time) ([]*models.Reflection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBetween", ctx, userID, start, end)
+	ret0, _ := ret[0].([]*models.Reflection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBetween indicates an expected call of ListBetween.
+func (mr *MockReflectionRepositoryMockRecorder) ListBetween(ctx, userID, start, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBetween", reflect.TypeOf((*MockReflectionRepository)(nil).ListBetween), ctx, userID, start, end)
+}
+
+// Update mocks base method.
+func (m *MockReflectionRepository) Update(ctx context.Context, reflection *models.Reflection) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, reflection)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockReflectionRepositoryMockRecorder) Update(ctx, reflection interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockReflectionRepository)(nil).Update), ctx, reflection)
+}
+
+// MockNudgeRepository is a mock of NudgeRepository interface.
+type MockNudgeRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockNudgeRepositoryMockRecorder
+}
+
+// MockNudgeRepositoryMockRecorder is the mock recorder for MockNudgeRepository.
+type MockNudgeRepositoryMockRecorder struct {
+	mock *MockNudgeRepository
+}
+
+// NewMockNudgeRepository creates a new mock instance.
+func NewMockNudgeRepository(ctrl *gomock.Controller) *MockNudgeRepository {
+	mock := &MockNudgeRepository{ctrl: ctrl}
+	mock.recorder = &MockNudgeRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNudgeRepository) EXPECT() *MockNudgeRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockNudgeRepository) Create(ctx context.Context, nudge *models.Nudge) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, nudge)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockNudgeRepositoryMockRecorder) Create(ctx, nudge interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockNudgeRepository)(nil).Create), ctx, nudge)
+}
+
+// Delete mocks base method.
+func (m *MockNudgeRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockNudgeRepositoryMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockNudgeRepository)(nil).Delete), ctx, id)
+}
+
+// FindByID mocks base method.
+func (m *MockNudgeRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Nudge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(*models.Nudge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockNudgeRepositoryMockRecorder) FindByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockNudgeRepository)(nil).FindByID), ctx, id)
+}
+
+// GetActive mocks base method.
+func (m *MockNudgeRepository) GetActive(ctx context.Context, userID uuid.UUID) ([]*models.Nudge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActive", ctx, userID)
+	ret0, _ := ret[0].([]*models.Nudge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActive indicates an expected call of GetActive.
+func (mr *MockNudgeRepositoryMockRecorder) GetActive(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActive", reflect.TypeOf((*MockNudgeRepository)(nil).GetActive), ctx, userID)
+}
+
+// HasPendingForPerson mocks base method.
+func (m *MockNudgeRepository) HasPendingForPerson(ctx context.Context, personID uuid.UUID, source string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasPendingForPerson", ctx, personID, source)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasPendingForPerson indicates an expected call of HasPendingForPerson.
+func (mr *MockNudgeRepositoryMockRecorder) HasPendingForPerson(ctx, personID, source interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasPendingForPerson", reflect.TypeOf((*MockNudgeRepository)(nil).HasPendingForPerson), ctx, personID, source)
+}
+
+// List mocks base method.
+func (m *MockNudgeRepository) List(ctx context.Context, opts FilterOptions) ([]*models.Nudge, *PaginationResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, opts)
+	ret0, _ := ret[0].([]*models.Nudge)
+	ret1, _ := ret[1].(*PaginationResult)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockNudgeRepositoryMockRecorder) List(ctx, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockNudgeRepository)(nil).List), ctx, opts)
+}
+
+// MarkActedOn mocks base method.
+func (m *MockNudgeRepository) MarkActedOn(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkActedOn", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkActedOn indicates an expected call of MarkActedOn.
+func (mr *MockNudgeRepositoryMockRecorder) MarkActedOn(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkActedOn", reflect.TypeOf((*MockNudgeRepository)(nil).MarkActedOn), ctx, id)
+}
+
+// MarkSeen mocks base method.
+func (m *MockNudgeRepository) MarkSeen(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkSeen", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkSeen indicates an expected call of MarkSeen.
+func (mr *MockNudgeRepositoryMockRecorder) MarkSeen(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkSeen", reflect.TypeOf((*MockNudgeRepository)(nil).MarkSeen), ctx, id)
+}
+
+// Update mocks base method.
+func (m *MockNudgeRepository) Update(ctx context.Context, nudge *models.Nudge) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, nudge)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockNudgeRepositoryMockRecorder) Update(ctx, nudge interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockNudgeRepository)(nil).Update), ctx, nudge)
+}
+
+// MockEventRepository is a mock of EventRepository interface.
+type MockEventRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventRepositoryMockRecorder
+}
+
+// MockEventRepositoryMockRecorder is the mock recorder for MockEventRepository.
+type MockEventRepositoryMockRecorder struct {
+	mock *MockEventRepository
+}
+
+// NewMockEventRepository creates a new mock instance.
+func NewMockEventRepository(ctrl *gomock.Controller) *MockEventRepository {
+	mock := &MockEventRepository{ctrl: ctrl}
+	mock.recorder = &MockEventRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventRepository) EXPECT() *MockEventRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockEventRepository) Create(ctx context.Context, event *models.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockEventRepositoryMockRecorder) Create(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockEventRepository)(nil).Create), ctx, event)
+}
+
+// GetByType mocks base method.
+func (m *MockEventRepository) GetByType(ctx context.Context, userID uuid.UUID, eventType string) ([]*models.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByType", ctx, userID, eventType)
+	ret0, _ := ret[0].([]*models.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByType indicates an expected call of GetByType.
+func (mr *MockEventRepositoryMockRecorder) GetByType(ctx, userID, eventType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByType", reflect.TypeOf((*MockEventRepository)(nil).GetByType), ctx, userID, eventType)
+}
+
+// List mocks base method.
+func (m *MockEventRepository) List(ctx context.Context, opts FilterOptions) ([]*models.Event, *PaginationResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, opts)
+	ret0, _ := ret[0].([]*models.Event)
+	ret1, _ := ret[1].(*PaginationResult)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockEventRepositoryMockRecorder) List(ctx, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockEventRepository)(nil).List), ctx, opts)
+}
+
+// MockConsentRepository is a mock of ConsentRepository interface.
+type MockConsentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockConsentRepositoryMockRecorder
+}
+
+// MockConsentRepositoryMockRecorder is the mock recorder for MockConsentRepository.
+type MockConsentRepositoryMockRecorder struct {
+	mock *MockConsentRepository
+}
+
+// NewMockConsentRepository creates a new mock instance.
+func NewMockConsentRepository(ctrl *gomock.Controller) *MockConsentRepository {
+	mock := &MockConsentRepository{ctrl: ctrl}
+	mock.recorder = &MockConsentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConsentRepository) EXPECT() *MockConsentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockConsentRepository) Create(ctx context.Context, consent *models.UserConsent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, consent)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockConsentRepositoryMockRecorder) Create(ctx, consent interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockConsentRepository)(nil).Create), ctx, consent)
+}
+
+// GetByType mocks base method.
+func (m *MockConsentRepository) GetByType(ctx context.Context, userID uuid.UUID, consentType string) (*models.UserConsent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByType", ctx, userID, consentType)
+	ret0, _ := ret[0].(*models.UserConsent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByType indicates an expected call of GetByType.
+func (mr *MockConsentRepositoryMockRecorder) GetByType(ctx, userID, consentType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByType", reflect.TypeOf((*MockConsentRepository)(nil).GetByType), ctx, userID, consentType)
+}
+
+// GetByUser mocks base method.
+func (m *MockConsentRepository) GetByUser(ctx context.Context, userID uuid.UUID) ([]*models.UserConsent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUser", ctx, userID)
+	ret0, _ := ret[0].([]*models.UserConsent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUser indicates an expected call of GetByUser.
+func (mr *MockConsentRepositoryMockRecorder) GetByUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUser", reflect.TypeOf((*MockConsentRepository)(nil).GetByUser), ctx, userID)
+}
+
+// Update mocks base method.
+func (m *MockConsentRepository) Update(ctx context.Context, consent *models.UserConsent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, consent)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockConsentRepositoryMockRecorder) Update(ctx, consent interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockConsentRepository)(nil).Update), ctx, consent)
+}
+
+// MockAuditLogRepository is a mock of AuditLogRepository interface.
+type MockAuditLogRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditLogRepositoryMockRecorder
+}
+
+// MockAuditLogRepositoryMockRecorder is the mock recorder for MockAuditLogRepository.
+type MockAuditLogRepositoryMockRecorder struct {
+	mock *MockAuditLogRepository
+}
+
+// NewMockAuditLogRepository creates a new mock instance.
+func NewMockAuditLogRepository(ctrl *gomock.Controller) *MockAuditLogRepository {
+	mock := &MockAuditLogRepository{ctrl: ctrl}
+	mock.recorder = &MockAuditLogRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditLogRepository) EXPECT() *MockAuditLogRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAuditLogRepository) Create(ctx context.Context, log *models.AuditLog) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, log)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAuditLogRepositoryMockRecorder) Create(ctx, log interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAuditLogRepository)(nil).Create), ctx, log)
+}
+
+// GetByUser mocks base method.
+func (m *MockAuditLogRepository) GetByUser(ctx context.Context, userID uuid.UUID) ([]*models.AuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUser", ctx, userID)
+	ret0, _ := ret[0].([]*models.AuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUser indicates an expected call of GetByUser.
+func (mr *MockAuditLogRepositoryMockRecorder) GetByUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUser", reflect.TypeOf((*MockAuditLogRepository)(nil).GetByUser), ctx, userID)
+}
+
+// List mocks base method.
+func (m *MockAuditLogRepository) List(ctx context.Context, opts FilterOptions) ([]*models.AuditLog, *PaginationResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, opts)
+	ret0, _ := ret[0].([]*models.AuditLog)
+	ret1, _ := ret[1].(*PaginationResult)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockAuditLogRepositoryMockRecorder) List(ctx, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAuditLogRepository)(nil).List), ctx, opts)
+}
+
+// MockDataExportRepository is a mock of DataExportRepository interface.
+type MockDataExportRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDataExportRepositoryMockRecorder
+}
+
+// MockDataExportRepositoryMockRecorder is the mock recorder for MockDataExportRepository.
+type MockDataExportRepositoryMockRecorder struct {
+	mock *MockDataExportRepository
+}
+
+// NewMockDataExportRepository creates a new mock instance.
+func NewMockDataExportRepository(ctrl *gomock.Controller) *MockDataExportRepository {
+	mock := &MockDataExportRepository{ctrl: ctrl}
+	mock.recorder = &MockDataExportRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDataExportRepository) EXPECT() *MockDataExportRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockDataExportRepository) Create(ctx context.Context, export *models.DataExport) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, export)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDataExportRepositoryMockRecorder) Create(ctx, export interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDataExportRepository)(nil).Create), ctx, export)
+}
+
+// FailStale mocks base method.
+func (m *MockDataExportRepository) FailStale(ctx context.Context, staleBefore time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailStale", ctx, staleBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailStale indicates an expected call of FailStale.
+func (mr *MockDataExportRepositoryMockRecorder) FailStale(ctx, staleBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailStale", reflect.TypeOf((*MockDataExportRepository)(nil).FailStale), ctx, staleBefore)
+}
+
+// FindByID mocks base method.
+func (m *MockDataExportRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(*models.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockDataExportRepositoryMockRecorder) FindByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockDataExportRepository)(nil).FindByID), ctx, id)
+}
+
+// GetExpired mocks base method.
+func (m *MockDataExportRepository) GetExpired(ctx context.Context, now time.Time, limit int) ([]*models.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpired", ctx, now, limit)
+	ret0, _ := ret[0].([]*models.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpired indicates an expected call of GetExpired.
+func (mr *MockDataExportRepositoryMockRecorder) GetExpired(ctx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpired", reflect.TypeOf((*MockDataExportRepository)(nil).GetExpired), ctx, now, limit)
+}
+
+// GetPending mocks base method.
+func (m *MockDataExportRepository) GetPending(ctx context.Context, userID uuid.UUID) (*models.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPending", ctx, userID)
+	ret0, _ := ret[0].(*models.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPending indicates an expected call of GetPending.
+func (mr *MockDataExportRepositoryMockRecorder) GetPending(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPending", reflect.TypeOf((*MockDataExportRepository)(nil).GetPending), ctx, userID)
+}
+
+// Update mocks base method.
+func (m *MockDataExportRepository) Update(ctx context.Context, export *models.DataExport) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, export)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockDataExportRepositoryMockRecorder) Update(ctx, export interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDataExportRepository)(nil).Update), ctx, export)
+}
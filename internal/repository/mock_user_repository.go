@@ -0,0 +1,823 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/user_repository.go
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	models "github.com/vyve/vyve-backend/internal/models"
+)
+
+// MockUserRepository is a mock of UserRepository interface.
+type MockUserRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserRepositoryMockRecorder
+}
+
+// MockUserRepositoryMockRecorder is the mock recorder for MockUserRepository.
+type MockUserRepositoryMockRecorder struct {
+	mock *MockUserRepository
+}
+
+// NewMockUserRepository creates a new mock instance.
+func NewMockUserRepository(ctrl *gomock.Controller) *MockUserRepository {
+	mock := &MockUserRepository{ctrl: ctrl}
+	mock.recorder = &MockUserRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddRole mocks base method.
+func (m *MockUserRepository) AddRole(ctx context.Context, id uuid.UUID, role string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddRole", ctx, id, role)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddRole indicates an expected call of AddRole.
+func (mr *MockUserRepositoryMockRecorder) AddRole(ctx, id, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRole", reflect.TypeOf((*MockUserRepository)(nil).AddRole), ctx, id, role)
+}
+
+// CheckEmailExists mocks base method.
+func (m *MockUserRepository) CheckEmailExists(ctx context.Context, email string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckEmailExists", ctx, email)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckEmailExists indicates an expected call of CheckEmailExists.
+func (mr *MockUserRepositoryMockRecorder) CheckEmailExists(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckEmailExists", reflect.TypeOf((*MockUserRepository)(nil).CheckEmailExists), ctx, email)
+}
+
+// CheckUsernameExists mocks base method.
+func (m *MockUserRepository) CheckUsernameExists(ctx context.Context, username string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckUsernameExists", ctx, username)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckUsernameExists indicates an expected call of CheckUsernameExists.
+func (mr *MockUserRepositoryMockRecorder) CheckUsernameExists(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckUsernameExists", reflect.TypeOf((*MockUserRepository)(nil).CheckUsernameExists), ctx, username)
+}
+
+// ConsumeActionToken mocks base method.
+func (m *MockUserRepository) ConsumeActionToken(ctx context.Context, purpose, tokenHash string) (*models.ActionToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeActionToken", ctx, purpose, tokenHash)
+	ret0, _ := ret[0].(*models.ActionToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeActionToken indicates an expected call of ConsumeActionToken.
+func (mr *MockUserRepositoryMockRecorder) ConsumeActionToken(ctx, purpose, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeActionToken", reflect.TypeOf((*MockUserRepository)(nil).ConsumeActionToken), ctx, purpose, tokenHash)
+}
+
+// ConsumeRecoveryCode mocks base method.
+func (m *MockUserRepository) ConsumeRecoveryCode(ctx context.Context, id uuid.UUID, codeHash string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeRecoveryCode", ctx, id, codeHash)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeRecoveryCode indicates an expected call of ConsumeRecoveryCode.
+func (mr *MockUserRepositoryMockRecorder) ConsumeRecoveryCode(ctx, id, codeHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeRecoveryCode", reflect.TypeOf((*MockUserRepository)(nil).ConsumeRecoveryCode), ctx, id, codeHash)
+}
+
+// CountRecoveryCodes mocks base method.
+func (m *MockUserRepository) CountRecoveryCodes(ctx context.Context, id uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountRecoveryCodes", ctx, id)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountRecoveryCodes indicates an expected call of CountRecoveryCodes.
+func (mr *MockUserRepositoryMockRecorder) CountRecoveryCodes(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRecoveryCodes", reflect.TypeOf((*MockUserRepository)(nil).CountRecoveryCodes), ctx, id)
+}
+
+// Create mocks base method.
+func (m *MockUserRepository) Create(ctx context.Context, user *models.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockUserRepositoryMockRecorder) Create(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserRepository)(nil).Create), ctx, user)
+}
+
+// CreateActionToken mocks base method.
+func (m *MockUserRepository) CreateActionToken(ctx context.Context, token *models.ActionToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateActionToken", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateActionToken indicates an expected call of CreateActionToken.
+func (mr *MockUserRepositoryMockRecorder) CreateActionToken(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateActionToken", reflect.TypeOf((*MockUserRepository)(nil).CreateActionToken), ctx, token)
+}
+
+// CreatePasskey mocks base method.
+func (m *MockUserRepository) CreatePasskey(ctx context.Context, passkey *models.Passkey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePasskey", ctx, passkey)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePasskey indicates an expected call of CreatePasskey.
+func (mr *MockUserRepositoryMockRecorder) CreatePasskey(ctx, passkey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePasskey", reflect.TypeOf((*MockUserRepository)(nil).CreatePasskey), ctx, passkey)
+}
+
+// DeactivatePushToken mocks base method.
+func (m *MockUserRepository) DeactivatePushToken(ctx context.Context, userID uuid.UUID, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeactivatePushToken", ctx, userID, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeactivatePushToken indicates an expected call of DeactivatePushToken.
+func (mr *MockUserRepositoryMockRecorder) DeactivatePushToken(ctx, userID, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivatePushToken", reflect.TypeOf((*MockUserRepository)(nil).DeactivatePushToken), ctx, userID, token)
+}
+
+// DeactivatePushTokens mocks base method.
+func (m *MockUserRepository) DeactivatePushTokens(ctx context.Context, tokens []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeactivatePushTokens", ctx, tokens)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeactivatePushTokens indicates an expected call of DeactivatePushTokens.
+func (mr *MockUserRepositoryMockRecorder) DeactivatePushTokens(ctx, tokens interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivatePushTokens", reflect.TypeOf((*MockUserRepository)(nil).DeactivatePushTokens), ctx, tokens)
+}
+
+// Delete mocks base method.
+func (m *MockUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockUserRepositoryMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUserRepository)(nil).Delete), ctx, id)
+}
+
+// DeletePasskey mocks base method.
+func (m *MockUserRepository) DeletePasskey(ctx context.Context, userID, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePasskey", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePasskey indicates an expected call of DeletePasskey.
+func (mr *MockUserRepositoryMockRecorder) DeletePasskey(ctx, userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePasskey", reflect.TypeOf((*MockUserRepository)(nil).DeletePasskey), ctx, userID, id)
+}
+
+// DeleteStaleRefreshTokens mocks base method.
+func (m *MockUserRepository) DeleteStaleRefreshTokens(ctx context.Context, revokedBefore time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteStaleRefreshTokens", ctx, revokedBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteStaleRefreshTokens indicates an expected call of DeleteStaleRefreshTokens.
+func (mr *MockUserRepositoryMockRecorder) DeleteStaleRefreshTokens(ctx, revokedBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStaleRefreshTokens", reflect.TypeOf((*MockUserRepository)(nil).DeleteStaleRefreshTokens), ctx, revokedBefore)
+}
+
+// DisableMFA mocks base method.
+func (m *MockUserRepository) DisableMFA(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableMFA", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableMFA indicates an expected call of DisableMFA.
+func (mr *MockUserRepositoryMockRecorder) DisableMFA(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableMFA", reflect.TypeOf((*MockUserRepository)(nil).DisableMFA), ctx, id)
+}
+
+// EnableMFA mocks base method.
+func (m *MockUserRepository) EnableMFA(ctx context.Context, id uuid.UUID, recoveryCodeHashes []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableMFA", ctx, id, recoveryCodeHashes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnableMFA indicates an expected call of EnableMFA.
+func (mr *MockUserRepositoryMockRecorder) EnableMFA(ctx, id, recoveryCodeHashes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableMFA", reflect.TypeOf((*MockUserRepository)(nil).EnableMFA), ctx, id, recoveryCodeHashes)
+}
+
+// FindByAuthProvider mocks base method.
+func (m *MockUserRepository) FindByAuthProvider(ctx context.Context, provider, providerID string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByAuthProvider", ctx, provider, providerID)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByAuthProvider indicates an expected call of FindByAuthProvider.
+func (mr *MockUserRepositoryMockRecorder) FindByAuthProvider(ctx, provider, providerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByAuthProvider", reflect.TypeOf((*MockUserRepository)(nil).FindByAuthProvider), ctx, provider, providerID)
+}
+
+// FindByEmail mocks base method.
+func (m *MockUserRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByEmail", ctx, email)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByEmail indicates an expected call of FindByEmail.
+func (mr *MockUserRepositoryMockRecorder) FindByEmail(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByEmail", reflect.TypeOf((*MockUserRepository)(nil).FindByEmail), ctx, email)
+}
+
+// FindByID mocks base method.
+func (m *MockUserRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockUserRepositoryMockRecorder) FindByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockUserRepository)(nil).FindByID), ctx, id)
+}
+
+// FindByUsername mocks base method.
+func (m *MockUserRepository) FindByUsername(ctx context.Context, username string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUsername", ctx, username)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUsername indicates an expected call of FindByUsername.
+func (mr *MockUserRepositoryMockRecorder) FindByUsername(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUsername", reflect.TypeOf((*MockUserRepository)(nil).FindByUsername), ctx, username)
+}
+
+// FindPasskey mocks base method.
+func (m *MockUserRepository) FindPasskey(ctx context.Context, credentialID string) (*models.Passkey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPasskey", ctx, credentialID)
+	ret0, _ := ret[0].(*models.Passkey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPasskey indicates an expected call of FindPasskey.
+func (mr *MockUserRepositoryMockRecorder) FindPasskey(ctx, credentialID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPasskey", reflect.TypeOf((*MockUserRepository)(nil).FindPasskey), ctx, credentialID)
+}
+
+// FindRefreshToken mocks base method.
+func (m *MockUserRepository) FindRefreshToken(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRefreshToken", ctx, tokenHash)
+	ret0, _ := ret[0].(*models.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRefreshToken indicates an expected call of FindRefreshToken.
+func (mr *MockUserRepositoryMockRecorder) FindRefreshToken(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRefreshToken", reflect.TypeOf((*MockUserRepository)(nil).FindRefreshToken), ctx, tokenHash)
+}
+
+// GetActiveUsers mocks base method.
+func (m *MockUserRepository) GetActiveUsers(ctx context.Context, since time.Time) ([]*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveUsers", ctx, since)
+	ret0, _ := ret[0].([]*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveUsers indicates an expected call of GetActiveUsers.
+func (mr *MockUserRepositoryMockRecorder) GetActiveUsers(ctx, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveUsers", reflect.TypeOf((*MockUserRepository)(nil).GetActiveUsers), ctx, since)
+}
+
+// GetAuthProviders mocks base method.
+func (m *MockUserRepository) GetAuthProviders(ctx context.Context, userID uuid.UUID) ([]*models.AuthProvider, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthProviders", ctx, userID)
+	ret0, _ := ret[0].([]*models.AuthProvider)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthProviders indicates an expected call of GetAuthProviders.
+func (mr *MockUserRepositoryMockRecorder) GetAuthProviders(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthProviders", reflect.TypeOf((*MockUserRepository)(nil).GetAuthProviders), ctx, userID)
+}
+
+// GetUserPushTokens mocks base method.
+func (m *MockUserRepository) GetUserPushTokens(ctx context.Context, userID uuid.UUID) ([]*models.PushToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserPushTokens", ctx, userID)
+	ret0, _ := ret[0].([]*models.PushToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserPushTokens indicates an expected call of GetUserPushTokens.
+func (mr *MockUserRepositoryMockRecorder) GetUserPushTokens(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserPushTokens", reflect.TypeOf((*MockUserRepository)(nil).GetUserPushTokens), ctx, userID)
+}
+
+// GetUserStats mocks base method.
+func (m *MockUserRepository) GetUserStats(ctx context.Context, userID uuid.UUID) (map[string]interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserStats", ctx, userID)
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserStats indicates an expected call of GetUserStats.
+func (mr *MockUserRepositoryMockRecorder) GetUserStats(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserStats", reflect.TypeOf((*MockUserRepository)(nil).GetUserStats), ctx, userID)
+}
+
+// GetUsersForReminders mocks base method.
+func (m *MockUserRepository) GetUsersForReminders(ctx context.Context, hour int) ([]*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsersForReminders", ctx, hour)
+	ret0, _ := ret[0].([]*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsersForReminders indicates an expected call of GetUsersForReminders.
+func (mr *MockUserRepositoryMockRecorder) GetUsersForReminders(ctx, hour interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersForReminders", reflect.TypeOf((*MockUserRepository)(nil).GetUsersForReminders), ctx, hour)
+}
+
+// LinkAuthProvider mocks base method.
+func (m *MockUserRepository) LinkAuthProvider(ctx context.Context, provider *models.AuthProvider) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LinkAuthProvider", ctx, provider)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LinkAuthProvider indicates an expected call of LinkAuthProvider.
+func (mr *MockUserRepositoryMockRecorder) LinkAuthProvider(ctx, provider interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LinkAuthProvider", reflect.TypeOf((*MockUserRepository)(nil).LinkAuthProvider), ctx, provider)
+}
+
+// List mocks base method.
+func (m *MockUserRepository) List(ctx context.Context, opts UserFilterOptions) ([]*models.User, *PaginationResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, opts)
+	ret0, _ := ret[0].([]*models.User)
+	ret1, _ := ret[1].(*PaginationResult)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockUserRepositoryMockRecorder) List(ctx, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockUserRepository)(nil).List), ctx, opts)
+}
+
+// ListActiveRefreshTokens mocks base method.
+func (m *MockUserRepository) ListActiveRefreshTokens(ctx context.Context, userID uuid.UUID) ([]*models.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActiveRefreshTokens", ctx, userID)
+	ret0, _ := ret[0].([]*models.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActiveRefreshTokens indicates an expected call of ListActiveRefreshTokens.
+func (mr *MockUserRepositoryMockRecorder) ListActiveRefreshTokens(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActiveRefreshTokens", reflect.TypeOf((*MockUserRepository)(nil).ListActiveRefreshTokens), ctx, userID)
+}
+
+// ListPasskeys mocks base method.
+func (m *MockUserRepository) ListPasskeys(ctx context.Context, userID uuid.UUID) ([]*models.Passkey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPasskeys", ctx, userID)
+	ret0, _ := ret[0].([]*models.Passkey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPasskeys indicates an expected call of ListPasskeys.
+func (mr *MockUserRepositoryMockRecorder) ListPasskeys(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPasskeys", reflect.TypeOf((*MockUserRepository)(nil).ListPasskeys), ctx, userID)
+}
+
+// MarkEmailVerified mocks base method.
+func (m *MockUserRepository) MarkEmailVerified(ctx context.Context, id uuid.UUID, email string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkEmailVerified", ctx, id, email)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkEmailVerified indicates an expected call of MarkEmailVerified.
+func (mr *MockUserRepositoryMockRecorder) MarkEmailVerified(ctx, id, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkEmailVerified", reflect.TypeOf((*MockUserRepository)(nil).MarkEmailVerified), ctx, id, email)
+}
+
+// RemoveRole mocks base method.
+func (m *MockUserRepository) RemoveRole(ctx context.Context, id uuid.UUID, role string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveRole", ctx, id, role)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveRole indicates an expected call of RemoveRole.
+func (mr *MockUserRepositoryMockRecorder) RemoveRole(ctx, id, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveRole", reflect.TypeOf((*MockUserRepository)(nil).RemoveRole), ctx, id, role)
+}
+
+// ReplaceRecoveryCodes mocks base method.
+func (m *MockUserRepository) ReplaceRecoveryCodes(ctx context.Context, id uuid.UUID, recoveryCodeHashes []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceRecoveryCodes", ctx, id, recoveryCodeHashes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceRecoveryCodes indicates an expected call of ReplaceRecoveryCodes.
+func (mr *MockUserRepositoryMockRecorder) ReplaceRecoveryCodes(ctx, id, recoveryCodeHashes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceRecoveryCodes", reflect.TypeOf((*MockUserRepository)(nil).ReplaceRecoveryCodes), ctx, id, recoveryCodeHashes)
+}
+
+// RevokeAllUserTokens mocks base method.
+func (m *MockUserRepository) RevokeAllUserTokens(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAllUserTokens", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAllUserTokens indicates an expected call of RevokeAllUserTokens.
+func (mr *MockUserRepositoryMockRecorder) RevokeAllUserTokens(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAllUserTokens", reflect.TypeOf((*MockUserRepository)(nil).RevokeAllUserTokens), ctx, userID)
+}
+
+// RevokeRefreshToken mocks base method.
+func (m *MockUserRepository) RevokeRefreshToken(ctx context.Context, tokenHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeRefreshToken", ctx, tokenHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeRefreshToken indicates an expected call of RevokeRefreshToken.
+func (mr *MockUserRepositoryMockRecorder) RevokeRefreshToken(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeRefreshToken", reflect.TypeOf((*MockUserRepository)(nil).RevokeRefreshToken), ctx, tokenHash)
+}
+
+// RevokeSessionTokens mocks base method.
+func (m *MockUserRepository) RevokeSessionTokens(ctx context.Context, userID uuid.UUID, sessionID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSessionTokens", ctx, userID, sessionID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeSessionTokens indicates an expected call of RevokeSessionTokens.
+func (mr *MockUserRepositoryMockRecorder) RevokeSessionTokens(ctx, userID, sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSessionTokens", reflect.TypeOf((*MockUserRepository)(nil).RevokeSessionTokens), ctx, userID, sessionID)
+}
+
+// RotateRefreshToken mocks base method.
+func (m *MockUserRepository) RotateRefreshToken(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateRefreshToken", ctx, tokenHash)
+	ret0, _ := ret[0].(*models.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateRefreshToken indicates an expected call of RotateRefreshToken.
+func (mr *MockUserRepositoryMockRecorder) RotateRefreshToken(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateRefreshToken", reflect.TypeOf((*MockUserRepository)(nil).RotateRefreshToken), ctx, tokenHash)
+}
+
+// SavePushToken mocks base method.
+func (m *MockUserRepository) SavePushToken(ctx context.Context, token *models.PushToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SavePushToken", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SavePushToken indicates an expected call of SavePushToken.
+func (mr *MockUserRepositoryMockRecorder) SavePushToken(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePushToken", reflect.TypeOf((*MockUserRepository)(nil).SavePushToken), ctx, token)
+}
+
+// SaveRefreshToken mocks base method.
+func (m *MockUserRepository) SaveRefreshToken(ctx context.Context, token *models.RefreshToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveRefreshToken", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveRefreshToken indicates an expected call of SaveRefreshToken.
+func (mr *MockUserRepositoryMockRecorder) SaveRefreshToken(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRefreshToken", reflect.TypeOf((*MockUserRepository)(nil).SaveRefreshToken), ctx, token)
+}
+
+// SearchUsers mocks base method.
+func (m *MockUserRepository) SearchUsers(ctx context.Context, query string, limit int) ([]*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchUsers", ctx, query, limit)
+	ret0, _ := ret[0].([]*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchUsers indicates an expected call of SearchUsers.
+func (mr *MockUserRepositoryMockRecorder) SearchUsers(ctx, query, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchUsers", reflect.TypeOf((*MockUserRepository)(nil).SearchUsers), ctx, query, limit)
+}
+
+// SetStreak mocks base method.
+func (m *MockUserRepository) SetStreak(ctx context.Context, id uuid.UUID, count, longest int, lastReflectionAt *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStreak", ctx, id, count, longest, lastReflectionAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetStreak indicates an expected call of SetStreak.
+func (mr *MockUserRepositoryMockRecorder) SetStreak(ctx, id, count, longest, lastReflectionAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStreak", reflect.TypeOf((*MockUserRepository)(nil).SetStreak), ctx, id, count, longest, lastReflectionAt)
+}
+
+// SetTOTPSecret mocks base method.
+func (m *MockUserRepository) SetTOTPSecret(ctx context.Context, id uuid.UUID, secret string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTOTPSecret", ctx, id, secret)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTOTPSecret indicates an expected call of SetTOTPSecret.
+func (mr *MockUserRepositoryMockRecorder) SetTOTPSecret(ctx, id, secret interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTOTPSecret", reflect.TypeOf((*MockUserRepository)(nil).SetTOTPSecret), ctx, id, secret)
+}
+
+// Suspend mocks base method.
+func (m *MockUserRepository) Suspend(ctx context.Context, id uuid.UUID, reason string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Suspend", ctx, id, reason)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Suspend indicates an expected call of Suspend.
+func (mr *MockUserRepositoryMockRecorder) Suspend(ctx, id, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Suspend", reflect.TypeOf((*MockUserRepository)(nil).Suspend), ctx, id, reason)
+}
+
+// TouchPushTokens mocks base method.
+func (m *MockUserRepository) TouchPushTokens(ctx context.Context, tokens []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchPushTokens", ctx, tokens)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchPushTokens indicates an expected call of TouchPushTokens.
+func (mr *MockUserRepositoryMockRecorder) TouchPushTokens(ctx, tokens interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchPushTokens", reflect.TypeOf((*MockUserRepository)(nil).TouchPushTokens), ctx, tokens)
+}
+
+// UnlinkAuthProvider mocks base method.
+func (m *MockUserRepository) UnlinkAuthProvider(ctx context.Context, userID uuid.UUID, provider string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlinkAuthProvider", ctx, userID, provider)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnlinkAuthProvider indicates an expected call of UnlinkAuthProvider.
+func (mr *MockUserRepositoryMockRecorder) UnlinkAuthProvider(ctx, userID, provider interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlinkAuthProvider", reflect.TypeOf((*MockUserRepository)(nil).UnlinkAuthProvider), ctx, userID, provider)
+}
+
+// Unsuspend mocks base method.
+func (m *MockUserRepository) Unsuspend(ctx context.Context, id uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unsuspend", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unsuspend indicates an expected call of Unsuspend.
+func (mr *MockUserRepositoryMockRecorder) Unsuspend(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unsuspend", reflect.TypeOf((*MockUserRepository)(nil).Unsuspend), ctx, id)
+}
+
+// Update mocks base method.
+func (m *MockUserRepository) Update(ctx context.Context, user *models.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockUserRepositoryMockRecorder) Update(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUserRepository)(nil).Update), ctx, user)
+}
+
+// UpdateFields mocks base method.
+func (m *MockUserRepository) UpdateFields(ctx context.Context, user *models.User, fields map[string]interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFields", ctx, user, fields)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateFields indicates an expected call of UpdateFields.
+func (mr *MockUserRepositoryMockRecorder) UpdateFields(ctx, user, fields interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFields", reflect.TypeOf((*MockUserRepository)(nil).UpdateFields), ctx, user, fields)
+}
+
+// UpdateLastActivity mocks base method.
+func (m *MockUserRepository) UpdateLastActivity(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLastActivity", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLastActivity indicates an expected call of UpdateLastActivity.
+func (mr *MockUserRepositoryMockRecorder) UpdateLastActivity(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLastActivity", reflect.TypeOf((*MockUserRepository)(nil).UpdateLastActivity), ctx, id)
+}
+
+// UpdateLastLogin mocks base method.
+func (m *MockUserRepository) UpdateLastLogin(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLastLogin", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLastLogin indicates an expected call of UpdateLastLogin.
+func (mr *MockUserRepositoryMockRecorder) UpdateLastLogin(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLastLogin", reflect.TypeOf((*MockUserRepository)(nil).UpdateLastLogin), ctx, id)
+}
+
+// UpdatePasskeyUsage mocks base method.
+func (m *MockUserRepository) UpdatePasskeyUsage(ctx context.Context, id uuid.UUID, signCount int64, backedUp bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePasskeyUsage", ctx, id, signCount, backedUp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePasskeyUsage indicates an expected call of UpdatePasskeyUsage.
+func (mr *MockUserRepositoryMockRecorder) UpdatePasskeyUsage(ctx, id, signCount, backedUp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePasskeyUsage", reflect.TypeOf((*MockUserRepository)(nil).UpdatePasskeyUsage), ctx, id, signCount, backedUp)
+}
+
+// UpdatePassword mocks base method.
+func (m *MockUserRepository) UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePassword", ctx, id, passwordHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePassword indicates an expected call of UpdatePassword.
+func (mr *MockUserRepositoryMockRecorder) UpdatePassword(ctx, id, passwordHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockUserRepository)(nil).UpdatePassword), ctx, id, passwordHash)
+}
+
+// UpdateStreak mocks base method.
+func (m *MockUserRepository) UpdateStreak(ctx context.Context, id uuid.UUID, count int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStreak", ctx, id, count)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStreak indicates an expected call of UpdateStreak.
+func (mr *MockUserRepositoryMockRecorder) UpdateStreak(ctx, id, count interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStreak", reflect.TypeOf((*MockUserRepository)(nil).UpdateStreak), ctx, id, count)
+}
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vyve/vyve-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate mockgen -source=notification_repository.go -destination=mock_notification_repository.go -package=repository
+
+// NotificationRepository handles notification data access: the outbox the notification
+// worker drains and the in-app inbox
+type NotificationRepository interface {
+	Create(ctx context.Context, notification *models.Notification) error
+
+	// Inbox operations
+	ListInbox(ctx context.Context, userID uuid.UUID, unreadOnly bool, page, limit int) ([]*models.Notification, *PaginationResult, error)
+	CountUnread(ctx context.Context, userID uuid.UUID) (int64, error)
+	MarkRead(ctx context.Context, userID, id uuid.UUID) (*models.Notification, error)
+	MarkAllRead(ctx context.Context, userID uuid.UUID) (int64, error)
+
+	// Outbox operations
+	ClaimDue(ctx context.Context, channel string, limit int, lease time.Duration) ([]*models.Notification, error)
+	FinishDelivery(ctx context.Context, notification *models.Notification) (bool, error)
+	ReleaseDelivery(ctx context.Context, notification *models.Notification) error
+	DeferDelivery(ctx context.Context, notification *models.Notification, until time.Time) (bool, error)
+}
+
+type notificationRepository struct {
+	BaseRepository
+}
+
+// NewNotificationRepository creates a new notification repository
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Create creates a new notification
+func (r *notificationRepository) Create(ctx context.Context, notification *models.Notification) error {
+	return r.db.WithContext(ctx).Create(notification).Error
+}
+
+// ListInbox lists the user's in-app notifications, newest first
+func (r *notificationRepository) ListInbox(ctx context.Context, userID uuid.UUID, unreadOnly bool, page, limit int) ([]*models.Notification, *PaginationResult, error) {
+	query := r.db.WithContext(ctx).
+		Model(&models.Notification{}).
+		Where("user_id = ? AND channel = ?", userID, models.ChannelInApp)
+	if unreadOnly {
+		query = query.Where("opened_at IS NULL")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, nil, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+
+	var notifications []*models.Notification
+	err := query.Order("created_at DESC").Limit(limit).Offset((page - 1) * limit).Find(&notifications).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	return notifications, &PaginationResult{
+		Total:       total,
+		Page:        page,
+		Limit:       limit,
+		TotalPages:  totalPages,
+		HasNext:     page < totalPages,
+		HasPrevious: page > 1,
+	}, nil
+}
+
+// CountUnread counts the user's unread in-app notifications
+func (r *notificationRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Notification{}).
+		Where("user_id = ? AND channel = ? AND opened_at IS NULL", userID, models.ChannelInApp).
+		Count(&count).Error
+	return count, err
+}
+
+// MarkRead records that the user opened one of their notifications. Notifications that
+// were already opened keep the time they were first opened.
+func (r *notificationRepository) MarkRead(ctx context.Context, userID, id uuid.UUID) (*models.Notification, error) {
+	var notification models.Notification
+	err := r.db.WithContext(ctx).First(&notification, "id = ? AND user_id = ?", id, userID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotificationNotFound
+		}
+		return nil, err
+	}
+	if notification.OpenedAt != nil {
+		return &notification, nil
+	}
+
+	now := time.Now()
+	err = r.db.WithContext(ctx).
+		Model(&models.Notification{}).
+		Where("id = ? AND opened_at IS NULL", id).
+		Update("opened_at", now).Error
+	if err != nil {
+		return nil, err
+	}
+	notification.OpenedAt = &now
+	return &notification, nil
+}
+
+// MarkAllRead marks all of the user's unread in-app notifications as read and returns
+// how many there were
+func (r *notificationRepository) MarkAllRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.Notification{}).
+		Where("user_id = ? AND channel = ? AND opened_at IS NULL", userID, models.ChannelInApp).
+		Update("opened_at", time.Now())
+	return result.RowsAffected, result.Error
+}
+
+// ClaimDue atomically claims up to limit pending notifications of a channel that are due,
+// oldest first. Each claim counts an attempt and holds the notification for the lease;
+// if the attempt never finishes, another worker claims it once the lease is over.
+func (r *notificationRepository) ClaimDue(ctx context.Context, channel string, limit int, lease time.Duration) ([]*models.Notification, error) {
+	var claimed []*models.Notification
+
+	err := r.Transaction(ctx, func(tx *gorm.DB) error {
+		now := time.Now()
+		var due []*models.Notification
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND channel = ? AND next_attempt_at <= ?", models.NotificationStatusPending, channel, now).
+			Order("next_attempt_at ASC").
+			Limit(limit).
+			Find(&due).Error
+		if err != nil || len(due) == 0 {
+			return err
+		}
+
+		ids := make([]uuid.UUID, len(due))
+		for i, notification := range due {
+			ids[i] = notification.ID
+		}
+		err = tx.Model(&models.Notification{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"attempts":        gorm.Expr("attempts + 1"),
+				"next_attempt_at": now.Add(lease),
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Where("id IN ?", ids).Order("next_attempt_at ASC, created_at ASC").Find(&claimed).Error
+	})
+
+	return claimed, err
+}
+
+// FinishDelivery records the outcome of a claimed delivery attempt. It returns false when
+// the attempt was superseded: its lease ran out and another worker claimed it.
+func (r *notificationRepository) FinishDelivery(ctx context.Context, notification *models.Notification) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.Notification{}).
+		Where("id = ? AND status = ? AND attempts = ?", notification.ID, models.NotificationStatusPending, notification.Attempts).
+		Updates(map[string]interface{}{
+			"status":          notification.Status,
+			"error":           notification.Error,
+			"sent_at":         notification.SentAt,
+			"next_attempt_at": notification.NextAttemptAt,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ReleaseDelivery hands a claimed notification back without counting the attempt, used
+// on shutdown
+func (r *notificationRepository) ReleaseDelivery(ctx context.Context, notification *models.Notification) error {
+	_, err := r.DeferDelivery(ctx, notification, time.Now())
+	return err
+}
+
+// DeferDelivery hands a claimed notification back without counting the attempt, to be
+// claimed again at the given time. Like FinishDelivery, it returns false when the
+// attempt was superseded.
+func (r *notificationRepository) DeferDelivery(ctx context.Context, notification *models.Notification, until time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.Notification{}).
+		Where("id = ? AND status = ? AND attempts = ?", notification.ID, models.NotificationStatusPending, notification.Attempts).
+		Updates(map[string]interface{}{
+			"attempts":        gorm.Expr("GREATEST(attempts - 1, 0)"),
+			"next_attempt_at": until,
+		})
+	return result.RowsAffected > 0, result.Error
+}
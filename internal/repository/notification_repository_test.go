@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"github.com/vyve/vyve-backend/internal/models"
+)
+
+func TestFinishDelivery(t *testing.T) {
+	tests := []struct {
+		name      string
+		affected  int64
+		wantOwned bool
+	}{
+		{"attempt still holds the lease", 1, true},
+		{"lease ran out and the notification was claimed again", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			repo := NewNotificationRepository(db)
+			notification := &models.Notification{Base: models.Base{ID: uuid.New()}, Status: models.NotificationStatusSent, Attempts: 2}
+
+			// The outcome is only written while the attempt count is still this attempt's
+			mock.ExpectBegin()
+			mock.ExpectExec(`UPDATE "notifications" SET .* WHERE \(id = \$\d+ AND status = \$\d+ AND attempts = \$\d+\)`).
+				WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), models.NotificationStatusSent, sqlmock.AnyArg(),
+					notification.ID, models.NotificationStatusPending, 2).
+				WillReturnResult(sqlmock.NewResult(0, tt.affected))
+			mock.ExpectCommit()
+
+			owned, err := repo.FinishDelivery(context.Background(), notification)
+			if err != nil {
+				t.Fatalf("FinishDelivery() error = %v", err)
+			}
+			if owned != tt.wantOwned {
+				t.Errorf("FinishDelivery() = %v, want %v", owned, tt.wantOwned)
+			}
+		})
+	}
+}
+
+func TestDeferDelivery(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewNotificationRepository(db)
+	notification := &models.Notification{Base: models.Base{ID: uuid.New()}, Attempts: 3}
+	until := time.Now().Add(8 * time.Hour)
+
+	// The claim's attempt is given back
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "notifications" SET "attempts"=GREATEST\(attempts - 1, 0\),"next_attempt_at"=\$1,.* WHERE \(id = \$\d+ AND status = \$\d+ AND attempts = \$\d+\)`).
+		WithArgs(until, sqlmock.AnyArg(), notification.ID, models.NotificationStatusPending, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	owned, err := repo.DeferDelivery(context.Background(), notification, until)
+	if err != nil || !owned {
+		t.Fatalf("DeferDelivery() = %v, %v, want it deferred", owned, err)
+	}
+}
+
+func TestMarkRead(t *testing.T) {
+	userID := uuid.New()
+	id := uuid.New()
+
+	tests := []struct {
+		name       string
+		owned      bool
+		openedAt   *time.Time
+		wantErr    error
+		wantUpdate bool
+	}{
+		{name: "own unread notification", owned: true, wantUpdate: true},
+		{name: "own notification already read", owned: true, openedAt: &time.Time{}},
+		{name: "another user's notification", wantErr: ErrNotificationNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			repo := NewNotificationRepository(db)
+
+			rows := sqlmock.NewRows([]string{"id", "user_id", "channel", "opened_at"})
+			if tt.owned {
+				rows.AddRow(id, userID, models.ChannelInApp, tt.openedAt)
+			}
+			mock.ExpectQuery(`SELECT \* FROM "notifications" WHERE \(id = \$1 AND user_id = \$2\)`).
+				WithArgs(id, userID, 1).
+				WillReturnRows(rows)
+			if tt.wantUpdate {
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE "notifications" SET "opened_at"=\$1,.* WHERE \(id = \$\d+ AND opened_at IS NULL\)`).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			}
+
+			notification, err := repo.MarkRead(context.Background(), userID, id)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("MarkRead() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && notification.OpenedAt == nil {
+				t.Error("notification not marked as read")
+			}
+		})
+	}
+}
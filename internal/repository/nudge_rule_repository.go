@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vyve/vyve-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+//go:generate mockgen -source=nudge_rule_repository.go -destination=mock_nudge_rule_repository.go -package=repository
+
+// NudgeRuleRepository handles nudge rule data access, for both the users' own rules and
+// the global rules managed by admins
+type NudgeRuleRepository interface {
+	Create(ctx context.Context, rule *models.NudgeRule) error
+	Update(ctx context.Context, rule *models.NudgeRule) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.NudgeRule, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.NudgeRule, error)
+	ListGlobal(ctx context.Context) ([]*models.NudgeRule, error)
+	GetEnabled(ctx context.Context, userID uuid.UUID) ([]*models.NudgeRule, error)
+	MarkFired(ctx context.Context, id uuid.UUID) error
+}
+
+type nudgeRuleRepository struct {
+	BaseRepository
+}
+
+// NewNudgeRuleRepository creates a new nudge rule repository
+func NewNudgeRuleRepository(db *gorm.DB) NudgeRuleRepository {
+	return &nudgeRuleRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Create creates a new nudge rule
+func (r *nudgeRuleRepository) Create(ctx context.Context, rule *models.NudgeRule) error {
+	return r.db.WithContext(ctx).Create(rule).Error
+}
+
+// Update updates a nudge rule
+func (r *nudgeRuleRepository) Update(ctx context.Context, rule *models.NudgeRule) error {
+	return r.db.WithContext(ctx).Save(rule).Error
+}
+
+// Delete soft deletes a nudge rule
+func (r *nudgeRuleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.NudgeRule{}, "id = ?", id).Error
+}
+
+// FindByID finds a nudge rule by ID
+func (r *nudgeRuleRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.NudgeRule, error) {
+	var rule models.NudgeRule
+	err := r.db.WithContext(ctx).First(&rule, "id = ?", id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrNudgeRuleNotFound
+		}
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// ListByUser lists all rules of a user in evaluation order
+func (r *nudgeRuleRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.NudgeRule, error) {
+	var rules []*models.NudgeRule
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&rules).Error
+	return rules, err
+}
+
+// ListGlobal lists all global rules in evaluation order
+func (r *nudgeRuleRepository) ListGlobal(ctx context.Context) ([]*models.NudgeRule, error) {
+	var rules []*models.NudgeRule
+	err := r.db.WithContext(ctx).
+		Where("user_id IS NULL").
+		Order("created_at ASC").
+		Find(&rules).Error
+	return rules, err
+}
+
+// GetEnabled gets the enabled rules that apply to a user in evaluation order: the user's
+// own rules first, then the global rules
+func (r *nudgeRuleRepository) GetEnabled(ctx context.Context, userID uuid.UUID) ([]*models.NudgeRule, error) {
+	var rules []*models.NudgeRule
+	err := r.db.WithContext(ctx).
+		Where("(user_id = ? OR user_id IS NULL) AND enabled = ?", userID, true).
+		Order("user_id IS NULL, created_at ASC").
+		Find(&rules).Error
+	return rules, err
+}
+
+// MarkFired records that a rule produced a nudge
+func (r *nudgeRuleRepository) MarkFired(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Model(&models.NudgeRule{}).
+		Where("id = ?", id).
+		Update("last_fired_at", time.Now()).Error
+}
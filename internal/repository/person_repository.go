@@ -6,6 +6,7 @@ import (
 	"errors"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -13,6 +14,8 @@ import (
 	"github.com/vyve/vyve-backend/internal/models"
 )
 
+//go:generate mockgen -source=person_repository.go -destination=mock_person_repository.go -package=repository
+
 // PersonRepository defines person data access interface
 type PersonRepository interface {
 	Create(ctx context.Context, person *models.Person) error
@@ -23,13 +26,16 @@ type PersonRepository interface {
 	List(ctx context.Context, opts FilterOptions) ([]*models.Person, *PaginationResult, error)
 	Search(ctx context.Context, userID uuid.UUID, query string, limit int) ([]*models.Person, error)
 	GetCategories(ctx context.Context, userID uuid.UUID) ([]string, error)
+	GetCategoryNames(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]string, error)
 	GetRecentInteractions(ctx context.Context, personID uuid.UUID, limit int) ([]*models.Interaction, error)
 	UpdateHealthScore(ctx context.Context, personID uuid.UUID, score float64) error
 	GetByCategory(ctx context.Context, userID uuid.UUID, category string) ([]*models.Person, error)
 	GetPeopleNeedingAttention(ctx context.Context, userID uuid.UUID) ([]*models.Person, error)
 	GetPeopleForReminders(ctx context.Context, userID uuid.UUID) ([]*models.Person, error)
+	SetNextReminder(ctx context.Context, personID uuid.UUID, at *time.Time) error
 	IncrementInteractionCount(ctx context.Context, personID uuid.UUID) error
 	UpdateLastInteraction(ctx context.Context, personID uuid.UUID) error
+	RecalculateInteractionStats(ctx context.Context, personID uuid.UUID) error
 }
 
 type personRepository struct {
@@ -184,6 +190,28 @@ func (r *personRepository) GetCategories(ctx context.Context, userID uuid.UUID)
 	return names, err
 }
 
+// GetCategoryNames maps the user's category IDs to their names
+func (r *personRepository) GetCategoryNames(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]string, error) {
+	var rows []struct {
+		ID   uuid.UUID
+		Name string
+	}
+	err := r.db.WithContext(ctx).
+		Table("categories").
+		Select("id, name").
+		Where("user_id = ?", userID).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[uuid.UUID]string, len(rows))
+	for _, row := range rows {
+		names[row.ID] = row.Name
+	}
+	return names, nil
+}
+
 // GetRecentInteractions gets recent interactions for a person
 func (r *personRepository) GetRecentInteractions(ctx context.Context, personID uuid.UUID, limit int) ([]*models.Interaction, error) {
 	var interactions []*models.Interaction
@@ -237,10 +265,20 @@ func (r *personRepository) GetPeopleForReminders(ctx context.Context, userID uui
 	var people []*models.Person
 	err := r.db.WithContext(ctx).
 		Where("user_id = ? AND next_reminder_at <= NOW()", userID).
+		Order("next_reminder_at ASC").
 		Find(&people).Error
 	return people, err
 }
 
+// SetNextReminder schedules a person's next reminder, or stops reminders when at is nil
+func (r *personRepository) SetNextReminder(ctx context.Context, personID uuid.UUID, at *time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Person{}).
+		Where("id = ?", personID).
+		UpdateColumn("next_reminder_at", at).
+		Error
+}
+
 // IncrementInteractionCount increments interaction count for a person
 func (r *personRepository) IncrementInteractionCount(ctx context.Context, personID uuid.UUID) error {
 	return r.db.WithContext(ctx).
@@ -258,3 +296,15 @@ func (r *personRepository) UpdateLastInteraction(ctx context.Context, personID u
 		UpdateColumn("last_interaction_at", gorm.Expr("NOW()")).
 		Error
 }
+
+// RecalculateInteractionStats sets the interaction count and last interaction time of a
+// person from their interactions, used after interactions are written in bulk
+func (r *personRepository) RecalculateInteractionStats(ctx context.Context, personID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Person{}).
+		Where("id = ?", personID).
+		UpdateColumns(map[string]interface{}{
+			"interaction_count":   gorm.Expr("(SELECT COUNT(*) FROM interactions WHERE person_id = ? AND deleted_at IS NULL)", personID),
+			"last_interaction_at": gorm.Expr("(SELECT MAX(interaction_at) FROM interactions WHERE person_id = ? AND deleted_at IS NULL)", personID),
+		}).Error
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"github.com/vyve/vyve-backend/internal/models"
+)
+
+func TestSavePushToken(t *testing.T) {
+	userID := uuid.New()
+	existingID := uuid.New()
+
+	tests := []struct {
+		name       string
+		deviceID   string
+		registered bool
+	}{
+		{"new token for a device", "device-1", false},
+		{"token registered again", "device-1", true},
+		{"token without a device", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			repo := NewUserRepository(db)
+			token := &models.PushToken{UserID: userID, Token: "fcm-token", Platform: "ios", DeviceID: tt.deviceID}
+
+			mock.ExpectBegin()
+			if tt.deviceID != "" {
+				// The device's previous token gives way to the new one
+				mock.ExpectExec(`DELETE FROM "push_tokens" WHERE user_id = \$1 AND device_id = \$2 AND token <> \$3`).
+					WithArgs(userID, tt.deviceID, "fcm-token").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+			rows := sqlmock.NewRows([]string{"id", "created_at", "user_id", "token"})
+			if tt.registered {
+				rows.AddRow(existingID, time.Now().Add(-24*time.Hour), uuid.New(), "fcm-token")
+			}
+			mock.ExpectQuery(`SELECT \* FROM "push_tokens" WHERE token = \$1`).
+				WithArgs("fcm-token", 1).
+				WillReturnRows(rows)
+			if tt.registered {
+				// Brought back, even if deleted, for this user and device
+				mock.ExpectExec(`UPDATE "push_tokens" SET .*"deleted_at"=\$\d+.* WHERE "id" = \$\d+`).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			} else {
+				mock.ExpectQuery(`INSERT INTO "push_tokens"`).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+			}
+			mock.ExpectCommit()
+
+			if err := repo.SavePushToken(context.Background(), token); err != nil {
+				t.Fatalf("SavePushToken() error = %v", err)
+			}
+			if !token.Active || token.LastUsedAt == nil {
+				t.Errorf("token not marked active and used: %+v", token)
+			}
+			if tt.registered && token.ID != existingID {
+				t.Errorf("token ID = %s, want the existing row's %s", token.ID, existingID)
+			}
+		})
+	}
+}
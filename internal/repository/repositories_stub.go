@@ -10,14 +10,18 @@ import (
 	"github.com/vyve/vyve-backend/internal/models"
 )
 
+//go:generate mockgen -source=repositories_stub.go -destination=mock_repositories_stub.go -package=repository
+
 // ReflectionRepository defines reflection data access interface
 type ReflectionRepository interface {
 	Create(ctx context.Context, reflection *models.Reflection) error
 	Update(ctx context.Context, reflection *models.Reflection) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	FindByID(ctx context.Context, id uuid.UUID) (*models.Reflection, error)
-	GetToday(ctx context.Context, userID uuid.UUID) (*models.Reflection, error)
+	FindForDay(ctx context.Context, userID uuid.UUID, dayStart, dayEnd time.Time) (*models.Reflection, error)
 	List(ctx context.Context, opts FilterOptions) ([]*models.Reflection, *PaginationResult, error)
+	GetCompletionTimes(ctx context.Context, userID uuid.UUID, since time.Time) ([]time.Time, error)
+	ListBetween(ctx context.Context, userID uuid.UUID, start, end time.Time) ([]*models.Reflection, error)
 }
 
 type reflectionRepository struct {
@@ -47,24 +51,27 @@ func (r *reflectionRepository) FindByID(ctx context.Context, id uuid.UUID) (*mod
 	var reflection models.Reflection
 	err := r.db.WithContext(ctx).First(&reflection, "id = ?", id).Error
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReflectionNotFound
+		}
 		return nil, err
 	}
 	return &reflection, nil
 }
 
-func (r *reflectionRepository) GetToday(ctx context.Context, userID uuid.UUID) (*models.Reflection, error) {
+// FindForDay returns the latest reflection completed in [dayStart, dayEnd). Callers pass
+// the boundaries of the user's local day.
+func (r *reflectionRepository) FindForDay(ctx context.Context, userID uuid.UUID, dayStart, dayEnd time.Time) (*models.Reflection, error) {
 	var reflection models.Reflection
-	today := time.Now().Truncate(24 * time.Hour)
-	tomorrow := today.Add(24 * time.Hour)
 
 	err := r.db.WithContext(ctx).
-		Where("user_id = ? AND created_at >= ? AND created_at < ?", userID, today, tomorrow).
-		Order("created_at DESC").
+		Where("user_id = ? AND completed_at >= ? AND completed_at < ?", userID, dayStart, dayEnd).
+		Order("completed_at DESC").
 		First(&reflection).Error
 
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, ErrNotFound
+			return nil, ErrReflectionNotFound
 		}
 		return nil, err
 	}
@@ -72,6 +79,33 @@ func (r *reflectionRepository) GetToday(ctx context.Context, userID uuid.UUID) (
 	return &reflection, nil
 }
 
+// GetCompletionTimes returns when each of the user's reflections completed since the
+// given time was completed, most recent first. A zero time returns the whole history.
+func (r *reflectionRepository) GetCompletionTimes(ctx context.Context, userID uuid.UUID, since time.Time) ([]time.Time, error) {
+	var times []time.Time
+	query := r.db.WithContext(ctx).
+		Model(&models.Reflection{}).
+		Where("user_id = ?", userID)
+	if !since.IsZero() {
+		query = query.Where("completed_at >= ?", since)
+	}
+	err := query.
+		Order("completed_at DESC").
+		Pluck("completed_at", &times).Error
+	return times, err
+}
+
+// ListBetween returns the user's reflections completed in [start, end), oldest first
+func (r *reflectionRepository) ListBetween(ctx context.Context, userID uuid.UUID, start, end time.Time) ([]*models.Reflection, error) {
+	var reflections []*models.Reflection
+	err := r.db.WithContext(ctx).
+		Select("id", "user_id", "mood", "energy_level", "completed_at").
+		Where("user_id = ? AND completed_at >= ? AND completed_at < ?", userID, start, end).
+		Order("completed_at ASC").
+		Find(&reflections).Error
+	return reflections, err
+}
+
 func (r *reflectionRepository) List(ctx context.Context, opts FilterOptions) ([]*models.Reflection, *PaginationResult, error) {
 	var reflections []*models.Reflection
 	query := r.db.WithContext(ctx).Model(&models.Reflection{})
@@ -81,12 +115,14 @@ func (r *reflectionRepository) List(ctx context.Context, opts FilterOptions) ([]
 		query = query.Where("user_id = ?", opts.UserID)
 	}
 
+	// Reflections are dated by when they were completed, which may differ from
+	// when they were saved
 	if opts.StartDate != nil {
-		query = query.Where("created_at >= ?", opts.StartDate)
+		query = query.Where("completed_at >= ?", opts.StartDate)
 	}
 
 	if opts.EndDate != nil {
-		query = query.Where("created_at <= ?", opts.EndDate)
+		query = query.Where("completed_at <= ?", opts.EndDate)
 	}
 
 	// Count total
@@ -106,7 +142,7 @@ func (r *reflectionRepository) List(ctx context.Context, opts FilterOptions) ([]
 	}
 
 	offset := (page - 1) * limit
-	err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&reflections).Error
+	err := query.Order("completed_at DESC").Limit(limit).Offset(offset).Find(&reflections).Error
 	if err != nil {
 		return nil, nil, err
 	}
@@ -128,6 +164,7 @@ type NudgeRepository interface {
 	Delete(ctx context.Context, id uuid.UUID) error
 	FindByID(ctx context.Context, id uuid.UUID) (*models.Nudge, error)
 	GetActive(ctx context.Context, userID uuid.UUID) ([]*models.Nudge, error)
+	HasPendingForPerson(ctx context.Context, personID uuid.UUID, source string) (bool, error)
 	MarkSeen(ctx context.Context, id uuid.UUID) error
 	MarkActedOn(ctx context.Context, id uuid.UUID) error
 	List(ctx context.Context, opts FilterOptions) ([]*models.Nudge, *PaginationResult, error)
@@ -175,6 +212,17 @@ func (r *nudgeRepository) GetActive(ctx context.Context, userID uuid.UUID) ([]*m
 	return nudges, err
 }
 
+// HasPendingForPerson reports whether a person already has an unexpired pending or seen nudge from the given source
+func (r *nudgeRepository) HasPendingForPerson(ctx context.Context, personID uuid.UUID, source string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Nudge{}).
+		Where("person_id = ? AND source = ? AND status IN (?, ?) AND (expires_at IS NULL OR expires_at > ?)",
+			personID, source, "pending", "seen", time.Now()).
+		Count(&count).Error
+	return count > 0, err
+}
+
 func (r *nudgeRepository) MarkSeen(ctx context.Context, id uuid.UUID) error {
 	return r.db.WithContext(ctx).
 		Model(&models.Nudge{}).
@@ -400,6 +448,8 @@ type DataExportRepository interface {
 	Update(ctx context.Context, export *models.DataExport) error
 	FindByID(ctx context.Context, id uuid.UUID) (*models.DataExport, error)
 	GetPending(ctx context.Context, userID uuid.UUID) (*models.DataExport, error)
+	GetExpired(ctx context.Context, now time.Time, limit int) ([]*models.DataExport, error)
+	FailStale(ctx context.Context, staleBefore time.Time) (int64, error)
 }
 
 type dataExportRepository struct {
@@ -425,6 +475,9 @@ func (r *dataExportRepository) FindByID(ctx context.Context, id uuid.UUID) (*mod
 	var export models.DataExport
 	err := r.db.WithContext(ctx).First(&export, "id = ?", id).Error
 	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrExportNotFound
+		}
 		return nil, err
 	}
 	return &export, nil
@@ -443,4 +496,29 @@ func (r *dataExportRepository) GetPending(ctx context.Context, userID uuid.UUID)
 		return nil, err
 	}
 	return &export, nil
+}
+
+// GetExpired returns completed exports whose files have expired, oldest first
+func (r *dataExportRepository) GetExpired(ctx context.Context, now time.Time, limit int) ([]*models.DataExport, error) {
+	var exports []*models.DataExport
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND expires_at < ?", "completed", now).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&exports).Error
+	return exports, err
+}
+
+// FailStale marks exports that are still pending or processing but were last updated
+// before staleBefore as failed. Their export was interrupted, and while they stay open
+// the user cannot request a new one.
+func (r *dataExportRepository) FailStale(ctx context.Context, staleBefore time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.DataExport{}).
+		Where("status IN ? AND updated_at < ?", []string{"pending", "processing"}, staleBefore).
+		Updates(map[string]interface{}{
+			"status": "failed",
+			"error":  "export did not finish",
+		})
+	return result.RowsAffected, result.Error
 }
\ No newline at end of file
@@ -11,31 +11,39 @@ import (
 
 // Repositories holds all repository instances
 type Repositories struct {
-	User        UserRepository
-	Person      PersonRepository
-	Interaction InteractionRepository
-	Reflection  ReflectionRepository
-	Nudge       NudgeRepository
-	Event       EventRepository
-	Consent     ConsentRepository
-	AuditLog    AuditLogRepository
-	DataExport  DataExportRepository
-	Analysis    AnalysisRepository
+	User         UserRepository
+	Person       PersonRepository
+	Interaction  InteractionRepository
+	Reflection   ReflectionRepository
+	Nudge        NudgeRepository
+	NudgeRule    NudgeRuleRepository
+	Event        EventRepository
+	Consent      ConsentRepository
+	AuditLog     AuditLogRepository
+	DataExport   DataExportRepository
+	Analysis     AnalysisRepository
+	Account      AccountRepository
+	Import       ImportRepository
+	Notification NotificationRepository
 }
 
 // NewRepositories creates new repository instances
 func NewRepositories(db *gorm.DB) *Repositories {
 	return &Repositories{
-		User:        NewUserRepository(db),
-		Person:      NewPersonRepository(db),
-		Interaction: NewInteractionRepository(db),
-		Reflection:  NewReflectionRepository(db),
-		Nudge:       NewNudgeRepository(db),
-		Event:       NewEventRepository(db),
-		Consent:     NewConsentRepository(db),
-		AuditLog:    NewAuditLogRepository(db),
-		DataExport:  NewDataExportRepository(db),
-		Analysis:    NewAnalysisRepository(db),
+		User:         NewUserRepository(db),
+		Person:       NewPersonRepository(db),
+		Interaction:  NewInteractionRepository(db),
+		Reflection:   NewReflectionRepository(db),
+		Nudge:        NewNudgeRepository(db),
+		NudgeRule:    NewNudgeRuleRepository(db),
+		Event:        NewEventRepository(db),
+		Consent:      NewConsentRepository(db),
+		AuditLog:     NewAuditLogRepository(db),
+		DataExport:   NewDataExportRepository(db),
+		Analysis:     NewAnalysisRepository(db),
+		Account:      NewAccountRepository(db),
+		Import:       NewImportRepository(db),
+		Notification: NewNotificationRepository(db),
 	}
 }
 
@@ -3,39 +3,70 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/vyve/vyve-backend/internal/models"
 )
 
+//go:generate mockgen -source=user_repository.go -destination=mock_user_repository.go -package=repository
+
 // UserRepository defines user data access interface
 type UserRepository interface {
 	Create(ctx context.Context, user *models.User) error
 	Update(ctx context.Context, user *models.User) error
+	UpdateFields(ctx context.Context, user *models.User, fields map[string]interface{}) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	FindByID(ctx context.Context, id uuid.UUID) (*models.User, error)
 	FindByEmail(ctx context.Context, email string) (*models.User, error)
 	FindByUsername(ctx context.Context, username string) (*models.User, error)
 	FindByAuthProvider(ctx context.Context, provider, providerID string) (*models.User, error)
-	List(ctx context.Context, opts FilterOptions) ([]*models.User, *PaginationResult, error)
+	List(ctx context.Context, opts UserFilterOptions) ([]*models.User, *PaginationResult, error)
+	Suspend(ctx context.Context, id uuid.UUID, reason string) (bool, error)
+	Unsuspend(ctx context.Context, id uuid.UUID) (bool, error)
+	AddRole(ctx context.Context, id uuid.UUID, role string) (bool, error)
+	RemoveRole(ctx context.Context, id uuid.UUID, role string) (bool, error)
 	UpdateLastLogin(ctx context.Context, id uuid.UUID) error
 	UpdateLastActivity(ctx context.Context, id uuid.UUID) error
 	UpdateStreak(ctx context.Context, id uuid.UUID, count int) error
+	SetStreak(ctx context.Context, id uuid.UUID, count, longest int, lastReflectionAt *time.Time) error
 	GetActiveUsers(ctx context.Context, since time.Time) ([]*models.User, error)
 	GetUsersForReminders(ctx context.Context, hour int) ([]*models.User, error)
 	SaveRefreshToken(ctx context.Context, token *models.RefreshToken) error
-	FindRefreshToken(ctx context.Context, token string) (*models.RefreshToken, error)
-	RevokeRefreshToken(ctx context.Context, token string) error
+	FindRefreshToken(ctx context.Context, tokenHash string) (*models.RefreshToken, error)
+	RotateRefreshToken(ctx context.Context, tokenHash string) (*models.RefreshToken, error)
+	RevokeRefreshToken(ctx context.Context, tokenHash string) error
 	RevokeAllUserTokens(ctx context.Context, userID uuid.UUID) error
+	ListActiveRefreshTokens(ctx context.Context, userID uuid.UUID) ([]*models.RefreshToken, error)
+	RevokeSessionTokens(ctx context.Context, userID uuid.UUID, sessionID string) (int64, error)
+	DeleteStaleRefreshTokens(ctx context.Context, revokedBefore time.Time) (int64, error)
+	CreateActionToken(ctx context.Context, token *models.ActionToken) error
+	ConsumeActionToken(ctx context.Context, purpose, tokenHash string) (*models.ActionToken, error)
+	MarkEmailVerified(ctx context.Context, id uuid.UUID, email string) (bool, error)
+	UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error
+	SetTOTPSecret(ctx context.Context, id uuid.UUID, secret string) error
+	EnableMFA(ctx context.Context, id uuid.UUID, recoveryCodeHashes []string) error
+	DisableMFA(ctx context.Context, id uuid.UUID) error
+	ReplaceRecoveryCodes(ctx context.Context, id uuid.UUID, recoveryCodeHashes []string) error
+	ConsumeRecoveryCode(ctx context.Context, id uuid.UUID, codeHash string) (bool, error)
+	CountRecoveryCodes(ctx context.Context, id uuid.UUID) (int64, error)
 	SavePushToken(ctx context.Context, token *models.PushToken) error
 	GetUserPushTokens(ctx context.Context, userID uuid.UUID) ([]*models.PushToken, error)
-	DeactivatePushToken(ctx context.Context, token string) error
+	DeactivatePushToken(ctx context.Context, userID uuid.UUID, token string) error
+	DeactivatePushTokens(ctx context.Context, tokens []string) error
+	TouchPushTokens(ctx context.Context, tokens []string) error
 	LinkAuthProvider(ctx context.Context, provider *models.AuthProvider) error
 	UnlinkAuthProvider(ctx context.Context, userID uuid.UUID, provider string) error
 	GetAuthProviders(ctx context.Context, userID uuid.UUID) ([]*models.AuthProvider, error)
+	CreatePasskey(ctx context.Context, passkey *models.Passkey) error
+	FindPasskey(ctx context.Context, credentialID string) (*models.Passkey, error)
+	ListPasskeys(ctx context.Context, userID uuid.UUID) ([]*models.Passkey, error)
+	UpdatePasskeyUsage(ctx context.Context, id uuid.UUID, signCount int64, backedUp bool) error
+	DeletePasskey(ctx context.Context, userID, id uuid.UUID) error
 	CheckUsernameExists(ctx context.Context, username string) (bool, error)
 	CheckEmailExists(ctx context.Context, email string) (bool, error)
 	SearchUsers(ctx context.Context, query string, limit int) ([]*models.User, error)
@@ -63,6 +94,12 @@ func (r *userRepository) Update(ctx context.Context, user *models.User) error {
 	return r.db.WithContext(ctx).Save(user).Error
 }
 
+// UpdateFields writes only the given columns of a user, so a concurrent change to any
+// other column isn't overwritten
+func (r *userRepository) UpdateFields(ctx context.Context, user *models.User, fields map[string]interface{}) error {
+	return r.db.WithContext(ctx).Model(user).Updates(fields).Error
+}
+
 // Delete soft deletes a user
 func (r *userRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return r.db.WithContext(ctx).Delete(&models.User{}, "id = ?", id).Error
@@ -129,10 +166,79 @@ func (r *userRepository) FindByAuthProvider(ctx context.Context, provider, provi
 	return r.FindByID(ctx, authProvider.UserID)
 }
 
-// List lists users with pagination
-func (r *userRepository) List(ctx context.Context, opts FilterOptions) ([]*models.User, *PaginationResult, error) {
+// UserFilterOptions filters the users listed to admins. StartDate and EndDate bound the
+// signup date and Search matches the username, email or display name.
+type UserFilterOptions struct {
+	FilterOptions
+	ActiveAfter  *time.Time
+	ActiveBefore *time.Time
+	Provider     string // "password", "passkey" or a linked provider such as "google"
+	Role         string
+	Suspended    *bool
+}
+
+// userOrderColumns are the columns users can be listed by
+var userOrderColumns = map[string]bool{
+	"created_at":       true,
+	"last_activity_at": true,
+	"last_login_at":    true,
+	"username":         true,
+	"email":            true,
+}
+
+// List lists users with pagination, newest first unless ordered otherwise
+func (r *userRepository) List(ctx context.Context, opts UserFilterOptions) ([]*models.User, *PaginationResult, error) {
 	query := r.db.WithContext(ctx).Model(&models.User{})
-	query = ApplyFilters(query, opts)
+
+	if opts.Search != "" {
+		query = searchUsers(query, opts.Search)
+	}
+	if opts.StartDate != nil {
+		query = query.Where("created_at >= ?", opts.StartDate)
+	}
+	if opts.EndDate != nil {
+		query = query.Where("created_at <= ?", opts.EndDate)
+	}
+	if opts.ActiveAfter != nil {
+		query = query.Where("last_activity_at >= ?", opts.ActiveAfter)
+	}
+	if opts.ActiveBefore != nil {
+		query = query.Where("last_activity_at <= ?", opts.ActiveBefore)
+	}
+	if opts.Role != "" {
+		query = query.Where("? = ANY(roles)", opts.Role)
+	}
+	if opts.Suspended != nil {
+		if *opts.Suspended {
+			query = query.Where("suspended_at IS NOT NULL")
+		} else {
+			query = query.Where("suspended_at IS NULL")
+		}
+	}
+	switch opts.Provider {
+	case "":
+	case "password":
+		query = query.Where("password_hash <> ''")
+	case "passkey":
+		query = query.Where("id IN (?)", r.db.Model(&models.Passkey{}).Select("user_id"))
+	default:
+		query = query.Where("id IN (?)", r.db.Model(&models.AuthProvider{}).Select("user_id").Where("provider = ?", opts.Provider))
+	}
+
+	orderBy := opts.OrderBy
+	if orderBy == "" {
+		orderBy, opts.Desc = "created_at", true
+	}
+	if !userOrderColumns[orderBy] {
+		return nil, nil, fmt.Errorf("%w: cannot order users by %q", ErrInvalidInput, orderBy)
+	}
+	if opts.Desc {
+		query = query.Order(orderBy + " DESC NULLS LAST")
+	} else {
+		query = query.Order(orderBy + " ASC NULLS LAST")
+	}
+	// Keep pages stable when many users share a value
+	query = query.Order("id ASC")
 
 	var users []*models.User
 	result, err := Paginate(ctx, query, opts.Page, opts.Limit, &users)
@@ -143,6 +249,79 @@ func (r *userRepository) List(ctx context.Context, opts FilterOptions) ([]*model
 	return users, result, nil
 }
 
+// Suspend marks a user as suspended. It returns false when the user already was.
+func (r *userRepository) Suspend(ctx context.Context, id uuid.UUID, reason string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.User{}).
+		Where("id = ? AND suspended_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"suspended_at":      time.Now(),
+			"suspension_reason": reason,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Unsuspend lifts a user's suspension. It returns false when the user wasn't suspended.
+func (r *userRepository) Unsuspend(ctx context.Context, id uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.User{}).
+		Where("id = ? AND suspended_at IS NOT NULL", id).
+		Updates(map[string]interface{}{
+			"suspended_at":      nil,
+			"suspension_reason": "",
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// AddRole grants a role to a user. It returns false when the user already had it.
+func (r *userRepository) AddRole(ctx context.Context, id uuid.UUID, role string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.User{}).
+		Where("id = ? AND NOT (? = ANY(roles))", id, role).
+		Update("roles", gorm.Expr("array_append(roles, ?)", role))
+	return result.RowsAffected > 0, result.Error
+}
+
+// RemoveRole revokes a role from a user. It returns false when the user did not have it,
+// and ErrLastAdmin rather than take the admin role from the last admin. The admins are
+// locked while they are counted, so concurrent revocations cannot remove them all.
+func (r *userRepository) RemoveRole(ctx context.Context, id uuid.UUID, role string) (bool, error) {
+	var changed bool
+
+	err := r.Transaction(ctx, func(tx *gorm.DB) error {
+		if role == models.RoleAdmin {
+			if err := ensureNotLastAdmin(tx, id); err != nil {
+				return err
+			}
+		}
+
+		result := tx.Model(&models.User{}).
+			Where("id = ? AND ? = ANY(roles)", id, role).
+			Update("roles", gorm.Expr("array_remove(roles, ?)", role))
+		changed = result.RowsAffected > 0
+		return result.Error
+	})
+
+	return changed, err
+}
+
+// ensureNotLastAdmin returns ErrLastAdmin when the user is the only admin. The admins
+// stay locked until tx ends, so concurrent calls cannot remove every admin.
+func ensureNotLastAdmin(tx *gorm.DB, id uuid.UUID) error {
+	var admins []uuid.UUID
+	err := tx.Model(&models.User{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("? = ANY(roles)", models.RoleAdmin).
+		Pluck("id", &admins).Error
+	if err != nil {
+		return err
+	}
+	if len(admins) == 1 && admins[0] == id {
+		return ErrLastAdmin
+	}
+	return nil
+}
+
 // UpdateLastLogin updates user's last login time
 func (r *userRepository) UpdateLastLogin(ctx context.Context, id uuid.UUID) error {
 	now := time.Now()
@@ -174,6 +353,19 @@ func (r *userRepository) UpdateStreak(ctx context.Context, id uuid.UUID, count i
 		}).Error
 }
 
+// SetStreak stores a recomputed streak and the longest one together with the time of
+// the latest reflection
+func (r *userRepository) SetStreak(ctx context.Context, id uuid.UUID, count, longest int, lastReflectionAt *time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.User{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"streak_count":       count,
+			"longest_streak":     longest,
+			"last_reflection_at": lastReflectionAt,
+		}).Error
+}
+
 // GetActiveUsers gets users active since a given time
 func (r *userRepository) GetActiveUsers(ctx context.Context, since time.Time) ([]*models.User, error) {
 	var users []*models.User
@@ -183,19 +375,22 @@ func (r *userRepository) GetActiveUsers(ctx context.Context, since time.Time) ([
 	return users, err
 }
 
-// GetUsersForReminders gets users for reminder notifications
-func (r *userRepository) GetUsersForReminders(ctx context.Context, hour int) ([]*models.User, error) {
+// GetUsersForReminders gets the users with people due a reminder whose reminder hour has
+// come today in their timezone. The hour is the reminder_hour setting, or defaultHour
+// for users who haven't chosen one. Suspended users and users without an active push
+// token are left out.
+func (r *userRepository) GetUsersForReminders(ctx context.Context, defaultHour int) ([]*models.User, error) {
 	var users []*models.User
-	
-	// Get users based on their timezone where it's the specified hour
 	err := r.db.WithContext(ctx).
-		Joins("JOIN push_tokens ON push_tokens.user_id = users.id").
-		Where("push_tokens.active = ?", true).
-		Where("DATE_PART('hour', NOW() AT TIME ZONE users.timezone) = ?", hour).
-		Where("users.last_reflection_at < CURRENT_DATE OR users.last_reflection_at IS NULL").
-		Group("users.id").
+		Where("users.suspended_at IS NULL").
+		// An unknown timezone would fail the whole query
+		Where("users.timezone IN (SELECT name FROM pg_timezone_names)").
+		Where("EXISTS (SELECT 1 FROM push_tokens WHERE push_tokens.user_id = users.id AND push_tokens.active AND push_tokens.deleted_at IS NULL)").
+		Where("EXISTS (SELECT 1 FROM people WHERE people.user_id = users.id AND people.next_reminder_at <= NOW() AND people.deleted_at IS NULL)").
+		Where(`DATE_PART('hour', NOW() AT TIME ZONE users.timezone) >= CASE
+			WHEN users.settings->>'reminder_hour' ~ '^([01]?[0-9]|2[0-3])$' THEN (users.settings->>'reminder_hour')::int
+			ELSE ? END`, defaultHour).
 		Find(&users).Error
-		
 	return users, err
 }
 
@@ -204,11 +399,11 @@ func (r *userRepository) SaveRefreshToken(ctx context.Context, token *models.Ref
 	return r.db.WithContext(ctx).Create(token).Error
 }
 
-// FindRefreshToken finds a refresh token
-func (r *userRepository) FindRefreshToken(ctx context.Context, token string) (*models.RefreshToken, error) {
+// FindRefreshToken finds an active refresh token by its hash
+func (r *userRepository) FindRefreshToken(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
 	var refreshToken models.RefreshToken
 	err := r.db.WithContext(ctx).
-		Where("token = ? AND revoked = ? AND expires_at > ?", token, false, time.Now()).
+		Where("token_hash = ? AND revoked = ? AND expires_at > ?", tokenHash, false, time.Now()).
 		First(&refreshToken).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -219,11 +414,50 @@ func (r *userRepository) FindRefreshToken(ctx context.Context, token string) (*m
 	return &refreshToken, nil
 }
 
+// RotateRefreshToken marks an active refresh token as used so it can be exchanged only
+// once. A token that was already exchanged is returned together with ErrTokenReused.
+func (r *userRepository) RotateRefreshToken(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	now := time.Now()
+	result := r.db.WithContext(ctx).
+		Model(&token).
+		Clauses(clause.Returning{}).
+		Where("token_hash = ? AND revoked = ? AND used_at IS NULL AND expires_at > ?", tokenHash, false, now).
+		Updates(map[string]interface{}{
+			"used_at":    now,
+			"revoked":    true,
+			"revoked_at": now,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return &token, nil
+	}
+
+	// Tell a replayed token apart from an unknown, expired or signed-out one
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTokenInvalid
+		}
+		return nil, err
+	}
+	switch {
+	case token.UsedAt != nil:
+		return &token, ErrTokenReused
+	case !token.ExpiresAt.After(now):
+		return nil, ErrTokenExpired
+	default:
+		return nil, ErrTokenRevoked
+	}
+}
+
 // RevokeRefreshToken revokes a refresh token
-func (r *userRepository) RevokeRefreshToken(ctx context.Context, token string) error {
+func (r *userRepository) RevokeRefreshToken(ctx context.Context, tokenHash string) error {
 	return r.db.WithContext(ctx).
 		Model(&models.RefreshToken{}).
-		Where("token = ?", token).
+		Where("token_hash = ?", tokenHash).
 		Updates(map[string]interface{}{
 			"revoked":    true,
 			"revoked_at": time.Now(),
@@ -241,21 +475,225 @@ func (r *userRepository) RevokeAllUserTokens(ctx context.Context, userID uuid.UU
 		}).Error
 }
 
-// SavePushToken saves a push notification token
-func (r *userRepository) SavePushToken(ctx context.Context, token *models.PushToken) error {
-	// Deactivate existing tokens for the same device
-	if token.DeviceID != "" {
-		r.db.WithContext(ctx).
-			Model(&models.PushToken{}).
-			Where("user_id = ? AND device_id = ?", token.UserID, token.DeviceID).
-			Update("active", false)
+// ListActiveRefreshTokens returns the user's unrevoked, unexpired refresh tokens, newest first
+func (r *userRepository) ListActiveRefreshTokens(ctx context.Context, userID uuid.UUID) ([]*models.RefreshToken, error) {
+	var tokens []*models.RefreshToken
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked = ? AND expires_at > ?", userID, false, time.Now()).
+		Order("created_at DESC").
+		Find(&tokens).Error
+	return tokens, err
+}
+
+// RevokeSessionTokens revokes the refresh tokens of one session and returns how many
+// were active
+func (r *userRepository) RevokeSessionTokens(ctx context.Context, userID uuid.UUID, sessionID string) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.RefreshToken{}).
+		Where("user_id = ? AND session_id = ? AND revoked = ?", userID, sessionID, false).
+		Updates(map[string]interface{}{
+			"revoked":    true,
+			"revoked_at": time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
+
+// DeleteStaleRefreshTokens deletes expired refresh tokens and tokens signed out before
+// revokedBefore. Rotated tokens are kept until they expire so reuse is still detected.
+func (r *userRepository) DeleteStaleRefreshTokens(ctx context.Context, revokedBefore time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Unscoped().
+		Where("expires_at < ? OR (revoked = ? AND used_at IS NULL AND revoked_at < ?)", time.Now(), true, revokedBefore).
+		Delete(&models.RefreshToken{})
+	return result.RowsAffected, result.Error
+}
+
+// CreateActionToken stores a new action token and invalidates the user's unused tokens
+// for the same purpose, so only the most recent email link works
+func (r *userRepository) CreateActionToken(ctx context.Context, token *models.ActionToken) error {
+	return r.Transaction(ctx, func(tx *gorm.DB) error {
+		err := tx.Model(&models.ActionToken{}).
+			Where("user_id = ? AND purpose = ? AND used_at IS NULL", token.UserID, token.Purpose).
+			Update("expires_at", time.Now()).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(token).Error
+	})
+}
+
+// ConsumeActionToken atomically marks an unused, unexpired token as used and returns it.
+// Returns ErrTokenInvalid when no such token exists.
+func (r *userRepository) ConsumeActionToken(ctx context.Context, purpose, tokenHash string) (*models.ActionToken, error) {
+	var token models.ActionToken
+	now := time.Now()
+	result := r.db.WithContext(ctx).
+		Model(&token).
+		Clauses(clause.Returning{}).
+		Where("purpose = ? AND token_hash = ? AND used_at IS NULL AND expires_at > ?", purpose, tokenHash, now).
+		Update("used_at", now)
+	if result.Error != nil {
+		return nil, result.Error
 	}
-	
-	// Upsert the new token
+	if result.RowsAffected == 0 {
+		return nil, ErrTokenInvalid
+	}
+	return &token, nil
+}
+
+// MarkEmailVerified marks the user's email as verified if it is still the given address.
+// It returns false when the user has changed their email since.
+func (r *userRepository) MarkEmailVerified(ctx context.Context, id uuid.UUID, email string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.User{}).
+		Where("id = ? AND LOWER(email) = LOWER(?)", id, email).
+		Update("email_verified", true)
+	return result.RowsAffected > 0, result.Error
+}
+
+// UpdatePassword sets a new password hash
+func (r *userRepository) UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error {
 	return r.db.WithContext(ctx).
-		Where("token = ?", token.Token).
-		Assign(token).
-		FirstOrCreate(token).Error
+		Model(&models.User{}).
+		Where("id = ?", id).
+		Update("password_hash", passwordHash).Error
+}
+
+// SetTOTPSecret stores the secret of a new authenticator enrollment, replacing any
+// unconfirmed one. Returns ErrMFAAlreadyEnabled once two-factor authentication is on.
+func (r *userRepository) SetTOTPSecret(ctx context.Context, id uuid.UUID, secret string) error {
+	// Updating through the struct applies the encrypted serializer
+	result := r.db.WithContext(ctx).
+		Model(&models.User{}).
+		Where("id = ? AND mfa_enabled_at IS NULL", id).
+		Select("totp_secret").
+		Updates(&models.User{TOTPSecret: secret})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrMFAAlreadyEnabled
+	}
+	return nil
+}
+
+// EnableMFA turns on two-factor authentication with the enrolled secret and replaces
+// the user's recovery codes
+func (r *userRepository) EnableMFA(ctx context.Context, id uuid.UUID, recoveryCodeHashes []string) error {
+	return r.Transaction(ctx, func(tx *gorm.DB) error {
+		result := tx.Model(&models.User{}).
+			Where("id = ? AND mfa_enabled_at IS NULL AND totp_secret IS NOT NULL AND totp_secret <> ''", id).
+			Update("mfa_enabled_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrMFAAlreadyEnabled
+		}
+		return replaceRecoveryCodes(tx, id, recoveryCodeHashes)
+	})
+}
+
+// DisableMFA turns off two-factor authentication and removes the secret and recovery codes
+func (r *userRepository) DisableMFA(ctx context.Context, id uuid.UUID) error {
+	return r.Transaction(ctx, func(tx *gorm.DB) error {
+		result := tx.Model(&models.User{}).
+			Where("id = ? AND mfa_enabled_at IS NOT NULL", id).
+			Select("totp_secret", "mfa_enabled_at").
+			Updates(&models.User{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrMFANotEnabled
+		}
+		return tx.Unscoped().Where("user_id = ?", id).Delete(&models.MFARecoveryCode{}).Error
+	})
+}
+
+// ReplaceRecoveryCodes swaps the user's recovery codes for new ones
+func (r *userRepository) ReplaceRecoveryCodes(ctx context.Context, id uuid.UUID, recoveryCodeHashes []string) error {
+	return r.Transaction(ctx, func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, id, recoveryCodeHashes)
+	})
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID uuid.UUID, codeHashes []string) error {
+	if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&models.MFARecoveryCode{}).Error; err != nil {
+		return err
+	}
+	codes := make([]models.MFARecoveryCode, len(codeHashes))
+	for i, hash := range codeHashes {
+		codes[i] = models.MFARecoveryCode{UserID: userID, CodeHash: hash}
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+	return tx.Create(&codes).Error
+}
+
+// ConsumeRecoveryCode marks an unused recovery code as used. It returns false when the
+// user has no such unused code.
+func (r *userRepository) ConsumeRecoveryCode(ctx context.Context, id uuid.UUID, codeHash string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.MFARecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", id, codeHash).
+		Update("used_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+// CountRecoveryCodes returns how many unused recovery codes the user has left
+func (r *userRepository) CountRecoveryCodes(ctx context.Context, id uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.MFARecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", id).
+		Count(&count).Error
+	return count, err
+}
+
+// SavePushToken registers a device's push token and marks it active and used now. A
+// device keeps one token: registering a new one for the same device replaces the old,
+// and a token registered again follows the user and device it now belongs to.
+func (r *userRepository) SavePushToken(ctx context.Context, token *models.PushToken) error {
+	now := time.Now()
+	token.Active = true
+	token.LastUsedAt = &now
+
+	return r.Transaction(ctx, func(tx *gorm.DB) error {
+		if token.DeviceID != "" {
+			err := tx.Unscoped().
+				Where("user_id = ? AND device_id = ? AND token <> ?", token.UserID, token.DeviceID, token.Token).
+				Delete(&models.PushToken{}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		// Deleted tokens still hold the unique key, so they are brought back
+		var existing models.PushToken
+		err := tx.Unscoped().Where("token = ?", token.Token).First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return tx.Create(token).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		token.ID = existing.ID
+		token.CreatedAt = existing.CreatedAt
+		return tx.Unscoped().
+			Model(&existing).
+			Updates(map[string]interface{}{
+				"user_id":      token.UserID,
+				"platform":     token.Platform,
+				"device_id":    token.DeviceID,
+				"device_info":  token.DeviceInfo,
+				"active":       true,
+				"last_used_at": now,
+				"deleted_at":   nil,
+			}).Error
+	})
 }
 
 // GetUserPushTokens gets user's active push tokens
@@ -267,39 +705,91 @@ func (r *userRepository) GetUserPushTokens(ctx context.Context, userID uuid.UUID
 	return tokens, err
 }
 
-// DeactivatePushToken deactivates a push token
-func (r *userRepository) DeactivatePushToken(ctx context.Context, token string) error {
+// DeactivatePushToken deactivates one of the user's push tokens
+func (r *userRepository) DeactivatePushToken(ctx context.Context, userID uuid.UUID, token string) error {
+	result := r.db.WithContext(ctx).
+		Model(&models.PushToken{}).
+		Where("user_id = ? AND token = ? AND active = ?", userID, token, true).
+		Update("active", false)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// DeactivatePushTokens deactivates push tokens whatever user they belong to, such as
+// the ones FCM no longer accepts
+func (r *userRepository) DeactivatePushTokens(ctx context.Context, tokens []string) error {
+	if len(tokens) == 0 {
+		return nil
+	}
 	return r.db.WithContext(ctx).
 		Model(&models.PushToken{}).
-		Where("token = ?", token).
+		Where("token IN ?", tokens).
 		Update("active", false).Error
 }
 
+// TouchPushTokens records that notifications were just delivered to push tokens
+func (r *userRepository) TouchPushTokens(ctx context.Context, tokens []string) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Model(&models.PushToken{}).
+		Where("token IN ?", tokens).
+		Update("last_used_at", time.Now()).Error
+}
+
 // LinkAuthProvider links an auth provider to a user
 func (r *userRepository) LinkAuthProvider(ctx context.Context, provider *models.AuthProvider) error {
-	return r.db.WithContext(ctx).Create(provider).Error
+	return r.Transaction(ctx, func(tx *gorm.DB) error {
+		// Links removed before unlinking became a hard delete still hold the unique key
+		err := tx.Unscoped().
+			Where("provider = ? AND provider_id = ? AND deleted_at IS NOT NULL", provider.Provider, provider.ProviderID).
+			Delete(&models.AuthProvider{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(provider).Error
+	})
 }
 
-// UnlinkAuthProvider unlinks an auth provider from a user
+// UnlinkAuthProvider unlinks an auth provider from a user. It refuses to remove the
+// user's last way to sign in.
 func (r *userRepository) UnlinkAuthProvider(ctx context.Context, userID uuid.UUID, provider string) error {
-	// Check if user has other auth methods
-	var count int64
-	r.db.WithContext(ctx).
-		Model(&models.AuthProvider{}).
-		Where("user_id = ?", userID).
-		Count(&count)
-	
-	// Also check if user has password
-	var user models.User
-	r.db.WithContext(ctx).First(&user, userID)
-	
-	if count <= 1 && user.PasswordHash == "" {
-		return ErrLastAuthMethod
-	}
-	
-	return r.db.WithContext(ctx).
-		Where("user_id = ? AND provider = ?", userID, provider).
-		Delete(&models.AuthProvider{}).Error
+	return r.Transaction(ctx, func(tx *gorm.DB) error {
+		// Lock the user so concurrent unlinks can't remove both remaining methods
+		var user models.User
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, "id = ?", userID).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+
+		others, err := countSignInMethods(tx, userID, provider, uuid.Nil)
+		if err != nil {
+			return err
+		}
+		if others == 0 && user.PasswordHash == "" {
+			return ErrLastAuthMethod
+		}
+
+		result := tx.Unscoped().
+			Where("user_id = ? AND provider = ?", userID, provider).
+			Delete(&models.AuthProvider{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
 }
 
 // GetAuthProviders gets user's auth providers
@@ -311,6 +801,102 @@ func (r *userRepository) GetAuthProviders(ctx context.Context, userID uuid.UUID)
 	return providers, err
 }
 
+// CreatePasskey stores a newly registered passkey
+func (r *userRepository) CreatePasskey(ctx context.Context, passkey *models.Passkey) error {
+	return r.db.WithContext(ctx).Create(passkey).Error
+}
+
+// FindPasskey finds a passkey by its credential ID
+func (r *userRepository) FindPasskey(ctx context.Context, credentialID string) (*models.Passkey, error) {
+	var passkey models.Passkey
+	err := r.db.WithContext(ctx).
+		Where("credential_id = ?", credentialID).
+		First(&passkey).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPasskeyNotFound
+		}
+		return nil, err
+	}
+	return &passkey, nil
+}
+
+// ListPasskeys returns the user's passkeys, oldest first
+func (r *userRepository) ListPasskeys(ctx context.Context, userID uuid.UUID) ([]*models.Passkey, error) {
+	var passkeys []*models.Passkey
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&passkeys).Error
+	return passkeys, err
+}
+
+// UpdatePasskeyUsage records a sign-in with a passkey
+func (r *userRepository) UpdatePasskeyUsage(ctx context.Context, id uuid.UUID, signCount int64, backedUp bool) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Passkey{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"sign_count":   signCount,
+			"backed_up":    backedUp,
+			"last_used_at": time.Now(),
+		}).Error
+}
+
+// DeletePasskey removes one of the user's passkeys. Like UnlinkAuthProvider, it refuses
+// to remove the user's last way to sign in.
+func (r *userRepository) DeletePasskey(ctx context.Context, userID, id uuid.UUID) error {
+	return r.Transaction(ctx, func(tx *gorm.DB) error {
+		var user models.User
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, "id = ?", userID).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+
+		others, err := countSignInMethods(tx, userID, "", id)
+		if err != nil {
+			return err
+		}
+		if others == 0 && user.PasswordHash == "" {
+			return ErrLastAuthMethod
+		}
+
+		// Hard delete so the credential can be registered again
+		result := tx.Unscoped().
+			Where("user_id = ? AND id = ?", userID, id).
+			Delete(&models.Passkey{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrPasskeyNotFound
+		}
+		return nil
+	})
+}
+
+// countSignInMethods counts the user's linked providers and passkeys, leaving out the
+// provider or passkey about to be removed
+func countSignInMethods(tx *gorm.DB, userID uuid.UUID, exceptProvider string, exceptPasskey uuid.UUID) (int64, error) {
+	var providers, passkeys int64
+	err := tx.Model(&models.AuthProvider{}).
+		Where("user_id = ? AND provider <> ?", userID, exceptProvider).
+		Count(&providers).Error
+	if err != nil {
+		return 0, err
+	}
+	err = tx.Model(&models.Passkey{}).
+		Where("user_id = ? AND id <> ?", userID, exceptPasskey).
+		Count(&passkeys).Error
+	if err != nil {
+		return 0, err
+	}
+	return providers + passkeys, nil
+}
+
 // CheckUsernameExists checks if username exists
 func (r *userRepository) CheckUsernameExists(ctx context.Context, username string) (bool, error) {
 	var count int64
@@ -334,14 +920,18 @@ func (r *userRepository) CheckEmailExists(ctx context.Context, email string) (bo
 // SearchUsers searches users by query
 func (r *userRepository) SearchUsers(ctx context.Context, query string, limit int) ([]*models.User, error) {
 	var users []*models.User
-	err := r.db.WithContext(ctx).
-		Where("username ILIKE ? OR email ILIKE ? OR display_name ILIKE ?",
-			"%"+query+"%", "%"+query+"%", "%"+query+"%").
+	err := searchUsers(r.db.WithContext(ctx), query).
 		Limit(limit).
 		Find(&users).Error
 	return users, err
 }
 
+// searchUsers matches users whose username, email or display name contains the query
+func searchUsers(db *gorm.DB, query string) *gorm.DB {
+	pattern := "%" + query + "%"
+	return db.Where("(username ILIKE ? OR email ILIKE ? OR display_name ILIKE ?)", pattern, pattern, pattern)
+}
+
 // GetUserStats gets user statistics
 func (r *userRepository) GetUserStats(ctx context.Context, userID uuid.UUID) (map[string]interface{}, error) {
 	stats := make(map[string]interface{})
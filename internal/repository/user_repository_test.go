@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"github.com/vyve/vyve-backend/internal/models"
+)
+
+func TestRemoveRole(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name        string
+		role        string
+		admins      []uuid.UUID
+		wantErr     error
+		wantUpdate  bool
+		wantChanged bool
+	}{
+		{"last admin", models.RoleAdmin, []uuid.UUID{userID}, ErrLastAdmin, false, false},
+		{"another admin left", models.RoleAdmin, []uuid.UUID{userID, uuid.New()}, nil, true, true},
+		{"not an admin", models.RoleAdmin, []uuid.UUID{uuid.New()}, nil, true, false},
+		{"other role", "support", nil, nil, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			repo := NewUserRepository(db)
+
+			mock.ExpectBegin()
+			if tt.role == models.RoleAdmin {
+				rows := sqlmock.NewRows([]string{"id"})
+				for _, id := range tt.admins {
+					rows.AddRow(id)
+				}
+				mock.ExpectQuery(`SELECT "id" FROM "users" WHERE .*ANY\(roles\).* FOR UPDATE`).
+					WithArgs(tt.role).
+					WillReturnRows(rows)
+			}
+			if tt.wantUpdate {
+				affected := int64(0)
+				if tt.wantChanged {
+					affected = 1
+				}
+				mock.ExpectExec(`UPDATE "users" SET "roles"=array_remove\(roles, \$1\)`).
+					WillReturnResult(sqlmock.NewResult(0, affected))
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			changed, err := repo.RemoveRole(context.Background(), userID, tt.role)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if changed != tt.wantChanged {
+				t.Errorf("changed = %v, want %v", changed, tt.wantChanged)
+			}
+		})
+	}
+}
+
+func TestUpdateFieldsWritesOnlyGivenColumns(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewUserRepository(db)
+	user := &models.User{Base: models.Base{ID: uuid.New()}, Email: "sam@example.com", DisplayName: "Sam"}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "users" SET "bio"=\$1,"updated_at"=\$2 WHERE .*"id" = \$3`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := repo.UpdateFields(context.Background(), user, map[string]interface{}{"bio": "Hello"}); err != nil {
+		t.Fatalf("UpdateFields() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUpdateFieldsNotificationPreferences(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewUserRepository(db)
+	user := &models.User{Base: models.Base{ID: uuid.New()}}
+	prefs := models.DefaultNotificationPreferences()
+	prefs.DigestFrequency = models.DigestWeekly
+
+	// Written as JSON even though a map of columns skips the field's serializer
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "users" SET "notification_preferences"=\$1,"updated_at"=\$2 WHERE .*"id" = \$3`).
+		WithArgs(jsonContaining(`"digest_frequency":"weekly"`), sqlmock.AnyArg(), user.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.UpdateFields(context.Background(), user, map[string]interface{}{"notification_preferences": &prefs})
+	if err != nil {
+		t.Fatalf("UpdateFields() error = %v", err)
+	}
+}
+
+// jsonContaining matches a JSON argument containing the given text
+type jsonContaining string
+
+func (j jsonContaining) Match(v driver.Value) bool {
+	b, ok := v.([]byte)
+	return ok && strings.Contains(string(b), string(j))
+}
@@ -26,6 +26,7 @@ type Handlers struct {
 	Onboarding  handlers.OnboardingHandler
 	Dictionary  handlers.DictionaryHandler
 	Analysis    handlers.AnalysisHandler
+	Import      handlers.ImportHandler
 }
 
 // Setup sets up all routes
@@ -49,7 +50,8 @@ func Setup(app *fiber.App, h *Handlers, authService services.AuthService, cfg *c
 
 	// Protected routes (authentication required)
 	protected := api.Use(middleware.AuthMiddleware(validateToken))
-	setupProtectedRoutes(protected, h)
+	requireVerified := middleware.RequireEmailVerified(authService.IsEmailVerified)
+	setupProtectedRoutes(protected, h, requireVerified)
 
 	// Admin routes (admin role required)
 	admin := protected.Use(middleware.RequireRole("admin"))
@@ -75,6 +77,11 @@ func setupPublicRoutes(api fiber.Router, h *Handlers) {
 		auth.Post("/forgot-password", h.Auth.ForgotPassword)
 		auth.Post("/reset-password", h.Auth.ResetPassword)
 		auth.Get("/verify-email/:token", h.Auth.VerifyEmail)
+		auth.Post("/mfa/verify", h.Auth.VerifyMFA)
+
+		// Passkeys
+		auth.Post("/passkey/login/begin", h.Auth.BeginPasskeyLogin)
+		auth.Post("/passkey/login/finish", h.Auth.FinishPasskeyLogin)
 
 		// OAuth
 		auth.Get("/google", h.Auth.GoogleAuth)
@@ -83,11 +90,20 @@ func setupPublicRoutes(api fiber.Router, h *Handlers) {
 		auth.Get("/linkedin/callback", h.Auth.LinkedInCallback)
 		auth.Get("/apple", h.Auth.AppleAuth)
 		auth.Post("/apple/callback", h.Auth.AppleCallback) // Apple uses POST
+		auth.Post("/oauth/token", h.Auth.ExchangeOAuthCode)
 	}
 }
 
-// setupProtectedRoutes sets up protected API routes
-func setupProtectedRoutes(api fiber.Router, h *Handlers) {
+// setupProtectedRoutes sets up protected API routes. requireVerified gates features that
+// need a verified email address.
+func setupProtectedRoutes(api fiber.Router, h *Handlers, requireVerified fiber.Handler) {
+	// Email verification
+	api.Post("/auth/verify-email/resend", h.Auth.ResendVerificationEmail) // POST /auth/verify-email/resend
+
+	// Passkey registration
+	api.Post("/auth/passkey/register/begin", h.Auth.BeginPasskeyRegistration)   // POST /auth/passkey/register/begin
+	api.Post("/auth/passkey/register/finish", h.Auth.FinishPasskeyRegistration) // POST /auth/passkey/register/finish
+
 	// User profile
 	user := api.Group("/users/me")
 	{
@@ -111,6 +127,24 @@ func setupProtectedRoutes(api fiber.Router, h *Handlers) {
 		user.Post("/change-password", h.User.ChangePassword) // POST /users/me/change-password
 		user.Post("/upload-avatar", h.User.UploadAvatar)     // POST /users/me/upload-avatar
 
+		// Signed-in devices
+		user.Get("/sessions", h.User.ListSessions)          // GET /users/me/sessions
+		user.Delete("/sessions/:id", h.User.RevokeSession) // DELETE /users/me/sessions/:id
+
+		// Two-factor authentication
+		mfa := user.Group("/mfa")
+		{
+			mfa.Get("", h.User.GetMFAStatus)                          // GET /users/me/mfa
+			mfa.Post("/enroll", h.User.EnrollMFA)                     // POST /users/me/mfa/enroll
+			mfa.Post("/confirm", h.User.ConfirmMFA)                   // POST /users/me/mfa/confirm
+			mfa.Post("/disable", h.User.DisableMFA)                   // POST /users/me/mfa/disable
+			mfa.Post("/recovery-codes", h.User.RegenerateRecoveryCodes) // POST /users/me/mfa/recovery-codes
+		}
+
+		// Passkeys
+		user.Get("/passkeys", h.User.ListPasskeys)         // GET /users/me/passkeys
+		user.Delete("/passkeys/:id", h.User.DeletePasskey) // DELETE /users/me/passkeys/:id
+
 		// Onboarding
 		onboarding := user.Group("/onboarding")
 		{
@@ -160,12 +194,15 @@ func setupProtectedRoutes(api fiber.Router, h *Handlers) {
 		people.Get("/:id/interactions", h.Person.GetInteractions)     // GET /people/:id/interactions
 		people.Get("/:id/health", h.Person.GetHealthScore)            // GET /people/:id/health
 		people.Put("/:id/reminder", h.Person.UpdateReminder)          // PUT /people/:id/reminder
+		people.Delete("/:id/reminder", h.Person.ClearReminder)        // DELETE /people/:id/reminder
+		people.Post("/:id/reminder/snooze", h.Person.SnoozeReminder)  // POST /people/:id/reminder/snooze
+		people.Post("/:id/reminder/skip", h.Person.SkipReminder)      // POST /people/:id/reminder/skip
 
 		// AI Analysis endpoints
-		people.Get("/:id/analysis", h.Analysis.GetPersonAnalysis)              // GET /people/:id/analysis
-		people.Post("/:id/analysis/refresh", h.Analysis.RefreshPersonAnalysis) // POST /people/:id/analysis/refresh
-		people.Get("/:id/analysis/history", h.Analysis.GetAnalysisHistory)     // GET /people/:id/analysis/history
-		people.Get("/:id/recommendations", h.Analysis.GetPersonRecommendations) // GET /people/:id/recommendations
+		people.Get("/:id/analysis", h.Analysis.GetPersonAnalysis)                               // GET /people/:id/analysis
+		people.Post("/:id/analysis/refresh", requireVerified, h.Analysis.RefreshPersonAnalysis) // POST /people/:id/analysis/refresh
+		people.Get("/:id/analysis/history", h.Analysis.GetAnalysisHistory)                      // GET /people/:id/analysis/history
+		people.Get("/:id/recommendations", h.Analysis.GetPersonRecommendations)                 // GET /people/:id/recommendations
 	}
 
 	// Interactions (vyves)
@@ -214,6 +251,15 @@ func setupProtectedRoutes(api fiber.Router, h *Handlers) {
 		nudges.Get("/history", h.Nudge.GetHistory)
 		nudges.Post("/generate", h.Nudge.GenerateNudges)
 
+		// User-defined nudge rules
+		nudges.Get("/rules", h.Nudge.ListRules)
+		nudges.Post("/rules", h.Nudge.CreateRule)
+		nudges.Post("/rules/dry-run", h.Nudge.DryRunRule)
+		nudges.Get("/rules/:id", h.Nudge.GetRule)
+		nudges.Put("/rules/:id", h.Nudge.UpdateRule)
+		nudges.Delete("/rules/:id", h.Nudge.DeleteRule)
+		nudges.Post("/rules/:id/dry-run", h.Nudge.DryRunRule)
+
 		// PARAMETERIZED ROUTES LAST
 		nudges.Get("/:id", h.Nudge.Get)
 		nudges.Post("/:id/seen", h.Nudge.MarkSeen)
@@ -249,9 +295,10 @@ func setupProtectedRoutes(api fiber.Router, h *Handlers) {
 		analytics.Get("/daily-metrics", h.User.GetDailyMetrics)
 		
 		// AI Analysis endpoints
-		analytics.Get("/insights", h.Analysis.GetOverallInsights)      // GET /analytics/insights
-		analytics.Post("/batch-analyze", h.Analysis.BatchAnalyze)      // POST /analytics/batch-analyze
-		analytics.Get("/jobs/:id", h.Analysis.GetJobStatus)            // GET /analytics/jobs/:id
+		analytics.Get("/insights", h.Analysis.GetOverallInsights)                  // GET /analytics/insights
+		analytics.Post("/batch-analyze", requireVerified, h.Analysis.BatchAnalyze) // POST /analytics/batch-analyze
+		analytics.Get("/jobs/:id", h.Analysis.GetJobStatus)                        // GET /analytics/jobs/:id
+		analytics.Post("/jobs/:id/cancel", h.Analysis.CancelJob)                   // POST /analytics/jobs/:id/cancel
 	}
 
 	// GDPR & Privacy
@@ -259,14 +306,23 @@ func setupProtectedRoutes(api fiber.Router, h *Handlers) {
 	{
 		gdpr.Get("/consent", h.GDPR.GetConsents)
 		gdpr.Post("/consent", h.GDPR.UpdateConsent)
-		gdpr.Post("/export", h.GDPR.RequestDataExport)
+		gdpr.Post("/export", requireVerified, h.GDPR.RequestDataExport)
 		gdpr.Delete("/data", h.GDPR.DeleteAllData)
 		gdpr.Post("/anonymize", h.GDPR.AnonymizeData)
 		gdpr.Get("/audit-log", h.GDPR.GetAuditLog)
 
 		// PARAMETERIZED ROUTES LAST
 		gdpr.Get("/export/:id", h.GDPR.GetExportStatus)
-		gdpr.Get("/export/:id/download", h.GDPR.DownloadExport)
+		gdpr.Get("/export/:id/download", requireVerified, h.GDPR.DownloadExport)
+	}
+
+	// Data import
+	imports := api.Group("/imports")
+	{
+		imports.Post("", requireVerified, h.Import.Create) // POST /imports
+		imports.Get("", h.Import.List)                     // GET /imports
+		imports.Get("/:id", h.Import.Get)                  // GET /imports/:id
+		imports.Post("/:id/confirm", h.Import.Confirm)     // POST /imports/:id/confirm
 	}
 
 	// Search
@@ -282,6 +338,11 @@ func setupProtectedRoutes(api fiber.Router, h *Handlers) {
 		notifications.Get("/preferences", h.User.GetNotificationPreferences)
 		notifications.Put("/preferences", h.User.UpdateNotificationPreferences)
 		notifications.Post("/test", h.User.SendTestNotification)
+
+		// In-app inbox
+		notifications.Get("/", h.User.ListNotifications)
+		notifications.Post("/read-all", h.User.MarkAllNotificationsRead)
+		notifications.Post("/:id/read", h.User.MarkNotificationRead)
 	}
 }
 
@@ -296,6 +357,19 @@ func setupAdminRoutes(api fiber.Router, h *Handlers) {
 		users.Delete("/:id", h.User.AdminDeleteUser)
 		users.Post("/:id/suspend", h.User.AdminSuspendUser)
 		users.Post("/:id/unsuspend", h.User.AdminUnsuspendUser)
+		users.Post("/:id/unlock", h.User.AdminUnlockUser)
+		users.Post("/:id/roles", h.User.AdminGrantRole)
+		users.Delete("/:id/roles/:role", h.User.AdminRevokeRole)
+	}
+
+	// Global nudge rules, evaluated for every user after their own rules
+	nudgeRules := api.Group("/nudge-rules")
+	{
+		nudgeRules.Get("/", h.Nudge.AdminListRules)
+		nudgeRules.Post("/", h.Nudge.AdminCreateRule)
+		nudgeRules.Get("/:id", h.Nudge.AdminGetRule)
+		nudgeRules.Put("/:id", h.Nudge.AdminUpdateRule)
+		nudgeRules.Delete("/:id", h.Nudge.AdminDeleteRule)
 	}
 
 	// System
@@ -12,6 +12,8 @@ import (
 	"github.com/vyve/vyve-backend/pkg/ai"
 )
 
+//go:generate mockgen -source=analysis_service.go -destination=mock_analysis_service.go -package=services
+
 // AnalysisService handles AI-powered relationship analysis
 type AnalysisService interface {
 	// Analysis operations
@@ -29,6 +31,7 @@ type AnalysisService interface {
 	// Batch operations
 	BatchAnalyze(ctx context.Context, userID uuid.UUID, personIDs []uuid.UUID) (*models.AIAnalysisJob, error)
 	GetJobStatus(ctx context.Context, userID, jobID uuid.UUID) (*models.AIAnalysisJob, error)
+	CancelJob(ctx context.Context, userID, jobID uuid.UUID) (*models.AIAnalysisJob, error)
 }
 
 type analysisService struct {
@@ -297,9 +300,7 @@ func (s *analysisService) BatchAnalyze(ctx context.Context, userID uuid.UUID, pe
 		return nil, fmt.Errorf("failed to create job: %w", err)
 	}
 	
-	// Process in background
-	go s.processBatchAnalysis(context.Background(), job)
-	
+	// The job is picked up by the AnalysisWorkerPool
 	return job, nil
 }
 
@@ -317,6 +318,25 @@ func (s *analysisService) GetJobStatus(ctx context.Context, userID, jobID uuid.U
 	return job, nil
 }
 
+// CancelJob cancels a pending or running analysis job
+func (s *analysisService) CancelJob(ctx context.Context, userID, jobID uuid.UUID) (*models.AIAnalysisJob, error) {
+	job, err := s.GetJobStatus(ctx, userID, jobID)
+	if err != nil {
+		return nil, err
+	}
+	
+	cancelled, err := s.analysisRepo.CancelJob(ctx, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel job: %w", err)
+	}
+	if !cancelled {
+		return nil, repository.ErrJobNotCancellable
+	}
+	
+	// A running worker notices the cancellation on its next heartbeat
+	return s.analysisRepo.GetJobByID(ctx, job.ID)
+}
+
 // Helper functions
 
 func (s *analysisService) buildAnalysisRequest(person *models.Person, interactions []*models.Interaction) ai.AnalysisRequest {
@@ -389,37 +409,3 @@ func (s *analysisService) buildRecommendationRequest(person *models.Person, anal
 	
 	return req
 }
-
-func (s *analysisService) processBatchAnalysis(ctx context.Context, job *models.AIAnalysisJob) {
-	// Update job status
-	now := time.Now()
-	job.Status = "processing"
-	job.StartedAt = &now
-	s.analysisRepo.UpdateJob(ctx, job)
-	
-	// Process each person
-	for _, personIDStr := range job.PersonIDs {
-		personID, err := uuid.Parse(personIDStr)
-		if err != nil {
-			job.FailedItems++
-			continue
-		}
-		
-		_, err = s.AnalyzeRelationship(ctx, job.UserID, personID)
-		if err != nil {
-			job.FailedItems++
-		} else {
-			job.ProcessedItems++
-		}
-		
-		// Update progress
-		job.Progress = float64(job.ProcessedItems+job.FailedItems) / float64(job.TotalItems) * 100
-		s.analysisRepo.UpdateJob(ctx, job)
-	}
-	
-	// Mark job as completed
-	completed := time.Now()
-	job.Status = "completed"
-	job.CompletedAt = &completed
-	s.analysisRepo.UpdateJob(ctx, job)
-}
@@ -0,0 +1,364 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vyve/vyve-backend/internal/config"
+	"github.com/vyve/vyve-backend/internal/models"
+	"github.com/vyve/vyve-backend/internal/repository"
+	"github.com/vyve/vyve-backend/pkg/ai"
+)
+
+// Per-item statuses stored in AIAnalysisJob.ResultData["items"]
+const (
+	jobItemCompleted = "completed"
+	jobItemFailed    = "failed" // permanent, never retried
+	jobItemRetry     = "retry"  // transient, retried on the next attempt
+)
+
+const (
+	jobRetryBaseDelay = 30 * time.Second
+	jobRetryMaxDelay  = 15 * time.Minute
+)
+
+// jobItemResult is the outcome of analyzing one person in a batch job
+type jobItemResult struct {
+	Status     string  `json:"status"`
+	AnalysisID string  `json:"analysis_id,omitempty"`
+	TokensUsed int     `json:"tokens_used,omitempty"`
+	Cost       float64 `json:"cost,omitempty"`
+	Error      string  `json:"error,omitempty"`
+	Attempts   int     `json:"attempts"`
+}
+
+// AnalysisWorkerPool processes queued AI analysis jobs from the ai_analysis_jobs table.
+// Jobs survive restarts: a job whose worker stops heartbeating is put back in the queue.
+type AnalysisWorkerPool struct {
+	analysisService AnalysisService
+	analysisRepo    repository.AnalysisRepository
+	workers         int
+	pollInterval    time.Duration
+	staleAfter      time.Duration
+	workerPrefix    string
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewAnalysisWorkerPool creates a new analysis worker pool
+func NewAnalysisWorkerPool(analysisService AnalysisService, analysisRepo repository.AnalysisRepository, cfg config.AIConfig) *AnalysisWorkerPool {
+	hostname, _ := os.Hostname()
+
+	pool := &AnalysisWorkerPool{
+		analysisService: analysisService,
+		analysisRepo:    analysisRepo,
+		workers:         cfg.JobWorkers,
+		pollInterval:    cfg.JobPollInterval,
+		staleAfter:      cfg.JobStaleAfter,
+		workerPrefix:    fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+	}
+	if pool.workers < 1 {
+		pool.workers = 1
+	}
+	if pool.pollInterval <= 0 {
+		pool.pollInterval = 5 * time.Second
+	}
+	if pool.staleAfter <= 0 {
+		pool.staleAfter = 2 * time.Minute
+	}
+
+	return pool
+}
+
+// Start starts the workers and the stale job reaper
+func (p *AnalysisWorkerPool) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+
+	for i := 0; i < p.workers; i++ {
+		p.wg.Add(1)
+		go p.runWorker(ctx, fmt.Sprintf("%s-%d", p.workerPrefix, i))
+	}
+
+	p.wg.Add(1)
+	go p.runReaper(ctx)
+
+	log.Printf("[ANALYSIS_WORKER] Started %d workers", p.workers)
+}
+
+// Stop stops the workers and waits for them to hand back their current jobs
+func (p *AnalysisWorkerPool) Stop() {
+	if p.cancel == nil {
+		return
+	}
+	p.cancel()
+	p.wg.Wait()
+	log.Printf("[ANALYSIS_WORKER] Stopped")
+}
+
+func (p *AnalysisWorkerPool) runWorker(ctx context.Context, workerID string) {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Drain the queue before going back to sleep; ClaimNextJob reports an empty
+		// queue as ErrNotFound
+		for ctx.Err() == nil {
+			job, err := p.analysisRepo.ClaimNextJob(ctx, workerID)
+			if err != nil {
+				if !errors.Is(err, repository.ErrNotFound) && ctx.Err() == nil {
+					log.Printf("[ANALYSIS_WORKER] %s failed to claim job: %v", workerID, err)
+				}
+				break
+			}
+
+			p.processJob(ctx, job, workerID)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runReaper periodically puts jobs of dead workers back in the queue
+func (p *AnalysisWorkerPool) runReaper(ctx context.Context) {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.staleAfter / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := p.analysisRepo.ReclaimStaleJobs(ctx, time.Now().Add(-p.staleAfter))
+			if err != nil {
+				log.Printf("[ANALYSIS_WORKER] Failed to reclaim stale jobs: %v", err)
+			} else if n > 0 {
+				log.Printf("[ANALYSIS_WORKER] Reclaimed %d stale jobs", n)
+			}
+		}
+	}
+}
+
+// processJob analyzes every person of a claimed job that has not been analyzed yet
+func (p *AnalysisWorkerPool) processJob(ctx context.Context, job *models.AIAnalysisJob, workerID string) {
+	log.Printf("[ANALYSIS_WORKER] %s processing job %s (attempt %d/%d)", workerID, job.ID, job.Attempts, job.MaxAttempts)
+
+	// jobCtx is cancelled when the job is cancelled or reclaimed by another worker
+	jobCtx, cancelJob := context.WithCancel(ctx)
+	defer cancelJob()
+
+	done := make(chan struct{})
+	defer close(done)
+	go p.heartbeat(jobCtx, done, cancelJob, job.ID, workerID)
+
+	items := decodeJobItems(job.ResultData)
+	var lastErr error
+
+	for _, personIDStr := range job.PersonIDs {
+		if jobCtx.Err() != nil {
+			break
+		}
+
+		item, ok := items[personIDStr]
+		if !ok {
+			item = &jobItemResult{}
+			items[personIDStr] = item
+		}
+		if item.Status == jobItemCompleted || item.Status == jobItemFailed {
+			continue
+		}
+
+		item.Attempts++
+		personID, err := uuid.Parse(personIDStr)
+		if err != nil {
+			item.Status = jobItemFailed
+			item.Error = "invalid person ID"
+		} else {
+			analysis, err := p.analysisService.AnalyzeRelationship(jobCtx, job.UserID, personID)
+			switch {
+			case err != nil && jobCtx.Err() != nil:
+				// Interrupted, not a real failure
+				item.Attempts--
+			case err != nil:
+				lastErr = err
+				item.Error = err.Error()
+				item.Status = jobItemRetry
+				if repository.IsForbidden(err) || repository.IsNotFound(err) {
+					item.Status = jobItemFailed
+				}
+			default:
+				item.Status = jobItemCompleted
+				item.Error = ""
+				item.AnalysisID = analysis.ID.String()
+				item.TokensUsed = analysis.TokensUsed
+				item.Cost = ai.EstimateCost(analysis.Model, analysis.TokensUsed)
+			}
+		}
+
+		applyJobItems(job, items)
+		owned, err := p.analysisRepo.SaveJobProgress(ctx, job, workerID)
+		if err != nil {
+			log.Printf("[ANALYSIS_WORKER] Failed to save progress of job %s: %v", job.ID, err)
+		} else if !owned {
+			cancelJob()
+		}
+	}
+
+	if ctx.Err() != nil {
+		// Shutting down: give the job back so another instance can pick it up right away
+		if err := p.analysisRepo.ReleaseJob(context.Background(), job.ID, workerID); err != nil {
+			log.Printf("[ANALYSIS_WORKER] Failed to release job %s: %v", job.ID, err)
+		}
+		return
+	}
+	if jobCtx.Err() != nil {
+		log.Printf("[ANALYSIS_WORKER] Job %s was cancelled or reclaimed", job.ID)
+		return
+	}
+
+	p.finishJob(ctx, job, items, lastErr, workerID)
+}
+
+// finishJob either schedules a retry for transient failures or records the final status
+func (p *AnalysisWorkerPool) finishJob(ctx context.Context, job *models.AIAnalysisJob, items map[string]*jobItemResult, lastErr error, workerID string) {
+	retryable := 0
+	for _, item := range items {
+		if item.Status == jobItemRetry {
+			retryable++
+		}
+	}
+
+	now := time.Now()
+	if retryable > 0 && job.Attempts < job.MaxAttempts {
+		nextRun := now.Add(jobRetryBackoff(job.Attempts))
+		job.Status = "pending"
+		job.NextRunAt = &nextRun
+		job.Error = fmt.Sprintf("%d items failed and will be retried: %v", retryable, lastErr)
+	} else {
+		for _, item := range items {
+			if item.Status == jobItemRetry {
+				item.Status = jobItemFailed
+			}
+		}
+		applyJobItems(job, items)
+
+		job.Status = "completed"
+		job.Error = ""
+		if job.TotalItems > 0 && job.ProcessedItems == 0 {
+			job.Status = "failed"
+		}
+		if lastErr != nil && job.FailedItems > 0 {
+			job.Error = lastErr.Error()
+		}
+		job.NextRunAt = nil
+		job.CompletedAt = &now
+	}
+
+	owned, err := p.analysisRepo.FinishJob(ctx, job, workerID)
+	if err != nil {
+		log.Printf("[ANALYSIS_WORKER] Failed to finish job %s: %v", job.ID, err)
+		return
+	}
+	if owned {
+		log.Printf("[ANALYSIS_WORKER] Job %s %s: %d processed, %d failed, %d tokens",
+			job.ID, job.Status, job.ProcessedItems, job.FailedItems, job.TotalTokensUsed)
+	}
+}
+
+// heartbeat keeps the job claimed while it is being processed and cancels it when
+// the worker has lost ownership
+func (p *AnalysisWorkerPool) heartbeat(ctx context.Context, done <-chan struct{}, cancelJob context.CancelFunc, jobID uuid.UUID, workerID string) {
+	ticker := time.NewTicker(p.staleAfter / 4)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			owned, err := p.analysisRepo.HeartbeatJob(ctx, jobID, workerID)
+			if err != nil {
+				log.Printf("[ANALYSIS_WORKER] Heartbeat failed for job %s: %v", jobID, err)
+				continue
+			}
+			if !owned {
+				cancelJob()
+				return
+			}
+		}
+	}
+}
+
+// jobRetryBackoff returns the delay before the next attempt, doubling after each attempt
+func jobRetryBackoff(attempt int) time.Duration {
+	delay := jobRetryBaseDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= jobRetryMaxDelay {
+			return jobRetryMaxDelay
+		}
+	}
+	return delay
+}
+
+// decodeJobItems reads the per-item results stored on a job
+func decodeJobItems(data models.JSONB) map[string]*jobItemResult {
+	items := make(map[string]*jobItemResult)
+	raw, ok := data["items"]
+	if !ok {
+		return items
+	}
+
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		return items
+	}
+	_ = json.Unmarshal(encoded, &items)
+	return items
+}
+
+// applyJobItems recomputes the job counters, cost and result data from per-item results
+func applyJobItems(job *models.AIAnalysisJob, items map[string]*jobItemResult) {
+	job.ProcessedItems = 0
+	job.FailedItems = 0
+	job.TotalTokensUsed = 0
+	job.EstimatedCost = 0
+
+	for _, item := range items {
+		switch item.Status {
+		case jobItemCompleted:
+			job.ProcessedItems++
+		case jobItemFailed:
+			job.FailedItems++
+		}
+		job.TotalTokensUsed += item.TokensUsed
+		job.EstimatedCost += item.Cost
+	}
+
+	if job.TotalItems > 0 {
+		job.Progress = float64(job.ProcessedItems+job.FailedItems) / float64(job.TotalItems) * 100
+	}
+
+	if job.ResultData == nil {
+		job.ResultData = models.JSONB{}
+	}
+	job.ResultData["items"] = items
+}
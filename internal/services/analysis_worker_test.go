@@ -0,0 +1,275 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/vyve/vyve-backend/internal/models"
+	"github.com/vyve/vyve-backend/internal/repository"
+	"github.com/vyve/vyve-backend/pkg/ai"
+)
+
+func TestJobRetryBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{5, 8 * time.Minute},
+		{6, jobRetryMaxDelay},
+		{20, jobRetryMaxDelay},
+	}
+	for _, tt := range tests {
+		if got := jobRetryBackoff(tt.attempt); got != tt.want {
+			t.Errorf("jobRetryBackoff(%d) = %s, want %s", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+// newTestWorkerPool returns a pool whose heartbeat never comes due during a test
+func newTestWorkerPool(ctrl *gomock.Controller) (*AnalysisWorkerPool, *MockAnalysisService, *repository.MockAnalysisRepository) {
+	analysisService := NewMockAnalysisService(ctrl)
+	analysisRepo := repository.NewMockAnalysisRepository(ctrl)
+	return &AnalysisWorkerPool{
+		analysisService: analysisService,
+		analysisRepo:    analysisRepo,
+		staleAfter:      time.Hour,
+	}, analysisService, analysisRepo
+}
+
+// newTestJob returns a claimed job on its given attempt for the given people
+func newTestJob(attempt int, personIDs ...uuid.UUID) *models.AIAnalysisJob {
+	job := &models.AIAnalysisJob{
+		Base:        models.Base{ID: uuid.New()},
+		UserID:      uuid.New(),
+		Status:      "processing",
+		TotalItems:  len(personIDs),
+		Attempts:    attempt,
+		MaxAttempts: 3,
+	}
+	for _, id := range personIDs {
+		job.PersonIDs = append(job.PersonIDs, id.String())
+	}
+	return job
+}
+
+func TestProcessJob(t *testing.T) {
+	analyzed, flaky, missing := uuid.New(), uuid.New(), uuid.New()
+	apiDown := errors.New("provider unavailable")
+	analysis := &models.RelationshipAnalysis{Base: models.Base{ID: uuid.New()}, Model: "gpt-4o-mini", TokensUsed: 1200}
+	analysisCost := ai.EstimateCost("gpt-4o-mini", 1200)
+
+	tests := []struct {
+		name          string
+		attempt       int
+		results       map[uuid.UUID]error
+		wantStatus    string
+		wantRetry     bool
+		wantProcessed int
+		wantFailed    int
+		wantItems     map[uuid.UUID]string
+	}{
+		{
+			name:          "transient failure is retried",
+			attempt:       1,
+			results:       map[uuid.UUID]error{analyzed: nil, flaky: apiDown, missing: repository.ErrPersonNotFound},
+			wantStatus:    "pending",
+			wantRetry:     true,
+			wantProcessed: 1,
+			wantFailed:    1,
+			wantItems:     map[uuid.UUID]string{analyzed: jobItemCompleted, flaky: jobItemRetry, missing: jobItemFailed},
+		},
+		{
+			name:          "transient failure on the last attempt",
+			attempt:       3,
+			results:       map[uuid.UUID]error{analyzed: nil, flaky: apiDown, missing: repository.ErrPersonNotFound},
+			wantStatus:    "completed",
+			wantProcessed: 1,
+			wantFailed:    2,
+			wantItems:     map[uuid.UUID]string{analyzed: jobItemCompleted, flaky: jobItemFailed, missing: jobItemFailed},
+		},
+		{
+			name:       "nothing could be analyzed",
+			attempt:    1,
+			results:    map[uuid.UUID]error{missing: repository.ErrPersonNotFound},
+			wantStatus: "failed",
+			wantFailed: 1,
+			wantItems:  map[uuid.UUID]string{missing: jobItemFailed},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			pool, analysisService, analysisRepo := newTestWorkerPool(ctrl)
+
+			var personIDs []uuid.UUID
+			for _, id := range []uuid.UUID{analyzed, flaky, missing} {
+				err, ok := tt.results[id]
+				if !ok {
+					continue
+				}
+				personIDs = append(personIDs, id)
+				if err != nil {
+					analysisService.EXPECT().AnalyzeRelationship(gomock.Any(), gomock.Any(), id).Return(nil, err)
+				} else {
+					analysisService.EXPECT().AnalyzeRelationship(gomock.Any(), gomock.Any(), id).Return(analysis, nil)
+				}
+			}
+			job := newTestJob(tt.attempt, personIDs...)
+
+			analysisRepo.EXPECT().SaveJobProgress(gomock.Any(), job, "worker").Return(true, nil).Times(len(personIDs))
+			analysisRepo.EXPECT().FinishJob(gomock.Any(), job, "worker").Return(true, nil)
+
+			before := time.Now()
+			pool.processJob(context.Background(), job, "worker")
+
+			if job.Status != tt.wantStatus || job.ProcessedItems != tt.wantProcessed || job.FailedItems != tt.wantFailed {
+				t.Errorf("job %s with %d processed and %d failed, want %s with %d and %d",
+					job.Status, job.ProcessedItems, job.FailedItems, tt.wantStatus, tt.wantProcessed, tt.wantFailed)
+			}
+			if tt.wantRetry {
+				if job.NextRunAt == nil || job.NextRunAt.Before(before.Add(jobRetryBackoff(tt.attempt))) || job.CompletedAt != nil {
+					t.Errorf("retry at %v and completed at %v, want a retry after %s", job.NextRunAt, job.CompletedAt, jobRetryBackoff(tt.attempt))
+				}
+			} else if job.NextRunAt != nil || job.CompletedAt == nil {
+				t.Errorf("retry at %v and completed at %v, want the job finished", job.NextRunAt, job.CompletedAt)
+			}
+			if tt.wantFailed > 0 && job.Error == "" {
+				t.Error("failed items left no error on the job")
+			}
+
+			items := decodeJobItems(job.ResultData)
+			for id, want := range tt.wantItems {
+				if got := items[id.String()]; got == nil || got.Status != want || got.Attempts != 1 {
+					t.Errorf("item %s = %+v, want %s after one attempt", id, got, want)
+				}
+			}
+			if _, ok := tt.results[analyzed]; ok {
+				if job.TotalTokensUsed != analysis.TokensUsed || math.Abs(job.EstimatedCost-analysisCost) > 1e-9 {
+					t.Errorf("job used %d tokens costing %f, want %d costing %f", job.TotalTokensUsed, job.EstimatedCost, analysis.TokensUsed, analysisCost)
+				}
+			}
+		})
+	}
+}
+
+func TestProcessJobResumes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	pool, analysisService, analysisRepo := newTestWorkerPool(ctrl)
+
+	done, failed, retried := uuid.New(), uuid.New(), uuid.New()
+	job := newTestJob(2, done, failed, retried)
+	applyJobItems(job, map[string]*jobItemResult{
+		done.String():    {Status: jobItemCompleted, TokensUsed: 800, Cost: 0.01, Attempts: 1},
+		failed.String():  {Status: jobItemFailed, Error: "person not found", Attempts: 1},
+		retried.String(): {Status: jobItemRetry, Error: "provider unavailable", Attempts: 1},
+	})
+
+	// Only the item that failed transiently is analyzed again
+	analysis := &models.RelationshipAnalysis{Base: models.Base{ID: uuid.New()}, Model: "gpt-4o", TokensUsed: 400}
+	analysisService.EXPECT().AnalyzeRelationship(gomock.Any(), job.UserID, retried).Return(analysis, nil)
+	analysisRepo.EXPECT().SaveJobProgress(gomock.Any(), job, "worker").Return(true, nil)
+	analysisRepo.EXPECT().FinishJob(gomock.Any(), job, "worker").Return(true, nil)
+
+	pool.processJob(context.Background(), job, "worker")
+
+	if job.Status != "completed" || job.ProcessedItems != 2 || job.FailedItems != 1 || job.Progress != 100 {
+		t.Errorf("job %s with %d processed, %d failed and %.0f%% progress", job.Status, job.ProcessedItems, job.FailedItems, job.Progress)
+	}
+	// Tokens and cost include the items finished on earlier attempts
+	wantCost := 0.01 + ai.EstimateCost("gpt-4o", 400)
+	if job.TotalTokensUsed != 1200 || math.Abs(job.EstimatedCost-wantCost) > 1e-9 {
+		t.Errorf("job used %d tokens costing %f, want 1200 costing %f", job.TotalTokensUsed, job.EstimatedCost, wantCost)
+	}
+	if item := decodeJobItems(job.ResultData)[retried.String()]; item.Attempts != 2 || item.Error != "" {
+		t.Errorf("retried item = %+v, want completed on its second attempt", item)
+	}
+}
+
+func TestProcessJobCancelled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	pool, analysisService, analysisRepo := newTestWorkerPool(ctrl)
+
+	first, second := uuid.New(), uuid.New()
+	job := newTestJob(1, first, second)
+
+	analysis := &models.RelationshipAnalysis{Base: models.Base{ID: uuid.New()}, Model: "gpt-4o", TokensUsed: 500}
+	analysisService.EXPECT().AnalyzeRelationship(gomock.Any(), job.UserID, first).Return(analysis, nil)
+	// The job was cancelled or reclaimed while the first person was analyzed, so the
+	// worker stops without analyzing the second, finishing or releasing the job
+	analysisRepo.EXPECT().SaveJobProgress(gomock.Any(), job, "worker").Return(false, nil)
+
+	pool.processJob(context.Background(), job, "worker")
+
+	if job.ProcessedItems != 1 || job.Status != "processing" || job.CompletedAt != nil {
+		t.Errorf("job %s with %d processed, completed at %v", job.Status, job.ProcessedItems, job.CompletedAt)
+	}
+}
+
+func TestProcessJobShutdown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	pool, analysisService, analysisRepo := newTestWorkerPool(ctrl)
+
+	first, second := uuid.New(), uuid.New()
+	job := newTestJob(1, first, second)
+	ctx, shutdown := context.WithCancel(context.Background())
+	defer shutdown()
+
+	// Shutdown interrupts the first analysis
+	analysisService.EXPECT().
+		AnalyzeRelationship(gomock.Any(), job.UserID, first).
+		DoAndReturn(func(ctx context.Context, userID, personID uuid.UUID) (*models.RelationshipAnalysis, error) {
+			shutdown()
+			return nil, ctx.Err()
+		})
+	analysisRepo.EXPECT().SaveJobProgress(gomock.Any(), job, "worker").Return(true, nil)
+	analysisRepo.EXPECT().
+		ReleaseJob(gomock.Any(), job.ID, "worker").
+		DoAndReturn(func(ctx context.Context, jobID uuid.UUID, workerID string) error {
+			if ctx.Err() != nil {
+				t.Error("job released with the cancelled context")
+			}
+			return nil
+		})
+
+	pool.processJob(ctx, job, "worker")
+
+	// The interrupted attempt doesn't count against the item
+	item := decodeJobItems(job.ResultData)[first.String()]
+	if item == nil || item.Attempts != 0 || item.Status != "" {
+		t.Errorf("interrupted item = %+v, want no attempt recorded", item)
+	}
+}
+
+func TestRunWorkerDrainsQueue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	pool, _, analysisRepo := newTestWorkerPool(ctrl)
+	pool.pollInterval = time.Hour
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+
+	// Jobs are claimed until the queue is empty, without listing it first
+	job := newTestJob(1)
+	gomock.InOrder(
+		analysisRepo.EXPECT().ClaimNextJob(gomock.Any(), "worker").Return(job, nil),
+		analysisRepo.EXPECT().FinishJob(gomock.Any(), job, "worker").Return(true, nil),
+		analysisRepo.EXPECT().
+			ClaimNextJob(gomock.Any(), "worker").
+			DoAndReturn(func(ctx context.Context, workerID string) (*models.AIAnalysisJob, error) {
+				stop()
+				return nil, repository.ErrNotFound
+			}),
+	)
+
+	pool.wg.Add(1)
+	pool.runWorker(ctx, "worker")
+}
@@ -0,0 +1,335 @@
+package services
+
+import (
+	"context"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vyve/vyve-backend/internal/models"
+	"github.com/vyve/vyve-backend/internal/repository"
+	"github.com/vyve/vyve-backend/pkg/analytics"
+	"github.com/vyve/vyve-backend/pkg/cache"
+	"github.com/vyve/vyve-backend/pkg/totp"
+	"github.com/vyve/vyve-backend/pkg/utils"
+)
+
+const (
+	// mfaIssuer names the account in authenticator apps
+	mfaIssuer = "Vyve"
+	// mfaChallengeTTL is how long a user has to enter their code after the password
+	mfaChallengeTTL = 5 * time.Minute
+	// mfaMaxAttempts bounds the codes tried against one challenge
+	mfaMaxAttempts = 5
+	// recoveryCodeCount is how many recovery codes are issued at a time
+	recoveryCodeCount = 10
+	// recoveryCodeLength is the number of characters in a recovery code, without the dash
+	recoveryCodeLength = 10
+)
+
+var recoveryCodeEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// MFAEnrollment is a pending authenticator setup. The app shows ProvisioningURI as a
+// QR code, with Secret for manual entry.
+type MFAEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// MFAStatus describes a user's two-factor authentication setup
+type MFAStatus struct {
+	Enabled                bool       `json:"enabled"`
+	EnabledAt              *time.Time `json:"enabled_at,omitempty"`
+	RecoveryCodesRemaining int64      `json:"recovery_codes_remaining"`
+}
+
+// mfaChallenge is a sign-in waiting for its second factor
+type mfaChallenge struct {
+	UserID uuid.UUID `json:"user_id"`
+	Method string    `json:"method"` // password, google, linkedin, apple
+}
+
+// GetMFAStatus returns the user's two-factor authentication setup
+func (s *authService) GetMFAStatus(ctx context.Context, userID uuid.UUID) (*MFAStatus, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	status := &MFAStatus{Enabled: user.MFAEnabled(), EnabledAt: user.MFAEnabledAt}
+	if status.Enabled {
+		if status.RecoveryCodesRemaining, err = s.userRepo.CountRecoveryCodes(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+// EnrollMFA creates a new authenticator secret. It takes effect once confirmed with a
+// code from the app; enrolling again before that replaces the secret.
+func (s *authService) EnrollMFA(ctx context.Context, userID uuid.UUID) (*MFAEnrollment, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFAEnabled() {
+		return nil, repository.ErrMFAAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.SetTOTPSecret(ctx, userID, secret); err != nil {
+		return nil, err
+	}
+
+	return &MFAEnrollment{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(secret, mfaIssuer, user.Email),
+	}, nil
+}
+
+// ConfirmMFA turns on two-factor authentication once the user proves their app produces
+// valid codes, and returns their recovery codes. They are only ever shown here.
+func (s *authService) ConfirmMFA(ctx context.Context, userID uuid.UUID, code string, metadata SessionMetadata) ([]string, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFAEnabled() {
+		return nil, repository.ErrMFAAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, repository.ErrMFANotEnrolled
+	}
+	if err := s.checkTOTP(ctx, user, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.EnableMFA(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+
+	s.auditSignInChange(ctx, userID, "mfa_enabled", metadata)
+	return codes, nil
+}
+
+// DisableMFA turns off two-factor authentication. It takes a current code or a
+// recovery code, so a stolen session alone can't remove it.
+func (s *authService) DisableMFA(ctx context.Context, userID uuid.UUID, code string, metadata SessionMetadata) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.checkSecondFactor(ctx, user, code); err != nil {
+		return err
+	}
+	if err := s.userRepo.DisableMFA(ctx, userID); err != nil {
+		return err
+	}
+
+	s.auditSignInChange(ctx, userID, "mfa_disabled", metadata)
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces the user's recovery codes with new ones
+func (s *authService) RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, code string, metadata SessionMetadata) ([]string, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkSecondFactor(ctx, user, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+
+	s.auditSignInChange(ctx, userID, "mfa_recovery_codes_regenerated", metadata)
+	return codes, nil
+}
+
+// VerifyMFA completes a sign-in that was answered with an MFA challenge
+func (s *authService) VerifyMFA(ctx context.Context, mfaToken, code string, metadata SessionMetadata) (*AuthResponse, error) {
+	key := mfaChallengeKey(mfaToken)
+	var challenge mfaChallenge
+	if err := s.cache.Get(ctx, key, &challenge); err != nil {
+		if errors.Is(err, cache.ErrCacheMiss) {
+			return nil, repository.ErrTokenInvalid
+		}
+		return nil, err
+	}
+
+	attempts, err := s.cache.IncrementWithTTL(ctx, key+":attempts", mfaChallengeTTL)
+	if err != nil {
+		return nil, err
+	}
+	if attempts > mfaMaxAttempts {
+		// Make the user start over with their password
+		_ = s.cache.Delete(ctx, key)
+		return nil, repository.ErrTooManyRequests
+	}
+
+	user, err := s.userRepo.FindByID(ctx, challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+	// Suspended while the challenge was pending
+	if user.Suspended() {
+		return nil, repository.ErrAccountSuspended
+	}
+	if err := s.checkSecondFactor(ctx, user, code); err != nil {
+		return nil, err
+	}
+
+	// Each challenge signs in once
+	if err := s.cache.GetDel(ctx, key, &challenge); err != nil {
+		if errors.Is(err, cache.ErrCacheMiss) {
+			return nil, repository.ErrTokenInvalid
+		}
+		return nil, err
+	}
+
+	_ = s.userRepo.UpdateLastLogin(ctx, user.ID)
+
+	tokenPair, err := s.GenerateTokenPair(ctx, user, metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	go s.analytics.Track(context.Background(), analytics.Event{
+		UserID:    user.ID.String(),
+		EventType: analytics.EventUserLogin,
+		Properties: map[string]interface{}{
+			"method": challenge.Method,
+			"mfa":    true,
+		},
+		Timestamp: time.Now(),
+	})
+
+	return &AuthResponse{
+		User:         s.mapUserToDTO(user),
+		AccessToken:  tokenPair.AccessToken,
+		RefreshToken: tokenPair.RefreshToken,
+		ExpiresIn:    tokenPair.ExpiresIn,
+		TokenType:    "Bearer",
+	}, nil
+}
+
+// startMFAChallenge answers a sign-in whose first factor checked out with a challenge
+// token for VerifyMFA, instead of tokens
+func (s *authService) startMFAChallenge(ctx context.Context, user *models.User, method string) (*AuthResponse, error) {
+	raw, err := utils.GenerateRandomBytes(32)
+	if err != nil {
+		return nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	challenge := mfaChallenge{UserID: user.ID, Method: method}
+	if err := s.cache.Set(ctx, mfaChallengeKey(token), challenge, mfaChallengeTTL); err != nil {
+		return nil, err
+	}
+
+	return &AuthResponse{
+		MFARequired: true,
+		MFAToken:    token,
+		ExpiresIn:   int(mfaChallengeTTL.Seconds()),
+	}, nil
+}
+
+// checkSecondFactor accepts a current authenticator code or an unused recovery code
+func (s *authService) checkSecondFactor(ctx context.Context, user *models.User, code string) error {
+	if !user.MFAEnabled() {
+		return repository.ErrMFANotEnabled
+	}
+
+	if recoveryCode := normalizeRecoveryCode(code); len(recoveryCode) == recoveryCodeLength {
+		used, err := s.userRepo.ConsumeRecoveryCode(ctx, user.ID, hashToken(recoveryCode))
+		if err != nil {
+			return err
+		}
+		if !used {
+			return repository.ErrInvalidMFACode
+		}
+		log.Printf("[AUTH] User %s used a recovery code", user.ID)
+		return nil
+	}
+
+	return s.checkTOTP(ctx, user, code)
+}
+
+// checkTOTP validates an authenticator code. Each code is accepted once, so one seen
+// over someone's shoulder can't be replayed.
+func (s *authService) checkTOTP(ctx context.Context, user *models.User, code string) error {
+	step, ok := totp.Validate(user.TOTPSecret, code, time.Now())
+	if !ok {
+		return repository.ErrInvalidMFACode
+	}
+
+	key := fmt.Sprintf("mfa_totp_used:%s:%d", user.ID, step)
+	first, err := s.cache.SetNX(ctx, key, true, (2*totp.Skew+1)*totp.Period)
+	if err != nil {
+		return err
+	}
+	if !first {
+		return repository.ErrInvalidMFACode
+	}
+	return nil
+}
+
+// auditSignInChange records a change to how a user signs in, such as two-factor
+// authentication or passkeys
+func (s *authService) auditSignInChange(ctx context.Context, userID uuid.UUID, action string, metadata SessionMetadata) {
+	entry := &models.AuditLog{
+		UserID:     &userID,
+		Action:     action,
+		EntityType: "user",
+		EntityID:   userID.String(),
+		IPAddress:  metadata.IPAddress,
+		UserAgent:  metadata.UserAgent,
+		Result:     "success",
+	}
+	if err := s.auditRepo.Create(ctx, entry); err != nil {
+		log.Printf("[AUTH] Failed to audit %s for user %s: %v", action, userID, err)
+	}
+}
+
+// generateRecoveryCodes returns new recovery codes, formatted for display, and their hashes
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		raw, err := utils.GenerateRandomBytes(recoveryCodeLength * 5 / 8)
+		if err != nil {
+			return nil, nil, err
+		}
+		code := recoveryCodeEncoding.EncodeToString(raw)
+		codes[i] = code[:recoveryCodeLength/2] + "-" + code[recoveryCodeLength/2:]
+		hashes[i] = hashToken(code)
+	}
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode strips the formatting users may type a recovery code with
+func normalizeRecoveryCode(code string) string {
+	return strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(strings.TrimSpace(code)))
+}
+
+func mfaChallengeKey(token string) string {
+	return fmt.Sprintf("mfa_challenge:%s", hashToken(token))
+}
@@ -0,0 +1,590 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	oauth2 "golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	googleoauth "google.golang.org/api/oauth2/v2"
+	"google.golang.org/api/option"
+
+	"github.com/vyve/vyve-backend/internal/models"
+	"github.com/vyve/vyve-backend/internal/repository"
+	"github.com/vyve/vyve-backend/pkg/analytics"
+	"github.com/vyve/vyve-backend/pkg/cache"
+	"github.com/vyve/vyve-backend/pkg/oidc"
+	"github.com/vyve/vyve-backend/pkg/utils"
+)
+
+// OAuth providers
+const (
+	OAuthProviderGoogle   = "google"
+	OAuthProviderLinkedIn = "linkedin"
+	OAuthProviderApple    = "apple"
+)
+
+const (
+	// oauthStateTTL is how long a user has to finish signing in with a provider
+	oauthStateTTL = 10 * time.Minute
+	// oauthCodeTTL is how long the app has to exchange the one-time code its deep link
+	// received for the sign-in result
+	oauthCodeTTL = time.Minute
+)
+
+// OAuthFlow is a social login in progress, from StartOAuth to the provider's callback
+type OAuthFlow struct {
+	Provider     string `json:"provider"`
+	CodeVerifier string `json:"code_verifier"`
+	// AppRedirectURL is the app deep link to return the tokens to, if the app started
+	// the flow
+	AppRedirectURL string `json:"app_redirect_url,omitempty"`
+}
+
+// oauthIdentity is a user identity asserted by an OAuth provider
+type oauthIdentity struct {
+	Provider      string
+	ProviderID    string
+	Email         string
+	EmailVerified bool
+	Name          string
+	AvatarURL     string
+	Token         *oauth2.Token
+	RawData       models.JSONB
+}
+
+// authProvider returns the auth_providers row linking the identity to a user
+func (i *oauthIdentity) authProvider(userID uuid.UUID) *models.AuthProvider {
+	ap := &models.AuthProvider{
+		UserID:     userID,
+		Provider:   i.Provider,
+		ProviderID: i.ProviderID,
+		RawData:    i.RawData,
+	}
+	if i.Token != nil {
+		ap.AccessToken = i.Token.AccessToken
+		ap.RefreshToken = i.Token.RefreshToken
+		if i.Token.Expiry.After(time.Now()) {
+			expiresAt := i.Token.Expiry
+			ap.ExpiresAt = &expiresAt
+		}
+	}
+	return ap
+}
+
+func (s *authService) googleOAuthConfig() (*oauth2.Config, error) {
+	if s.cfg == nil {
+		return nil, errors.New("oauth config not initialized")
+	}
+	googleCfg := s.cfg.OAuth.Google
+	if googleCfg.ClientID == "" || googleCfg.ClientSecret == "" || googleCfg.RedirectURL == "" {
+		return nil, fmt.Errorf("%w: google", repository.ErrProviderNotConfigured)
+	}
+	return &oauth2.Config{
+		ClientID:     googleCfg.ClientID,
+		ClientSecret: googleCfg.ClientSecret,
+		RedirectURL:  googleCfg.RedirectURL,
+		Scopes: []string{
+			googleoauth.UserinfoEmailScope,
+			googleoauth.UserinfoProfileScope,
+			"openid",
+		},
+		Endpoint: google.Endpoint,
+	}, nil
+}
+
+func (s *authService) linkedInOAuthConfig(ctx context.Context) (*oauth2.Config, error) {
+	if s.cfg == nil {
+		return nil, errors.New("oauth config not initialized")
+	}
+	linkedIn := s.cfg.OAuth.LinkedIn
+	if linkedIn.ClientID == "" || linkedIn.ClientSecret == "" || linkedIn.RedirectURL == "" {
+		return nil, fmt.Errorf("%w: linkedin", repository.ErrProviderNotConfigured)
+	}
+	endpoint, err := s.linkedInOIDC.Endpoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &oauth2.Config{
+		ClientID:     linkedIn.ClientID,
+		ClientSecret: linkedIn.ClientSecret,
+		RedirectURL:  linkedIn.RedirectURL,
+		Scopes:       []string{"openid", "profile", "email"},
+		Endpoint:     endpoint,
+	}, nil
+}
+
+// appleOAuthConfig returns the Sign in with Apple config with a freshly signed client
+// secret
+func (s *authService) appleOAuthConfig(ctx context.Context) (*oauth2.Config, error) {
+	if s.cfg == nil {
+		return nil, errors.New("oauth config not initialized")
+	}
+	apple := s.cfg.OAuth.Apple
+	if apple.ClientID == "" || apple.TeamID == "" || apple.KeyID == "" || apple.PrivateKey == "" || apple.RedirectURL == "" {
+		return nil, fmt.Errorf("%w: apple", repository.ErrProviderNotConfigured)
+	}
+	secret, err := oidc.AppleClientSecret(apple.TeamID, apple.ClientID, apple.KeyID, apple.PrivateKey, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	endpoint, err := s.appleOIDC.Endpoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &oauth2.Config{
+		ClientID:     apple.ClientID,
+		ClientSecret: secret,
+		RedirectURL:  apple.RedirectURL,
+		Scopes:       []string{"name", "email"},
+		Endpoint:     endpoint,
+	}, nil
+}
+
+// oauthConfig returns the OAuth config of a provider
+func (s *authService) oauthConfig(ctx context.Context, provider string) (*oauth2.Config, error) {
+	switch provider {
+	case OAuthProviderGoogle:
+		return s.googleOAuthConfig()
+	case OAuthProviderLinkedIn:
+		return s.linkedInOAuthConfig(ctx)
+	case OAuthProviderApple:
+		return s.appleOAuthConfig(ctx)
+	default:
+		return nil, fmt.Errorf("%w: unsupported provider %q", repository.ErrInvalidInput, provider)
+	}
+}
+
+// StartOAuth begins a social login and returns the provider's authorization URL along
+// with the flow's nonce. The flow's PKCE verifier and optional app redirect are kept in
+// Redis under the nonce, and the state parameter carries that nonce signed, so the
+// callback can tell its own flows from forged or replayed ones. The caller hands the
+// nonce to the browser in a cookie, tying the flow to the browser that started it.
+func (s *authService) StartOAuth(ctx context.Context, provider, appRedirectURL string) (string, string, error) {
+	if appRedirectURL != "" && !s.isAllowedAppRedirect(appRedirectURL) {
+		return "", "", fmt.Errorf("%w: redirect target is not allowed", repository.ErrInvalidInput)
+	}
+
+	conf, err := s.oauthConfig(ctx, provider)
+	if err != nil {
+		return "", "", err
+	}
+
+	raw, err := utils.GenerateRandomBytes(16)
+	if err != nil {
+		return "", "", err
+	}
+	nonce := base64.RawURLEncoding.EncodeToString(raw)
+
+	flow := OAuthFlow{
+		Provider:       provider,
+		CodeVerifier:   oauth2.GenerateVerifier(),
+		AppRedirectURL: appRedirectURL,
+	}
+	if err := s.cache.Set(ctx, oauthFlowKey(nonce), flow, oauthStateTTL); err != nil {
+		return "", "", err
+	}
+
+	state := s.signOAuthState(provider, nonce, time.Now().Add(oauthStateTTL))
+	opts := []oauth2.AuthCodeOption{oauth2.S256ChallengeOption(flow.CodeVerifier)}
+	switch provider {
+	case OAuthProviderGoogle:
+		opts = append(opts, oauth2.AccessTypeOffline, oauth2.ApprovalForce)
+	case OAuthProviderApple:
+		// Apple only returns name and email to a form_post redirect
+		opts = append(opts, oauth2.SetAuthURLParam("response_mode", "form_post"))
+	}
+	return conf.AuthCodeURL(state, opts...), nonce, nil
+}
+
+// CompleteOAuth checks the state a provider's callback came back with against the
+// nonce the browser kept from StartOAuth, and returns the flow it belongs to. Each
+// state can be used once.
+func (s *authService) CompleteOAuth(ctx context.Context, provider, state, nonce string) (*OAuthFlow, error) {
+	stateNonce, err := s.verifyOAuthState(provider, state, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	// A callback in a browser that didn't start the flow is someone else's sign-in
+	if nonce == "" || subtle.ConstantTimeCompare([]byte(stateNonce), []byte(nonce)) != 1 {
+		return nil, repository.ErrInvalidOAuthState
+	}
+
+	var flow OAuthFlow
+	if err := s.cache.GetDel(ctx, oauthFlowKey(nonce), &flow); err != nil {
+		if errors.Is(err, cache.ErrCacheMiss) {
+			return nil, repository.ErrInvalidOAuthState
+		}
+		return nil, err
+	}
+	if flow.Provider != provider {
+		return nil, repository.ErrInvalidOAuthState
+	}
+	return &flow, nil
+}
+
+// IssueOAuthCode keeps the result of a sign-in the app started and returns a one-time
+// code for its deep link, so tokens never travel in a redirect URL
+func (s *authService) IssueOAuthCode(ctx context.Context, response *AuthResponse) (string, error) {
+	code := utils.GenerateRandomString(32)
+	if err := s.cache.Set(ctx, oauthCodeKey(code), response, oauthCodeTTL); err != nil {
+		return "", err
+	}
+	return code, nil
+}
+
+// ExchangeOAuthCode returns the sign-in result behind a code from IssueOAuthCode. Each
+// code can be exchanged once.
+func (s *authService) ExchangeOAuthCode(ctx context.Context, code string) (*AuthResponse, error) {
+	if code == "" {
+		return nil, repository.ErrTokenInvalid
+	}
+	var response AuthResponse
+	if err := s.cache.GetDel(ctx, oauthCodeKey(code), &response); err != nil {
+		if errors.Is(err, cache.ErrCacheMiss) {
+			return nil, repository.ErrTokenInvalid
+		}
+		return nil, err
+	}
+	return &response, nil
+}
+
+// isAllowedAppRedirect reports whether target is one of the configured app deep links
+func (s *authService) isAllowedAppRedirect(target string) bool {
+	if s.cfg == nil {
+		return false
+	}
+	for _, allowed := range s.cfg.OAuth.AppRedirectURLs {
+		if allowed = strings.TrimSpace(allowed); allowed != "" && allowed == target {
+			return true
+		}
+	}
+	return false
+}
+
+// signOAuthState returns nonce.expiry.signature, with the signature binding the nonce
+// to the provider and expiry
+func (s *authService) signOAuthState(provider, nonce string, expiresAt time.Time) string {
+	payload := fmt.Sprintf("%s.%d", nonce, expiresAt.Unix())
+	return payload + "." + s.oauthStateMAC(provider, payload)
+}
+
+// verifyOAuthState checks a state's signature and expiry and returns its nonce
+func (s *authService) verifyOAuthState(provider, state string, now time.Time) (string, error) {
+	parts := strings.Split(state, ".")
+	if len(parts) != 3 {
+		return "", repository.ErrInvalidOAuthState
+	}
+	payload := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(parts[2]), []byte(s.oauthStateMAC(provider, payload))) {
+		return "", repository.ErrInvalidOAuthState
+	}
+	expiresAt, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || now.Unix() > expiresAt {
+		return "", repository.ErrInvalidOAuthState
+	}
+	return parts[0], nil
+}
+
+func (s *authService) oauthStateMAC(provider, payload string) string {
+	mac := hmac.New(sha256.New, []byte(s.jwtCfg.Secret))
+	mac.Write([]byte("oauth_state:" + provider + ":" + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func oauthFlowKey(nonce string) string {
+	return fmt.Sprintf("oauth_state:%s", nonce)
+}
+
+func oauthCodeKey(code string) string {
+	return fmt.Sprintf("oauth_code:%s", hashToken(code))
+}
+
+// exchangeOAuthCode trades an authorization code for the identity it asserts. The code
+// verifier is empty for codes obtained without PKCE, such as those the mobile SDKs hand
+// over for linking.
+func (s *authService) exchangeOAuthCode(ctx context.Context, provider, code, codeVerifier string) (*oauthIdentity, error) {
+	switch provider {
+	case OAuthProviderGoogle:
+		return s.exchangeGoogleCode(ctx, code, codeVerifier)
+	case OAuthProviderLinkedIn:
+		return s.exchangeLinkedInCode(ctx, code, codeVerifier)
+	case OAuthProviderApple:
+		return s.exchangeAppleCode(ctx, code, codeVerifier)
+	default:
+		return nil, fmt.Errorf("%w: unsupported provider %q", repository.ErrInvalidInput, provider)
+	}
+}
+
+// verifierOptions returns the token request options for a PKCE code verifier
+func verifierOptions(codeVerifier string) []oauth2.AuthCodeOption {
+	if codeVerifier == "" {
+		return nil
+	}
+	return []oauth2.AuthCodeOption{oauth2.VerifierOption(codeVerifier)}
+}
+
+func (s *authService) exchangeGoogleCode(ctx context.Context, code, codeVerifier string) (*oauthIdentity, error) {
+	conf, err := s.googleOAuthConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	// Exchange code for token
+	tok, err := conf.Exchange(ctx, code, verifierOptions(codeVerifier)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange code: %w", err)
+	}
+
+	// Fetch user info
+	httpClient := conf.Client(ctx, tok)
+	oauth2Service, err := googleoauth.NewService(ctx, option.WithHTTPClient(httpClient))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create google oauth2 service: %w", err)
+	}
+	userInfo, err := oauth2Service.Userinfo.V2.Me.Get().Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user info: %w", err)
+	}
+
+	return &oauthIdentity{
+		Provider:      OAuthProviderGoogle,
+		ProviderID:    userInfo.Id,
+		Email:         userInfo.Email,
+		EmailVerified: userInfo.VerifiedEmail != nil && *userInfo.VerifiedEmail,
+		Name:          userInfo.Name,
+		AvatarURL:     userInfo.Picture,
+		Token:         tok,
+		RawData:       models.JSONB{"provider": OAuthProviderGoogle},
+	}, nil
+}
+
+func (s *authService) exchangeLinkedInCode(ctx context.Context, code, codeVerifier string) (*oauthIdentity, error) {
+	conf, err := s.linkedInOAuthConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tok, idToken, err := s.linkedInOIDC.Exchange(ctx, conf, code, verifierOptions(codeVerifier)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &oauthIdentity{
+		Provider:      OAuthProviderLinkedIn,
+		ProviderID:    idToken.Subject,
+		Email:         idToken.Email,
+		EmailVerified: bool(idToken.EmailVerified),
+		Name:          idToken.Name,
+		AvatarURL:     idToken.Picture,
+		Token:         tok,
+		RawData:       models.JSONB{"provider": OAuthProviderLinkedIn, "locale": idToken.Locale},
+	}, nil
+}
+
+func (s *authService) exchangeAppleCode(ctx context.Context, code, codeVerifier string) (*oauthIdentity, error) {
+	conf, err := s.appleOAuthConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tok, idToken, err := s.appleOIDC.Exchange(ctx, conf, code, verifierOptions(codeVerifier)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &oauthIdentity{
+		Provider:      OAuthProviderApple,
+		ProviderID:    idToken.Subject,
+		Email:         idToken.Email,
+		EmailVerified: bool(idToken.EmailVerified),
+		Token:         tok,
+		RawData: models.JSONB{
+			"provider":         OAuthProviderApple,
+			"is_private_email": bool(idToken.IsPrivateEmail),
+		},
+	}, nil
+}
+
+// signInWithIdentity signs in the user an OAuth identity belongs to. Unknown identities
+// are linked to the account with the same verified email, or get a new account.
+func (s *authService) signInWithIdentity(ctx context.Context, identity *oauthIdentity, metadata SessionMetadata) (*AuthResponse, error) {
+	user, err := s.userRepo.FindByAuthProvider(ctx, identity.Provider, identity.ProviderID)
+	if err != nil && !repository.IsNotFound(err) {
+		return nil, err
+	}
+
+	signUp := false
+	if user == nil {
+		if identity.Email == "" {
+			return nil, fmt.Errorf("%w: %s did not share an email address", repository.ErrInvalidInput, identity.Provider)
+		}
+
+		existing, err := s.userRepo.FindByEmail(ctx, identity.Email)
+		switch {
+		case err == nil && (!identity.EmailVerified || !existing.EmailVerified):
+			// Linking on an unverified address would let anyone claim the account, and an
+			// account whose address was never verified may have been registered by someone
+			// else ahead of the address's real owner
+			return nil, fmt.Errorf("%w: sign in with your password and link %s from your account settings", repository.ErrUserAlreadyExists, identity.Provider)
+		case err == nil:
+			user = existing
+		case repository.IsNotFound(err):
+			if user, err = s.createOAuthUser(ctx, identity); err != nil {
+				return nil, err
+			}
+			signUp = true
+		default:
+			return nil, err
+		}
+
+		if err := s.userRepo.LinkAuthProvider(ctx, identity.authProvider(user.ID)); err != nil {
+			return nil, err
+		}
+	}
+
+	if user.Suspended() {
+		return nil, repository.ErrAccountSuspended
+	}
+
+	if user.MFAEnabled() {
+		return s.startMFAChallenge(ctx, user, identity.Provider)
+	}
+
+	// Update last login
+	_ = s.userRepo.UpdateLastLogin(ctx, user.ID)
+
+	// Generate tokens
+	tokenPair, err := s.GenerateTokenPair(ctx, user, metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	eventType := analytics.EventUserLogin
+	if signUp {
+		eventType = analytics.EventUserSignUp
+	}
+	go s.analytics.Track(context.Background(), analytics.Event{
+		UserID:    user.ID.String(),
+		EventType: eventType,
+		Properties: map[string]interface{}{
+			"method": identity.Provider,
+		},
+		Timestamp: time.Now(),
+	})
+
+	return &AuthResponse{
+		User:         s.mapUserToDTO(user),
+		AccessToken:  tokenPair.AccessToken,
+		RefreshToken: tokenPair.RefreshToken,
+		ExpiresIn:    tokenPair.ExpiresIn,
+		TokenType:    "Bearer",
+	}, nil
+}
+
+// createOAuthUser creates a passwordless account for an OAuth identity
+func (s *authService) createOAuthUser(ctx context.Context, identity *oauthIdentity) (*models.User, error) {
+	base := identity.Email
+	if idx := strings.Index(base, "@"); idx > 0 {
+		base = base[:idx]
+	}
+	username, err := s.availableUsername(ctx, base)
+	if err != nil {
+		return nil, err
+	}
+
+	displayName := identity.Name
+	if displayName == "" {
+		displayName = username
+	}
+
+	user := &models.User{
+		Username:      username,
+		Email:         identity.Email,
+		EmailVerified: identity.EmailVerified,
+		DisplayName:   displayName,
+		AvatarURL:     identity.AvatarURL,
+		Timezone:      "UTC",
+		Locale:        "en",
+	}
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// availableUsername derives an unused, valid username from base
+func (s *authService) availableUsername(ctx context.Context, base string) (string, error) {
+	var b strings.Builder
+	for _, r := range strings.ToLower(base) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
+			b.WriteRune(r)
+		}
+	}
+	base = utils.TruncateString(b.String(), 40)
+	for len(base) < 3 {
+		base += "_"
+	}
+
+	candidate := base
+	for attempt := 0; attempt < 5; attempt++ {
+		exists, err := s.userRepo.CheckUsernameExists(ctx, candidate)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s_%s", base, strings.ToLower(utils.GenerateRandomString(4)))
+	}
+	return "", fmt.Errorf("failed to find an available username for %q", base)
+}
+
+// LinkOAuthAccount adds a login method to an existing account
+func (s *authService) LinkOAuthAccount(ctx context.Context, userID uuid.UUID, provider string, code string) error {
+	identity, err := s.exchangeOAuthCode(ctx, provider, code, "")
+	if err != nil {
+		return err
+	}
+
+	owner, err := s.userRepo.FindByAuthProvider(ctx, provider, identity.ProviderID)
+	switch {
+	case err == nil && owner.ID == userID:
+		return nil
+	case err == nil:
+		return fmt.Errorf("%w: this %s account is linked to another user", repository.ErrAlreadyExists, provider)
+	case !repository.IsNotFound(err):
+		return err
+	}
+
+	linked, err := s.userRepo.GetAuthProviders(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, ap := range linked {
+		if ap.Provider == provider {
+			return fmt.Errorf("%w: another %s account is already linked; unlink it first", repository.ErrAlreadyExists, provider)
+		}
+	}
+
+	if err := s.userRepo.LinkAuthProvider(ctx, identity.authProvider(userID)); err != nil {
+		return err
+	}
+	log.Printf("[AUTH] Linked %s account to user %s", provider, userID)
+	return nil
+}
+
+// UnlinkOAuthAccount removes a login method. The last one can't be removed.
+func (s *authService) UnlinkOAuthAccount(ctx context.Context, userID uuid.UUID, provider string) error {
+	return s.userRepo.UnlinkAuthProvider(ctx, userID, provider)
+}
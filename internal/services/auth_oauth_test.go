@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/vyve/vyve-backend/internal/config"
+	"github.com/vyve/vyve-backend/internal/models"
+	"github.com/vyve/vyve-backend/internal/repository"
+	"github.com/vyve/vyve-backend/pkg/analytics"
+)
+
+func TestSignInWithIdentityLinking(t *testing.T) {
+	tests := []struct {
+		name             string
+		identityVerified bool
+		accountVerified  bool
+		wantErr          error
+	}{
+		{"both addresses verified", true, true, nil},
+		{"unverified identity", false, true, repository.ErrUserAlreadyExists},
+		{"unverified existing account", true, false, repository.ErrUserAlreadyExists},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			account := &models.User{Base: models.Base{ID: userID}, Email: "sam@example.com", EmailVerified: tt.accountVerified}
+
+			ctrl := gomock.NewController(t)
+			userRepo := repository.NewMockUserRepository(ctrl)
+			userRepo.EXPECT().
+				FindByAuthProvider(gomock.Any(), "google", "google-sam").
+				Return(nil, repository.ErrUserNotFound)
+			userRepo.EXPECT().
+				FindByEmail(gomock.Any(), "sam@example.com").
+				Return(account, nil)
+			// The provider may only be linked once both addresses are verified
+			if tt.wantErr == nil {
+				userRepo.EXPECT().
+					LinkAuthProvider(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, provider *models.AuthProvider) error {
+						if provider.UserID != userID {
+							t.Errorf("provider linked to %s, want the existing account %s", provider.UserID, userID)
+						}
+						return nil
+					})
+				userRepo.EXPECT().UpdateLastLogin(gomock.Any(), userID).Return(nil)
+				userRepo.EXPECT().SaveRefreshToken(gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			svc := &authService{
+				userRepo:  userRepo,
+				cache:     newTestCache(t),
+				jwtCfg:    config.JWTConfig{Secret: "secret", Expiry: time.Minute, RefreshTokenExpiry: time.Hour},
+				analytics: analytics.NewDatabaseAnalytics(),
+			}
+			identity := &oauthIdentity{
+				Provider:      "google",
+				ProviderID:    "google-sam",
+				Email:         "sam@example.com",
+				EmailVerified: tt.identityVerified,
+			}
+
+			resp, err := svc.signInWithIdentity(context.Background(), identity, SessionMetadata{})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("signInWithIdentity() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if resp.User.ID != userID {
+				t.Errorf("signed in as %s, want %s", resp.User.ID, userID)
+			}
+		})
+	}
+}
+
+func TestCompleteOAuth(t *testing.T) {
+	svc := &authService{
+		cache:  newTestCache(t),
+		jwtCfg: config.JWTConfig{Secret: "secret"},
+		cfg: &config.Config{OAuth: config.OAuthConfig{
+			Google: config.OAuthProvider{ClientID: "id", ClientSecret: "secret", RedirectURL: "https://api.example.com/callback"},
+		}},
+	}
+	ctx := context.Background()
+
+	start := func(t *testing.T) (string, string) {
+		t.Helper()
+		authURL, nonce, err := svc.StartOAuth(ctx, OAuthProviderGoogle, "")
+		if err != nil {
+			t.Fatalf("StartOAuth() error = %v", err)
+		}
+		parsed, err := url.Parse(authURL)
+		if err != nil {
+			t.Fatalf("invalid authorization URL %q: %v", authURL, err)
+		}
+		return parsed.Query().Get("state"), nonce
+	}
+
+	tests := []struct {
+		name    string
+		cookie  func(nonce, otherNonce string) string
+		wantErr error
+	}{
+		{"cookie from the same browser", func(nonce, _ string) string { return nonce }, nil},
+		{"no cookie", func(string, string) string { return "" }, repository.ErrInvalidOAuthState},
+		{"cookie of another flow", func(_, otherNonce string) string { return otherNonce }, repository.ErrInvalidOAuthState},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, nonce := start(t)
+			_, otherNonce := start(t)
+
+			flow, err := svc.CompleteOAuth(ctx, OAuthProviderGoogle, state, tt.cookie(nonce, otherNonce))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CompleteOAuth() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if flow.CodeVerifier == "" {
+				t.Error("flow has no PKCE verifier")
+			}
+			if _, err := svc.CompleteOAuth(ctx, OAuthProviderGoogle, state, nonce); !errors.Is(err, repository.ErrInvalidOAuthState) {
+				t.Errorf("replayed state error = %v, want %v", err, repository.ErrInvalidOAuthState)
+			}
+		})
+	}
+}
+
+func TestExchangeOAuthCode(t *testing.T) {
+	svc := &authService{cache: newTestCache(t)}
+	ctx := context.Background()
+
+	code, err := svc.IssueOAuthCode(ctx, &AuthResponse{AccessToken: "access", RefreshToken: "refresh", TokenType: "Bearer"})
+	if err != nil {
+		t.Fatalf("IssueOAuthCode() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		code       string
+		wantAccess string
+		wantErr    error
+	}{
+		{"issued code", code, "access", nil},
+		{"code already exchanged", code, "", repository.ErrTokenInvalid},
+		{"unknown code", "unknown", "", repository.ErrTokenInvalid},
+		{"no code", "", "", repository.ErrTokenInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := svc.ExchangeOAuthCode(ctx, tt.code)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ExchangeOAuthCode() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && response.AccessToken != tt.wantAccess {
+				t.Errorf("access token = %q, want %q", response.AccessToken, tt.wantAccess)
+			}
+		})
+	}
+}
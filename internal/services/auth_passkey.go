@@ -0,0 +1,418 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/vyve/vyve-backend/internal/models"
+	"github.com/vyve/vyve-backend/internal/repository"
+	"github.com/vyve/vyve-backend/pkg/analytics"
+	"github.com/vyve/vyve-backend/pkg/cache"
+	"github.com/vyve/vyve-backend/pkg/utils"
+)
+
+const (
+	// passkeyNameMaxLength bounds the label users give a passkey
+	passkeyNameMaxLength = 100
+	// passkeyTimeout is how long the browser gives the user to complete a ceremony
+	passkeyTimeout = 5 * time.Minute
+)
+
+// passkeyCeremony is a registration or sign-in in progress. VerifiedWith records how a
+// registration was confirmed when two-factor authentication is on.
+type passkeyCeremony struct {
+	Session      webauthn.SessionData `json:"session"`
+	VerifiedWith string               `json:"verified_with,omitempty"`
+}
+
+// passkeyUser presents an account and its passkeys to the WebAuthn library
+type passkeyUser struct {
+	user     *models.User
+	passkeys []*models.Passkey
+}
+
+// WebAuthnID is the user handle, the account ID, which carries no personal information
+func (u *passkeyUser) WebAuthnID() []byte {
+	return u.user.ID[:]
+}
+
+func (u *passkeyUser) WebAuthnName() string {
+	return u.user.Email
+}
+
+func (u *passkeyUser) WebAuthnDisplayName() string {
+	if u.user.DisplayName != "" {
+		return u.user.DisplayName
+	}
+	return u.user.Username
+}
+
+func (u *passkeyUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, 0, len(u.passkeys))
+	for _, passkey := range u.passkeys {
+		id, err := base64.RawURLEncoding.DecodeString(passkey.CredentialID)
+		if err != nil {
+			continue
+		}
+		transports := make([]protocol.AuthenticatorTransport, len(passkey.Transports))
+		for i, transport := range passkey.Transports {
+			transports[i] = protocol.AuthenticatorTransport(transport)
+		}
+		var aaguid []byte
+		if parsed, err := uuid.Parse(passkey.AAGUID); err == nil {
+			aaguid = parsed[:]
+		}
+		credentials = append(credentials, webauthn.Credential{
+			ID:        id,
+			PublicKey: passkey.PublicKey,
+			Transport: transports,
+			Flags: webauthn.CredentialFlags{
+				BackupEligible: passkey.BackupEligible,
+				BackupState:    passkey.BackedUp,
+			},
+			Authenticator: webauthn.Authenticator{
+				AAGUID:    aaguid,
+				SignCount: uint32(passkey.SignCount),
+			},
+		})
+	}
+	return credentials
+}
+
+// relyingParty returns the site passkeys are registered for. Passkeys are
+// discoverable, so the user can sign in without typing who they are, and must verify
+// the user, since a passkey signs in on its own. Attestation isn't asked for; the
+// public key is trusted on first use, as with a password set at sign-up.
+func (s *authService) relyingParty() (*webauthn.WebAuthn, error) {
+	if s.cfg == nil || s.cfg.Passkey.RPID == "" || len(s.cfg.Passkey.Origins) == 0 {
+		return nil, fmt.Errorf("%w: passkey", repository.ErrProviderNotConfigured)
+	}
+	name := s.cfg.Passkey.RPName
+	if name == "" {
+		name = s.cfg.Passkey.RPID
+	}
+	timeout := webauthn.TimeoutConfig{Enforce: true, Timeout: passkeyTimeout, TimeoutUVD: passkeyTimeout}
+	rp, err := webauthn.New(&webauthn.Config{
+		RPID:                  s.cfg.Passkey.RPID,
+		RPDisplayName:         name,
+		RPOrigins:             s.cfg.Passkey.Origins,
+		AttestationPreference: protocol.PreferNoAttestation,
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			ResidentKey:        protocol.ResidentKeyRequirementRequired,
+			RequireResidentKey: protocol.ResidentKeyRequired(),
+			UserVerification:   protocol.VerificationRequired,
+		},
+		Timeouts: webauthn.TimeoutsConfig{Login: timeout, Registration: timeout},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: passkey: %v", repository.ErrProviderNotConfigured, err)
+	}
+	return rp, nil
+}
+
+// BeginPasskeyRegistration returns the options for navigator.credentials.create() to
+// add a passkey to the user's account. A passkey signs in without the second factor, so
+// with two-factor authentication on, the user confirms with a current code, a recovery
+// code or their password first.
+func (s *authService) BeginPasskeyRegistration(ctx context.Context, userID uuid.UUID, code, password string, metadata SessionMetadata) (*protocol.PublicKeyCredentialCreationOptions, error) {
+	rp, err := s.relyingParty()
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	verifiedWith, err := s.confirmPasskeyRegistration(ctx, user, code, password, metadata)
+	if err != nil {
+		return nil, err
+	}
+	passkeys, err := s.userRepo.ListPasskeys(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Stop the browser from registering an authenticator that already holds a passkey
+	account := &passkeyUser{user: user, passkeys: passkeys}
+	exclude := webauthn.Credentials(account.WebAuthnCredentials()).CredentialDescriptors()
+	creation, session, err := rp.BeginRegistration(account, webauthn.WithExclusions(exclude))
+	if err != nil {
+		return nil, err
+	}
+
+	// One registration at a time per user; starting again replaces the challenge
+	key := fmt.Sprintf("passkey_registration:%s", userID)
+	if err := s.cache.Set(ctx, key, passkeyCeremony{Session: *session, VerifiedWith: verifiedWith}, passkeyTimeout); err != nil {
+		return nil, err
+	}
+	return &creation.Response, nil
+}
+
+// confirmPasskeyRegistration checks the second factor or password a registration was
+// started with when the user has two-factor authentication on, and returns which one
+// it was. Wrong passwords count towards the sign-in lockout.
+func (s *authService) confirmPasskeyRegistration(ctx context.Context, user *models.User, code, password string, metadata SessionMetadata) (string, error) {
+	switch {
+	case !user.MFAEnabled():
+		return "", nil
+	case code != "":
+		if err := s.checkSecondFactor(ctx, user, code); err != nil {
+			return "", err
+		}
+		return "mfa", nil
+	case password != "" && user.PasswordHash != "":
+		subject := loginSubject(user.ID, "")
+		if err := s.checkLoginThrottle(ctx, subject, metadata.IPAddress); err != nil {
+			return "", err
+		}
+		if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+			return "", s.recordLoginFailure(ctx, subject, metadata.IPAddress, user)
+		}
+		s.clearLoginFailures(ctx, subject)
+		return "password", nil
+	default:
+		return "", repository.ErrReauthRequired
+	}
+}
+
+// FinishPasskeyRegistration verifies the browser's response to BeginPasskeyRegistration
+// and stores the new passkey. Without a name, it is named after the device.
+func (s *authService) FinishPasskeyRegistration(ctx context.Context, userID uuid.UUID, name string, resp *protocol.ParsedCredentialCreationData, metadata SessionMetadata) (*models.Passkey, error) {
+	rp, err := s.relyingParty()
+	if err != nil {
+		return nil, err
+	}
+
+	var ceremony passkeyCeremony
+	if err := s.cache.GetDel(ctx, fmt.Sprintf("passkey_registration:%s", userID), &ceremony); err != nil {
+		if errors.Is(err, cache.ErrCacheMiss) {
+			return nil, fmt.Errorf("%w: no passkey registration in progress", repository.ErrInvalidInput)
+		}
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	credential, err := rp.CreateCredential(&passkeyUser{user: user}, ceremony.Session, resp)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", repository.ErrInvalidInput, passkeyErrorDetails(err))
+	}
+
+	credentialID := base64.RawURLEncoding.EncodeToString(credential.ID)
+	if _, err := s.userRepo.FindPasskey(ctx, credentialID); err == nil {
+		return nil, fmt.Errorf("%w: this passkey is already registered", repository.ErrAlreadyExists)
+	} else if !repository.IsNotFound(err) {
+		return nil, err
+	}
+
+	transports := make(models.StringArray, len(credential.Transport))
+	for i, transport := range credential.Transport {
+		transports[i] = string(transport)
+	}
+	passkey := &models.Passkey{
+		UserID:         userID,
+		CredentialID:   credentialID,
+		PublicKey:      credential.PublicKey,
+		SignCount:      int64(credential.Authenticator.SignCount),
+		AAGUID:         formatAAGUID(credential.Authenticator.AAGUID),
+		Transports:     transports,
+		Name:           passkeyName(name, metadata),
+		BackupEligible: credential.Flags.BackupEligible,
+		BackedUp:       credential.Flags.BackupState,
+	}
+	if err := s.userRepo.CreatePasskey(ctx, passkey); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[AUTH] Registered passkey %s for user %s", passkey.ID, userID)
+	s.auditPasskeyRegistration(ctx, passkey, ceremony.VerifiedWith, metadata)
+	return passkey, nil
+}
+
+// auditPasskeyRegistration records a new passkey, and how the registration was
+// confirmed when two-factor authentication is on
+func (s *authService) auditPasskeyRegistration(ctx context.Context, passkey *models.Passkey, verifiedWith string, metadata SessionMetadata) {
+	changes := models.JSONB{
+		"name":   passkey.Name,
+		"aaguid": passkey.AAGUID,
+	}
+	if verifiedWith != "" {
+		changes["verified_with"] = verifiedWith
+	}
+	entry := &models.AuditLog{
+		UserID:     &passkey.UserID,
+		Action:     "passkey_registered",
+		EntityType: "passkey",
+		EntityID:   passkey.ID.String(),
+		Changes:    changes,
+		IPAddress:  metadata.IPAddress,
+		UserAgent:  metadata.UserAgent,
+		Result:     "success",
+	}
+	if err := s.auditRepo.Create(ctx, entry); err != nil {
+		log.Printf("[AUTH] Failed to audit passkey registration for user %s: %v", passkey.UserID, err)
+	}
+}
+
+// BeginPasskeyLogin returns the options for navigator.credentials.get(). Passkeys are
+// discoverable, so the user picks their account in the browser rather than typing it.
+func (s *authService) BeginPasskeyLogin(ctx context.Context) (*protocol.PublicKeyCredentialRequestOptions, error) {
+	rp, err := s.relyingParty()
+	if err != nil {
+		return nil, err
+	}
+
+	assertion, session, err := rp.BeginDiscoverableLogin(webauthn.WithUserVerification(protocol.VerificationRequired))
+	if err != nil {
+		return nil, err
+	}
+	// The response carries the challenge back, so it doubles as the ceremony's key
+	if err := s.cache.Set(ctx, passkeyLoginKey(session.Challenge), passkeyCeremony{Session: *session}, passkeyTimeout); err != nil {
+		return nil, err
+	}
+	return &assertion.Response, nil
+}
+
+// FinishPasskeyLogin verifies the browser's response to BeginPasskeyLogin and signs the
+// user in. A passkey verifies the user on the device, with a PIN or biometrics, so it
+// counts as both factors and skips the MFA challenge.
+func (s *authService) FinishPasskeyLogin(ctx context.Context, resp *protocol.ParsedCredentialAssertionData, metadata SessionMetadata) (*AuthResponse, error) {
+	rp, err := s.relyingParty()
+	if err != nil {
+		return nil, err
+	}
+
+	// Each challenge signs in once
+	var ceremony passkeyCeremony
+	if err := s.cache.GetDel(ctx, passkeyLoginKey(resp.Response.CollectedClientData.Challenge), &ceremony); err != nil {
+		if errors.Is(err, cache.ErrCacheMiss) {
+			return nil, repository.ErrTokenInvalid
+		}
+		return nil, err
+	}
+
+	passkey, err := s.userRepo.FindPasskey(ctx, base64.RawURLEncoding.EncodeToString(resp.RawID))
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, repository.ErrInvalidCredentials
+		}
+		return nil, err
+	}
+	user, err := s.userRepo.FindByID(ctx, passkey.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	account := &passkeyUser{user: user, passkeys: []*models.Passkey{passkey}}
+	_, credential, err := rp.ValidatePasskeyLogin(func(rawID, userHandle []byte) (webauthn.User, error) {
+		return account, nil
+	}, ceremony.Session, resp)
+	if err != nil {
+		log.Printf("[AUTH] Passkey %s failed verification: %s", passkey.ID, passkeyErrorDetails(err))
+		return nil, repository.ErrInvalidCredentials
+	}
+	if credential.Authenticator.CloneWarning {
+		log.Printf("[AUTH] Signature counter of passkey %s went backwards; it may have been cloned", passkey.ID)
+		return nil, repository.ErrInvalidCredentials
+	}
+	if err := s.userRepo.UpdatePasskeyUsage(ctx, passkey.ID, int64(credential.Authenticator.SignCount), credential.Flags.BackupState); err != nil {
+		log.Printf("[AUTH] Failed to record use of passkey %s: %v", passkey.ID, err)
+	}
+
+	if user.Suspended() {
+		return nil, repository.ErrAccountSuspended
+	}
+
+	_ = s.userRepo.UpdateLastLogin(ctx, user.ID)
+
+	tokenPair, err := s.GenerateTokenPair(ctx, user, metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	go s.analytics.Track(context.Background(), analytics.Event{
+		UserID:    user.ID.String(),
+		EventType: analytics.EventUserLogin,
+		Properties: map[string]interface{}{
+			"method": "passkey",
+		},
+		Timestamp: time.Now(),
+	})
+
+	return &AuthResponse{
+		User:         s.mapUserToDTO(user),
+		AccessToken:  tokenPair.AccessToken,
+		RefreshToken: tokenPair.RefreshToken,
+		ExpiresIn:    tokenPair.ExpiresIn,
+		TokenType:    "Bearer",
+	}, nil
+}
+
+// ListPasskeys returns the user's passkeys
+func (s *authService) ListPasskeys(ctx context.Context, userID uuid.UUID) ([]*models.Passkey, error) {
+	return s.userRepo.ListPasskeys(ctx, userID)
+}
+
+// DeletePasskey removes one of the user's passkeys. The last way to sign in can't be removed.
+func (s *authService) DeletePasskey(ctx context.Context, userID, passkeyID uuid.UUID, metadata SessionMetadata) error {
+	if err := s.userRepo.DeletePasskey(ctx, userID, passkeyID); err != nil {
+		return err
+	}
+	s.auditSignInChange(ctx, userID, "passkey_deleted", metadata)
+	return nil
+}
+
+// passkeyName picks the label of a new passkey
+func passkeyName(name string, metadata SessionMetadata) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = metadata.DeviceName
+	}
+	if name == "" {
+		name = utils.DescribeUserAgent(metadata.UserAgent)
+	}
+	for len(name) > passkeyNameMaxLength {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	return name
+}
+
+// formatAAGUID renders an authenticator model ID in UUID form. Authenticators that don't
+// disclose their model send zeroes.
+func formatAAGUID(aaguid []byte) string {
+	if len(aaguid) != 16 {
+		return ""
+	}
+	id, _ := uuid.FromBytes(aaguid)
+	if id == uuid.Nil {
+		return ""
+	}
+	return id.String()
+}
+
+func passkeyLoginKey(challenge string) string {
+	return fmt.Sprintf("passkey_login:%s", hashToken(challenge))
+}
+
+// passkeyErrorDetails describes why the WebAuthn library rejected a response
+func passkeyErrorDetails(err error) string {
+	var protocolErr *protocol.Error
+	if errors.As(err, &protocolErr) && protocolErr.Details != "" {
+		return protocolErr.Details
+	}
+	return err.Error()
+}
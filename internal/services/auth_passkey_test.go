@@ -0,0 +1,360 @@
+package services
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/vyve/vyve-backend/internal/config"
+	"github.com/vyve/vyve-backend/internal/models"
+	"github.com/vyve/vyve-backend/internal/repository"
+	"github.com/vyve/vyve-backend/pkg/analytics"
+)
+
+const testOrigin = "https://vyve.app"
+
+// softwarePasskey is an authenticator that answers ceremonies the way a browser would
+type softwarePasskey struct {
+	credentialID []byte
+	key          *ecdsa.PrivateKey
+	signCount    uint32
+}
+
+func newSoftwarePasskey(t *testing.T) *softwarePasskey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &softwarePasskey{credentialID: []byte(uuid.NewString()), key: key}
+}
+
+// authData returns authenticator data with the user present and verified, and the
+// credential synced
+func (p *softwarePasskey) authData(t *testing.T, attested bool) []byte {
+	t.Helper()
+	rpIDHash := sha256.Sum256([]byte("vyve.app"))
+	flags := byte(protocol.FlagUserPresent | protocol.FlagUserVerified | protocol.FlagBackupEligible | protocol.FlagBackupState)
+	if attested {
+		flags |= byte(protocol.FlagAttestedCredentialData)
+	}
+	data := append(rpIDHash[:], flags)
+	data = binary.BigEndian.AppendUint32(data, p.signCount)
+	if !attested {
+		return data
+	}
+
+	x := make([]byte, 32)
+	y := make([]byte, 32)
+	p.key.X.FillBytes(x)
+	p.key.Y.FillBytes(y)
+	coseKey, err := webauthncbor.Marshal(map[int]interface{}{1: 2, 3: -7, -1: 1, -2: x, -3: y})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = append(data, make([]byte, 16)...)
+	data = binary.BigEndian.AppendUint16(data, uint16(len(p.credentialID)))
+	data = append(data, p.credentialID...)
+	return append(data, coseKey...)
+}
+
+func clientData(t *testing.T, ceremony string, challenge []byte) []byte {
+	t.Helper()
+	raw, err := json.Marshal(map[string]string{
+		"type":      ceremony,
+		"challenge": base64.RawURLEncoding.EncodeToString(challenge),
+		"origin":    testOrigin,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+func (p *softwarePasskey) create(t *testing.T, options *protocol.PublicKeyCredentialCreationOptions) *protocol.ParsedCredentialCreationData {
+	t.Helper()
+	attestation, err := webauthncbor.Marshal(map[string]interface{}{
+		"fmt":      "none",
+		"attStmt":  map[string]interface{}{},
+		"authData": p.authData(t, true),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parse(t, p, protocol.ParseCredentialCreationResponseBytes, map[string]interface{}{
+		"clientDataJSON":    protocol.URLEncodedBase64(clientData(t, "webauthn.create", options.Challenge)),
+		"attestationObject": protocol.URLEncodedBase64(attestation),
+		"transports":        []string{"internal", "hybrid"},
+	})
+}
+
+func (p *softwarePasskey) get(t *testing.T, options *protocol.PublicKeyCredentialRequestOptions, userHandle []byte) *protocol.ParsedCredentialAssertionData {
+	t.Helper()
+	authData := p.authData(t, false)
+	data := clientData(t, "webauthn.get", options.Challenge)
+	clientDataHash := sha256.Sum256(data)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, p.key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parse(t, p, protocol.ParseCredentialRequestResponseBytes, map[string]interface{}{
+		"clientDataJSON":    protocol.URLEncodedBase64(data),
+		"authenticatorData": protocol.URLEncodedBase64(authData),
+		"signature":         protocol.URLEncodedBase64(signature),
+		"userHandle":        protocol.URLEncodedBase64(userHandle),
+	})
+}
+
+// parse wraps an authenticator response in a PublicKeyCredential and parses it the way
+// the handlers do
+func parse[T any](t *testing.T, p *softwarePasskey, parseFn func([]byte) (T, error), response map[string]interface{}) T {
+	t.Helper()
+	body, err := json.Marshal(map[string]interface{}{
+		"id":       base64.RawURLEncoding.EncodeToString(p.credentialID),
+		"rawId":    protocol.URLEncodedBase64(p.credentialID),
+		"type":     "public-key",
+		"response": response,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := parseFn(body)
+	if err != nil {
+		t.Fatalf("parsing the credential: %v", err)
+	}
+	return parsed
+}
+
+// passkeyStore keeps passkeys and recovery codes in memory, and serves user from
+// FindByID
+type passkeyStore struct {
+	*repository.MockUserRepository
+	passkeys []*models.Passkey
+	// unused recovery codes by hash
+	recoveryCodes map[string]bool
+}
+
+func newPasskeyStore(t *testing.T, user *models.User) *passkeyStore {
+	t.Helper()
+	store := &passkeyStore{MockUserRepository: repository.NewMockUserRepository(gomock.NewController(t))}
+	store.EXPECT().FindByID(gomock.Any(), user.ID).Return(user, nil).AnyTimes()
+	store.EXPECT().UpdateLastLogin(gomock.Any(), user.ID).Return(nil).AnyTimes()
+	store.EXPECT().SaveRefreshToken(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	return store
+}
+
+func (r *passkeyStore) ConsumeRecoveryCode(ctx context.Context, id uuid.UUID, codeHash string) (bool, error) {
+	if !r.recoveryCodes[codeHash] {
+		return false, nil
+	}
+	delete(r.recoveryCodes, codeHash)
+	return true, nil
+}
+
+func (r *passkeyStore) CreatePasskey(ctx context.Context, passkey *models.Passkey) error {
+	passkey.ID = uuid.New()
+	r.passkeys = append(r.passkeys, passkey)
+	return nil
+}
+
+func (r *passkeyStore) FindPasskey(ctx context.Context, credentialID string) (*models.Passkey, error) {
+	for _, passkey := range r.passkeys {
+		if passkey.CredentialID == credentialID {
+			return passkey, nil
+		}
+	}
+	return nil, repository.ErrPasskeyNotFound
+}
+
+func (r *passkeyStore) ListPasskeys(ctx context.Context, userID uuid.UUID) ([]*models.Passkey, error) {
+	var passkeys []*models.Passkey
+	for _, passkey := range r.passkeys {
+		if passkey.UserID == userID {
+			passkeys = append(passkeys, passkey)
+		}
+	}
+	return passkeys, nil
+}
+
+func (r *passkeyStore) UpdatePasskeyUsage(ctx context.Context, id uuid.UUID, signCount int64, backedUp bool) error {
+	for _, passkey := range r.passkeys {
+		if passkey.ID == id {
+			passkey.SignCount = signCount
+			passkey.BackedUp = backedUp
+		}
+	}
+	return nil
+}
+
+func newPasskeyService(t *testing.T, userRepo repository.UserRepository, auditRepo repository.AuditLogRepository) *authService {
+	t.Helper()
+	return &authService{
+		userRepo:  userRepo,
+		auditRepo: auditRepo,
+		cache:     newTestCache(t),
+		jwtCfg:    config.JWTConfig{Secret: "secret", Expiry: time.Minute, RefreshTokenExpiry: time.Hour},
+		cfg: &config.Config{Passkey: config.PasskeyConfig{
+			RPID:    "vyve.app",
+			RPName:  "Vyve",
+			Origins: []string{testOrigin},
+		}},
+		analytics: analytics.NewDatabaseAnalytics(),
+	}
+}
+
+func TestPasskeyRegistration(t *testing.T) {
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte("Longenough1!"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mfaEnabledAt := time.Now()
+
+	tests := []struct {
+		name         string
+		mfa          bool
+		code         string
+		password     string
+		wantErr      error
+		verifiedWith string
+	}{
+		{name: "two-factor off", wantErr: nil},
+		{name: "two-factor on without confirmation", mfa: true, wantErr: repository.ErrReauthRequired},
+		{name: "recovery code", mfa: true, code: "abcde-fghij", verifiedWith: "mfa"},
+		{name: "wrong code", mfa: true, code: "000000", wantErr: repository.ErrInvalidMFACode},
+		{name: "password", mfa: true, password: "Longenough1!", verifiedWith: "password"},
+		{name: "wrong password", mfa: true, password: "wrong", wantErr: repository.ErrInvalidCredentials},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			user := &models.User{Base: models.Base{ID: userID}, Email: "sam@example.com", PasswordHash: string(passwordHash)}
+			if tt.mfa {
+				user.MFAEnabledAt = &mfaEnabledAt
+			}
+			userRepo := newPasskeyStore(t, user)
+			userRepo.recoveryCodes = map[string]bool{hashToken("abcdefghij"): true}
+
+			// Only a completed registration is audited
+			var logs []*models.AuditLog
+			auditRepo := repository.NewMockAuditLogRepository(gomock.NewController(t))
+			auditRepo.EXPECT().
+				Create(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, entry *models.AuditLog) error {
+					logs = append(logs, entry)
+					return nil
+				}).
+				AnyTimes()
+			svc := newPasskeyService(t, userRepo, auditRepo)
+			ctx := context.Background()
+
+			options, err := svc.BeginPasskeyRegistration(ctx, userID, tt.code, tt.password, SessionMetadata{})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("BeginPasskeyRegistration() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if _, err := svc.FinishPasskeyRegistration(ctx, userID, "", newSoftwarePasskey(t).create(t, &protocol.PublicKeyCredentialCreationOptions{Challenge: make([]byte, 32)}), SessionMetadata{}); !errors.Is(err, repository.ErrInvalidInput) {
+					t.Errorf("finishing an unconfirmed registration error = %v, want %v", err, repository.ErrInvalidInput)
+				}
+				return
+			}
+
+			passkey, err := svc.FinishPasskeyRegistration(ctx, userID, "Laptop", newSoftwarePasskey(t).create(t, options), SessionMetadata{})
+			if err != nil {
+				t.Fatalf("FinishPasskeyRegistration() error = %v", err)
+			}
+			if len(userRepo.passkeys) != 1 || !passkey.BackupEligible || len(passkey.Transports) != 2 {
+				t.Errorf("stored passkeys %+v", userRepo.passkeys)
+			}
+
+			if len(logs) != 1 {
+				t.Fatalf("%d audit entries, want 1", len(logs))
+			}
+			entry := logs[0]
+			if entry.Action != "passkey_registered" || entry.EntityID != passkey.ID.String() {
+				t.Errorf("unexpected audit entry %+v", entry)
+			}
+			if got, _ := entry.Changes["verified_with"].(string); got != tt.verifiedWith {
+				t.Errorf("audited verified_with = %q, want %q", got, tt.verifiedWith)
+			}
+		})
+	}
+}
+
+func TestPasskeyLogin(t *testing.T) {
+	userID := uuid.New()
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		signCount  uint32
+		storedSign int64
+		userHandle []byte
+		wantErr    error
+	}{
+		{name: "synced passkey", userHandle: userID[:]},
+		{name: "counter increased", signCount: 8, storedSign: 7, userHandle: userID[:]},
+		{name: "counter went backwards", signCount: 6, storedSign: 7, userHandle: userID[:], wantErr: repository.ErrInvalidCredentials},
+		{name: "another user's handle", userHandle: []byte("someone-else"), wantErr: repository.ErrInvalidCredentials},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := newPasskeyStore(t, &models.User{Base: models.Base{ID: userID}, Email: "sam@example.com"})
+			auditRepo := repository.NewMockAuditLogRepository(gomock.NewController(t))
+			auditRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+			svc := newPasskeyService(t, userRepo, auditRepo)
+
+			// Register, then rewind the stored counter to the case's
+			authenticator := newSoftwarePasskey(t)
+			options, err := svc.BeginPasskeyRegistration(ctx, userID, "", "", SessionMetadata{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			passkey, err := svc.FinishPasskeyRegistration(ctx, userID, "", authenticator.create(t, options), SessionMetadata{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			passkey.SignCount = tt.storedSign
+			authenticator.signCount = tt.signCount
+
+			request, err := svc.BeginPasskeyLogin(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertion := authenticator.get(t, request, tt.userHandle)
+
+			response, err := svc.FinishPasskeyLogin(ctx, assertion, SessionMetadata{})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("FinishPasskeyLogin() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if response.User.ID != userID || response.AccessToken == "" {
+				t.Errorf("signed in as %s with token %q", response.User.ID, response.AccessToken)
+			}
+			if passkey.SignCount != int64(tt.signCount) {
+				t.Errorf("stored counter = %d, want %d", passkey.SignCount, tt.signCount)
+			}
+			if _, err := svc.FinishPasskeyLogin(ctx, assertion, SessionMetadata{}); !errors.Is(err, repository.ErrTokenInvalid) {
+				t.Errorf("replayed assertion error = %v, want %v", err, repository.ErrTokenInvalid)
+			}
+		})
+	}
+}
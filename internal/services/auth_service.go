@@ -2,75 +2,134 @@ package services
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"log"
 	"strings"
 	"time"
 
+	"github.com/go-webauthn/webauthn/protocol"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
-	oauth2 "golang.org/x/oauth2"
-	"golang.org/x/oauth2/google"
-	googleoauth "google.golang.org/api/oauth2/v2"
-	"google.golang.org/api/option"
 
 	"github.com/vyve/vyve-backend/internal/config"
 	"github.com/vyve/vyve-backend/internal/models"
 	"github.com/vyve/vyve-backend/internal/repository"
 	"github.com/vyve/vyve-backend/pkg/analytics"
 	"github.com/vyve/vyve-backend/pkg/cache"
+	"github.com/vyve/vyve-backend/pkg/email"
+	"github.com/vyve/vyve-backend/pkg/oidc"
 	"github.com/vyve/vyve-backend/pkg/utils"
 )
 
+const (
+	verifyEmailTokenTTL   = 24 * time.Hour
+	passwordResetTokenTTL = time.Hour
+	emailSendTimeout      = time.Minute
+	verifyResendInterval  = time.Minute
+
+	// A reset link is emailed to an address at most once per passwordResetInterval,
+	// and one IP may request passwordResetIPLimit links per passwordResetIPWindow
+	passwordResetInterval = time.Minute
+	passwordResetIPLimit  = 10
+	passwordResetIPWindow = time.Hour
+
+	// Signed-out refresh tokens are kept this long for auditing; rotated tokens are kept
+	// until they expire so reuse is still detected
+	revokedRefreshTokenRetention = 7 * 24 * time.Hour
+)
+
+// dummyPasswordHash is checked when no account with a password matches a sign-in, so
+// it takes as long as a wrong password and the timing doesn't reveal which accounts
+// exist. Its cost must match the cost passwords are hashed with.
+var dummyPasswordHash = []byte("$2a$10$2LMEOVe7jYdpfEGnkRLV4ehsfBvmBhu6mcqIW0zqPPNWpzHo/2BKG")
+
 // AuthService handles authentication logic
 type AuthService interface {
 	Register(ctx context.Context, req RegisterRequest) (*AuthResponse, error)
 	Login(ctx context.Context, req LoginRequest) (*AuthResponse, error)
-	RefreshToken(ctx context.Context, refreshToken string) (*AuthResponse, error)
+	RefreshToken(ctx context.Context, refreshToken string, metadata SessionMetadata) (*AuthResponse, error)
 	Logout(ctx context.Context, userID uuid.UUID, token string) error
 	LogoutAll(ctx context.Context, userID uuid.UUID) error
 	VerifyEmail(ctx context.Context, token string) error
-	ForgotPassword(ctx context.Context, email string) error
+	ForgotPassword(ctx context.Context, email, ip string) error
 	ResetPassword(ctx context.Context, token, newPassword string) error
-	ChangePassword(ctx context.Context, userID uuid.UUID, oldPassword, newPassword string) error
+	ChangePassword(ctx context.Context, userID uuid.UUID, sessionID, oldPassword, newPassword string, metadata SessionMetadata) (*AuthResponse, error)
+	ResendVerificationEmail(ctx context.Context, userID uuid.UUID) error
+	IsEmailVerified(ctx context.Context, userID uuid.UUID) (bool, error)
+	UnlockAccount(ctx context.Context, actorID, userID uuid.UUID) error
+
+	// Two-factor authentication
+	GetMFAStatus(ctx context.Context, userID uuid.UUID) (*MFAStatus, error)
+	EnrollMFA(ctx context.Context, userID uuid.UUID) (*MFAEnrollment, error)
+	ConfirmMFA(ctx context.Context, userID uuid.UUID, code string, metadata SessionMetadata) ([]string, error)
+	DisableMFA(ctx context.Context, userID uuid.UUID, code string, metadata SessionMetadata) error
+	RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, code string, metadata SessionMetadata) ([]string, error)
+	VerifyMFA(ctx context.Context, mfaToken, code string, metadata SessionMetadata) (*AuthResponse, error)
+
+	// Passkeys
+	BeginPasskeyRegistration(ctx context.Context, userID uuid.UUID, code, password string, metadata SessionMetadata) (*protocol.PublicKeyCredentialCreationOptions, error)
+	FinishPasskeyRegistration(ctx context.Context, userID uuid.UUID, name string, resp *protocol.ParsedCredentialCreationData, metadata SessionMetadata) (*models.Passkey, error)
+	BeginPasskeyLogin(ctx context.Context) (*protocol.PublicKeyCredentialRequestOptions, error)
+	FinishPasskeyLogin(ctx context.Context, resp *protocol.ParsedCredentialAssertionData, metadata SessionMetadata) (*AuthResponse, error)
+	ListPasskeys(ctx context.Context, userID uuid.UUID) ([]*models.Passkey, error)
+	DeletePasskey(ctx context.Context, userID, passkeyID uuid.UUID, metadata SessionMetadata) error
 
 	// OAuth methods
-	GetGoogleAuthURL(state string) string
-	HandleGoogleAuth(ctx context.Context, code string) (*AuthResponse, error)
-	HandleLinkedInAuth(ctx context.Context, code string) (*AuthResponse, error)
-	HandleAppleAuth(ctx context.Context, code string) (*AuthResponse, error)
+	StartOAuth(ctx context.Context, provider, appRedirectURL string) (string, string, error)
+	CompleteOAuth(ctx context.Context, provider, state, nonce string) (*OAuthFlow, error)
+	IssueOAuthCode(ctx context.Context, response *AuthResponse) (string, error)
+	ExchangeOAuthCode(ctx context.Context, code string) (*AuthResponse, error)
+	HandleGoogleAuth(ctx context.Context, code, codeVerifier string, metadata SessionMetadata) (*AuthResponse, error)
+	HandleLinkedInAuth(ctx context.Context, code, codeVerifier string, metadata SessionMetadata) (*AuthResponse, error)
+	HandleAppleAuth(ctx context.Context, code, codeVerifier, name string, metadata SessionMetadata) (*AuthResponse, error)
 	LinkOAuthAccount(ctx context.Context, userID uuid.UUID, provider string, code string) error
 	UnlinkOAuthAccount(ctx context.Context, userID uuid.UUID, provider string) error
 
 	// Token methods
-	GenerateTokenPair(ctx context.Context, user *models.User) (*TokenPair, error)
+	GenerateTokenPair(ctx context.Context, user *models.User, metadata SessionMetadata) (*TokenPair, error)
 	ValidateToken(ctx context.Context, tokenString string) (*Claims, error)
 	RevokeToken(ctx context.Context, token string) error
+	PurgeStaleRefreshTokens(ctx context.Context) (int64, error)
 
 	// Session management
 	CreateSession(ctx context.Context, userID uuid.UUID, metadata SessionMetadata) (*Session, error)
 	GetSession(ctx context.Context, sessionID string) (*Session, error)
 	EndSession(ctx context.Context, sessionID string) error
+	ListSessions(ctx context.Context, userID uuid.UUID, currentSessionID string) ([]*SessionInfo, error)
+	RevokeSession(ctx context.Context, userID uuid.UUID, sessionID string) error
 }
 
 type authService struct {
 	userRepo  repository.UserRepository
+	auditRepo repository.AuditLogRepository
 	cache     cache.Cache
 	jwtCfg    config.JWTConfig
 	cfg       *config.Config
 	analytics analytics.Analytics
+	mailer    email.Mailer
+
+	linkedInOIDC *oidc.Provider
+	appleOIDC    *oidc.Provider
 }
 
 // NewAuthService creates a new auth service
-func NewAuthService(userRepo repository.UserRepository, cache cache.Cache, jwtCfg config.JWTConfig, cfg *config.Config, analyticsService analytics.Analytics) AuthService {
+func NewAuthService(userRepo repository.UserRepository, auditRepo repository.AuditLogRepository, cache cache.Cache, jwtCfg config.JWTConfig, cfg *config.Config, analyticsService analytics.Analytics, mailer email.Mailer) AuthService {
 	return &authService{
 		userRepo:  userRepo,
+		auditRepo: auditRepo,
 		cache:     cache,
 		jwtCfg:    jwtCfg,
 		cfg:       cfg,
 		analytics: analyticsService,
+		mailer:    mailer,
+
+		linkedInOIDC: oidc.NewProvider(oidc.IssuerLinkedIn, nil),
+		appleOIDC:    oidc.NewProvider(oidc.IssuerApple, nil),
 	}
 }
 
@@ -80,20 +139,29 @@ type RegisterRequest struct {
 	Email       string `json:"email" validate:"required,email"`
 	Password    string `json:"password" validate:"required,min=8"`
 	DisplayName string `json:"display_name" validate:"omitempty,min=2,max=50"`
+
+	Session SessionMetadata `json:"-"`
 }
 
 type LoginRequest struct {
 	Username string `json:"username,omitempty"`
 	Email    string `json:"email,omitempty"`
 	Password string `json:"password" validate:"required"`
+
+	Session SessionMetadata `json:"-"`
 }
 
+// AuthResponse carries the tokens of a sign-in. When the user has two-factor
+// authentication on, it only carries MFAToken, to be completed with VerifyMFA, and
+// ExpiresIn is the time left to do so.
 type AuthResponse struct {
-	User         *UserDTO `json:"user"`
-	AccessToken  string   `json:"access_token"`
-	RefreshToken string   `json:"refresh_token"`
+	User         *UserDTO `json:"user,omitempty"`
+	AccessToken  string   `json:"access_token,omitempty"`
+	RefreshToken string   `json:"refresh_token,omitempty"`
 	ExpiresIn    int      `json:"expires_in"`
-	TokenType    string   `json:"token_type"`
+	TokenType    string   `json:"token_type,omitempty"`
+	MFARequired  bool     `json:"mfa_required,omitempty"`
+	MFAToken     string   `json:"mfa_token,omitempty"`
 }
 
 type TokenPair struct {
@@ -103,13 +171,25 @@ type TokenPair struct {
 }
 
 type Claims struct {
-	UserID    uuid.UUID `json:"user_id"`
-	Email     string    `json:"email"`
-	Username  string    `json:"username"`
-	SessionID string    `json:"session_id"`
+	UserID        uuid.UUID `json:"user_id"`
+	Email         string    `json:"email"`
+	Username      string    `json:"username"`
+	SessionID     string    `json:"session_id"`
+	EmailVerified bool      `json:"email_verified"`
+	Roles         []string  `json:"roles,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// HasRole reports whether the token was issued to a user holding role
+func (c *Claims) HasRole(role string) bool {
+	for _, r := range c.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
 type Session struct {
 	ID        string          `json:"id"`
 	UserID    uuid.UUID       `json:"user_id"`
@@ -119,23 +199,40 @@ type Session struct {
 }
 
 type SessionMetadata struct {
-	IPAddress string `json:"ip_address"`
-	UserAgent string `json:"user_agent"`
-	DeviceID  string `json:"device_id,omitempty"`
+	IPAddress  string `json:"ip_address"`
+	UserAgent  string `json:"user_agent"`
+	DeviceID   string `json:"device_id,omitempty"`
+	DeviceName string `json:"device_name,omitempty"`
+}
+
+// SessionInfo describes one signed-in device
+type SessionInfo struct {
+	ID         string    `json:"id"`
+	Device     string    `json:"device"`
+	DeviceID   string    `json:"device_id,omitempty"`
+	IPAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent"`
+	SignedInAt time.Time `json:"signed_in_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
 }
 
 type UserDTO struct {
-	ID          uuid.UUID  `json:"id"`
-	Username    string     `json:"username"`
-	Email       string     `json:"email"`
-	DisplayName string     `json:"display_name"`
-	AvatarURL   string     `json:"avatar_url"`
-	Bio         string     `json:"bio"`
-	Timezone    string     `json:"timezone"`
-	Locale      string     `json:"locale"`
-	StreakCount int        `json:"streak_count"`
-	LastLoginAt *time.Time `json:"last_login_at"`
-	CreatedAt   time.Time  `json:"created_at"`
+	ID            uuid.UUID  `json:"id"`
+	Username      string     `json:"username"`
+	Email         string     `json:"email"`
+	EmailVerified bool       `json:"email_verified"`
+	DisplayName   string     `json:"display_name"`
+	AvatarURL     string     `json:"avatar_url"`
+	Bio           string     `json:"bio"`
+	Timezone      string     `json:"timezone"`
+	Locale        string     `json:"locale"`
+	StreakCount   int        `json:"streak_count"`
+	Roles         []string   `json:"roles"`
+	MFAEnabled    bool       `json:"mfa_enabled"`
+	LastLoginAt   *time.Time `json:"last_login_at"`
+	CreatedAt     time.Time  `json:"created_at"`
 }
 
 // Register registers a new user
@@ -185,13 +282,13 @@ func (s *authService) Register(ctx context.Context, req RegisterRequest) (*AuthR
 	}
 
 	// Generate tokens
-	tokenPair, err := s.GenerateTokenPair(ctx, user)
+	tokenPair, err := s.GenerateTokenPair(ctx, user, req.Session)
 	if err != nil {
 		return nil, err
 	}
 
 	// Send verification email
-	go s.sendVerificationEmail(user.Email, user.ID.String())
+	go s.sendVerificationEmail(user)
 
 	// Track sign up event
 	go s.analytics.Track(ctx, analytics.Event{
@@ -215,47 +312,53 @@ func (s *authService) Register(ctx context.Context, req RegisterRequest) (*AuthR
 
 // Login authenticates a user
 func (s *authService) Login(ctx context.Context, req LoginRequest) (*AuthResponse, error) {
-	log.Printf("Login attempt for email: %s", req.Email)
 	var user *models.User
 	var err error
 
 	// Find user by email or username
+	identifier := req.Email
 	if req.Email != "" {
-		log.Printf("Looking up user by email: %s", req.Email)
 		user, err = s.userRepo.FindByEmail(ctx, req.Email)
 	} else if req.Username != "" {
-		log.Printf("Looking up user by username: %s", req.Username)
+		identifier = req.Username
 		user, err = s.userRepo.FindByUsername(ctx, req.Username)
 	} else {
-		log.Println("No email or username provided")
 		return nil, errors.New("email or username required")
 	}
 
-	if err != nil {
-		log.Printf("Error finding user: %v", err)
-		if repository.IsNotFound(err) {
-			log.Println("User not found")
-			return nil, repository.ErrInvalidCredentials
-		}
+	userID := uuid.Nil
+	switch {
+	case err == nil:
+		userID = user.ID
+	case repository.IsNotFound(err):
+		user = nil
+	default:
 		return nil, fmt.Errorf("error finding user: %w", err)
 	}
 
-	log.Printf("User found: ID=%s, Email=%s, Username=%s, EmailVerified=%v",
-		user.ID, user.Email, user.Username, user.EmailVerified)
+	subject := loginSubject(userID, identifier)
+	if err := s.checkLoginThrottle(ctx, subject, req.Session.IPAddress); err != nil {
+		return nil, err
+	}
 
 	// Check password
-	log.Printf("user hash %v", user.PasswordHash)
-	log.Printf("user password %v", req.Password)
+	hash := dummyPasswordHash
+	if user != nil && user.PasswordHash != "" {
+		hash = []byte(user.PasswordHash)
+	}
+	if bcrypt.CompareHashAndPassword(hash, []byte(req.Password)) != nil || user == nil || user.PasswordHash == "" {
+		return nil, s.recordLoginFailure(ctx, subject, req.Session.IPAddress, user)
+	}
+	s.clearLoginFailures(ctx, subject)
 
-	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password))
-	if err != nil {
-		log.Printf("Password comparison failed: %v", err)
-		log.Printf("Stored hash length: %d, starts with: %s...",
-			len(user.PasswordHash), safeSubstring(user.PasswordHash, 0, 10))
-		return nil, repository.ErrInvalidCredentials
+	// Only reveal a suspension to someone who knows the password
+	if user.Suspended() {
+		return nil, repository.ErrAccountSuspended
 	}
 
-	log.Println("Password check passed")
+	if user.MFAEnabled() {
+		return s.startMFAChallenge(ctx, user, "password")
+	}
 
 	// Update last login
 	if err := s.userRepo.UpdateLastLogin(ctx, user.ID); err != nil {
@@ -264,7 +367,7 @@ func (s *authService) Login(ctx context.Context, req LoginRequest) (*AuthRespons
 	}
 
 	// Generate tokens
-	tokenPair, err := s.GenerateTokenPair(ctx, user)
+	tokenPair, err := s.GenerateTokenPair(ctx, user, req.Session)
 	if err != nil {
 		return nil, err
 	}
@@ -289,17 +392,16 @@ func (s *authService) Login(ctx context.Context, req LoginRequest) (*AuthRespons
 	}, nil
 }
 
-// RefreshToken refreshes access token using refresh token
-func (s *authService) RefreshToken(ctx context.Context, refreshToken string) (*AuthResponse, error) {
-	// Find refresh token
-	token, err := s.userRepo.FindRefreshToken(ctx, refreshToken)
+// RefreshToken exchanges a refresh token for new tokens in the same session. Each
+// refresh token can be exchanged once; presenting one again signs the session out.
+func (s *authService) RefreshToken(ctx context.Context, refreshToken string, metadata SessionMetadata) (*AuthResponse, error) {
+	// Rotate refresh token
+	token, err := s.userRepo.RotateRefreshToken(ctx, hashToken(refreshToken))
 	if err != nil {
-		return nil, repository.ErrTokenInvalid
-	}
-
-	// Check if expired
-	if time.Now().After(token.ExpiresAt) {
-		return nil, repository.ErrTokenExpired
+		if errors.Is(err, repository.ErrTokenReused) {
+			s.revokeTokenFamily(ctx, token, metadata)
+		}
+		return nil, err
 	}
 
 	// Get user
@@ -308,14 +410,22 @@ func (s *authService) RefreshToken(ctx context.Context, refreshToken string) (*A
 		return nil, err
 	}
 
-	// Revoke old refresh token
-	if err := s.userRepo.RevokeRefreshToken(ctx, refreshToken); err != nil {
-		// Log error but continue
-		fmt.Printf("Failed to revoke old refresh token: %v\n", err)
+	// Generate new tokens, keeping the device details the client doesn't resend
+	if metadata.DeviceID == "" {
+		metadata.DeviceID = token.DeviceID
 	}
-
-	// Generate new tokens
-	tokenPair, err := s.GenerateTokenPair(ctx, user)
+	if metadata.DeviceName == "" {
+		metadata.DeviceName = token.DeviceName
+	}
+	sessionID := token.SessionID
+	if sessionID == "" {
+		sessionID = uuid.New().String()
+	}
+	signedInAt := token.SignedInAt
+	if signedInAt.IsZero() {
+		signedInAt = token.CreatedAt
+	}
+	tokenPair, err := s.issueTokenPair(ctx, user, sessionID, signedInAt, metadata)
 	if err != nil {
 		return nil, err
 	}
@@ -340,7 +450,7 @@ func (s *authService) Logout(ctx context.Context, userID uuid.UUID, token string
 
 	// Revoke refresh token if provided
 	if token != "" {
-		if err := s.userRepo.RevokeRefreshToken(ctx, token); err != nil {
+		if err := s.userRepo.RevokeRefreshToken(ctx, hashToken(token)); err != nil {
 			// Log error but don't fail logout
 			fmt.Printf("Failed to revoke refresh token: %v\n", err)
 		}
@@ -363,10 +473,18 @@ func (s *authService) LogoutAll(ctx context.Context, userID uuid.UUID) error {
 	return s.cache.DeletePattern(ctx, sessionPattern)
 }
 
-// GenerateTokenPair generates access and refresh tokens
-func (s *authService) GenerateTokenPair(ctx context.Context, user *models.User) (*TokenPair, error) {
-	// Create session
-	sessionID := uuid.New().String()
+// GenerateTokenPair generates access and refresh tokens for a new session
+func (s *authService) GenerateTokenPair(ctx context.Context, user *models.User, metadata SessionMetadata) (*TokenPair, error) {
+	return s.issueTokenPair(ctx, user, uuid.New().String(), time.Now(), metadata)
+}
+
+// issueTokenPair generates access and refresh tokens for a session and (re)caches it
+func (s *authService) issueTokenPair(ctx context.Context, user *models.User, sessionID string, signedInAt time.Time, metadata SessionMetadata) (*TokenPair, error) {
+	// Every sign-in and refresh ends here, so no path hands a suspended user tokens
+	if user.Suspended() {
+		return nil, repository.ErrAccountSuspended
+	}
+
 	sessionKey := fmt.Sprintf("session:%s:%s", user.ID.String(), sessionID)
 
 	// Generate access token
@@ -374,10 +492,12 @@ func (s *authService) GenerateTokenPair(ctx context.Context, user *models.User)
 	expiresAt := now.Add(s.jwtCfg.Expiry)
 
 	claims := &Claims{
-		UserID:    user.ID,
-		Email:     user.Email,
-		Username:  user.Username,
-		SessionID: sessionID,
+		UserID:        user.ID,
+		Email:         user.Email,
+		Username:      user.Username,
+		SessionID:     sessionID,
+		EmailVerified: user.EmailVerified,
+		Roles:         user.Roles,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(now),
@@ -395,10 +515,17 @@ func (s *authService) GenerateTokenPair(ctx context.Context, user *models.User)
 	}
 
 	// Generate refresh token
+	rawRefreshToken := utils.GenerateRandomString(64)
 	refreshToken := &models.RefreshToken{
-		UserID:    user.ID,
-		Token:     utils.GenerateRandomString(64),
-		ExpiresAt: now.Add(s.jwtCfg.RefreshTokenExpiry),
+		UserID:     user.ID,
+		TokenHash:  hashToken(rawRefreshToken),
+		ExpiresAt:  now.Add(s.jwtCfg.RefreshTokenExpiry),
+		IPAddress:  metadata.IPAddress,
+		UserAgent:  metadata.UserAgent,
+		SessionID:  sessionID,
+		DeviceID:   metadata.DeviceID,
+		DeviceName: metadata.DeviceName,
+		SignedInAt: signedInAt,
 	}
 
 	if err := s.userRepo.SaveRefreshToken(ctx, refreshToken); err != nil {
@@ -409,8 +536,9 @@ func (s *authService) GenerateTokenPair(ctx context.Context, user *models.User)
 	session := &Session{
 		ID:        sessionID,
 		UserID:    user.ID,
-		CreatedAt: now,
+		CreatedAt: signedInAt,
 		ExpiresAt: expiresAt,
+		Metadata:  metadata,
 	}
 
 	if err := s.cache.Set(ctx, sessionKey, session, s.jwtCfg.Expiry); err != nil {
@@ -420,7 +548,7 @@ func (s *authService) GenerateTokenPair(ctx context.Context, user *models.User)
 
 	return &TokenPair{
 		AccessToken:  accessToken,
-		RefreshToken: refreshToken.Token,
+		RefreshToken: rawRefreshToken,
 		ExpiresIn:    int(s.jwtCfg.Expiry.Seconds()),
 	}, nil
 }
@@ -455,197 +583,315 @@ func (s *authService) ValidateToken(ctx context.Context, tokenString string) (*C
 
 // Helper methods
 
-// safeSubstring returns a substring of s from start to start+length, or the entire string if out of bounds
-func safeSubstring(s string, start, length int) string {
-	runes := []rune(s)
-	if start >= len(runes) {
-		return ""
+func (s *authService) mapUserToDTO(user *models.User) *UserDTO {
+	return &UserDTO{
+		ID:            user.ID,
+		Username:      user.Username,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified,
+		DisplayName:   user.DisplayName,
+		AvatarURL:     user.AvatarURL,
+		Bio:           user.Bio,
+		Timezone:      user.Timezone,
+		Locale:        user.Locale,
+		StreakCount:   user.StreakCount,
+		Roles:         user.Roles,
+		MFAEnabled:    user.MFAEnabled(),
+		LastLoginAt:   user.LastLoginAt,
+		CreatedAt:     user.CreatedAt,
 	}
-	end := start + length
-	if end > len(runes) {
-		end = len(runes)
+}
+
+// sendVerificationEmail emails an email verification link to the user
+func (s *authService) sendVerificationEmail(user *models.User) {
+	ctx, cancel := context.WithTimeout(context.Background(), emailSendTimeout)
+	defer cancel()
+
+	token, err := s.issueActionToken(ctx, user, models.TokenPurposeVerifyEmail, verifyEmailTokenTTL)
+	if err != nil {
+		log.Printf("[AUTH] Failed to create verification token for user %s: %v", user.ID, err)
+		return
 	}
-	return string(runes[start:end])
+
+	link := fmt.Sprintf("%s/api/v1/auth/verify-email/%s", strings.TrimRight(s.cfg.Email.AppURL, "/"), token)
+	s.sendEmail(ctx, user, email.TemplateVerifyEmail, email.Data{
+		"Name":      displayNameOf(user),
+		"Link":      link,
+		"ExpiresIn": int(verifyEmailTokenTTL.Hours()),
+	})
 }
 
-func (s *authService) mapUserToDTO(user *models.User) *UserDTO {
-	return &UserDTO{
-		ID:          user.ID,
-		Username:    user.Username,
-		Email:       user.Email,
-		DisplayName: user.DisplayName,
-		AvatarURL:   user.AvatarURL,
-		Bio:         user.Bio,
-		Timezone:    user.Timezone,
-		Locale:      user.Locale,
-		StreakCount: user.StreakCount,
-		LastLoginAt: user.LastLoginAt,
-		CreatedAt:   user.CreatedAt,
+// sendPasswordResetEmail emails a password reset link to the user
+func (s *authService) sendPasswordResetEmail(user *models.User) {
+	ctx, cancel := context.WithTimeout(context.Background(), emailSendTimeout)
+	defer cancel()
+
+	token, err := s.issueActionToken(ctx, user, models.TokenPurposePasswordReset, passwordResetTokenTTL)
+	if err != nil {
+		log.Printf("[AUTH] Failed to create password reset token for user %s: %v", user.ID, err)
+		return
 	}
+
+	link := fmt.Sprintf("%s?token=%s", s.cfg.Email.PasswordResetURL, token)
+	s.sendEmail(ctx, user, email.TemplatePasswordReset, email.Data{
+		"Name":      displayNameOf(user),
+		"Link":      link,
+		"ExpiresIn": int(passwordResetTokenTTL.Minutes()),
+	})
 }
 
-func (s *authService) sendVerificationEmail(email, userID string) {
-	// TODO: Implement email sending
-	fmt.Printf("Sending verification email to %s for user %s\n", email, userID)
+// sendEmail renders a template in the user's locale and sends it, logging failures
+func (s *authService) sendEmail(ctx context.Context, user *models.User, tmpl email.Template, data email.Data) {
+	msg, err := email.Render(tmpl, user.Locale, user.Email, data)
+	if err != nil {
+		log.Printf("[AUTH] Failed to render %s email: %v", tmpl, err)
+		return
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		log.Printf("[AUTH] Failed to send %s email to user %s: %v", tmpl, user.ID, err)
+	}
 }
 
-// Stub implementations for other methods
-func (s *authService) VerifyEmail(ctx context.Context, token string) error {
-	// TODO: Implement
-	return nil
+// issueActionToken creates a single-use token for the user and returns it. Only its hash
+// is stored, and any earlier unused token for the same purpose stops working.
+func (s *authService) issueActionToken(ctx context.Context, user *models.User, purpose string, ttl time.Duration) (string, error) {
+	raw, err := utils.GenerateRandomBytes(32)
+	if err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	err = s.userRepo.CreateActionToken(ctx, &models.ActionToken{
+		UserID:    user.ID,
+		Purpose:   purpose,
+		TokenHash: hashToken(token),
+		Email:     user.Email,
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
 }
 
-func (s *authService) ForgotPassword(ctx context.Context, email string) error {
-	// TODO: Implement
-	return nil
+// hashToken returns the hex SHA-256 of a token. Email and refresh tokens are only
+// stored hashed.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
 
-func (s *authService) ResetPassword(ctx context.Context, token, newPassword string) error {
-	// TODO: Implement
-	return nil
+// displayNameOf returns the name used to greet a user in emails
+func displayNameOf(user *models.User) string {
+	if user.DisplayName != "" {
+		return user.DisplayName
+	}
+	return user.Username
 }
 
-func (s *authService) ChangePassword(ctx context.Context, userID uuid.UUID, oldPassword, newPassword string) error {
-	// TODO: Implement
+// VerifyEmail consumes a verification token and marks the address it was sent to as verified
+func (s *authService) VerifyEmail(ctx context.Context, token string) error {
+	actionToken, err := s.userRepo.ConsumeActionToken(ctx, models.TokenPurposeVerifyEmail, hashToken(token))
+	if err != nil {
+		return err
+	}
+
+	// The link only verifies the address it was sent to
+	verified, err := s.userRepo.MarkEmailVerified(ctx, actionToken.UserID, actionToken.Email)
+	if err != nil {
+		return err
+	}
+	if !verified {
+		return repository.ErrTokenInvalid
+	}
+
 	return nil
 }
 
-func (s *authService) GetGoogleAuthURL(state string) string {
-	if s.cfg == nil {
-		return ""
+// ResendVerificationEmail sends a new verification link, at most once per
+// verifyResendInterval. Earlier links stop working.
+func (s *authService) ResendVerificationEmail(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerified {
+		return repository.ErrEmailAlreadyVerified
+	}
+
+	key := fmt.Sprintf("verify_email_resend:%s", userID)
+	first, err := s.cache.SetNX(ctx, key, true, verifyResendInterval)
+	if err != nil {
+		return err
 	}
-	conf := &oauth2.Config{
-		ClientID:     s.cfg.OAuth.Google.ClientID,
-		ClientSecret: s.cfg.OAuth.Google.ClientSecret,
-		RedirectURL:  s.cfg.OAuth.Google.RedirectURL,
-		Scopes: []string{
-			googleoauth.UserinfoEmailScope,
-			googleoauth.UserinfoProfileScope,
-			"openid",
-		},
-		Endpoint: google.Endpoint,
+	if !first {
+		return repository.ErrTooManyRequests
 	}
-	return conf.AuthCodeURL(state, oauth2.AccessTypeOffline, oauth2.ApprovalForce)
+
+	go s.sendVerificationEmail(user)
+	return nil
 }
 
-func (s *authService) HandleGoogleAuth(ctx context.Context, code string) (*AuthResponse, error) {
-	if s.cfg == nil {
-		return nil, errors.New("oauth config not initialized")
+// IsEmailVerified reports whether the user's current email address is verified
+func (s *authService) IsEmailVerified(ctx context.Context, userID uuid.UUID) (bool, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return false, err
 	}
+	return user.EmailVerified, nil
+}
 
-	if s.cfg.OAuth.Google.ClientID == "" || s.cfg.OAuth.Google.ClientSecret == "" || s.cfg.OAuth.Google.RedirectURL == "" {
-		return nil, errors.New("google oauth is not configured")
+// ForgotPassword emails a password reset link. Unknown addresses and addresses sent a
+// link within passwordResetInterval are ignored, so the response does not reveal which
+// emails have accounts. An IP asking too often gets ErrTooManyRequests, whatever the
+// address.
+func (s *authService) ForgotPassword(ctx context.Context, emailAddress, ip string) error {
+	if ip != "" {
+		requests, err := s.cache.IncrementWithTTL(ctx, "password_reset_ip:"+ip, passwordResetIPWindow)
+		if err != nil {
+			return err
+		}
+		if requests > passwordResetIPLimit {
+			return repository.ErrTooManyRequests
+		}
 	}
 
-	// Build OAuth2 config
-	conf := &oauth2.Config{
-		ClientID:     s.cfg.OAuth.Google.ClientID,
-		ClientSecret: s.cfg.OAuth.Google.ClientSecret,
-		RedirectURL:  s.cfg.OAuth.Google.RedirectURL,
-		Scopes: []string{
-			googleoauth.UserinfoEmailScope,
-			googleoauth.UserinfoProfileScope,
-			"openid",
-		},
-		Endpoint: google.Endpoint,
+	// Keyed by the address typed rather than the account, so unknown addresses are
+	// throttled alike
+	emailAddress = strings.TrimSpace(emailAddress)
+	key := "password_reset:" + hashToken(strings.ToLower(emailAddress))
+	first, err := s.cache.SetNX(ctx, key, true, passwordResetInterval)
+	if err != nil {
+		return err
+	}
+	if !first {
+		return nil
 	}
 
-	// Exchange code for token
-	tok, err := conf.Exchange(ctx, code)
+	user, err := s.userRepo.FindByEmail(ctx, emailAddress)
 	if err != nil {
-		return nil, fmt.Errorf("failed to exchange code: %w", err)
+		if repository.IsNotFound(err) {
+			return nil
+		}
+		return err
 	}
 
-	// Fetch user info
-	httpClient := conf.Client(ctx, tok)
-	oauth2Service, err := googleoauth.NewService(ctx, option.WithHTTPClient(httpClient))
+	go s.sendPasswordResetEmail(user)
+	return nil
+}
+
+// ResetPassword sets a new password with a reset token and signs the user out everywhere
+func (s *authService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	if !utils.IsValidPassword(newPassword) {
+		return fmt.Errorf("%w: password must be at least 8 characters and include upper and lower case letters, a number and a symbol", repository.ErrInvalidInput)
+	}
+
+	actionToken, err := s.userRepo.ConsumeActionToken(ctx, models.TokenPurposePasswordReset, hashToken(token))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create google oauth2 service: %w", err)
+		return err
 	}
-	userInfo, err := oauth2Service.Userinfo.V2.Me.Get().Do()
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user info: %w", err)
+		return err
+	}
+	if err := s.userRepo.UpdatePassword(ctx, actionToken.UserID, string(hashedPassword)); err != nil {
+		return err
 	}
 
-	// userInfo contains Id, Email, VerifiedEmail, Name, GivenName, FamilyName, Picture
-	provider := "google"
-	providerID := userInfo.Id
+	// Receiving the link proves ownership of the address
+	if _, err := s.userRepo.MarkEmailVerified(ctx, actionToken.UserID, actionToken.Email); err != nil {
+		log.Printf("[AUTH] Failed to mark email verified for user %s: %v", actionToken.UserID, err)
+	}
 
-	// Try to find by provider
-	var user *models.User
-	user, err = s.userRepo.FindByAuthProvider(ctx, provider, providerID)
-	if err != nil {
-		if !repository.IsNotFound(err) {
-			return nil, err
-		}
+	if err := s.LogoutAll(ctx, actionToken.UserID); err != nil {
+		log.Printf("[AUTH] Failed to end sessions of user %s after password reset: %v", actionToken.UserID, err)
 	}
+	s.clearLoginFailures(ctx, loginSubject(actionToken.UserID, ""))
 
-	if user == nil {
-		// Try find by email
-		if userInfo.Email != "" {
-			u, err := s.userRepo.FindByEmail(ctx, userInfo.Email)
-			if err == nil {
-				user = u
-			} else if !repository.IsNotFound(err) {
-				return nil, err
-			}
-		}
+	go s.analytics.Track(context.Background(), analytics.Event{
+		UserID:    actionToken.UserID.String(),
+		EventType: analytics.EventPasswordChanged,
+		Properties: map[string]interface{}{
+			"method": "reset",
+		},
+		Timestamp: time.Now(),
+	})
+
+	return nil
+}
+
+// ChangePassword replaces the user's password after checking the current one. Every
+// other session is signed out; the calling session gets fresh tokens.
+func (s *authService) ChangePassword(ctx context.Context, userID uuid.UUID, sessionID, oldPassword, newPassword string, metadata SessionMetadata) (*AuthResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
 	}
 
-	if user == nil {
-		// Create new user
-		username := userInfo.Email
-		if idx := strings.Index(username, "@"); idx > 0 {
-			username = username[:idx]
-		}
-		displayName := userInfo.Name
-		if displayName == "" {
-			displayName = username
-		}
-		user = &models.User{
-			Username:      username,
-			Email:         userInfo.Email,
-			EmailVerified: userInfo.VerifiedEmail != nil && *userInfo.VerifiedEmail,
-			DisplayName:   displayName,
-			AvatarURL:     userInfo.Picture,
-			Timezone:      "UTC",
-			Locale:        "en",
-		}
-		if err := s.userRepo.Create(ctx, user); err != nil {
-			return nil, err
-		}
+	if user.PasswordHash == "" {
+		return nil, fmt.Errorf("%w: account has no password; use forgot password to set one", repository.ErrInvalidInput)
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(oldPassword)); err != nil {
+		return nil, repository.ErrInvalidCredentials
+	}
+	if !utils.IsValidPassword(newPassword) {
+		return nil, fmt.Errorf("%w: password must be at least 8 characters and include upper and lower case letters, a number and a symbol", repository.ErrInvalidInput)
+	}
+	if newPassword == oldPassword {
+		return nil, fmt.Errorf("%w: new password must differ from the current one", repository.ErrInvalidInput)
 	}
 
-	// Link provider (idempotent create)
-	var expiresAt *time.Time
-	if tok.Expiry.After(time.Now()) {
-		t := tok.Expiry
-		expiresAt = &t
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
 	}
-	ap := &models.AuthProvider{
-		UserID:       user.ID,
-		Provider:     provider,
-		ProviderID:   providerID,
-		AccessToken:  tok.AccessToken,
-		RefreshToken: tok.RefreshToken,
-		ExpiresAt:    expiresAt,
-		RawData:      models.JSONB{"provider": provider},
+
+	// Keep the current session's sign-in time and device across the re-issue
+	signedInAt := time.Now()
+	tokens, err := s.userRepo.ListActiveRefreshTokens(ctx, userID)
+	if err != nil {
+		return nil, err
 	}
-	// Best-effort: try to create, ignore if exists
-	if err := s.userRepo.LinkAuthProvider(ctx, ap); err != nil {
-		// continue on unique violation or similar; repository layer not exposing, so just log
-		log.Printf("LinkAuthProvider error (continuing): %v", err)
+	for _, token := range tokens {
+		if token.SessionID == sessionID {
+			if !token.SignedInAt.IsZero() {
+				signedInAt = token.SignedInAt
+			}
+			if metadata.DeviceID == "" {
+				metadata.DeviceID = token.DeviceID
+			}
+			if metadata.DeviceName == "" {
+				metadata.DeviceName = token.DeviceName
+			}
+			break
+		}
 	}
 
-	// Update last login
-	_ = s.userRepo.UpdateLastLogin(ctx, user.ID)
+	if err := s.userRepo.UpdatePassword(ctx, userID, string(hashedPassword)); err != nil {
+		return nil, err
+	}
+	if err := s.LogoutAll(ctx, userID); err != nil {
+		return nil, err
+	}
 
-	// Generate tokens
-	tokenPair, err := s.GenerateTokenPair(ctx, user)
+	if sessionID == "" {
+		sessionID = uuid.New().String()
+	}
+	tokenPair, err := s.issueTokenPair(ctx, user, sessionID, signedInAt, metadata)
 	if err != nil {
 		return nil, err
 	}
 
+	go s.analytics.Track(context.Background(), analytics.Event{
+		UserID:    userID.String(),
+		EventType: analytics.EventPasswordChanged,
+		Properties: map[string]interface{}{
+			"method": "change",
+		},
+		Timestamp: time.Now(),
+	})
+
 	return &AuthResponse{
 		User:         s.mapUserToDTO(user),
 		AccessToken:  tokenPair.AccessToken,
@@ -655,46 +901,92 @@ func (s *authService) HandleGoogleAuth(ctx context.Context, code string) (*AuthR
 	}, nil
 }
 
-func (s *authService) HandleLinkedInAuth(ctx context.Context, code string) (*AuthResponse, error) {
-	// LinkedIn OAuth implementation placeholder
-	// In production, this would:
-	// 1. Exchange code for access token
-	// 2. Fetch user info from LinkedIn API
-	// 3. Create or update user account
-	// 4. Generate JWT tokens
-	return nil, errors.New("LinkedIn OAuth not configured - please add LinkedIn OAuth credentials to enable this feature")
+func (s *authService) HandleGoogleAuth(ctx context.Context, code, codeVerifier string, metadata SessionMetadata) (*AuthResponse, error) {
+	identity, err := s.exchangeGoogleCode(ctx, code, codeVerifier)
+	if err != nil {
+		return nil, err
+	}
+	return s.signInWithIdentity(ctx, identity, metadata)
+}
+
+// HandleLinkedInAuth signs in with LinkedIn through OpenID Connect
+func (s *authService) HandleLinkedInAuth(ctx context.Context, code, codeVerifier string, metadata SessionMetadata) (*AuthResponse, error) {
+	identity, err := s.exchangeLinkedInCode(ctx, code, codeVerifier)
+	if err != nil {
+		return nil, err
+	}
+	return s.signInWithIdentity(ctx, identity, metadata)
 }
 
-func (s *authService) HandleAppleAuth(ctx context.Context, code string) (*AuthResponse, error) {
-	// Apple Sign In implementation placeholder
-	// In production, this would:
-	// 1. Verify Apple ID token
-	// 2. Extract user info
-	// 3. Create or update user account
-	// 4. Generate JWT tokens
-	return nil, errors.New("Apple Sign In not configured - please add Apple OAuth credentials to enable this feature")
+// HandleAppleAuth signs in with Apple. Apple sends the user's name only on the first
+// sign-in, alongside the code rather than in the ID token.
+func (s *authService) HandleAppleAuth(ctx context.Context, code, codeVerifier, name string, metadata SessionMetadata) (*AuthResponse, error) {
+	identity, err := s.exchangeAppleCode(ctx, code, codeVerifier)
+	if err != nil {
+		return nil, err
+	}
+	identity.Name = name
+	return s.signInWithIdentity(ctx, identity, metadata)
 }
 
-func (s *authService) LinkOAuthAccount(ctx context.Context, userID uuid.UUID, provider string, code string) error {
-	// Link an OAuth account to an existing user
-	// This allows users to add additional login methods
-	user, err := s.userRepo.FindByID(ctx, userID)
+func (s *authService) RevokeToken(ctx context.Context, token string) error {
+	return s.userRepo.RevokeRefreshToken(ctx, hashToken(token))
+}
+
+// revokeTokenFamily signs out the session of a refresh token that was presented after
+// it had been rotated. Either the client or whoever copied the token is replaying it,
+// and there's no telling which, so the whole family goes.
+func (s *authService) revokeTokenFamily(ctx context.Context, token *models.RefreshToken, metadata SessionMetadata) {
+	revoked, err := s.userRepo.RevokeSessionTokens(ctx, token.UserID, token.SessionID)
 	if err != nil {
-		return err
+		log.Printf("[AUTH] Failed to revoke token family %s of user %s: %v", token.SessionID, token.UserID, err)
 	}
 
-	// In production, exchange code for provider tokens and link account
-	// For now, return placeholder
-	_ = user
-	return errors.New("OAuth account linking not fully implemented - provider: " + provider)
+	sessionKey := fmt.Sprintf("session:%s:%s", token.UserID.String(), token.SessionID)
+	if err := s.cache.Delete(ctx, sessionKey); err != nil {
+		log.Printf("[AUTH] Failed to end session %s of user %s: %v", token.SessionID, token.UserID, err)
+	}
+
+	log.Printf("[AUTH] Refresh token reuse detected for user %s, session %s; revoked %d tokens", token.UserID, token.SessionID, revoked)
+
+	entry := &models.AuditLog{
+		UserID:     &token.UserID,
+		Action:     "refresh_token_reused",
+		EntityType: "session",
+		EntityID:   token.SessionID,
+		Changes: models.JSONB{
+			"token_id":       token.ID,
+			"used_at":        token.UsedAt,
+			"revoked_tokens": revoked,
+		},
+		IPAddress:    metadata.IPAddress,
+		UserAgent:    metadata.UserAgent,
+		SessionID:    token.SessionID,
+		Result:       "failure",
+		ErrorMessage: "refresh token presented after rotation",
+	}
+	if err := s.auditRepo.Create(ctx, entry); err != nil {
+		log.Printf("[AUTH] Failed to audit refresh token reuse for user %s: %v", token.UserID, err)
+	}
 }
 
-func (s *authService) UnlinkOAuthAccount(ctx context.Context, userID uuid.UUID, provider string) error {
-	return s.userRepo.UnlinkAuthProvider(ctx, userID, provider)
+// PurgeStaleRefreshTokens is run periodically to clean up the refresh_tokens table
+func PurgeStaleRefreshTokens(authService AuthService) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	purged, err := authService.PurgeStaleRefreshTokens(ctx)
+	if err != nil {
+		log.Printf("[AUTH] Failed to purge stale refresh tokens: %v", err)
+	}
+	if purged > 0 {
+		log.Printf("[AUTH] Purged %d stale refresh tokens", purged)
+	}
 }
 
-func (s *authService) RevokeToken(ctx context.Context, token string) error {
-	return s.userRepo.RevokeRefreshToken(ctx, token)
+// PurgeStaleRefreshTokens deletes expired refresh tokens and old signed-out ones
+func (s *authService) PurgeStaleRefreshTokens(ctx context.Context) (int64, error) {
+	return s.userRepo.DeleteStaleRefreshTokens(ctx, time.Now().Add(-revokedRefreshTokenRetention))
 }
 
 func (s *authService) CreateSession(ctx context.Context, userID uuid.UUID, metadata SessionMetadata) (*Session, error) {
@@ -732,3 +1024,79 @@ func (s *authService) EndSession(ctx context.Context, sessionID string) error {
 }
 
 // (duplicate removed)
+
+// ListSessions returns the user's signed-in devices, most recently used first
+func (s *authService) ListSessions(ctx context.Context, userID uuid.UUID, currentSessionID string) ([]*SessionInfo, error) {
+	tokens, err := s.userRepo.ListActiveRefreshTokens(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A session can briefly hold more than one active token; the newest one wins
+	sessions := make([]*SessionInfo, 0, len(tokens))
+	seen := make(map[string]bool, len(tokens))
+	for _, token := range tokens {
+		if seen[token.SessionID] {
+			continue
+		}
+		seen[token.SessionID] = true
+
+		device := token.DeviceName
+		if device == "" {
+			device = utils.DescribeUserAgent(token.UserAgent)
+		}
+		signedInAt := token.SignedInAt
+		if signedInAt.IsZero() {
+			signedInAt = token.CreatedAt
+		}
+
+		sessions = append(sessions, &SessionInfo{
+			ID:         token.SessionID,
+			Device:     device,
+			DeviceID:   token.DeviceID,
+			IPAddress:  token.IPAddress,
+			UserAgent:  token.UserAgent,
+			SignedInAt: signedInAt,
+			LastUsedAt: token.CreatedAt,
+			ExpiresAt:  token.ExpiresAt,
+			Current:    token.SessionID == currentSessionID,
+		})
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession signs out one device: its refresh tokens are revoked and its access
+// tokens stop validating
+func (s *authService) RevokeSession(ctx context.Context, userID uuid.UUID, sessionID string) error {
+	revoked, err := s.userRepo.RevokeSessionTokens(ctx, userID, sessionID)
+	if err != nil {
+		return err
+	}
+
+	sessionKey := fmt.Sprintf("session:%s:%s", userID.String(), sessionID)
+	cached, err := s.cache.Exists(ctx, sessionKey)
+	if err != nil {
+		return err
+	}
+	if revoked == 0 && !cached {
+		return repository.ErrSessionNotFound
+	}
+	if cached {
+		if err := s.cache.Delete(ctx, sessionKey); err != nil {
+			return err
+		}
+	}
+
+	go s.analytics.Track(context.Background(), analytics.Event{
+		UserID:    userID.String(),
+		EventType: analytics.EventUserLogout,
+		Properties: map[string]interface{}{
+			"session_id": sessionID,
+			"remote":     true,
+		},
+		Timestamp: time.Now(),
+	})
+
+	return nil
+}
@@ -0,0 +1,288 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/vyve/vyve-backend/internal/config"
+	"github.com/vyve/vyve-backend/internal/models"
+	"github.com/vyve/vyve-backend/internal/repository"
+	"github.com/vyve/vyve-backend/pkg/cache"
+	"github.com/vyve/vyve-backend/pkg/email"
+)
+
+// newTestCache returns a cache backed by an in-memory Redis server, for tests that
+// need its expiry and atomic operations
+func newTestCache(t *testing.T) cache.Cache {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	c, err := cache.NewRedisClient(config.RedisConfig{URL: "redis://" + mr.Addr()})
+	if err != nil {
+		t.Fatalf("connecting to the test Redis server: %v", err)
+	}
+	return c
+}
+
+func TestResetPasswordStrength(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	userRepo := repository.NewMockUserRepository(ctrl)
+	userRepo.EXPECT().
+		ConsumeActionToken(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, repository.ErrTokenInvalid).
+		AnyTimes()
+	svc := &authService{userRepo: userRepo}
+
+	tests := []struct {
+		name     string
+		password string
+		wantErr  error
+	}{
+		{"too short", "Ab1!", repository.ErrInvalidInput},
+		{"letters only", "longenoughpassword", repository.ErrInvalidInput},
+		{"no symbol", "Longenough1", repository.ErrInvalidInput},
+		// A strong password gets as far as the token check
+		{"strong", "Longenough1!", repository.ErrTokenInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := svc.ResetPassword(context.Background(), "token", tt.password)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ResetPassword() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestForgotPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	user := &models.User{Base: models.Base{ID: uuid.New()}, Email: "sam@example.com"}
+	userRepo := repository.NewMockUserRepository(ctrl)
+	userRepo.EXPECT().
+		FindByEmail(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, address string) (*models.User, error) {
+			if address == user.Email {
+				return user, nil
+			}
+			return nil, repository.ErrUserNotFound
+		}).
+		AnyTimes()
+	sent := make(chan string, 10)
+	userRepo.EXPECT().
+		CreateActionToken(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, token *models.ActionToken) error {
+			sent <- token.Email
+			return nil
+		}).
+		AnyTimes()
+
+	svc := &authService{
+		userRepo: userRepo,
+		cache:    newTestCache(t),
+		cfg:      &config.Config{},
+		mailer:   email.NewLogMailer(),
+	}
+	ctx := context.Background()
+
+	// emailsSent waits briefly for the links sent in the background
+	emailsSent := func() int {
+		count := 0
+		for {
+			select {
+			case <-sent:
+				count++
+			case <-time.After(100 * time.Millisecond):
+				return count
+			}
+		}
+	}
+
+	// A repeated request within the interval gets the same answer without an email,
+	// whether or not the address has an account
+	for _, address := range []string{"sam@example.com", " SAM@example.com", "nobody@example.com", "nobody@example.com"} {
+		if err := svc.ForgotPassword(ctx, address, "203.0.113.1"); err != nil {
+			t.Errorf("ForgotPassword(%q) error = %v", address, err)
+		}
+	}
+	if n := emailsSent(); n != 1 {
+		t.Errorf("sent %d reset emails, want 1", n)
+	}
+
+	// One IP is limited however many addresses it tries
+	for i := 0; i < passwordResetIPLimit-4; i++ {
+		if err := svc.ForgotPassword(ctx, uuid.NewString()+"@example.com", "203.0.113.1"); err != nil {
+			t.Fatalf("request %d error = %v", i, err)
+		}
+	}
+	if err := svc.ForgotPassword(ctx, "another@example.com", "203.0.113.1"); !errors.Is(err, repository.ErrTooManyRequests) {
+		t.Errorf("ForgotPassword() over the IP limit error = %v, want ErrTooManyRequests", err)
+	}
+	if err := svc.ForgotPassword(ctx, "another@example.com", "203.0.113.2"); err != nil {
+		t.Errorf("ForgotPassword() from another IP error = %v", err)
+	}
+}
+
+func TestDummyPasswordHashCost(t *testing.T) {
+	// A cheaper dummy hash would make unknown-account logins measurably faster
+	cost, err := bcrypt.Cost(dummyPasswordHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cost != bcrypt.DefaultCost {
+		t.Errorf("dummy hash cost = %d, want %d", cost, bcrypt.DefaultCost)
+	}
+}
+
+// refreshTokenStore keeps refresh tokens in memory so a test can follow a token family
+// through rotation and revocation
+type refreshTokenStore struct {
+	*repository.MockUserRepository
+	// refresh tokens by hash, and the sessions whose tokens were revoked
+	tokens          map[string]*models.RefreshToken
+	revokedSessions []string
+}
+
+func (r *refreshTokenStore) SaveRefreshToken(ctx context.Context, token *models.RefreshToken) error {
+	r.tokens[token.TokenHash] = token
+	return nil
+}
+
+func (r *refreshTokenStore) RotateRefreshToken(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	token, ok := r.tokens[tokenHash]
+	switch {
+	case !ok:
+		return nil, repository.ErrTokenInvalid
+	case token.UsedAt != nil:
+		return token, repository.ErrTokenReused
+	case token.Revoked:
+		return nil, repository.ErrTokenRevoked
+	}
+	now := time.Now()
+	token.UsedAt = &now
+	token.Revoked = true
+	return token, nil
+}
+
+func (r *refreshTokenStore) RevokeSessionTokens(ctx context.Context, userID uuid.UUID, sessionID string) (int64, error) {
+	var revoked int64
+	for _, token := range r.tokens {
+		if token.UserID == userID && token.SessionID == sessionID && !token.Revoked {
+			token.Revoked = true
+			revoked++
+		}
+	}
+	r.revokedSessions = append(r.revokedSessions, sessionID)
+	return revoked, nil
+}
+
+func TestRefreshToken(t *testing.T) {
+	userID := uuid.New()
+	suspendedID := uuid.New()
+	suspendedAt := time.Now().Add(-time.Hour)
+	usedAt := time.Now().Add(-time.Minute)
+	users := map[uuid.UUID]*models.User{
+		userID:      {Base: models.Base{ID: userID}},
+		suspendedID: {Base: models.Base{ID: suspendedID}, SuspendedAt: &suspendedAt},
+	}
+
+	tests := []struct {
+		name        string
+		token       string
+		wantErr     error
+		wantRevoked bool
+	}{
+		{name: "active token rotates", token: "active"},
+		{name: "replayed token signs the session out", token: "used", wantErr: repository.ErrTokenReused, wantRevoked: true},
+		{name: "unknown token", token: "unknown", wantErr: repository.ErrTokenInvalid},
+		{name: "suspended user", token: "suspended", wantErr: repository.ErrAccountSuspended},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			userRepo := &refreshTokenStore{
+				MockUserRepository: repository.NewMockUserRepository(ctrl),
+				tokens: map[string]*models.RefreshToken{
+					hashToken("active"):    {UserID: userID, SessionID: "s1", DeviceID: "phone", ExpiresAt: time.Now().Add(time.Hour)},
+					hashToken("used"):      {UserID: userID, SessionID: "s2", Revoked: true, UsedAt: &usedAt},
+					hashToken("successor"): {UserID: userID, SessionID: "s2", ExpiresAt: time.Now().Add(time.Hour)},
+					hashToken("suspended"): {UserID: suspendedID, SessionID: "s3", ExpiresAt: time.Now().Add(time.Hour)},
+				},
+			}
+			userRepo.EXPECT().
+				FindByID(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, id uuid.UUID) (*models.User, error) {
+					return users[id], nil
+				}).
+				AnyTimes()
+
+			auditRepo := repository.NewMockAuditLogRepository(ctrl)
+			if tt.wantRevoked {
+				auditRepo.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, entry *models.AuditLog) error {
+						if entry.Action != "refresh_token_reused" || entry.SessionID != "s2" {
+							t.Errorf("audit entry %q for session %q, want refresh_token_reused for s2", entry.Action, entry.SessionID)
+						}
+						return nil
+					})
+			}
+
+			sessions := newTestCache(t)
+			replayedSession := "session:" + userID.String() + ":s2"
+			if err := sessions.Set(context.Background(), replayedSession, "live", time.Hour); err != nil {
+				t.Fatal(err)
+			}
+
+			svc := &authService{
+				userRepo:  userRepo,
+				auditRepo: auditRepo,
+				cache:     sessions,
+				jwtCfg:    config.JWTConfig{Secret: "secret", Expiry: time.Minute, RefreshTokenExpiry: time.Hour},
+			}
+
+			resp, err := svc.RefreshToken(context.Background(), tt.token, SessionMetadata{})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RefreshToken() error = %v, want %v", err, tt.wantErr)
+			}
+
+			live, err := sessions.Exists(context.Background(), replayedSession)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantRevoked {
+				if successor := userRepo.tokens[hashToken("successor")]; !successor.Revoked {
+					t.Error("the rest of the token family was not revoked")
+				}
+				if live {
+					t.Error("the replayed session was not ended")
+				}
+			} else if len(userRepo.revokedSessions) != 0 || !live {
+				t.Errorf("sessions %v revoked for a non-replayed token", userRepo.revokedSessions)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			next, ok := userRepo.tokens[hashToken(resp.RefreshToken)]
+			if !ok {
+				t.Fatal("the new refresh token was not saved")
+			}
+			if next.SessionID != "s1" || next.DeviceID != "phone" {
+				t.Errorf("new token has session %q and device %q, want the previous token's", next.SessionID, next.DeviceID)
+			}
+
+			auditRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+			if _, err := svc.RefreshToken(context.Background(), "active", SessionMetadata{}); !errors.Is(err, repository.ErrTokenReused) {
+				t.Errorf("second exchange error = %v, want %v", err, repository.ErrTokenReused)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vyve/vyve-backend/internal/models"
+	"github.com/vyve/vyve-backend/internal/repository"
+	"github.com/vyve/vyve-backend/pkg/cache"
+	"github.com/vyve/vyve-backend/pkg/email"
+)
+
+const (
+	// loginFailureWindow is how long failed password attempts are remembered
+	loginFailureWindow = 15 * time.Minute
+	// loginDelayAfter is how many failures an account is allowed before each further
+	// attempt has to wait, doubling from a second up to loginMaxDelay
+	loginDelayAfter = 3
+	loginMaxDelay   = 30 * time.Second
+	// loginLockoutThreshold failures within the window lock the account for
+	// loginLockoutDuration
+	loginLockoutThreshold = 10
+	loginLockoutDuration  = 30 * time.Minute
+	// loginIPFailureLimit failures from one address within the window block it, whichever
+	// accounts they were for
+	loginIPFailureLimit = 50
+)
+
+// LoginThrottledError is returned by Login while an account or address has to wait
+// before trying again. It wraps ErrAccountLocked or ErrTooManyRequests.
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+	err        error
+}
+
+func (e *LoginThrottledError) Error() string {
+	return fmt.Sprintf("%v; retry in %s", e.err, e.RetryAfter.Round(time.Second))
+}
+
+func (e *LoginThrottledError) Unwrap() error {
+	return e.err
+}
+
+// loginSubject names what password failures are counted against: the account, or the
+// identifier typed when no account matches, so unknown accounts are throttled alike and
+// lockouts don't reveal which accounts exist
+func loginSubject(userID uuid.UUID, identifier string) string {
+	if userID != uuid.Nil {
+		return "user:" + userID.String()
+	}
+	return "unknown:" + hashToken(strings.ToLower(strings.TrimSpace(identifier)))
+}
+
+// checkLoginThrottle refuses a password attempt while the account is locked or delayed,
+// or the address has failed too often. Cache errors let the attempt through, so an
+// outage doesn't stop everyone from signing in.
+func (s *authService) checkLoginThrottle(ctx context.Context, subject, ip string) error {
+	if ip != "" {
+		var failures int64
+		err := s.cache.Get(ctx, "login_failures_ip:"+ip, &failures)
+		switch {
+		case err == nil && failures >= loginIPFailureLimit:
+			return s.throttled(ctx, "login_failures_ip:"+ip, repository.ErrTooManyRequests)
+		case err != nil && !errors.Is(err, cache.ErrCacheMiss):
+			log.Printf("[AUTH] Failed to check login failures of %s: %v", ip, err)
+		}
+	}
+
+	// A lockout outranks a delay
+	for _, block := range []struct {
+		key    string
+		reason error
+	}{
+		{"login_locked:" + subject, repository.ErrAccountLocked},
+		{"login_delay:" + subject, repository.ErrTooManyRequests},
+	} {
+		ttl, err := s.cache.TTL(ctx, block.key)
+		if err != nil {
+			log.Printf("[AUTH] Failed to check %s: %v", block.key, err)
+			continue
+		}
+		if ttl > 0 {
+			return &LoginThrottledError{RetryAfter: ttl, err: block.reason}
+		}
+	}
+	return nil
+}
+
+// recordLoginFailure counts a failed password attempt and returns the error to answer
+// it with. Repeated failures slow the account down and then lock it, and the owner is
+// emailed when that happens.
+func (s *authService) recordLoginFailure(ctx context.Context, subject, ip string, user *models.User) error {
+	if ip != "" {
+		if _, err := s.countFailure(ctx, "login_failures_ip:"+ip); err != nil {
+			log.Printf("[AUTH] Failed to count login failure of %s: %v", ip, err)
+		}
+	}
+
+	failures, err := s.countFailure(ctx, "login_failures:"+subject)
+	if err != nil {
+		log.Printf("[AUTH] Failed to count login failure of %s: %v", subject, err)
+		return repository.ErrInvalidCredentials
+	}
+
+	if failures >= loginLockoutThreshold {
+		if err := s.cache.Set(ctx, "login_locked:"+subject, true, loginLockoutDuration); err != nil {
+			log.Printf("[AUTH] Failed to lock %s: %v", subject, err)
+			return repository.ErrInvalidCredentials
+		}
+		_ = s.deleteLoginKeys(ctx, subject, "login_failures", "login_delay")
+
+		if user != nil {
+			log.Printf("[AUTH] Locked user %s after %d failed sign-ins, the last from %s", user.ID, failures, ip)
+			s.auditLockout(ctx, user.ID, ip)
+			go s.sendAccountLockedEmail(user)
+		}
+		return &LoginThrottledError{RetryAfter: loginLockoutDuration, err: repository.ErrAccountLocked}
+	}
+
+	if failures >= loginDelayAfter {
+		delay := loginMaxDelay
+		if shift := failures - loginDelayAfter; shift < 5 {
+			delay = min(time.Second<<shift, loginMaxDelay)
+		}
+		if err := s.cache.Set(ctx, "login_delay:"+subject, true, delay); err != nil {
+			log.Printf("[AUTH] Failed to delay %s: %v", subject, err)
+		}
+	}
+	return repository.ErrInvalidCredentials
+}
+
+// clearLoginFailures forgets an account's failed attempts after it signs in or resets
+// its password
+func (s *authService) clearLoginFailures(ctx context.Context, subject string) {
+	if err := s.deleteLoginKeys(ctx, subject, "login_failures", "login_delay"); err != nil {
+		log.Printf("[AUTH] Failed to clear login failures of %s: %v", subject, err)
+	}
+}
+
+// UnlockAccount lifts a lockout and forgets the account's failed attempts
+func (s *authService) UnlockAccount(ctx context.Context, actorID, userID uuid.UUID) error {
+	if _, err := s.userRepo.FindByID(ctx, userID); err != nil {
+		return err
+	}
+
+	subject := loginSubject(userID, "")
+	if err := s.deleteLoginKeys(ctx, subject, "login_locked", "login_failures", "login_delay"); err != nil {
+		return err
+	}
+
+	entry := &models.AuditLog{
+		UserID:     &actorID,
+		Action:     "account_unlocked",
+		EntityType: "user",
+		EntityID:   userID.String(),
+		Result:     "success",
+	}
+	if err := s.auditRepo.Create(ctx, entry); err != nil {
+		log.Printf("[AUTH] Failed to audit unlock of user %s: %v", userID, err)
+	}
+	return nil
+}
+
+// countFailure increments a failure counter, starting its window on the first failure
+func (s *authService) countFailure(ctx context.Context, key string) (int64, error) {
+	return s.cache.IncrementWithTTL(ctx, key, loginFailureWindow)
+}
+
+// deleteLoginKeys removes the given kinds of throttling keys of a subject
+func (s *authService) deleteLoginKeys(ctx context.Context, subject string, kinds ...string) error {
+	for _, kind := range kinds {
+		if err := s.cache.Delete(ctx, kind+":"+subject); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// throttled builds the error for a key that blocks sign-in until it expires
+func (s *authService) throttled(ctx context.Context, key string, reason error) error {
+	retryAfter, err := s.cache.TTL(ctx, key)
+	if err != nil || retryAfter <= 0 {
+		retryAfter = loginFailureWindow
+	}
+	return &LoginThrottledError{RetryAfter: retryAfter, err: reason}
+}
+
+// auditLockout records that an account was locked by failed sign-ins
+func (s *authService) auditLockout(ctx context.Context, userID uuid.UUID, ip string) {
+	entry := &models.AuditLog{
+		UserID:     &userID,
+		Action:     "account_locked",
+		EntityType: "user",
+		EntityID:   userID.String(),
+		IPAddress:  ip,
+		Result:     "failure",
+	}
+	if err := s.auditRepo.Create(ctx, entry); err != nil {
+		log.Printf("[AUTH] Failed to audit lockout of user %s: %v", userID, err)
+	}
+}
+
+// sendAccountLockedEmail tells the user their account was locked, with a link to reset
+// their password in case someone else has been guessing it
+func (s *authService) sendAccountLockedEmail(user *models.User) {
+	ctx, cancel := context.WithTimeout(context.Background(), emailSendTimeout)
+	defer cancel()
+
+	token, err := s.issueActionToken(ctx, user, models.TokenPurposePasswordReset, passwordResetTokenTTL)
+	if err != nil {
+		log.Printf("[AUTH] Failed to create password reset token for user %s: %v", user.ID, err)
+		return
+	}
+
+	link := fmt.Sprintf("%s?token=%s", s.cfg.Email.PasswordResetURL, token)
+	s.sendEmail(ctx, user, email.TemplateAccountLocked, email.Data{
+		"Name":      displayNameOf(user),
+		"Link":      link,
+		"Minutes":   int(loginLockoutDuration.Minutes()),
+		"ExpiresIn": int(passwordResetTokenTTL.Minutes()),
+	})
+}
@@ -0,0 +1,485 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vyve/vyve-backend/internal/models"
+	"github.com/vyve/vyve-backend/internal/repository"
+)
+
+// Data export formats
+const (
+	ExportFormatJSON = "json"
+	ExportFormatCSV  = "csv"
+)
+
+const (
+	exportBatchSize     = 500
+	exportTTL           = 7 * 24 * time.Hour // how long a finished archive can be downloaded
+	exportDownloadTTL   = 15 * time.Minute   // lifetime of a presigned download link
+	exportTimeout       = 30 * time.Minute
+	exportStatusTimeout = 10 * time.Second              // the final status write gets its own deadline
+	exportStaleAfter    = exportTimeout + 5*time.Minute // unfinished exports older than this were abandoned
+)
+
+// ErrStorageUnavailable is returned when a feature needs file storage and none is configured
+var ErrStorageUnavailable = errors.New("storage is not configured")
+
+// exportEntities lists the per-user tables written to the archive. newDest returns a
+// pointer to an empty slice of the model.
+var exportEntities = []struct {
+	name    string
+	newDest func() interface{}
+}{
+	{"people", func() interface{} { return &[]models.Person{} }},
+	{"interactions", func() interface{} { return &[]models.Interaction{} }},
+	{"reflections", func() interface{} { return &[]models.Reflection{} }},
+	{"nudges", func() interface{} { return &[]models.Nudge{} }},
+	{"notifications", func() interface{} { return &[]models.Notification{} }},
+	{"analyses", func() interface{} { return &[]models.RelationshipAnalysis{} }},
+	{"consents", func() interface{} { return &[]models.UserConsent{} }},
+	{"audit_logs", func() interface{} { return &[]models.AuditLog{} }},
+}
+
+// exportKey returns the storage key of an export archive
+func exportKey(export *models.DataExport) string {
+	return fmt.Sprintf("exports/%s/%s.zip", export.UserID, export.ID)
+}
+
+// processDataExport builds the export archive, uploads it and records the result
+func (s *gdprService) processDataExport(ctx context.Context, export *models.DataExport) {
+	ctx, cancel := context.WithTimeout(ctx, exportTimeout)
+	defer cancel()
+
+	export.Status = "processing"
+	if err := s.repos.DataExport.Update(ctx, export); err != nil {
+		log.Printf("[GDPR] Failed to update export %s: %v", export.ID, err)
+	}
+
+	size, err := s.uploadExport(ctx, export)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = fmt.Errorf("export timed out after %s", exportTimeout)
+		}
+		log.Printf("[GDPR] Export %s failed: %v", export.ID, err)
+		export.Status = "failed"
+		export.Error = err.Error()
+		s.finishExport(export)
+		return
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(exportTTL)
+	export.Status = "completed"
+	export.Error = ""
+	export.CompletedAt = &now
+	export.ExpiresAt = &expiresAt
+	export.FileSize = size
+	export.FileURL = "/api/v1/gdpr/export/" + export.ID.String() + "/download"
+
+	s.finishExport(export)
+}
+
+// finishExport records the final status of an export. It uses its own context so the
+// status is saved even when the export ran out of time.
+func (s *gdprService) finishExport(export *models.DataExport) {
+	ctx, cancel := context.WithTimeout(context.Background(), exportStatusTimeout)
+	defer cancel()
+
+	if err := s.repos.DataExport.Update(ctx, export); err != nil {
+		log.Printf("[GDPR] Failed to update export %s: %v", export.ID, err)
+	}
+}
+
+// uploadExport writes the archive to a temporary file so it never has to fit in
+// memory, then streams it to storage. It returns the archive size.
+func (s *gdprService) uploadExport(ctx context.Context, export *models.DataExport) (int64, error) {
+	if s.storage == nil {
+		return 0, ErrStorageUnavailable
+	}
+
+	file, err := os.CreateTemp("", "vyve-export-*.zip")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	if err := s.writeExportArchive(ctx, file, export); err != nil {
+		return 0, err
+	}
+
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	if _, err := s.storage.UploadStream(ctx, exportKey(export), file, size, "application/zip"); err != nil {
+		return 0, err
+	}
+	return size, nil
+}
+
+// writeExportArchive writes one file per entity in the export's format plus a
+// manifest.json describing the archive
+func (s *gdprService) writeExportArchive(ctx context.Context, w io.Writer, export *models.DataExport) error {
+	zw := zip.NewWriter(w)
+	counts := make(map[string]int, len(exportEntities)+1)
+
+	user, err := s.repos.User.FindByID(ctx, export.UserID)
+	if err != nil {
+		return fmt.Errorf("user: %w", err)
+	}
+	if err := writeExportFile(zw, "user", export.Format, []models.User{*user}, counts); err != nil {
+		return err
+	}
+
+	for _, entity := range exportEntities {
+		if err := s.writeEntityFile(ctx, zw, export, entity.name, entity.newDest(), counts); err != nil {
+			return err
+		}
+	}
+
+	manifest, err := zw.Create("manifest.json")
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(manifest)
+	enc.SetIndent("", "  ")
+	err = enc.Encode(map[string]interface{}{
+		"export_id":    export.ID,
+		"user_id":      export.UserID,
+		"format":       export.Format,
+		"generated_at": time.Now().UTC(),
+		"records":      counts,
+	})
+	if err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// writeEntityFile streams the user's rows of one table into the archive in batches
+func (s *gdprService) writeEntityFile(ctx context.Context, zw *zip.Writer, export *models.DataExport, name string, dest interface{}, counts map[string]int) error {
+	f, err := zw.Create(name + "." + export.Format)
+	if err != nil {
+		return err
+	}
+	rw := newRecordWriter(export.Format, f, reflect.TypeOf(dest).Elem().Elem())
+
+	err = s.repos.Account.FindInBatches(ctx, export.UserID, dest, exportBatchSize, func() error {
+		rows := reflect.ValueOf(dest).Elem()
+		for i := 0; i < rows.Len(); i++ {
+			if err := rw.Write(rows.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		counts[name] += rows.Len()
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return rw.Close()
+}
+
+// writeExportFile writes an in-memory slice of records as one archive file
+func writeExportFile(zw *zip.Writer, name, format string, records interface{}, counts map[string]int) error {
+	f, err := zw.Create(name + "." + format)
+	if err != nil {
+		return err
+	}
+	rows := reflect.ValueOf(records)
+	rw := newRecordWriter(format, f, rows.Type().Elem())
+	for i := 0; i < rows.Len(); i++ {
+		if err := rw.Write(rows.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+	counts[name] = rows.Len()
+	return rw.Close()
+}
+
+// GetExportDownloadURL returns a short-lived presigned link to a completed export
+func (s *gdprService) GetExportDownloadURL(ctx context.Context, userID, exportID uuid.UUID) (string, error) {
+	export, err := s.GetExport(ctx, userID, exportID)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case export.Status == "expired" || (export.ExpiresAt != nil && export.ExpiresAt.Before(time.Now())):
+		return "", repository.ErrExportExpired
+	case export.Status != "completed":
+		return "", repository.ErrExportNotReady
+	case s.storage == nil:
+		return "", ErrStorageUnavailable
+	}
+
+	return s.storage.GeneratePresignedURL(ctx, exportKey(export), exportDownloadTTL)
+}
+
+// PurgeExpiredExports deletes the archives of expired exports and marks them expired.
+// The export rows are kept as a record of the request.
+func (s *gdprService) PurgeExpiredExports(ctx context.Context) (int, error) {
+	const batchSize = 100
+	purged := 0
+
+	for {
+		exports, err := s.repos.DataExport.GetExpired(ctx, time.Now(), batchSize)
+		if err != nil {
+			return purged, err
+		}
+
+		expired := 0
+		for _, export := range exports {
+			if s.storage != nil {
+				if err := s.storage.Delete(ctx, exportKey(export)); err != nil {
+					// Leave the row completed so the next sweep retries
+					log.Printf("[GDPR] Failed to delete export %s: %v", export.ID, err)
+					continue
+				}
+			}
+
+			export.Status = "expired"
+			export.FileURL = ""
+			if err := s.repos.DataExport.Update(ctx, export); err != nil {
+				return purged, err
+			}
+			expired++
+		}
+		purged += expired
+
+		// Stop on a short batch, or when every delete failed and the same rows would come back
+		if len(exports) < batchSize || expired == 0 {
+			return purged, nil
+		}
+	}
+}
+
+// FailStaleExports fails exports that were interrupted before they finished, for
+// example by a restart, so the user can request a new one
+func (s *gdprService) FailStaleExports(ctx context.Context) (int64, error) {
+	return s.repos.DataExport.FailStale(ctx, time.Now().Add(-exportStaleAfter))
+}
+
+// PurgeExpiredExports runs the export sweeper: it fails abandoned exports and purges
+// expired ones
+func PurgeExpiredExports(gdprService GDPRService) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	failed, err := gdprService.FailStaleExports(ctx)
+	if err != nil {
+		log.Printf("[GDPR] Failed to mark stale exports as failed: %v", err)
+	}
+	if failed > 0 {
+		log.Printf("[GDPR] Marked %d stale exports as failed", failed)
+	}
+
+	purged, err := gdprService.PurgeExpiredExports(ctx)
+	if err != nil {
+		log.Printf("[GDPR] Failed to purge expired exports: %v", err)
+	}
+	if purged > 0 {
+		log.Printf("[GDPR] Purged %d expired exports", purged)
+	}
+}
+
+// validateExportFormat defaults an empty format to JSON
+func validateExportFormat(format string) (string, error) {
+	switch format = strings.ToLower(strings.TrimSpace(format)); format {
+	case "":
+		return ExportFormatJSON, nil
+	case ExportFormatJSON, ExportFormatCSV:
+		return format, nil
+	default:
+		return "", fmt.Errorf("%w: format must be json or csv", repository.ErrInvalidInput)
+	}
+}
+
+// recordWriter writes the records of one export file
+type recordWriter interface {
+	Write(record interface{}) error
+	Close() error
+}
+
+func newRecordWriter(format string, w io.Writer, recordType reflect.Type) recordWriter {
+	if format == ExportFormatCSV {
+		return newCSVRecordWriter(w, recordType)
+	}
+	return &jsonRecordWriter{w: w}
+}
+
+// jsonRecordWriter writes records as a JSON array, one record at a time
+type jsonRecordWriter struct {
+	w     io.Writer
+	count int
+}
+
+func (j *jsonRecordWriter) Write(record interface{}) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	sep := ",\n"
+	if j.count == 0 {
+		sep = "[\n"
+	}
+	j.count++
+	if _, err := io.WriteString(j.w, sep); err != nil {
+		return err
+	}
+	_, err = j.w.Write(data)
+	return err
+}
+
+func (j *jsonRecordWriter) Close() error {
+	end := "\n]\n"
+	if j.count == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(j.w, end)
+	return err
+}
+
+// csvColumn is a CSV column backed by a struct field
+type csvColumn struct {
+	name  string
+	index []int
+}
+
+// csvRecordWriter writes records as CSV with one column per JSON field. Nested values
+// such as arrays and objects are written as JSON.
+type csvRecordWriter struct {
+	w       *csv.Writer
+	columns []csvColumn
+	header  bool
+}
+
+func newCSVRecordWriter(w io.Writer, recordType reflect.Type) *csvRecordWriter {
+	return &csvRecordWriter{
+		w:       csv.NewWriter(w),
+		columns: csvColumns(recordType, nil),
+	}
+}
+
+func (c *csvRecordWriter) writeHeader() error {
+	if c.header {
+		return nil
+	}
+	c.header = true
+	names := make([]string, len(c.columns))
+	for i, col := range c.columns {
+		names[i] = col.name
+	}
+	return c.w.Write(names)
+}
+
+func (c *csvRecordWriter) Write(record interface{}) error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	v := reflect.Indirect(reflect.ValueOf(record))
+	row := make([]string, len(c.columns))
+	for i, col := range c.columns {
+		value, err := csvValue(v.FieldByIndex(col.index))
+		if err != nil {
+			return fmt.Errorf("%s: %w", col.name, err)
+		}
+		row[i] = value
+	}
+	return c.w.Write(row)
+}
+
+func (c *csvRecordWriter) Close() error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// csvColumns returns the JSON-visible fields of a struct, flattening embedded structs
+func csvColumns(t reflect.Type, index []int) []csvColumn {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	var columns []csvColumn
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		fieldIndex := append(append([]int{}, index...), i)
+
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			columns = append(columns, csvColumns(field.Type, fieldIndex)...)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		columns = append(columns, csvColumn{name: name, index: fieldIndex})
+	}
+	return columns
+}
+
+// csvValue formats a field value for a CSV cell
+func csvValue(v reflect.Value) (string, error) {
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		return "", nil
+	}
+	v = reflect.Indirect(v)
+
+	switch value := v.Interface().(type) {
+	case time.Time:
+		if value.IsZero() {
+			return "", nil
+		}
+		return value.UTC().Format(time.RFC3339), nil
+	case uuid.UUID:
+		return value.String(), nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return fmt.Sprint(v.Interface()), nil
+	case reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return "", nil
+		}
+	}
+
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
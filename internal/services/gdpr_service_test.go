@@ -0,0 +1,270 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/vyve/vyve-backend/internal/models"
+	"github.com/vyve/vyve-backend/internal/repository"
+	"github.com/vyve/vyve-backend/pkg/storage"
+)
+
+// newBucket returns a storage mock holding keys, and the keys deleted from it
+func newBucket(ctrl *gomock.Controller, keys []string, listErr error) (*storage.MockStorage, *[]string) {
+	var deleted []string
+	bucket := storage.NewMockStorage(ctrl)
+	bucket.EXPECT().
+		ListObjects(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, prefix string) ([]string, error) {
+			if listErr != nil {
+				return nil, listErr
+			}
+			var found []string
+			for _, key := range keys {
+				if strings.HasPrefix(key, prefix) {
+					found = append(found, key)
+				}
+			}
+			return found, nil
+		}).
+		AnyTimes()
+	bucket.EXPECT().
+		Delete(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, key string) error {
+			deleted = append(deleted, key)
+			return nil
+		}).
+		AnyTimes()
+	return bucket, &deleted
+}
+
+func TestEraseUser(t *testing.T) {
+	userID := uuid.New()
+	adminID := uuid.New()
+	dbErr := errors.New("connection reset")
+	keys := []string{
+		"avatars/" + userID.String() + "/me.png",
+		"avatars/people/" + userID.String() + "/sam.png",
+		"avatars/" + uuid.NewString() + "/someone-else.png",
+	}
+
+	tests := []struct {
+		name          string
+		actorID       *uuid.UUID
+		deleteErr     error
+		listErr       error
+		auditErr      error
+		wantErr       error
+		wantFiles     []string
+		wantTombstone bool
+	}{
+		{name: "self erasure", wantFiles: keys[:2], wantTombstone: true},
+		{name: "admin erasure", actorID: &adminID, wantFiles: keys[:2], wantTombstone: true},
+		{name: "admin erasing themselves", actorID: &userID, wantErr: repository.ErrInvalidInput},
+		{name: "last admin", deleteErr: repository.ErrLastAdmin, wantErr: repository.ErrLastAdmin},
+		{name: "database failure", deleteErr: dbErr, wantErr: dbErr},
+		{name: "storage failure is not fatal", listErr: errors.New("bucket unavailable"), wantTombstone: true},
+		{name: "tombstone failure is not fatal", auditErr: dbErr, wantFiles: keys[:2]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			accountRepo := repository.NewMockAccountRepository(ctrl)
+			erased := accountRepo.EXPECT().
+				DeleteAllData(gomock.Any(), userID).
+				Return(map[string]int64{"users": 1}, tt.deleteErr).
+				MaxTimes(1)
+
+			var logs []*models.AuditLog
+			auditRepo := repository.NewMockAuditLogRepository(ctrl)
+			auditRepo.EXPECT().
+				Create(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, entry *models.AuditLog) error {
+					if tt.auditErr != nil {
+						return tt.auditErr
+					}
+					logs = append(logs, entry)
+					return nil
+				}).
+				// Only after the user's own audit logs were deleted with their data
+				After(erased).
+				MaxTimes(1)
+
+			bucket, deleted := newBucket(ctrl, keys, tt.listErr)
+			sessions := newTestCache(t)
+			sessionKey := "session:" + userID.String() + ":s1"
+			if err := sessions.Set(context.Background(), sessionKey, "live", time.Hour); err != nil {
+				t.Fatal(err)
+			}
+
+			svc := &gdprService{
+				repos: &repository.Repositories{
+					Account:  accountRepo,
+					AuditLog: auditRepo,
+				},
+				storage: bucket,
+				cache:   sessions,
+			}
+
+			var err error
+			if tt.actorID != nil {
+				err = svc.DeleteUserDataAsAdmin(context.Background(), *tt.actorID, userID)
+			} else {
+				err = svc.DeleteAllUserData(context.Background(), userID)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+
+			if len(*deleted) != len(tt.wantFiles) {
+				t.Errorf("deleted files %v, want %v", *deleted, tt.wantFiles)
+			}
+			live, err := sessions.Exists(context.Background(), sessionKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantErr != nil {
+				if !live {
+					t.Error("sessions purged after a failed erasure")
+				}
+				return
+			}
+			if live {
+				t.Error("sessions were not purged")
+			}
+
+			if got := len(logs) == 1; got != tt.wantTombstone {
+				t.Fatalf("tombstone written = %v, want %v", got, tt.wantTombstone)
+			}
+			if !tt.wantTombstone {
+				return
+			}
+			tombstone := logs[0]
+			if tombstone.Action != "user_data_erased" || tombstone.EntityID != userID.String() {
+				t.Errorf("unexpected tombstone %+v", tombstone)
+			}
+			if (tombstone.UserID == nil) != (tt.actorID == nil) {
+				t.Errorf("tombstone actor = %v, want %v", tombstone.UserID, tt.actorID)
+			}
+		})
+	}
+}
+
+func TestProcessDataExport(t *testing.T) {
+	userID := uuid.New()
+
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		noStorage  bool
+		wantStatus string
+		wantError  string
+	}{
+		{"completed", context.Background(), false, "completed", ""},
+		{"timed out", expired, false, "failed", "export timed out"},
+		{"no storage", context.Background(), true, "failed", ErrStorageUnavailable.Error()},
+	}
+
+	// exportUpdate is a status write of the export and whether its context was still live
+	type exportUpdate struct {
+		status string
+		ctxErr error
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			userRepo := repository.NewMockUserRepository(ctrl)
+			userRepo.EXPECT().
+				FindByID(gomock.Any(), userID).
+				Return(&models.User{Base: models.Base{ID: userID}}, nil).
+				AnyTimes()
+
+			accountRepo := repository.NewMockAccountRepository(ctrl)
+			accountRepo.EXPECT().
+				FindInBatches(gomock.Any(), userID, gomock.Any(), exportBatchSize, gomock.Any()).
+				DoAndReturn(func(ctx context.Context, userID uuid.UUID, dest interface{}, batchSize int, fn func() error) error {
+					if err := ctx.Err(); err != nil {
+						return err
+					}
+					return fn()
+				}).
+				AnyTimes()
+
+			var updates []exportUpdate
+			exportRepo := repository.NewMockDataExportRepository(ctrl)
+			exportRepo.EXPECT().
+				Update(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, export *models.DataExport) error {
+					updates = append(updates, exportUpdate{status: export.Status, ctxErr: ctx.Err()})
+					return ctx.Err()
+				}).
+				AnyTimes()
+
+			svc := &gdprService{
+				repos: &repository.Repositories{
+					User:       userRepo,
+					Account:    accountRepo,
+					DataExport: exportRepo,
+				},
+			}
+			if !tt.noStorage {
+				bucket := storage.NewMockStorage(ctrl)
+				bucket.EXPECT().
+					UploadStream(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "application/zip").
+					DoAndReturn(func(ctx context.Context, key string, r io.Reader, size int64, contentType string) (string, error) {
+						_, err := io.Copy(io.Discard, r)
+						return key, err
+					}).
+					AnyTimes()
+				svc.storage = bucket
+			}
+			export := &models.DataExport{Base: models.Base{ID: uuid.New()}, UserID: userID, Format: ExportFormatJSON}
+
+			svc.processDataExport(tt.ctx, export)
+
+			if len(updates) == 0 {
+				t.Fatal("export status was never written")
+			}
+			last := updates[len(updates)-1]
+			if last.status != tt.wantStatus || last.ctxErr != nil {
+				t.Errorf("final status %q written with context error %v, want %q saved", last.status, last.ctxErr, tt.wantStatus)
+			}
+			if !strings.Contains(export.Error, tt.wantError) {
+				t.Errorf("error = %q, want it to contain %q", export.Error, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestFailStaleExports(t *testing.T) {
+	var staleBefore time.Time
+	exportRepo := repository.NewMockDataExportRepository(gomock.NewController(t))
+	exportRepo.EXPECT().
+		FailStale(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, cutoff time.Time) (int64, error) {
+			staleBefore = cutoff
+			return 1, nil
+		})
+	svc := &gdprService{repos: &repository.Repositories{DataExport: exportRepo}}
+
+	if _, err := svc.FailStaleExports(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Exports are only abandoned once they have outlived their own timeout
+	if age := time.Since(staleBefore); age < exportTimeout {
+		t.Errorf("stale cutoff %s ago is shorter than the export timeout %s", age, exportTimeout)
+	}
+}
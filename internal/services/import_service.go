@@ -0,0 +1,407 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vyve/vyve-backend/internal/importer"
+	"github.com/vyve/vyve-backend/internal/models"
+	"github.com/vyve/vyve-backend/internal/repository"
+	"github.com/vyve/vyve-backend/pkg/analytics"
+	"github.com/vyve/vyve-backend/pkg/storage"
+)
+
+const (
+	maxImportFileSize  = 10 << 20
+	maxImportPreview   = 200 // items listed in ResultData["preview"]
+	maxImportErrors    = 100 // errors listed in ResultData["errors"]
+	importSaveInterval = 25  // items between progress saves
+)
+
+// Import preview actions
+const (
+	importActionCreate = "create"
+	importActionMatch  = "match" // duplicate of an existing record, skipped
+	importActionFailed = "failed"
+)
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// ImportService imports people and interactions from files as background jobs
+type ImportService interface {
+	Create(ctx context.Context, userID uuid.UUID, req ImportRequest) (*models.ImportJob, error)
+	Get(ctx context.Context, userID, jobID uuid.UUID) (*models.ImportJob, error)
+	List(ctx context.Context, userID uuid.UUID) ([]*models.ImportJob, error)
+	Confirm(ctx context.Context, userID, jobID uuid.UUID) (*models.ImportJob, error)
+
+	// Process runs a claimed job. save is called periodically with the job's progress
+	// and returns false when the job should stop.
+	Process(ctx context.Context, job *models.ImportJob, save func() bool) error
+	// DiscardFile deletes the uploaded file of a job that will not run again
+	DiscardFile(ctx context.Context, job *models.ImportJob)
+}
+
+// ImportRequest represents an uploaded import file
+type ImportRequest struct {
+	FileName string
+	Data     []byte
+	Source   string // vyve_export, csv or vcard; detected from the file name when empty
+	DryRun   bool   // only preview what would be imported
+}
+
+// importPreviewItem describes what happened, or would happen, to one imported record
+type importPreviewItem struct {
+	Type   string `json:"type"` // person, interaction
+	Name   string `json:"name"`
+	Action string `json:"action"`
+	At     string `json:"at,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// importSummary counts the outcome per record type
+type importSummary struct {
+	PeopleCreated       int `json:"people_created"`
+	PeopleMatched       int `json:"people_matched"`
+	PeopleFailed        int `json:"people_failed"`
+	InteractionsCreated int `json:"interactions_created"`
+	InteractionsSkipped int `json:"interactions_skipped"`
+	InteractionsFailed  int `json:"interactions_failed"`
+}
+
+type importService struct {
+	importRepo      repository.ImportRepository
+	personRepo      repository.PersonRepository
+	interactionRepo repository.InteractionRepository
+	storage         storage.Storage
+	analytics       analytics.Analytics
+}
+
+// NewImportService creates a new import service
+func NewImportService(importRepo repository.ImportRepository, personRepo repository.PersonRepository, interactionRepo repository.InteractionRepository, storage storage.Storage, analytics analytics.Analytics) ImportService {
+	return &importService{
+		importRepo:      importRepo,
+		personRepo:      personRepo,
+		interactionRepo: interactionRepo,
+		storage:         storage,
+		analytics:       analytics,
+	}
+}
+
+// Create validates the file, stores it and queues the import
+func (s *importService) Create(ctx context.Context, userID uuid.UUID, req ImportRequest) (*models.ImportJob, error) {
+	if s.storage == nil {
+		return nil, ErrStorageUnavailable
+	}
+	if len(req.Data) == 0 {
+		return nil, fmt.Errorf("%w: file is empty", repository.ErrInvalidInput)
+	}
+	if len(req.Data) > maxImportFileSize {
+		return nil, fmt.Errorf("%w: file is larger than %d MB", repository.ErrInvalidInput, maxImportFileSize>>20)
+	}
+
+	source := req.Source
+	if source == "" {
+		detected, err := importer.DetectSource(req.FileName)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", repository.ErrInvalidInput, err)
+		}
+		source = detected
+	}
+
+	// Parse now so that unreadable files are rejected before anything is queued
+	batch, err := importer.Parse(source, req.Data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", repository.ErrInvalidInput, err)
+	}
+
+	fileName := unsafeFileChars.ReplaceAllString(filepath.Base(req.FileName), "_")
+	job := &models.ImportJob{
+		UserID:     userID,
+		Source:     source,
+		FileName:   fileName,
+		DryRun:     req.DryRun,
+		Status:     "pending",
+		TotalItems: len(batch.People) + len(batch.Interactions),
+	}
+	job.ID = uuid.New()
+	job.FileKey = fmt.Sprintf("imports/%s/%s/%s", userID, job.ID, fileName)
+
+	if _, err := s.storage.Upload(ctx, job.FileKey, req.Data, "application/octet-stream"); err != nil {
+		return nil, fmt.Errorf("failed to store import file: %w", err)
+	}
+	if err := s.importRepo.Create(ctx, job); err != nil {
+		_ = s.storage.Delete(ctx, job.FileKey)
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// Get gets an import job owned by the user
+func (s *importService) Get(ctx context.Context, userID, jobID uuid.UUID) (*models.ImportJob, error) {
+	job, err := s.importRepo.FindByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.UserID != userID {
+		return nil, repository.ErrForbidden
+	}
+	return job, nil
+}
+
+// List lists the user's recent imports
+func (s *importService) List(ctx context.Context, userID uuid.UUID) ([]*models.ImportJob, error) {
+	return s.importRepo.ListByUser(ctx, userID, 20)
+}
+
+// Confirm queues a finished dry run as a real import of the same file
+func (s *importService) Confirm(ctx context.Context, userID, jobID uuid.UUID) (*models.ImportJob, error) {
+	if _, err := s.Get(ctx, userID, jobID); err != nil {
+		return nil, err
+	}
+
+	queued, err := s.importRepo.QueueDryRun(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if !queued {
+		return nil, repository.ErrImportNotDryRun
+	}
+	return s.importRepo.FindByID(ctx, jobID)
+}
+
+// Process imports the people of the file first, matching them by email or name against
+// the user's existing people, then their interactions, skipping interactions that
+// already exist at the same time. Re-running a job is safe: records written by an
+// earlier attempt are matched instead of duplicated.
+func (s *importService) Process(ctx context.Context, job *models.ImportJob, save func() bool) error {
+	if s.storage == nil {
+		return ErrStorageUnavailable
+	}
+
+	data, err := s.storage.Download(ctx, job.FileKey)
+	if err != nil {
+		return fmt.Errorf("failed to read import file: %w", err)
+	}
+	batch, err := importer.Parse(job.Source, data)
+	if err != nil {
+		return err
+	}
+
+	existing, err := s.personRepo.FindByUserID(ctx, job.UserID)
+	if err != nil {
+		return err
+	}
+	matcher := importer.NewMatcher()
+	for _, p := range existing {
+		email, _ := p.CustomFields["email"].(string)
+		matcher.Add(p.ID.String(), p.Name, email)
+	}
+
+	job.TotalItems = len(batch.People) + len(batch.Interactions)
+	job.ProcessedItems, job.CreatedItems, job.SkippedItems, job.FailedItems = 0, 0, 0, 0
+
+	var (
+		summary importSummary
+		preview []importPreviewItem
+		errs    = append([]string{}, batch.Errors...)
+	)
+	record := func(item importPreviewItem) {
+		job.ProcessedItems++
+		switch item.Action {
+		case importActionCreate:
+			job.CreatedItems++
+		case importActionMatch:
+			job.SkippedItems++
+		case importActionFailed:
+			job.FailedItems++
+			errs = append(errs, fmt.Sprintf("%s %s: %s", item.Type, item.Name, item.Error))
+		}
+		if len(preview) < maxImportPreview {
+			preview = append(preview, item)
+		}
+		job.Progress = float64(job.ProcessedItems) / float64(job.TotalItems) * 100
+	}
+	checkpoint := func() error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if job.ProcessedItems%importSaveInterval == 0 && !save() {
+			return context.Canceled
+		}
+		return nil
+	}
+
+	// People, keyed by their reference in the file
+	refs := make(map[string]string, len(batch.People))
+	names := make(map[string]string, len(batch.People))
+	for _, p := range batch.People {
+		item := importPreviewItem{Type: "person", Name: p.Name}
+
+		if id, ok := matcher.Match(p.Name, p.Email); ok {
+			refs[p.Ref] = id
+			item.Action = importActionMatch
+			summary.PeopleMatched++
+		} else if job.DryRun {
+			id := "new:" + p.Ref
+			refs[p.Ref] = id
+			matcher.Add(id, p.Name, p.Email)
+			item.Action = importActionCreate
+			summary.PeopleCreated++
+		} else {
+			person := importedPerson(job.UserID, p)
+			if err := s.personRepo.Create(ctx, person); err != nil {
+				item.Action = importActionFailed
+				item.Error = err.Error()
+				summary.PeopleFailed++
+			} else {
+				refs[p.Ref] = person.ID.String()
+				matcher.Add(person.ID.String(), p.Name, p.Email)
+				item.Action = importActionCreate
+				summary.PeopleCreated++
+			}
+		}
+		names[p.Ref] = p.Name
+
+		record(item)
+		if err := checkpoint(); err != nil {
+			return err
+		}
+	}
+
+	// Interactions
+	touched := make(map[uuid.UUID]bool)
+	for _, in := range batch.Interactions {
+		item := importPreviewItem{Type: "interaction", Name: names[in.PersonRef], At: in.InteractionAt.Format(time.RFC3339)}
+
+		ref, ok := refs[in.PersonRef]
+		personID, parseErr := uuid.Parse(ref)
+		switch {
+		case !ok:
+			item.Action = importActionFailed
+			item.Error = "person was not imported"
+			summary.InteractionsFailed++
+		case parseErr != nil:
+			// Person created by this dry run, so nothing can be a duplicate yet
+			item.Action = importActionCreate
+			summary.InteractionsCreated++
+		default:
+			exists, err := s.interactionRepo.ExistsAt(ctx, personID, in.InteractionAt)
+			switch {
+			case err != nil:
+				item.Action = importActionFailed
+				item.Error = err.Error()
+				summary.InteractionsFailed++
+			case exists:
+				item.Action = importActionMatch
+				summary.InteractionsSkipped++
+			case job.DryRun:
+				item.Action = importActionCreate
+				summary.InteractionsCreated++
+			default:
+				if err := s.interactionRepo.Create(ctx, importedInteraction(job.UserID, personID, in)); err != nil {
+					item.Action = importActionFailed
+					item.Error = err.Error()
+					summary.InteractionsFailed++
+				} else {
+					item.Action = importActionCreate
+					summary.InteractionsCreated++
+					touched[personID] = true
+				}
+			}
+		}
+
+		record(item)
+		if err := checkpoint(); err != nil {
+			return err
+		}
+	}
+
+	for personID := range touched {
+		if err := s.personRepo.RecalculateInteractionStats(ctx, personID); err != nil {
+			log.Printf("[IMPORT] Failed to update interaction stats of person %s: %v", personID, err)
+		}
+	}
+
+	if len(errs) > maxImportErrors {
+		errs = errs[:maxImportErrors]
+	}
+	job.ResultData = models.JSONB{
+		"summary": summary,
+		"preview": preview,
+		"errors":  errs,
+	}
+	job.Progress = 100
+
+	if !job.DryRun {
+		go s.analytics.Track(context.Background(), analytics.Event{
+			UserID:    job.UserID.String(),
+			EventType: analytics.EventDataImported,
+			Properties: map[string]interface{}{
+				"source":               job.Source,
+				"people_created":       summary.PeopleCreated,
+				"people_matched":       summary.PeopleMatched,
+				"interactions_created": summary.InteractionsCreated,
+			},
+			Timestamp: time.Now(),
+		})
+	}
+
+	return nil
+}
+
+// DiscardFile removes the uploaded file once it is no longer needed
+func (s *importService) DiscardFile(ctx context.Context, job *models.ImportJob) {
+	if s.storage == nil || job.FileKey == "" {
+		return
+	}
+	if err := s.storage.Delete(ctx, job.FileKey); err != nil {
+		log.Printf("[IMPORT] Failed to delete file of import %s: %v", job.ID, err)
+		return
+	}
+	job.FileKey = ""
+}
+
+func importedPerson(userID uuid.UUID, p *importer.Person) *models.Person {
+	fields := models.JSONB{}
+	for k, v := range p.CustomFields {
+		fields[k] = v
+	}
+	if p.Email != "" {
+		fields["email"] = p.Email
+	}
+	if p.Phone != "" {
+		fields["phone"] = p.Phone
+	}
+
+	return &models.Person{
+		UserID:       userID,
+		Name:         p.Name,
+		Relationship: p.Relationship,
+		Notes:        p.Notes,
+		Context:      models.StringArray(p.Context),
+		CustomFields: fields,
+		HealthScore:  50.0,
+	}
+}
+
+func importedInteraction(userID, personID uuid.UUID, in *importer.Interaction) *models.Interaction {
+	return &models.Interaction{
+		UserID:        userID,
+		PersonID:      personID,
+		EnergyImpact:  in.EnergyImpact,
+		Context:       models.StringArray(in.Context),
+		Duration:      in.Duration,
+		Quality:       in.Quality,
+		Notes:         in.Notes,
+		Location:      in.Location,
+		SpecialTags:   models.StringArray(in.SpecialTags),
+		InteractionAt: in.InteractionAt,
+		Metadata:      models.JSONB{"imported": true},
+	}
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/vyve/vyve-backend/internal/models"
+	"github.com/vyve/vyve-backend/internal/repository"
+)
+
+const (
+	importPollInterval = 5 * time.Second
+	importStaleAfter   = 2 * time.Minute
+)
+
+// ImportWorkerPool processes queued imports from the import_jobs table. Like the
+// analysis queue, a job whose worker stops reporting progress is put back in the queue.
+type ImportWorkerPool struct {
+	importService ImportService
+	importRepo    repository.ImportRepository
+	workerID      string
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewImportWorkerPool creates a new import worker pool
+func NewImportWorkerPool(importService ImportService, importRepo repository.ImportRepository) *ImportWorkerPool {
+	hostname, _ := os.Hostname()
+
+	return &ImportWorkerPool{
+		importService: importService,
+		importRepo:    importRepo,
+		workerID:      fmt.Sprintf("%s-%d-import", hostname, os.Getpid()),
+	}
+}
+
+// Start starts the worker and the stale job reaper
+func (p *ImportWorkerPool) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+
+	p.wg.Add(2)
+	go p.runWorker(ctx)
+	go p.runReaper(ctx)
+
+	log.Printf("[IMPORT_WORKER] Started")
+}
+
+// Stop stops the worker and waits for it to hand back its current job
+func (p *ImportWorkerPool) Stop() {
+	if p.cancel == nil {
+		return
+	}
+	p.cancel()
+	p.wg.Wait()
+	log.Printf("[IMPORT_WORKER] Stopped")
+}
+
+func (p *ImportWorkerPool) runWorker(ctx context.Context) {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(importPollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Drain the queue before going back to sleep
+		for ctx.Err() == nil {
+			job, err := p.importRepo.ClaimNext(ctx, p.workerID)
+			if err != nil {
+				if !errors.Is(err, repository.ErrNotFound) && ctx.Err() == nil {
+					log.Printf("[IMPORT_WORKER] Failed to claim import: %v", err)
+				}
+				break
+			}
+			p.processJob(ctx, job)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runReaper periodically puts imports of dead workers back in the queue
+func (p *ImportWorkerPool) runReaper(ctx context.Context) {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(importStaleAfter / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := p.importRepo.ReclaimStale(ctx, time.Now().Add(-importStaleAfter))
+			if err != nil {
+				log.Printf("[IMPORT_WORKER] Failed to reclaim stale imports: %v", err)
+			} else if n > 0 {
+				log.Printf("[IMPORT_WORKER] Reclaimed %d stale imports", n)
+			}
+		}
+	}
+}
+
+// processJob runs a claimed import and records the outcome
+func (p *ImportWorkerPool) processJob(ctx context.Context, job *models.ImportJob) {
+	log.Printf("[IMPORT_WORKER] Processing import %s (%s, dry run: %v, attempt %d/%d)", job.ID, job.Source, job.DryRun, job.Attempts, job.MaxAttempts)
+
+	// jobCtx is cancelled when the job was reclaimed by another worker
+	jobCtx, cancelJob := context.WithCancel(ctx)
+	defer cancelJob()
+
+	save := func() bool {
+		owned, err := p.importRepo.SaveProgress(ctx, job, p.workerID)
+		if err != nil {
+			log.Printf("[IMPORT_WORKER] Failed to save progress of import %s: %v", job.ID, err)
+			return true
+		}
+		if !owned {
+			cancelJob()
+		}
+		return owned
+	}
+
+	err := p.importService.Process(jobCtx, job, save)
+
+	if ctx.Err() != nil {
+		// Shutting down: give the job back so another instance can pick it up right away
+		if err := p.importRepo.Release(context.Background(), job.ID, p.workerID); err != nil {
+			log.Printf("[IMPORT_WORKER] Failed to release import %s: %v", job.ID, err)
+		}
+		return
+	}
+	if jobCtx.Err() != nil {
+		log.Printf("[IMPORT_WORKER] Import %s was reclaimed", job.ID)
+		return
+	}
+
+	now := time.Now()
+	switch {
+	case err == nil:
+		job.Status = "completed"
+		job.Error = ""
+		job.CompletedAt = &now
+		// Dry runs keep the file so they can be confirmed
+		if !job.DryRun {
+			p.importService.DiscardFile(ctx, job)
+		}
+	case job.Attempts < job.MaxAttempts:
+		job.Status = "pending"
+		job.Error = err.Error()
+	default:
+		job.Status = "failed"
+		job.Error = err.Error()
+		job.CompletedAt = &now
+		p.importService.DiscardFile(ctx, job)
+	}
+
+	owned, finishErr := p.importRepo.Finish(ctx, job, p.workerID)
+	if finishErr != nil {
+		log.Printf("[IMPORT_WORKER] Failed to finish import %s: %v", job.ID, finishErr)
+		return
+	}
+	if owned {
+		log.Printf("[IMPORT_WORKER] Import %s %s: %d created, %d skipped, %d failed",
+			job.ID, job.Status, job.CreatedItems, job.SkippedItems, job.FailedItems)
+	}
+}
@@ -0,0 +1,201 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/analysis_service.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	models "github.com/vyve/vyve-backend/internal/models"
+)
+
+// MockAnalysisService is a mock of AnalysisService interface.
+type MockAnalysisService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAnalysisServiceMockRecorder
+}
+
+// MockAnalysisServiceMockRecorder is the mock recorder for MockAnalysisService.
+type MockAnalysisServiceMockRecorder struct {
+	mock *MockAnalysisService
+}
+
+// NewMockAnalysisService creates a new mock instance.
+func NewMockAnalysisService(ctrl *gomock.Controller) *MockAnalysisService {
+	mock := &MockAnalysisService{ctrl: ctrl}
+	mock.recorder = &MockAnalysisServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAnalysisService) EXPECT() *MockAnalysisServiceMockRecorder {
+	return m.recorder
+}
+
+// AnalyzeRelationship mocks base method.
+func (m *MockAnalysisService) AnalyzeRelationship(ctx context.Context, userID, personID uuid.UUID) (*models.RelationshipAnalysis, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnalyzeRelationship", ctx, userID, personID)
+	ret0, _ := ret[0].(*models.RelationshipAnalysis)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AnalyzeRelationship indicates an expected call of AnalyzeRelationship.
+func (mr *MockAnalysisServiceMockRecorder) AnalyzeRelationship(ctx, userID, personID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnalyzeRelationship", reflect.TypeOf((*MockAnalysisService)(nil).AnalyzeRelationship), ctx, userID, personID)
+}
+
+// BatchAnalyze mocks base method.
+func (m *MockAnalysisService) BatchAnalyze(ctx context.Context, userID uuid.UUID, personIDs []uuid.UUID) (*models.AIAnalysisJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchAnalyze", ctx, userID, personIDs)
+	ret0, _ := ret[0].(*models.AIAnalysisJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchAnalyze indicates an expected call of BatchAnalyze.
+func (mr *MockAnalysisServiceMockRecorder) BatchAnalyze(ctx, userID, personIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchAnalyze", reflect.TypeOf((*MockAnalysisService)(nil).BatchAnalyze), ctx, userID, personIDs)
+}
+
+// CancelJob mocks base method.
+func (m *MockAnalysisService) CancelJob(ctx context.Context, userID, jobID uuid.UUID) (*models.AIAnalysisJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelJob", ctx, userID, jobID)
+	ret0, _ := ret[0].(*models.AIAnalysisJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelJob indicates an expected call of CancelJob.
+func (mr *MockAnalysisServiceMockRecorder) CancelJob(ctx, userID, jobID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelJob", reflect.TypeOf((*MockAnalysisService)(nil).CancelJob), ctx, userID, jobID)
+}
+
+// GenerateRecommendations mocks base method.
+func (m *MockAnalysisService) GenerateRecommendations(ctx context.Context, userID, personID uuid.UUID) ([]*models.Nudge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateRecommendations", ctx, userID, personID)
+	ret0, _ := ret[0].([]*models.Nudge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateRecommendations indicates an expected call of GenerateRecommendations.
+func (mr *MockAnalysisServiceMockRecorder) GenerateRecommendations(ctx, userID, personID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateRecommendations", reflect.TypeOf((*MockAnalysisService)(nil).GenerateRecommendations), ctx, userID, personID)
+}
+
+// GetActiveRecommendations mocks base method.
+func (m *MockAnalysisService) GetActiveRecommendations(ctx context.Context, userID uuid.UUID) ([]*models.Nudge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveRecommendations", ctx, userID)
+	ret0, _ := ret[0].([]*models.Nudge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveRecommendations indicates an expected call of GetActiveRecommendations.
+func (mr *MockAnalysisServiceMockRecorder) GetActiveRecommendations(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveRecommendations", reflect.TypeOf((*MockAnalysisService)(nil).GetActiveRecommendations), ctx, userID)
+}
+
+// GetAnalysisHistory mocks base method.
+func (m *MockAnalysisService) GetAnalysisHistory(ctx context.Context, userID, personID uuid.UUID, limit int) ([]*models.RelationshipAnalysis, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAnalysisHistory", ctx, userID, personID, limit)
+	ret0, _ := ret[0].([]*models.RelationshipAnalysis)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAnalysisHistory indicates an expected call of GetAnalysisHistory.
+func (mr *MockAnalysisServiceMockRecorder) GetAnalysisHistory(ctx, userID, personID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAnalysisHistory", reflect.TypeOf((*MockAnalysisService)(nil).GetAnalysisHistory), ctx, userID, personID, limit)
+}
+
+// GetJobStatus mocks base method.
+func (m *MockAnalysisService) GetJobStatus(ctx context.Context, userID, jobID uuid.UUID) (*models.AIAnalysisJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJobStatus", ctx, userID, jobID)
+	ret0, _ := ret[0].(*models.AIAnalysisJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJobStatus indicates an expected call of GetJobStatus.
+func (mr *MockAnalysisServiceMockRecorder) GetJobStatus(ctx, userID, jobID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobStatus", reflect.TypeOf((*MockAnalysisService)(nil).GetJobStatus), ctx, userID, jobID)
+}
+
+// GetLatestAnalysis mocks base method.
+func (m *MockAnalysisService) GetLatestAnalysis(ctx context.Context, userID, personID uuid.UUID) (*models.RelationshipAnalysis, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestAnalysis", ctx, userID, personID)
+	ret0, _ := ret[0].(*models.RelationshipAnalysis)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestAnalysis indicates an expected call of GetLatestAnalysis.
+func (mr *MockAnalysisServiceMockRecorder) GetLatestAnalysis(ctx, userID, personID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestAnalysis", reflect.TypeOf((*MockAnalysisService)(nil).GetLatestAnalysis), ctx, userID, personID)
+}
+
+// GetRecommendationsForPerson mocks base method.
+func (m *MockAnalysisService) GetRecommendationsForPerson(ctx context.Context, userID, personID uuid.UUID) ([]*models.Nudge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecommendationsForPerson", ctx, userID, personID)
+	ret0, _ := ret[0].([]*models.Nudge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecommendationsForPerson indicates an expected call of GetRecommendationsForPerson.
+func (mr *MockAnalysisServiceMockRecorder) GetRecommendationsForPerson(ctx, userID, personID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecommendationsForPerson", reflect.TypeOf((*MockAnalysisService)(nil).GetRecommendationsForPerson), ctx, userID, personID)
+}
+
+// RefreshAnalysis mocks base method.
+func (m *MockAnalysisService) RefreshAnalysis(ctx context.Context, userID, personID uuid.UUID) (*models.RelationshipAnalysis, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshAnalysis", ctx, userID, personID)
+	ret0, _ := ret[0].(*models.RelationshipAnalysis)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshAnalysis indicates an expected call of RefreshAnalysis.
+func (mr *MockAnalysisServiceMockRecorder) RefreshAnalysis(ctx, userID, personID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshAnalysis", reflect.TypeOf((*MockAnalysisService)(nil).RefreshAnalysis), ctx, userID, personID)
+}
+
+// UpdateRecommendationStatus mocks base method.
+func (m *MockAnalysisService) UpdateRecommendationStatus(ctx context.Context, userID, recommendationID uuid.UUID, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRecommendationStatus", ctx, userID, recommendationID, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRecommendationStatus indicates an expected call of UpdateRecommendationStatus.
+func (mr *MockAnalysisServiceMockRecorder) UpdateRecommendationStatus(ctx, userID, recommendationID, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRecommendationStatus", reflect.TypeOf((*MockAnalysisService)(nil).UpdateRecommendationStatus), ctx, userID, recommendationID, status)
+}
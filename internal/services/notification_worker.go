@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/vyve/vyve-backend/internal/models"
+	"github.com/vyve/vyve-backend/internal/repository"
+	"github.com/vyve/vyve-backend/pkg/notifications"
+)
+
+const (
+	notificationPollInterval   = 5 * time.Second
+	notificationBatchSize      = 50
+	notificationLease          = 2 * time.Minute
+	notificationRetryBaseDelay = 30 * time.Second
+	notificationRetryMaxDelay  = time.Hour
+)
+
+// NotificationWorkerPool delivers the push notifications waiting in the notifications
+// table. Failed deliveries are retried with exponential backoff until they run out of
+// attempts, and a notification whose worker died is claimed again once its lease is over.
+// A notification that comes due inside the user's quiet hours waits until they end.
+type NotificationWorkerPool struct {
+	notificationRepo repository.NotificationRepository
+	userRepo         repository.UserRepository
+	sender           notifications.NotificationService
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewNotificationWorkerPool creates a new notification worker pool
+func NewNotificationWorkerPool(notificationRepo repository.NotificationRepository, userRepo repository.UserRepository, sender notifications.NotificationService) *NotificationWorkerPool {
+	return &NotificationWorkerPool{
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		sender:           sender,
+	}
+}
+
+// Start starts the worker
+func (p *NotificationWorkerPool) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+
+	p.wg.Add(1)
+	go p.runWorker(ctx)
+
+	log.Printf("[NOTIFICATION_WORKER] Started")
+}
+
+// Stop stops the worker and waits for it to hand back the notifications it holds
+func (p *NotificationWorkerPool) Stop() {
+	if p.cancel == nil {
+		return
+	}
+	p.cancel()
+	p.wg.Wait()
+	log.Printf("[NOTIFICATION_WORKER] Stopped")
+}
+
+func (p *NotificationWorkerPool) runWorker(ctx context.Context) {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(notificationPollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Drain the outbox before going back to sleep
+		for ctx.Err() == nil {
+			due, err := p.notificationRepo.ClaimDue(ctx, models.ChannelPush, notificationBatchSize, notificationLease)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Printf("[NOTIFICATION_WORKER] Failed to claim notifications: %v", err)
+				}
+				break
+			}
+			if len(due) == 0 {
+				break
+			}
+
+			for _, notification := range due {
+				if ctx.Err() != nil {
+					// Shutting down: give the rest back so another instance can send them right away
+					if err := p.notificationRepo.ReleaseDelivery(context.Background(), notification); err != nil {
+						log.Printf("[NOTIFICATION_WORKER] Failed to release notification %s: %v", notification.ID, err)
+					}
+					continue
+				}
+				p.deliver(ctx, notification)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliver pushes a claimed notification to the user's devices and records the outcome
+func (p *NotificationWorkerPool) deliver(ctx context.Context, notification *models.Notification) {
+	now := time.Now()
+	notification.NextAttemptAt = nil
+
+	if notification.Attempts > notification.MaxAttempts {
+		// The last attempt never finished
+		notification.Status = models.NotificationStatusFailed
+		notification.Error = "delivery stopped responding"
+		p.finish(ctx, notification)
+		return
+	}
+
+	// Quiet hours are checked again, since the user may have changed them since the
+	// notification was queued and a retry may come due inside them
+	var sent int
+	user, err := p.userRepo.FindByID(ctx, notification.UserID)
+	if err == nil {
+		if end, quiet := quietHoursEnd(user, now); quiet {
+			p.deferUntil(ctx, notification, end)
+			return
+		}
+
+		// The app reports the notification as opened by its ID
+		data := map[string]string{"notification_id": notification.ID.String()}
+		for key, value := range notification.Payload.Data {
+			data[key] = value
+		}
+		sent, err = pushToDevices(ctx, p.userRepo, p.sender, notification.UserID, notifications.Notification{
+			Title:    notification.Payload.Title,
+			Body:     notification.Payload.Body,
+			Data:     data,
+			Priority: notification.Payload.Priority,
+		})
+	}
+	if err != nil && ctx.Err() != nil {
+		// Interrupted by shutdown, not a real failure
+		if err := p.notificationRepo.ReleaseDelivery(context.Background(), notification); err != nil {
+			log.Printf("[NOTIFICATION_WORKER] Failed to release notification %s: %v", notification.ID, err)
+		}
+		return
+	}
+	switch {
+	case err == nil && sent > 0:
+		notification.Status = models.NotificationStatusSent
+		notification.Error = ""
+		notification.SentAt = &now
+	case errors.Is(err, errNoPushDevices), errors.Is(err, repository.ErrUserNotFound):
+		// Retrying won't help until the user registers a device, or ever once they're gone
+		notification.Status = models.NotificationStatusFailed
+		notification.Error = err.Error()
+	default:
+		if err == nil {
+			err = errors.New("no device accepted the notification")
+		}
+		notification.Error = err.Error()
+		if notification.Attempts < notification.MaxAttempts {
+			retryAt := now.Add(notificationRetryBackoff(notification.Attempts))
+			notification.NextAttemptAt = &retryAt
+		} else {
+			notification.Status = models.NotificationStatusFailed
+		}
+	}
+
+	p.finish(ctx, notification)
+}
+
+// finish records the outcome of a delivery attempt
+func (p *NotificationWorkerPool) finish(ctx context.Context, notification *models.Notification) {
+	owned, err := p.notificationRepo.FinishDelivery(ctx, notification)
+	if err != nil {
+		log.Printf("[NOTIFICATION_WORKER] Failed to finish notification %s: %v", notification.ID, err)
+		return
+	}
+	if !owned {
+		log.Printf("[NOTIFICATION_WORKER] Notification %s was reclaimed", notification.ID)
+		return
+	}
+	if notification.Error != "" {
+		log.Printf("[NOTIFICATION_WORKER] Notification %s %s after attempt %d/%d: %s",
+			notification.ID, notification.Status, notification.Attempts, notification.MaxAttempts, notification.Error)
+	}
+}
+
+// deferUntil hands a claimed notification back until the given time, without counting the
+// attempt
+func (p *NotificationWorkerPool) deferUntil(ctx context.Context, notification *models.Notification, until time.Time) {
+	owned, err := p.notificationRepo.DeferDelivery(ctx, notification, until)
+	if err != nil {
+		log.Printf("[NOTIFICATION_WORKER] Failed to defer notification %s: %v", notification.ID, err)
+		return
+	}
+	if !owned {
+		log.Printf("[NOTIFICATION_WORKER] Notification %s was reclaimed", notification.ID)
+	}
+}
+
+// notificationRetryBackoff returns the delay before the next delivery attempt, doubling
+// after each attempt
+func notificationRetryBackoff(attempt int) time.Duration {
+	delay := notificationRetryBaseDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= notificationRetryMaxDelay {
+			return notificationRetryMaxDelay
+		}
+	}
+	return delay
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/vyve/vyve-backend/internal/models"
+	"github.com/vyve/vyve-backend/internal/repository"
+)
+
+func TestNotificationRetryBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{7, 32 * time.Minute},
+		{8, notificationRetryMaxDelay},
+		{20, notificationRetryMaxDelay},
+	}
+	for _, tt := range tests {
+		if got := notificationRetryBackoff(tt.attempt); got != tt.want {
+			t.Errorf("notificationRetryBackoff(%d) = %s, want %s", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestDeliverNotification(t *testing.T) {
+	userID := uuid.New()
+	fcmDown := errors.New("fcm unavailable")
+
+	// Quiet hours from this hour for two hours, so they still hold if the hour turns
+	hour := time.Now().UTC().Truncate(time.Hour)
+	quiet := models.DefaultNotificationPreferences()
+	quietEnd := hour.Add(2 * time.Hour)
+	quiet.QuietHours = models.QuietHours{Enabled: true, Start: hour.Hour(), End: quietEnd.Hour()}
+
+	tests := []struct {
+		name       string
+		attempts   int
+		noUser     bool
+		noDevices  bool
+		prefs      *models.NotificationPreferences
+		sendErr    error
+		wantStatus string
+		wantRetry  time.Duration
+		wantDefer  *time.Time
+	}{
+		{name: "delivered", attempts: 1, wantStatus: models.NotificationStatusSent},
+		{name: "first failure", attempts: 1, sendErr: fcmDown, wantStatus: models.NotificationStatusPending, wantRetry: 30 * time.Second},
+		{name: "third failure", attempts: 3, sendErr: fcmDown, wantStatus: models.NotificationStatusPending, wantRetry: 2 * time.Minute},
+		{name: "last attempt failed", attempts: 5, sendErr: fcmDown, wantStatus: models.NotificationStatusFailed},
+		{name: "no devices", attempts: 1, noDevices: true, wantStatus: models.NotificationStatusFailed},
+		{name: "user gone", attempts: 1, noUser: true, wantStatus: models.NotificationStatusFailed},
+		{name: "previous attempt never finished", attempts: 6, wantStatus: models.NotificationStatusFailed},
+		{name: "quiet hours", attempts: 2, prefs: &quiet, wantDefer: &quietEnd},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			userRepo := &pushTokenStore{MockUserRepository: repository.NewMockUserRepository(ctrl)}
+			if tt.noUser {
+				userRepo.EXPECT().FindByID(gomock.Any(), userID).Return(nil, repository.ErrUserNotFound).AnyTimes()
+			} else {
+				user := &models.User{Base: models.Base{ID: userID}, Timezone: "UTC", NotificationPreferences: tt.prefs}
+				userRepo.EXPECT().FindByID(gomock.Any(), userID).Return(user, nil).AnyTimes()
+			}
+			if !tt.noDevices {
+				userRepo.tokens = []*models.PushToken{{UserID: userID, Token: "phone", Active: true}}
+			}
+
+			// Each attempt either finishes or, in quiet hours, is deferred
+			var finished int
+			var deferredUntil *time.Time
+			notificationRepo := repository.NewMockNotificationRepository(ctrl)
+			notificationRepo.EXPECT().
+				FinishDelivery(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, notification *models.Notification) (bool, error) {
+					finished++
+					return true, nil
+				}).
+				AnyTimes()
+			notificationRepo.EXPECT().
+				DeferDelivery(gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, notification *models.Notification, until time.Time) (bool, error) {
+					deferredUntil = &until
+					return true, nil
+				}).
+				AnyTimes()
+			sender := &fakePushSender{err: tt.sendErr}
+			pool := NewNotificationWorkerPool(notificationRepo, userRepo, sender)
+			notification := &models.Notification{
+				Base:        models.Base{ID: uuid.New()},
+				UserID:      userID,
+				Channel:     models.ChannelPush,
+				Payload:     models.NotificationPayload{Title: "Hi", Data: map[string]string{"type": "nudge"}},
+				Status:      models.NotificationStatusPending,
+				Attempts:    tt.attempts,
+				MaxAttempts: 5,
+			}
+
+			before := time.Now()
+			pool.deliver(context.Background(), notification)
+			after := time.Now()
+
+			if tt.wantDefer != nil {
+				if finished != 0 || len(sender.sent) != 0 {
+					t.Fatal("notification was delivered inside quiet hours")
+				}
+				if deferredUntil == nil || !deferredUntil.Equal(*tt.wantDefer) {
+					t.Errorf("deferred until %v, want %v", deferredUntil, tt.wantDefer)
+				}
+				return
+			}
+
+			if finished != 1 {
+				t.Fatalf("attempt finished %d times, want once", finished)
+			}
+			if notification.Status != tt.wantStatus {
+				t.Errorf("status = %q (%s), want %q", notification.Status, notification.Error, tt.wantStatus)
+			}
+			if tt.wantStatus == models.NotificationStatusSent {
+				if notification.SentAt == nil || sender.sent[0].Data["notification_id"] != notification.ID.String() {
+					t.Errorf("sent at %v with data %v", notification.SentAt, sender.sent[0].Data)
+				}
+			}
+			switch {
+			case tt.wantRetry == 0 && notification.NextAttemptAt != nil:
+				t.Errorf("retry scheduled at %v, want none", notification.NextAttemptAt)
+			case tt.wantRetry != 0 && (notification.NextAttemptAt == nil ||
+				notification.NextAttemptAt.Before(before.Add(tt.wantRetry)) || notification.NextAttemptAt.After(after.Add(tt.wantRetry))):
+				t.Errorf("retry scheduled at %v, want %s from now", notification.NextAttemptAt, tt.wantRetry)
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vyve/vyve-backend/internal/models"
+	"github.com/vyve/vyve-backend/internal/nudgerules"
+)
+
+const (
+	nudgeSourceSystem = "system"
+
+	// systemNudgeWindow is how many recent interactions the rules look at
+	systemNudgeWindow = 10
+	// energyMixWindow is how many of the most recent interactions define the energy mix
+	energyMixWindow = 5
+	// healthTrendThreshold is the score change (0-100) considered a real trend
+	healthTrendThreshold = 15.0
+
+	defaultReminderIntervalDays = 14
+)
+
+// reminderIntervalDays maps a person's reminder frequency to the number of days
+// without contact after which a reach out nudge is due
+var reminderIntervalDays = map[string]int{
+	"daily":     1,
+	"weekly":    7,
+	"biweekly":  14,
+	"monthly":   30,
+	"quarterly": 90,
+}
+
+// energyMix counts energy impacts across the most recent interactions
+type energyMix struct {
+	Energizing int
+	Neutral    int
+	Draining   int
+	Total      int
+}
+
+// evaluateSystemRules applies the built-in nudge rules to a person and returns the
+// first matching nudge, or nil if the relationship needs no attention right now.
+// Interactions must be ordered most recent first.
+func evaluateSystemRules(person *models.Person, interactions []*models.Interaction, now time.Time) *models.Nudge {
+	mix := recentEnergyMix(interactions, energyMixWindow)
+	trend := healthScoreTrend(interactions)
+
+	// Rule 1: mostly draining interactions -> protect the user's energy first
+	if mix.Total >= 3 && mix.Draining*2 > mix.Total {
+		priority := "medium"
+		if trend <= -healthTrendThreshold {
+			priority = "high"
+		}
+		return &models.Nudge{
+			Type:     "set_boundary",
+			Title:    fmt.Sprintf("Protect your energy with %s", person.Name),
+			Message:  fmt.Sprintf("%d of your last %d interactions with %s left you drained. Consider setting a boundary before your next conversation.", mix.Draining, mix.Total, person.Name),
+			Priority: priority,
+			SuggestedActions: models.StringArray{
+				"Decide in advance how long you want to spend together",
+				"Plan a low-effort activity for your next meeting",
+			},
+			Timing:          "this_week",
+			EstimatedImpact: priority,
+			Reasoning:       fmt.Sprintf("draining=%d/%d, health_trend=%.0f", mix.Draining, mix.Total, trend),
+		}
+	}
+
+	// Rule 2: no contact for longer than the reminder frequency -> reach out
+	interval := reminderInterval(person.ReminderFrequency)
+	daysSince := daysSinceLastInteraction(person, now)
+
+	if daysSince >= interval {
+		priority := "medium"
+		timing := "this_week"
+		if daysSince >= 2*interval || trend <= -healthTrendThreshold {
+			priority = "high"
+			timing = "today"
+		}
+
+		message := fmt.Sprintf("It's been %d days since you last connected with %s.", daysSince, person.Name)
+		if person.LastInteractionAt == nil {
+			message = fmt.Sprintf("You haven't logged an interaction with %s yet.", person.Name)
+		}
+		if mix.Total > 0 && interactions[0].EnergyImpact == "energizing" {
+			message += " Your last time together was energizing."
+		}
+
+		return &models.Nudge{
+			Type:     "reach_out",
+			Title:    fmt.Sprintf("Reach out to %s", person.Name),
+			Message:  message,
+			Priority: priority,
+			SuggestedActions: models.StringArray{
+				"Send a quick message",
+				"Schedule a call",
+			},
+			Timing:          timing,
+			EstimatedImpact: priority,
+			Reasoning:       fmt.Sprintf("days_since_last_interaction=%d, reminder_interval=%d, health_trend=%.0f", daysSince, interval, trend),
+		}
+	}
+
+	// Rule 3: relationship improving and mostly energizing -> celebrate it
+	if trend >= healthTrendThreshold && mix.Energizing*2 > mix.Total {
+		return &models.Nudge{
+			Type:     "celebrate",
+			Title:    fmt.Sprintf("Things are going well with %s", person.Name),
+			Message:  fmt.Sprintf("Your recent time with %s has been energizing. Take a moment to appreciate it or let them know.", person.Name),
+			Priority: "low",
+			SuggestedActions: models.StringArray{
+				"Tell them you enjoyed your time together",
+			},
+			Timing:          "this_week",
+			EstimatedImpact: "medium",
+			Reasoning:       fmt.Sprintf("energizing=%d/%d, health_trend=%.0f", mix.Energizing, mix.Total, trend),
+		}
+	}
+
+	return nil
+}
+
+// ruleNudgeTitles holds the title template for each rule action
+var ruleNudgeTitles = map[string]string{
+	"reach_out":     "Reach out to %s",
+	"schedule_call": "Schedule a call with %s",
+	"set_boundary":  "Protect your energy with %s",
+	"celebrate":     "Celebrate your connection with %s",
+	"check_in":      "Check in with %s",
+}
+
+// ruleNudge builds the nudge produced by a user-defined or global rule
+func ruleNudge(rule *models.NudgeRule, parsed *nudgerules.Rule, person *models.Person) *models.Nudge {
+	timing := "this_week"
+	if parsed.Priority == "high" {
+		timing = "today"
+	}
+
+	message := fmt.Sprintf("Your rule \"%s\" matched %s.", rule.Name, person.Name)
+	if rule.UserID == nil {
+		message = fmt.Sprintf("Suggested because \"%s\" applies to %s.", rule.Name, person.Name)
+	}
+
+	return &models.Nudge{
+		Type:            parsed.Action,
+		Title:           fmt.Sprintf(ruleNudgeTitles[parsed.Action], person.Name),
+		Message:         message,
+		Priority:        parsed.Priority,
+		Timing:          timing,
+		EstimatedImpact: parsed.Priority,
+		Reasoning:       parsed.String(),
+		ActionData: models.JSONB{
+			"rule_id":   rule.ID.String(),
+			"rule_name": rule.Name,
+			"global":    rule.UserID == nil,
+		},
+	}
+}
+
+// buildRuleFacts collects the facts user-defined rules are evaluated against.
+// Interactions must be ordered most recent first.
+func buildRuleFacts(person *models.Person, interactions []*models.Interaction, categories map[uuid.UUID]string, now time.Time) nudgerules.Facts {
+	mix := recentEnergyMix(interactions, energyMixWindow)
+
+	facts := nudgerules.Facts{
+		Relationship:             person.Relationship,
+		ReminderFrequency:        person.ReminderFrequency,
+		DaysSinceLastInteraction: daysSinceLastInteraction(person, now),
+		HealthScore:              person.HealthScore,
+		HealthTrend:              healthScoreTrend(interactions),
+		InteractionCount:         person.InteractionCount,
+		EnergizingCount:          mix.Energizing,
+		DrainingCount:            mix.Draining,
+	}
+	if person.CategoryID != nil {
+		facts.Category = categories[*person.CategoryID]
+	}
+	if len(interactions) > 0 {
+		facts.LastEnergyImpact = interactions[0].EnergyImpact
+	}
+
+	return facts
+}
+
+// daysSinceLastInteraction counts whole days since the last interaction, or since the
+// person was added if there has been none
+func daysSinceLastInteraction(person *models.Person, now time.Time) int {
+	last := person.CreatedAt
+	if person.LastInteractionAt != nil {
+		last = *person.LastInteractionAt
+	}
+	return int(now.Sub(last).Hours() / 24)
+}
+
+// reminderInterval returns the contact interval in days for a reminder frequency
+func reminderInterval(frequency string) int {
+	if days, ok := reminderIntervalDays[frequency]; ok {
+		return days
+	}
+	return defaultReminderIntervalDays
+}
+
+// recentEnergyMix counts the energy impact of the latest n interactions
+func recentEnergyMix(interactions []*models.Interaction, n int) energyMix {
+	var mix energyMix
+	for i, interaction := range interactions {
+		if i >= n {
+			break
+		}
+		switch interaction.EnergyImpact {
+		case "energizing":
+			mix.Energizing++
+		case "draining":
+			mix.Draining++
+		default:
+			mix.Neutral++
+		}
+		mix.Total++
+	}
+	return mix
+}
+
+// healthScoreTrend compares the current health score with the score before the
+// two most recent interactions. Positive values mean the relationship is improving.
+func healthScoreTrend(interactions []*models.Interaction) float64 {
+	const shift = 2
+	if len(interactions) <= shift+1 {
+		return 0
+	}
+	return calculateHealthScore(interactions) - calculateHealthScore(interactions[shift:])
+}
+
+// userLocation resolves a user's timezone, falling back to UTC
+func userLocation(timezone string) *time.Location {
+	if timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// nudgeExpiry returns the end of the user's local day for "today" nudges and the end
+// of the sixth following local day otherwise
+func nudgeExpiry(now time.Time, loc *time.Location, timing string) *time.Time {
+	days := 6
+	if timing == "today" || timing == "now" {
+		days = 0
+	}
+	expiresAt := endOfLocalDay(now, loc, days)
+	return &expiresAt
+}
+
+// endOfLocalDay returns midnight at the end of the local day that is addDays after now
+func endOfLocalDay(now time.Time, loc *time.Location, addDays int) time.Time {
+	local := now.In(loc)
+	y, m, d := local.Date()
+	return time.Date(y, m, d+addDays+1, 0, 0, 0, 0, loc)
+}
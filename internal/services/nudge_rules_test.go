@@ -0,0 +1,268 @@
+package services
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vyve/vyve-backend/internal/models"
+)
+
+// interactionsOf returns interactions with the given energy impacts, most recent first
+func interactionsOf(impacts ...string) []*models.Interaction {
+	interactions := make([]*models.Interaction, len(impacts))
+	for i, impact := range impacts {
+		interactions[i] = &models.Interaction{Base: models.Base{ID: uuid.New()}, EnergyImpact: impact}
+	}
+	return interactions
+}
+
+func TestHealthScoreTrend(t *testing.T) {
+	tests := []struct {
+		name    string
+		impacts []string
+		want    float64
+	}{
+		{"no interactions", nil, 0},
+		{"too few to compare", []string{"energizing", "draining", "draining"}, 0},
+		{"improving", []string{"energizing", "energizing", "draining", "draining"}, 72},
+		{"worsening", []string{"draining", "draining", "energizing", "energizing"}, -72},
+		{"steady", []string{"neutral", "neutral", "neutral", "neutral"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := healthScoreTrend(interactionsOf(tt.impacts...)); math.Abs(got-tt.want) > 0.5 {
+				t.Errorf("healthScoreTrend() = %.1f, want %.0f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateSystemRules(t *testing.T) {
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) *time.Time {
+		at := now.Add(-time.Duration(days) * 24 * time.Hour)
+		return &at
+	}
+
+	tests := []struct {
+		name         string
+		frequency    string
+		lastContact  *time.Time
+		impacts      []string
+		wantType     string
+		wantPriority string
+		wantMessage  string
+	}{
+		{
+			name:         "mostly draining",
+			frequency:    "weekly",
+			lastContact:  daysAgo(1),
+			impacts:      []string{"draining", "draining", "neutral"},
+			wantType:     "set_boundary",
+			wantPriority: "medium",
+		},
+		{
+			name:         "mostly draining and worsening",
+			frequency:    "weekly",
+			lastContact:  daysAgo(1),
+			impacts:      []string{"draining", "draining", "draining", "energizing", "energizing"},
+			wantType:     "set_boundary",
+			wantPriority: "high",
+		},
+		{
+			name:        "too few draining interactions to judge",
+			frequency:   "weekly",
+			lastContact: daysAgo(1),
+			impacts:     []string{"draining", "draining"},
+		},
+		{
+			name:        "in touch",
+			frequency:   "weekly",
+			lastContact: daysAgo(6),
+			impacts:     []string{"neutral"},
+		},
+		{
+			name:         "overdue",
+			frequency:    "weekly",
+			lastContact:  daysAgo(7),
+			impacts:      []string{"neutral"},
+			wantType:     "reach_out",
+			wantPriority: "medium",
+			wantMessage:  "It's been 7 days",
+		},
+		{
+			name:         "twice overdue",
+			frequency:    "weekly",
+			lastContact:  daysAgo(14),
+			impacts:      []string{"neutral"},
+			wantType:     "reach_out",
+			wantPriority: "high",
+		},
+		{
+			name:         "overdue after an energizing meeting",
+			frequency:    "monthly",
+			lastContact:  daysAgo(30),
+			impacts:      []string{"energizing"},
+			wantType:     "reach_out",
+			wantPriority: "medium",
+			wantMessage:  "Your last time together was energizing.",
+		},
+		{
+			name:         "never contacted",
+			frequency:    "weekly",
+			wantType:     "reach_out",
+			wantPriority: "high",
+			wantMessage:  "You haven't logged an interaction",
+		},
+		{
+			name:        "unknown frequency within the default interval",
+			frequency:   "sometimes",
+			lastContact: daysAgo(defaultReminderIntervalDays - 1),
+		},
+		{
+			name:         "unknown frequency past the default interval",
+			frequency:    "sometimes",
+			lastContact:  daysAgo(defaultReminderIntervalDays),
+			wantType:     "reach_out",
+			wantPriority: "medium",
+		},
+		{
+			name:         "improving and energizing",
+			frequency:    "weekly",
+			lastContact:  daysAgo(1),
+			impacts:      []string{"energizing", "energizing", "energizing", "draining"},
+			wantType:     "celebrate",
+			wantPriority: "low",
+		},
+		{
+			name:        "energizing but steady",
+			frequency:   "weekly",
+			lastContact: daysAgo(1),
+			impacts:     []string{"energizing", "energizing", "energizing", "energizing"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			person := &models.Person{
+				// Added a fortnight ago, for the person never contacted
+				Base:              models.Base{ID: uuid.New(), CreatedAt: *daysAgo(14)},
+				Name:              "Sam",
+				ReminderFrequency: tt.frequency,
+				LastInteractionAt: tt.lastContact,
+			}
+
+			nudge := evaluateSystemRules(person, interactionsOf(tt.impacts...), now)
+			if tt.wantType == "" {
+				if nudge != nil {
+					t.Fatalf("got a %s nudge (%s), want none", nudge.Type, nudge.Reasoning)
+				}
+				return
+			}
+			if nudge == nil {
+				t.Fatalf("got no nudge, want %s", tt.wantType)
+			}
+			if nudge.Type != tt.wantType || nudge.Priority != tt.wantPriority {
+				t.Errorf("got %s with %s priority (%s), want %s with %s", nudge.Type, nudge.Priority, nudge.Reasoning, tt.wantType, tt.wantPriority)
+			}
+			if !strings.Contains(nudge.Message, tt.wantMessage) {
+				t.Errorf("message %q does not contain %q", nudge.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestNudgeExpiry(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		now    time.Time
+		loc    *time.Location
+		timing string
+		want   time.Time
+	}{
+		{
+			name:   "today ends at local midnight",
+			now:    time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC),
+			loc:    time.UTC,
+			timing: "today",
+			want:   time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "now expires with today",
+			now:    time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC),
+			loc:    time.UTC,
+			timing: "now",
+			want:   time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "late evening west of UTC is still the local day",
+			now:    time.Date(2026, 10, 14, 3, 30, 0, 0, time.UTC),
+			loc:    newYork,
+			timing: "today",
+			want:   time.Date(2026, 10, 14, 0, 0, 0, 0, newYork),
+		},
+		{
+			name:   "early morning east of UTC is already the next local day",
+			now:    time.Date(2026, 10, 14, 20, 0, 0, 0, time.UTC),
+			loc:    tokyo,
+			timing: "today",
+			want:   time.Date(2026, 10, 16, 0, 0, 0, 0, tokyo),
+		},
+		{
+			name:   "this week runs to the end of the sixth following day",
+			now:    time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC),
+			loc:    time.UTC,
+			timing: "this_week",
+			want:   time.Date(2026, 10, 21, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "this week across the end of daylight saving time",
+			now:    time.Date(2026, 10, 30, 16, 0, 0, 0, time.UTC),
+			loc:    newYork,
+			timing: "this_week",
+			want:   time.Date(2026, 11, 6, 0, 0, 0, 0, newYork),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := nudgeExpiry(tt.now, tt.loc, tt.timing)
+			if got == nil || !got.Equal(tt.want) {
+				t.Errorf("nudgeExpiry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEndOfLocalDay(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 23:30 on the day daylight saving time ends, a 25 hour day in Berlin
+	now := time.Date(2026, 10, 25, 22, 30, 0, 0, time.UTC)
+
+	if got, want := endOfLocalDay(now, berlin, 0), time.Date(2026, 10, 26, 0, 0, 0, 0, berlin); !got.Equal(want) {
+		t.Errorf("endOfLocalDay() = %v, want %v", got, want)
+	}
+	if got, want := endOfLocalDay(now, time.UTC, 0), time.Date(2026, 10, 26, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("endOfLocalDay() in UTC = %v, want %v", got, want)
+	}
+	if got, want := endOfLocalDay(now, berlin, 1), time.Date(2026, 10, 27, 0, 0, 0, 0, berlin); !got.Equal(want) {
+		t.Errorf("endOfLocalDay() a day later = %v, want %v", got, want)
+	}
+}
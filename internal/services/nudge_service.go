@@ -2,28 +2,45 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"log"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/vyve/vyve-backend/internal/models"
+	"github.com/vyve/vyve-backend/internal/nudgerules"
 	"github.com/vyve/vyve-backend/internal/repository"
 	"github.com/vyve/vyve-backend/pkg/analytics"
-	"github.com/vyve/vyve-backend/pkg/notifications"
 )
 
 type nudgeServiceImpl struct {
-	nudgeRepo     repository.NudgeRepository
-	personRepo    repository.PersonRepository
-	notifications notifications.NotificationService
-	analytics     analytics.Analytics
+	nudgeRepo        repository.NudgeRepository
+	ruleRepo         repository.NudgeRuleRepository
+	personRepo       repository.PersonRepository
+	userRepo         repository.UserRepository
+	consentRepo      repository.ConsentRepository
+	notificationRepo repository.NotificationRepository
+	analytics        analytics.Analytics
 }
 
 // NewNudgeService creates a new nudge service
-func NewNudgeService(nudgeRepo repository.NudgeRepository, notifications notifications.NotificationService, analyticsService analytics.Analytics) NudgeService {
+func NewNudgeService(
+	nudgeRepo repository.NudgeRepository,
+	ruleRepo repository.NudgeRuleRepository,
+	personRepo repository.PersonRepository,
+	userRepo repository.UserRepository,
+	consentRepo repository.ConsentRepository,
+	notificationRepo repository.NotificationRepository,
+	analyticsService analytics.Analytics,
+) NudgeService {
 	return &nudgeServiceImpl{
-		nudgeRepo:     nudgeRepo,
-		notifications: notifications,
-		analytics:     analyticsService,
+		nudgeRepo:        nudgeRepo,
+		ruleRepo:         ruleRepo,
+		personRepo:       personRepo,
+		userRepo:         userRepo,
+		consentRepo:      consentRepo,
+		notificationRepo: notificationRepo,
+		analytics:        analyticsService,
 	}
 }
 
@@ -165,21 +182,353 @@ func (s *nudgeServiceImpl) GenerateForPerson(ctx context.Context, userID, person
 	return nil, nil
 }
 
-// GenerateSystemNudges generates system-based nudges for all relationships
+// GenerateSystemNudges runs the user's own rules, the global rules and then the built-in
+// rules over all of their relationships and stores one nudge per person that matches a
+// rule. People that already have a pending system nudge are skipped so the user is never
+// nudged twice about the same person.
 func (s *nudgeServiceImpl) GenerateSystemNudges(ctx context.Context, userID uuid.UUID) ([]*models.Nudge, error) {
-	// This would analyze all relationships and create system nudges
-	// based on rules like:
-	// - No interaction in X days -> "reconnect" nudge
-	// - Low quality interactions -> "improve quality" nudge
-	// - Unbalanced communication -> "balance" nudge
-	
-	// For now, return empty as this is a complex feature
-	// that requires the person and interaction repositories
-	return nil, nil
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	people, err := s.personRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load people: %w", err)
+	}
+
+	rules, err := s.loadUserRules(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var categories map[uuid.UUID]string
+	if len(rules) > 0 {
+		if categories, err = s.personRepo.GetCategoryNames(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	loc := userLocation(user.Timezone)
+	created := make([]*models.Nudge, 0)
+
+	for _, person := range people {
+		pending, err := s.nudgeRepo.HasPendingForPerson(ctx, person.ID, nudgeSourceSystem)
+		if err != nil {
+			return created, err
+		}
+		if pending {
+			continue
+		}
+
+		interactions, err := s.personRepo.GetRecentInteractions(ctx, person.ID, systemNudgeWindow)
+		if err != nil {
+			return created, err
+		}
+
+		var nudge *models.Nudge
+		if len(rules) > 0 {
+			facts := buildRuleFacts(person, interactions, categories, now)
+			for _, rule := range rules {
+				if rule.parsed.Match(facts) {
+					nudge = ruleNudge(rule.model, rule.parsed, person)
+					if err := s.ruleRepo.MarkFired(ctx, rule.model.ID); err != nil {
+						log.Printf("[NUDGE] Failed to update rule %s: %v", rule.model.ID, err)
+					}
+					break
+				}
+			}
+		}
+		if nudge == nil {
+			nudge = evaluateSystemRules(person, interactions, now)
+		}
+		if nudge == nil {
+			continue
+		}
+
+		personID := person.ID
+		nudge.UserID = userID
+		nudge.PersonID = &personID
+		nudge.Source = nudgeSourceSystem
+		nudge.Status = "pending"
+		nudge.ExpiresAt = nudgeExpiry(now, loc, nudge.Timing)
+
+		if err := s.nudgeRepo.Create(ctx, nudge); err != nil {
+			return created, fmt.Errorf("failed to create nudge: %w", err)
+		}
+		created = append(created, nudge)
+
+		go s.analytics.Track(context.Background(), analytics.Event{
+			UserID:    userID.String(),
+			EventType: analytics.EventNudgeGenerated,
+			Properties: map[string]interface{}{
+				"nudge_id":   nudge.ID.String(),
+				"nudge_type": nudge.Type,
+				"source":     nudge.Source,
+				"priority":   nudge.Priority,
+			},
+			Timestamp: now,
+		})
+	}
+
+	return created, nil
+}
+
+// compiledRule pairs a stored rule with its parsed expression
+type compiledRule struct {
+	model  *models.NudgeRule
+	parsed *nudgerules.Rule
+}
+
+// loadUserRules loads and parses the enabled rules that apply to the user, their own
+// before the global ones, skipping any that no longer parse
+func (s *nudgeServiceImpl) loadUserRules(ctx context.Context, userID uuid.UUID) ([]compiledRule, error) {
+	if s.ruleRepo == nil {
+		return nil, nil
+	}
+
+	stored, err := s.ruleRepo.GetEnabled(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load nudge rules: %w", err)
+	}
+
+	rules := make([]compiledRule, 0, len(stored))
+	for _, rule := range stored {
+		parsed, err := nudgerules.Parse(rule.Expression)
+		if err != nil {
+			log.Printf("[NUDGE] Skipping invalid rule %s: %v", rule.ID, err)
+			continue
+		}
+		rules = append(rules, compiledRule{model: rule, parsed: parsed})
+	}
+
+	return rules, nil
 }
 
-// GenerateNudges is the original interface method (kept for compatibility)
+// GenerateNudges generates system nudges and notifies the user of the high priority ones
 func (s *nudgeServiceImpl) GenerateNudges(ctx context.Context, userID uuid.UUID) error {
-	_, err := s.GenerateSystemNudges(ctx, userID)
-	return err
+	nudges, err := s.GenerateSystemNudges(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, nudge := range nudges {
+		if nudge.Priority != "high" {
+			continue
+		}
+		s.notifyNudge(ctx, userID, nudge)
+	}
+
+	return nil
+}
+
+// notifyNudge queues a nudge as a push and in-app notification to the user, on the
+// channels their notification preferences allow
+func (s *nudgeServiceImpl) notifyNudge(ctx context.Context, userID uuid.UUID, nudge *models.Nudge) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		log.Printf("[NUDGE] Failed to load user %s: %v", userID, err)
+		return
+	}
+
+	_, err = queueNotification(ctx, s.notificationRepo, s.consentRepo, user, models.NotificationNudge, models.NotificationPayload{
+		Title:    nudge.Title,
+		Body:     nudge.Message,
+		Priority: nudge.Priority,
+		Data: map[string]string{
+			"type":     "nudge",
+			"nudge_id": nudge.ID.String(),
+		},
+	}, time.Now())
+	if err != nil {
+		log.Printf("[NUDGE] Failed to queue notification of nudge %s: %v", nudge.ID, err)
+	}
+}
+
+// NudgeRuleRequest represents a request to create or update a nudge rule
+type NudgeRuleRequest struct {
+	Name       string `json:"name"`
+	Expression string `json:"expression"`
+	Enabled    *bool  `json:"enabled"`
+}
+
+// RuleMatch describes a person a rule would fire for
+type RuleMatch struct {
+	PersonID   uuid.UUID        `json:"person_id"`
+	PersonName string           `json:"person_name"`
+	Action     string           `json:"action"`
+	Priority   string           `json:"priority"`
+	Facts      nudgerules.Facts `json:"facts"`
+}
+
+// ListRules lists the user's nudge rules
+func (s *nudgeServiceImpl) ListRules(ctx context.Context, userID uuid.UUID) ([]*models.NudgeRule, error) {
+	return s.ruleRepo.ListByUser(ctx, userID)
+}
+
+// GetRule gets a nudge rule owned by the user
+func (s *nudgeServiceImpl) GetRule(ctx context.Context, userID, ruleID uuid.UUID) (*models.NudgeRule, error) {
+	rule, err := s.ruleRepo.FindByID(ctx, ruleID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify ownership; global rules belong to no user
+	if rule.UserID == nil || *rule.UserID != userID {
+		return nil, repository.ErrForbidden
+	}
+
+	return rule, nil
+}
+
+// CreateRule validates and stores a new nudge rule
+func (s *nudgeServiceImpl) CreateRule(ctx context.Context, userID uuid.UUID, req NudgeRuleRequest) (*models.NudgeRule, error) {
+	return s.createRule(ctx, &userID, req)
+}
+
+// UpdateRule updates a nudge rule owned by the user
+func (s *nudgeServiceImpl) UpdateRule(ctx context.Context, userID, ruleID uuid.UUID, req NudgeRuleRequest) (*models.NudgeRule, error) {
+	rule, err := s.GetRule(ctx, userID, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	return s.updateRule(ctx, rule, req)
+}
+
+// DeleteRule deletes a nudge rule owned by the user
+func (s *nudgeServiceImpl) DeleteRule(ctx context.Context, userID, ruleID uuid.UUID) error {
+	if _, err := s.GetRule(ctx, userID, ruleID); err != nil {
+		return err
+	}
+	return s.ruleRepo.Delete(ctx, ruleID)
+}
+
+// ListGlobalRules lists the global nudge rules
+func (s *nudgeServiceImpl) ListGlobalRules(ctx context.Context) ([]*models.NudgeRule, error) {
+	return s.ruleRepo.ListGlobal(ctx)
+}
+
+// GetGlobalRule gets a global nudge rule. A user's own rule is reported as not found.
+func (s *nudgeServiceImpl) GetGlobalRule(ctx context.Context, ruleID uuid.UUID) (*models.NudgeRule, error) {
+	rule, err := s.ruleRepo.FindByID(ctx, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	if rule.UserID != nil {
+		return nil, repository.ErrNudgeRuleNotFound
+	}
+	return rule, nil
+}
+
+// CreateGlobalRule validates and stores a new global nudge rule
+func (s *nudgeServiceImpl) CreateGlobalRule(ctx context.Context, req NudgeRuleRequest) (*models.NudgeRule, error) {
+	return s.createRule(ctx, nil, req)
+}
+
+// UpdateGlobalRule updates a global nudge rule
+func (s *nudgeServiceImpl) UpdateGlobalRule(ctx context.Context, ruleID uuid.UUID, req NudgeRuleRequest) (*models.NudgeRule, error) {
+	rule, err := s.GetGlobalRule(ctx, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	return s.updateRule(ctx, rule, req)
+}
+
+// DeleteGlobalRule deletes a global nudge rule
+func (s *nudgeServiceImpl) DeleteGlobalRule(ctx context.Context, ruleID uuid.UUID) error {
+	if _, err := s.GetGlobalRule(ctx, ruleID); err != nil {
+		return err
+	}
+	return s.ruleRepo.Delete(ctx, ruleID)
+}
+
+// createRule validates and stores a new rule of the user, or a global rule when userID is nil
+func (s *nudgeServiceImpl) createRule(ctx context.Context, userID *uuid.UUID, req NudgeRuleRequest) (*models.NudgeRule, error) {
+	if req.Name == "" {
+		return nil, fmt.Errorf("%w: name is required", repository.ErrInvalidInput)
+	}
+	if _, err := nudgerules.Parse(req.Expression); err != nil {
+		return nil, err
+	}
+
+	rule := &models.NudgeRule{
+		UserID:     userID,
+		Name:       req.Name,
+		Expression: req.Expression,
+		Enabled:    true,
+	}
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+
+	if err := s.ruleRepo.Create(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// updateRule applies the set fields of the request to a rule and stores it
+func (s *nudgeServiceImpl) updateRule(ctx context.Context, rule *models.NudgeRule, req NudgeRuleRequest) (*models.NudgeRule, error) {
+	if req.Name != "" {
+		rule.Name = req.Name
+	}
+	if req.Expression != "" {
+		if _, err := nudgerules.Parse(req.Expression); err != nil {
+			return nil, err
+		}
+		rule.Expression = req.Expression
+	}
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+
+	if err := s.ruleRepo.Update(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// DryRunRule evaluates a rule expression against the user's current data without creating nudges
+func (s *nudgeServiceImpl) DryRunRule(ctx context.Context, userID uuid.UUID, expression string) ([]*RuleMatch, error) {
+	parsed, err := nudgerules.Parse(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	people, err := s.personRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load people: %w", err)
+	}
+
+	categories, err := s.personRepo.GetCategoryNames(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	matches := make([]*RuleMatch, 0)
+	for _, person := range people {
+		interactions, err := s.personRepo.GetRecentInteractions(ctx, person.ID, systemNudgeWindow)
+		if err != nil {
+			return nil, err
+		}
+
+		facts := buildRuleFacts(person, interactions, categories, now)
+		if !parsed.Match(facts) {
+			continue
+		}
+
+		matches = append(matches, &RuleMatch{
+			PersonID:   person.ID,
+			PersonName: person.Name,
+			Action:     parsed.Action,
+			Priority:   parsed.Priority,
+			Facts:      facts,
+		})
+	}
+
+	return matches, nil
 }
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/vyve/vyve-backend/internal/models"
+	"github.com/vyve/vyve-backend/internal/repository"
+	"github.com/vyve/vyve-backend/pkg/analytics"
+)
+
+func TestGenerateSystemNudgesRuleOrder(t *testing.T) {
+	userID := uuid.New()
+	lastContact := time.Now().Add(-20 * 24 * time.Hour)
+
+	ownRule := &models.NudgeRule{Base: models.Base{ID: uuid.New()}, UserID: &userID, Name: "own", Expression: "days_since_last_interaction > 14 -> check_in", Enabled: true}
+	globalRule := &models.NudgeRule{Base: models.Base{ID: uuid.New()}, Name: "global", Expression: "days_since_last_interaction > 7 -> reach_out", Enabled: true}
+	unmetRule := &models.NudgeRule{Base: models.Base{ID: uuid.New()}, UserID: &userID, Name: "unmet", Expression: "days_since_last_interaction > 30 -> celebrate", Enabled: true}
+
+	// rules are as GetEnabled returns them, the user's own before the global ones
+	tests := []struct {
+		name        string
+		rules       []*models.NudgeRule
+		wantType    string
+		wantMessage string
+	}{
+		{"own rule before global", []*models.NudgeRule{ownRule, globalRule}, "check_in", "Your rule \"own\""},
+		{"global rule", []*models.NudgeRule{unmetRule, globalRule}, "reach_out", "Suggested because \"global\""},
+		{"no rule matches", []*models.NudgeRule{unmetRule}, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			person := &models.Person{
+				Base:              models.Base{ID: uuid.New()},
+				UserID:            userID,
+				Name:              "Sam",
+				ReminderFrequency: "monthly",
+				LastInteractionAt: &lastContact,
+			}
+
+			userRepo := repository.NewMockUserRepository(ctrl)
+			userRepo.EXPECT().FindByID(gomock.Any(), userID).Return(&models.User{Base: models.Base{ID: userID}}, nil)
+			personRepo := repository.NewMockPersonRepository(ctrl)
+			personRepo.EXPECT().FindByUserID(gomock.Any(), userID).Return([]*models.Person{person}, nil)
+			personRepo.EXPECT().GetCategoryNames(gomock.Any(), userID).Return(map[uuid.UUID]string{}, nil)
+			personRepo.EXPECT().GetRecentInteractions(gomock.Any(), person.ID, gomock.Any()).Return(nil, nil)
+			ruleRepo := repository.NewMockNudgeRuleRepository(ctrl)
+			ruleRepo.EXPECT().GetEnabled(gomock.Any(), userID).Return(tt.rules, nil)
+			ruleRepo.EXPECT().MarkFired(gomock.Any(), gomock.Any()).Return(nil).MaxTimes(1)
+			nudgeRepo := repository.NewMockNudgeRepository(ctrl)
+			nudgeRepo.EXPECT().HasPendingForPerson(gomock.Any(), person.ID, nudgeSourceSystem).Return(false, nil)
+			nudgeRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).MaxTimes(1)
+
+			svc := NewNudgeService(nudgeRepo, ruleRepo, personRepo, userRepo, nil, nil, analytics.NewDatabaseAnalytics())
+
+			nudges, err := svc.GenerateSystemNudges(context.Background(), userID)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantType == "" {
+				if len(nudges) != 0 {
+					t.Fatalf("expected no nudges, got %+v", nudges[0])
+				}
+				return
+			}
+			if len(nudges) != 1 {
+				t.Fatalf("expected 1 nudge, got %d", len(nudges))
+			}
+			if nudges[0].Type != tt.wantType || !strings.HasPrefix(nudges[0].Message, tt.wantMessage) {
+				t.Errorf("got %s %q, want %s %q", nudges[0].Type, nudges[0].Message, tt.wantType, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestNudgeRuleScopes(t *testing.T) {
+	userID := uuid.New()
+	ownRule := &models.NudgeRule{Base: models.Base{ID: uuid.New()}, UserID: &userID, Name: "own", Expression: "health_score > 80 -> celebrate"}
+	globalRule := &models.NudgeRule{Base: models.Base{ID: uuid.New()}, Name: "global", Expression: "health_score > 80 -> celebrate"}
+	ruleRepo := repository.NewMockNudgeRuleRepository(gomock.NewController(t))
+	ruleRepo.EXPECT().FindByID(gomock.Any(), ownRule.ID).Return(ownRule, nil).AnyTimes()
+	ruleRepo.EXPECT().FindByID(gomock.Any(), globalRule.ID).Return(globalRule, nil).AnyTimes()
+	svc := NewNudgeService(nil, ruleRepo, nil, nil, nil, nil, analytics.NewDatabaseAnalytics())
+	ctx := context.Background()
+
+	if _, err := svc.GetRule(ctx, userID, ownRule.ID); err != nil {
+		t.Errorf("GetRule(own) error = %v", err)
+	}
+	if _, err := svc.GetRule(ctx, userID, globalRule.ID); !repository.IsForbidden(err) {
+		t.Errorf("GetRule(global) error = %v, want forbidden", err)
+	}
+	if _, err := svc.GetGlobalRule(ctx, globalRule.ID); err != nil {
+		t.Errorf("GetGlobalRule(global) error = %v", err)
+	}
+	if _, err := svc.GetGlobalRule(ctx, ownRule.ID); !repository.IsNotFound(err) {
+		t.Errorf("GetGlobalRule(own) error = %v, want not found", err)
+	}
+}
+
+func TestGenerateSystemNudgesSkipsPending(t *testing.T) {
+	userID := uuid.New()
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Both are twice overdue, so each would get a nudge due today
+	lastContact := time.Now().Add(-15 * 24 * time.Hour)
+	nudged := &models.Person{Base: models.Base{ID: uuid.New()}, UserID: userID, Name: "Sam", ReminderFrequency: "weekly", LastInteractionAt: &lastContact}
+	fresh := &models.Person{Base: models.Base{ID: uuid.New()}, UserID: userID, Name: "Alex", ReminderFrequency: "weekly", LastInteractionAt: &lastContact}
+
+	ctrl := gomock.NewController(t)
+	userRepo := repository.NewMockUserRepository(ctrl)
+	userRepo.EXPECT().FindByID(gomock.Any(), userID).Return(&models.User{Base: models.Base{ID: userID}, Timezone: tokyo.String()}, nil)
+	personRepo := repository.NewMockPersonRepository(ctrl)
+	personRepo.EXPECT().FindByUserID(gomock.Any(), userID).Return([]*models.Person{nudged, fresh}, nil)
+	// The person with a pending nudge is skipped before their interactions are loaded
+	personRepo.EXPECT().GetRecentInteractions(gomock.Any(), fresh.ID, systemNudgeWindow).Return(nil, nil)
+	ruleRepo := repository.NewMockNudgeRuleRepository(ctrl)
+	ruleRepo.EXPECT().GetEnabled(gomock.Any(), userID).Return(nil, nil)
+	nudgeRepo := repository.NewMockNudgeRepository(ctrl)
+	nudgeRepo.EXPECT().HasPendingForPerson(gomock.Any(), nudged.ID, nudgeSourceSystem).Return(true, nil)
+	nudgeRepo.EXPECT().HasPendingForPerson(gomock.Any(), fresh.ID, nudgeSourceSystem).Return(false, nil)
+	nudgeRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+	svc := NewNudgeService(nudgeRepo, ruleRepo, personRepo, userRepo, nil, nil, analytics.NewDatabaseAnalytics())
+	before := time.Now()
+	nudges, err := svc.GenerateSystemNudges(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(nudges) != 1 || *nudges[0].PersonID != fresh.ID {
+		t.Fatalf("got %d nudges, want one for the person without a pending nudge", len(nudges))
+	}
+	nudge := nudges[0]
+	if nudge.Source != nudgeSourceSystem || nudge.Status != "pending" || nudge.Timing != "today" {
+		t.Errorf("nudge has source %q, status %q and timing %q", nudge.Source, nudge.Status, nudge.Timing)
+	}
+	// Today ends at midnight in the user's timezone, not the server's
+	y, m, d := before.In(tokyo).Date()
+	if want := time.Date(y, m, d+1, 0, 0, 0, 0, tokyo); nudge.ExpiresAt == nil || !nudge.ExpiresAt.Equal(want) {
+		t.Errorf("nudge expires at %v, want %v", nudge.ExpiresAt, want)
+	}
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vyve/vyve-backend/internal/models"
+	"github.com/vyve/vyve-backend/internal/repository"
+)
+
+// maxSnoozeDays bounds how far ahead a reminder can be snoozed
+const maxSnoozeDays = 90
+
+// SnoozeReminderRequest puts off a person's next reminder, either to a time or by a
+// number of days
+type SnoozeReminderRequest struct {
+	Until *time.Time `json:"until"`
+	Days  int        `json:"days"`
+}
+
+// SetReminder sets when a person's reminders recur and schedules the first one, which
+// may be today. Without a start date, the schedule starts today in the user's timezone.
+func (s *personService) SetReminder(ctx context.Context, userID, personID uuid.UUID, schedule models.ReminderSchedule) (*models.Person, error) {
+	person, err := s.GetByID(ctx, userID, personID)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := s.userLocation(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().In(loc)
+	if schedule.StartDate == "" {
+		schedule.StartDate = now.Format("2006-01-02")
+	}
+	if err := schedule.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", repository.ErrInvalidInput, err)
+	}
+
+	// The first reminder is the first on or after today
+	first, ok := schedule.Next(now.AddDate(0, 0, -1), loc)
+	if !ok {
+		return nil, fmt.Errorf("%w: the schedule has no upcoming reminders", repository.ErrInvalidInput)
+	}
+	first = first.UTC()
+
+	person.ReminderSchedule = &schedule
+	person.ReminderFrequency = schedule.Frequency()
+	person.NextReminderAt = &first
+	if err := s.personRepo.Update(ctx, person); err != nil {
+		return nil, err
+	}
+	return person, nil
+}
+
+// ClearReminder stops a person's reminders
+func (s *personService) ClearReminder(ctx context.Context, userID, personID uuid.UUID) (*models.Person, error) {
+	person, err := s.GetByID(ctx, userID, personID)
+	if err != nil {
+		return nil, err
+	}
+
+	person.ReminderSchedule = nil
+	person.NextReminderAt = nil
+	if err := s.personRepo.Update(ctx, person); err != nil {
+		return nil, err
+	}
+	return person, nil
+}
+
+// SnoozeReminder moves a person's next reminder later. Snoozing by days moves it to the
+// start of that day in the user's timezone. Reminders after it follow the schedule again.
+func (s *personService) SnoozeReminder(ctx context.Context, userID, personID uuid.UUID, req SnoozeReminderRequest) (*models.Person, error) {
+	person, err := s.GetByID(ctx, userID, personID)
+	if err != nil {
+		return nil, err
+	}
+	if person.NextReminderAt == nil {
+		return nil, fmt.Errorf("%w: no reminder is scheduled", repository.ErrInvalidInput)
+	}
+	loc, err := s.userLocation(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var until time.Time
+	switch {
+	case req.Until != nil && req.Days != 0:
+		return nil, fmt.Errorf("%w: give either until or days", repository.ErrInvalidInput)
+	case req.Until != nil:
+		until = req.Until.UTC()
+		if !until.After(now) {
+			return nil, fmt.Errorf("%w: until must be in the future", repository.ErrInvalidInput)
+		}
+	case req.Days >= 1 && req.Days <= maxSnoozeDays:
+		local := now.In(loc)
+		until = time.Date(local.Year(), local.Month(), local.Day()+req.Days, 0, 0, 0, 0, loc).UTC()
+	default:
+		return nil, fmt.Errorf("%w: days must be between 1 and %d", repository.ErrInvalidInput, maxSnoozeDays)
+	}
+	if until.After(now.AddDate(0, 0, maxSnoozeDays)) {
+		return nil, fmt.Errorf("%w: reminders can be snoozed for up to %d days", repository.ErrInvalidInput, maxSnoozeDays)
+	}
+
+	if err := s.personRepo.SetNextReminder(ctx, personID, &until); err != nil {
+		return nil, err
+	}
+	person.NextReminderAt = &until
+	return person, nil
+}
+
+// SkipNextReminder drops a person's next reminder, or the one due now, and schedules the
+// one after it. Reminders stop when the skipped one was the schedule's last.
+func (s *personService) SkipNextReminder(ctx context.Context, userID, personID uuid.UUID) (*models.Person, error) {
+	person, err := s.GetByID(ctx, userID, personID)
+	if err != nil {
+		return nil, err
+	}
+	if person.NextReminderAt == nil {
+		return nil, fmt.Errorf("%w: no reminder is scheduled", repository.ErrInvalidInput)
+	}
+	loc, err := s.userLocation(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A reminder that is overdue hasn't been sent yet, so it is the one skipped
+	skipped := *person.NextReminderAt
+	if now := time.Now(); skipped.Before(now) {
+		skipped = now
+	}
+	next := reminderAfter(person, loc, skipped)
+
+	if err := s.personRepo.SetNextReminder(ctx, personID, next); err != nil {
+		return nil, err
+	}
+	person.NextReminderAt = next
+	return person, nil
+}
+
+// userLocation loads the timezone reminders are scheduled in
+func (s *personService) userLocation(ctx context.Context, userID uuid.UUID) (*time.Location, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return userLocation(user.Timezone), nil
+}
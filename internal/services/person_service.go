@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/vyve/vyve-backend/internal/models"
@@ -32,18 +33,26 @@ type PersonService interface {
 
 	// People operations
 	CountPeople(ctx context.Context, userID uuid.UUID) (int64, error)
+
+	// Reminder schedules
+	SetReminder(ctx context.Context, userID, personID uuid.UUID, schedule models.ReminderSchedule) (*models.Person, error)
+	ClearReminder(ctx context.Context, userID, personID uuid.UUID) (*models.Person, error)
+	SnoozeReminder(ctx context.Context, userID, personID uuid.UUID, req SnoozeReminderRequest) (*models.Person, error)
+	SkipNextReminder(ctx context.Context, userID, personID uuid.UUID) (*models.Person, error)
 }
 
 type personService struct {
 	personRepo repository.PersonRepository
+	userRepo   repository.UserRepository
 	analytics  analytics.Analytics
 	storage    storage.Storage
 }
 
 // NewPersonService creates a new person service
-func NewPersonService(personRepo repository.PersonRepository, analyticsService analytics.Analytics, storageService storage.Storage) PersonService {
+func NewPersonService(personRepo repository.PersonRepository, userRepo repository.UserRepository, analyticsService analytics.Analytics, storageService storage.Storage) PersonService {
 	return &personService{
 		personRepo: personRepo,
+		userRepo:   userRepo,
 		analytics:  analyticsService,
 		storage:    storageService,
 	}
@@ -64,14 +73,16 @@ type CreatePersonRequest struct {
 
 // ListOptions represents listing options
 type ListOptions struct {
-	Page     int         `json:"page"`
-	Limit    int         `json:"limit"`
-	Category string      `json:"category"`
-	Search   string      `json:"search"`
-	OrderBy  string      `json:"order_by"`
-	Source   string      `json:"source"`    // For nudges: 'ai' or 'system'
-	Status   string      `json:"status"`    // For nudges: status filter
-	PersonID *uuid.UUID  `json:"person_id"` // For nudges: filter by person
+	Page      int        `json:"page"`
+	Limit     int        `json:"limit"`
+	Category  string     `json:"category"`
+	Search    string     `json:"search"`
+	OrderBy   string     `json:"order_by"`
+	Source    string     `json:"source"`     // For nudges: 'ai' or 'system'
+	Status    string     `json:"status"`     // For nudges: status filter
+	PersonID  *uuid.UUID `json:"person_id"`  // For nudges: filter by person
+	StartDate *time.Time `json:"start_date"` // For reflections: completed on or after
+	EndDate   *time.Time `json:"end_date"`   // For reflections: completed on or before
 }
 
 // Create creates a new person
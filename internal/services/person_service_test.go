@@ -19,15 +19,15 @@ func TestPersonService_CountPeople(t *testing.T) {
 
 	// Create a mock person repository
 	mockRepo := repository.NewMockPersonRepository(ctrl)
-	service := svc.NewPersonService(mockRepo)
+	service := svc.NewPersonService(mockRepo, nil, nil, nil)
 
 	// Test data
 	userID := uuid.New()
 	t.Run("Success", func(t *testing.T) {
 		// Mock data
 		people := []*models.Person{
-			{ID: uuid.New(), UserID: userID, Name: "Person 1"},
-			{ID: uuid.New(), UserID: userID, Name: "Person 2"},
+			{Base: models.Base{ID: uuid.New()}, UserID: userID, Name: "Person 1"},
+			{Base: models.Base{ID: uuid.New()}, UserID: userID, Name: "Person 2"},
 		}
 
 		// Setup expectations
@@ -0,0 +1,392 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vyve/vyve-backend/internal/models"
+	"github.com/vyve/vyve-backend/internal/repository"
+	"github.com/vyve/vyve-backend/pkg/analytics"
+)
+
+// ReflectionService handles reflection business logic
+type ReflectionService interface {
+	Create(ctx context.Context, userID uuid.UUID, req CreateReflectionRequest) (*models.Reflection, error)
+	GetByID(ctx context.Context, userID, reflectionID uuid.UUID) (*models.Reflection, error)
+	List(ctx context.Context, userID uuid.UUID, opts ListOptions) ([]*models.Reflection, *repository.PaginationResult, error)
+	Update(ctx context.Context, userID, reflectionID uuid.UUID, req UpdateReflectionRequest) (*models.Reflection, error)
+	Delete(ctx context.Context, userID, reflectionID uuid.UUID) error
+	GetToday(ctx context.Context, userID uuid.UUID) (*models.Reflection, error)
+
+	// Streaks and trends
+	GetStreak(ctx context.Context, userID uuid.UUID) (*StreakInfo, error)
+	GetMoodTrends(ctx context.Context, userID uuid.UUID, opts MoodTrendOptions) (*MoodTrends, error)
+}
+
+type reflectionService struct {
+	reflectionRepo repository.ReflectionRepository
+	userRepo       repository.UserRepository
+	analytics      analytics.Analytics
+}
+
+// NewReflectionService creates a new reflection service
+func NewReflectionService(
+	reflectionRepo repository.ReflectionRepository,
+	userRepo repository.UserRepository,
+	analytics analytics.Analytics,
+) ReflectionService {
+	return &reflectionService{
+		reflectionRepo: reflectionRepo,
+		userRepo:       userRepo,
+		analytics:      analytics,
+	}
+}
+
+// CreateReflectionRequest represents a request to create a reflection
+type CreateReflectionRequest struct {
+	Prompt      string     `json:"prompt"`
+	Responses   []string   `json:"responses"`
+	Mood        string     `json:"mood"`
+	EnergyLevel int        `json:"energy_level" validate:"min=1,max=10"`
+	Insights    []string   `json:"insights"`
+	Intentions  []string   `json:"intentions"`
+	Gratitude   []string   `json:"gratitude"`
+	CompletedAt *time.Time `json:"completed_at"`
+	// AllowMultiple allows another reflection on a local day that already has one
+	AllowMultiple bool `json:"allow_multiple"`
+}
+
+// UpdateReflectionRequest represents a partial update of a reflection.
+// Nil fields are left unchanged.
+type UpdateReflectionRequest struct {
+	Prompt      *string    `json:"prompt"`
+	Responses   []string   `json:"responses"`
+	Mood        *string    `json:"mood"`
+	EnergyLevel *int       `json:"energy_level"`
+	Insights    []string   `json:"insights"`
+	Intentions  []string   `json:"intentions"`
+	Gratitude   []string   `json:"gratitude"`
+	CompletedAt *time.Time `json:"completed_at"`
+	// AllowMultiple allows moving the reflection to a local day that already has one
+	AllowMultiple bool `json:"allow_multiple"`
+}
+
+// maxCompletedAtSkew tolerates client clocks running slightly ahead of the server
+const maxCompletedAtSkew = 5 * time.Minute
+
+func (s *reflectionService) Create(ctx context.Context, userID uuid.UUID, req CreateReflectionRequest) (*models.Reflection, error) {
+	now := time.Now()
+	completedAt := now
+	if req.CompletedAt != nil {
+		completedAt = *req.CompletedAt
+	}
+	if err := validateReflection(req.EnergyLevel, completedAt, now); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !req.AllowMultiple {
+		if err := s.ensureDayIsFree(ctx, userID, completedAt, userLocation(user.Timezone)); err != nil {
+			return nil, err
+		}
+	}
+
+	reflection := &models.Reflection{
+		UserID:      userID,
+		Prompt:      req.Prompt,
+		Responses:   req.Responses,
+		Mood:        req.Mood,
+		EnergyLevel: req.EnergyLevel,
+		Insights:    req.Insights,
+		Intentions:  req.Intentions,
+		Gratitude:   req.Gratitude,
+		CompletedAt: completedAt,
+	}
+
+	if err := s.reflectionRepo.Create(ctx, reflection); err != nil {
+		return nil, err
+	}
+
+	streak := 0
+	if info, err := s.refreshStreak(ctx, userID); err != nil {
+		log.Printf("[REFLECTION] Failed to update streak for user %s: %v", userID, err)
+	} else {
+		streak = info.Current
+	}
+
+	go analytics.TrackReflection(context.Background(), s.analytics, userID.String(), reflection.Mood, streak)
+
+	return reflection, nil
+}
+
+// GetByID returns a reflection owned by the user
+func (s *reflectionService) GetByID(ctx context.Context, userID, reflectionID uuid.UUID) (*models.Reflection, error) {
+	reflection, err := s.reflectionRepo.FindByID(ctx, reflectionID)
+	if err != nil {
+		return nil, err
+	}
+	if reflection.UserID != userID {
+		return nil, repository.ErrForbidden
+	}
+	return reflection, nil
+}
+
+// List returns the user's reflections, most recently completed first
+func (s *reflectionService) List(ctx context.Context, userID uuid.UUID, opts ListOptions) ([]*models.Reflection, *repository.PaginationResult, error) {
+	if opts.Limit > 100 {
+		opts.Limit = 100
+	}
+	if opts.StartDate != nil && opts.EndDate != nil && opts.EndDate.Before(*opts.StartDate) {
+		return nil, nil, fmt.Errorf("%w: end_date is before start_date", repository.ErrInvalidInput)
+	}
+
+	return s.reflectionRepo.List(ctx, repository.FilterOptions{
+		UserID:    userID,
+		StartDate: opts.StartDate,
+		EndDate:   opts.EndDate,
+		Page:      opts.Page,
+		Limit:     opts.Limit,
+	})
+}
+
+func (s *reflectionService) Update(ctx context.Context, userID, reflectionID uuid.UUID, req UpdateReflectionRequest) (*models.Reflection, error) {
+	reflection, err := s.GetByID(ctx, userID, reflectionID)
+	if err != nil {
+		return nil, err
+	}
+	previousCompletedAt := reflection.CompletedAt
+
+	if req.Prompt != nil {
+		reflection.Prompt = *req.Prompt
+	}
+	if req.Responses != nil {
+		reflection.Responses = req.Responses
+	}
+	if req.Mood != nil {
+		reflection.Mood = *req.Mood
+	}
+	if req.EnergyLevel != nil {
+		reflection.EnergyLevel = *req.EnergyLevel
+	}
+	if req.Insights != nil {
+		reflection.Insights = req.Insights
+	}
+	if req.Intentions != nil {
+		reflection.Intentions = req.Intentions
+	}
+	if req.Gratitude != nil {
+		reflection.Gratitude = req.Gratitude
+	}
+	if req.CompletedAt != nil {
+		reflection.CompletedAt = *req.CompletedAt
+	}
+
+	// Only the fields being changed are validated, so reflections saved before a rule
+	// existed stay editable
+	if req.EnergyLevel != nil {
+		if err := validateEnergyLevel(reflection.EnergyLevel); err != nil {
+			return nil, err
+		}
+	}
+	if req.CompletedAt != nil {
+		if err := validateCompletedAt(reflection.CompletedAt, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.CompletedAt != nil && !req.AllowMultiple {
+		user, err := s.userRepo.FindByID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		loc := userLocation(user.Timezone)
+		if civilDay(previousCompletedAt, loc) != civilDay(reflection.CompletedAt, loc) {
+			if err := s.ensureDayIsFree(ctx, userID, reflection.CompletedAt, loc); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := s.reflectionRepo.Update(ctx, reflection); err != nil {
+		return nil, err
+	}
+
+	// Moving a reflection to another day can extend or break the streak
+	if req.CompletedAt != nil {
+		if _, err := s.refreshStreak(ctx, userID); err != nil {
+			log.Printf("[REFLECTION] Failed to update streak for user %s: %v", userID, err)
+		}
+	}
+
+	return reflection, nil
+}
+
+func (s *reflectionService) Delete(ctx context.Context, userID, reflectionID uuid.UUID) error {
+	if _, err := s.GetByID(ctx, userID, reflectionID); err != nil {
+		return err
+	}
+
+	if err := s.reflectionRepo.Delete(ctx, reflectionID); err != nil {
+		return err
+	}
+
+	if _, err := s.refreshStreak(ctx, userID); err != nil {
+		log.Printf("[REFLECTION] Failed to update streak for user %s: %v", userID, err)
+	}
+
+	return nil
+}
+
+// GetToday returns the reflection of the user's current local day
+func (s *reflectionService) GetToday(ctx context.Context, userID uuid.UUID) (*models.Reflection, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	loc := userLocation(user.Timezone)
+	now := time.Now()
+
+	return s.reflectionRepo.FindForDay(ctx, userID, endOfLocalDay(now, loc, -1), endOfLocalDay(now, loc, 0))
+}
+
+// GetStreak returns the user's current streak. The streak is recomputed so that it
+// reads 0 once the user has missed more days than the grace period allows, even if
+// no reflection was written since.
+func (s *reflectionService) GetStreak(ctx context.Context, userID uuid.UUID) (*StreakInfo, error) {
+	return s.refreshStreak(ctx, userID)
+}
+
+// GetMoodTrends returns mood and energy averages bucketed by day, week or month in
+// the user's timezone
+func (s *reflectionService) GetMoodTrends(ctx context.Context, userID uuid.UUID, opts MoodTrendOptions) (*MoodTrends, error) {
+	opts, err := opts.normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	loc := userLocation(user.Timezone)
+
+	now := time.Now()
+	buckets := trendBuckets(opts.Period, opts.Days, now, loc)
+	start := buckets[0].Start
+	end := buckets[len(buckets)-1].End
+
+	reflections, err := s.reflectionRepo.ListBetween(ctx, userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	trends := buildMoodTrends(buckets, reflections, opts.Window)
+	trends.Period = opts.Period
+	trends.Timezone = loc.String()
+	return trends, nil
+}
+
+// refreshStreak recomputes the streak from the user's reflection history and stores it
+// on the user when it changed
+func (s *reflectionService) refreshStreak(ctx context.Context, userID uuid.UUID) (*StreakInfo, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	loc := userLocation(user.Timezone)
+	graceDays := streakGraceDays(user)
+
+	// The longest streak is kept on the user, so only the reflections that can still be
+	// part of the current streak are read. A user who reflected before the longest streak
+	// was recorded has their whole history read once to seed it.
+	var since time.Time
+	if user.LongestStreak > 0 || user.LastReflectionAt == nil {
+		since = endOfLocalDay(now, loc, -streakWindowDays)
+	}
+	var info *StreakInfo
+	for window := streakWindowDays; ; window *= 2 {
+		times, err := s.reflectionRepo.GetCompletionTimes(ctx, userID, since)
+		if err != nil {
+			return nil, err
+		}
+		info = computeStreak(times, now, loc, graceDays)
+
+		// A streak reaching back to the start of the window may go on before it
+		if since.IsZero() || info.Current == 0 || dayGap(civilDay(since, loc), info.runStart) > graceDays {
+			break
+		}
+		since = endOfLocalDay(now, loc, -2*window)
+	}
+	info.Longest = max(info.Longest, user.LongestStreak)
+
+	if info.Current != user.StreakCount || info.Longest != user.LongestStreak || !sameTime(info.LastReflectionAt, user.LastReflectionAt) {
+		if err := s.userRepo.SetStreak(ctx, userID, info.Current, info.Longest, info.LastReflectionAt); err != nil {
+			return nil, err
+		}
+
+		if info.Current != user.StreakCount {
+			go s.analytics.Track(context.Background(), analytics.Event{
+				UserID:    userID.String(),
+				EventType: analytics.EventStreakUpdated,
+				Properties: map[string]interface{}{
+					"old_streak": user.StreakCount,
+					"new_streak": info.Current,
+					"grace_days": info.GraceDays,
+				},
+				Timestamp: time.Now(),
+			})
+		}
+	}
+
+	return info, nil
+}
+
+// ensureDayIsFree returns ErrReflectionAlreadyExists when the user already reflected on
+// the local day of t
+func (s *reflectionService) ensureDayIsFree(ctx context.Context, userID uuid.UUID, t time.Time, loc *time.Location) error {
+	_, err := s.reflectionRepo.FindForDay(ctx, userID, endOfLocalDay(t, loc, -1), endOfLocalDay(t, loc, 0))
+	if err == nil {
+		return repository.ErrReflectionAlreadyExists
+	}
+	if repository.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// validateReflection checks the fields of a new reflection
+func validateReflection(energyLevel int, completedAt, now time.Time) error {
+	if err := validateEnergyLevel(energyLevel); err != nil {
+		return err
+	}
+	return validateCompletedAt(completedAt, now)
+}
+
+func validateEnergyLevel(energyLevel int) error {
+	if energyLevel < 1 || energyLevel > 10 {
+		return fmt.Errorf("%w: energy_level must be between 1 and 10", repository.ErrInvalidInput)
+	}
+	return nil
+}
+
+func validateCompletedAt(completedAt, now time.Time) error {
+	if completedAt.After(now.Add(maxCompletedAtSkew)) {
+		return fmt.Errorf("%w: completed_at cannot be in the future", repository.ErrInvalidInput)
+	}
+	return nil
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/vyve/vyve-backend/internal/models"
+	"github.com/vyve/vyve-backend/internal/repository"
+	"github.com/vyve/vyve-backend/pkg/analytics"
+)
+
+func TestValidateReflection(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name        string
+		energyLevel int
+		completedAt time.Time
+		wantErr     bool
+	}{
+		{"energy 0", 0, now, true},
+		{"energy 1", 1, now, false},
+		{"energy 10", 10, now, false},
+		{"energy 11", 11, now, true},
+		{"within clock skew", 5, now.Add(time.Minute), false},
+		{"in the future", 5, now.Add(time.Hour), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateReflection(tt.energyLevel, tt.completedAt, now)
+			if tt.wantErr && !errors.Is(err, repository.ErrInvalidInput) {
+				t.Errorf("validateReflection() error = %v, want ErrInvalidInput", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("validateReflection() unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestUpdateValidatesChangedFields(t *testing.T) {
+	mood, noEnergy, energy := "calm", 0, 7
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		req     UpdateReflectionRequest
+		wantErr bool
+	}{
+		{name: "mood on a reflection saved without energy", req: UpdateReflectionRequest{Mood: &mood}},
+		{name: "energy on a reflection saved without energy", req: UpdateReflectionRequest{EnergyLevel: &energy}},
+		{name: "energy 0", req: UpdateReflectionRequest{EnergyLevel: &noEnergy}, wantErr: true},
+		{name: "completed in the future", req: UpdateReflectionRequest{CompletedAt: &future}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			reflectionRepo := repository.NewMockReflectionRepository(ctrl)
+			svc := NewReflectionService(reflectionRepo, repository.NewMockUserRepository(ctrl), analytics.NewDatabaseAnalytics())
+
+			// Saved before energy_level was required
+			legacy := &models.Reflection{
+				Base:        models.Base{ID: uuid.New()},
+				UserID:      uuid.New(),
+				Mood:        "okay",
+				CompletedAt: time.Now().Add(-48 * time.Hour),
+			}
+			reflectionRepo.EXPECT().FindByID(gomock.Any(), legacy.ID).Return(legacy, nil)
+			if !tt.wantErr {
+				reflectionRepo.EXPECT().Update(gomock.Any(), legacy).Return(nil)
+			}
+
+			_, err := svc.Update(context.Background(), legacy.UserID, legacy.ID, tt.req)
+			if tt.wantErr && !errors.Is(err, repository.ErrInvalidInput) {
+				t.Errorf("Update() error = %v, want ErrInvalidInput", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Update() unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestRefreshStreakWindow(t *testing.T) {
+	now := time.Now()
+	// daily returns one reflection a day for the given number of days, ending daysAgo
+	daily := func(days, daysAgo int) []time.Time {
+		times := make([]time.Time, days)
+		for i := range times {
+			times[i] = now.AddDate(0, 0, -daysAgo-i)
+		}
+		return times
+	}
+	lastReflection := now.AddDate(0, 0, -1)
+
+	tests := []struct {
+		name        string
+		user        models.User
+		history     []time.Time
+		wantQueries int
+		wantSeed    bool
+		wantCurrent int
+		wantLongest int
+	}{
+		{
+			name:        "short streak reads one window",
+			user:        models.User{StreakCount: 3, LongestStreak: 10, LastReflectionAt: &lastReflection},
+			history:     append(daily(3, 0), daily(8, 100)...),
+			wantQueries: 1,
+			wantCurrent: 3,
+			wantLongest: 10,
+		},
+		{
+			name:        "streak reaching the window start reads further back",
+			user:        models.User{StreakCount: 99, LongestStreak: 99, LastReflectionAt: &lastReflection},
+			history:     daily(100, 0),
+			wantQueries: 2,
+			wantCurrent: 100,
+			wantLongest: 100,
+		},
+		{
+			name:        "longest streak seeded from the whole history",
+			user:        models.User{StreakCount: 1, LastReflectionAt: &lastReflection},
+			history:     append(daily(2, 0), daily(5, 200)...),
+			wantQueries: 1,
+			wantSeed:    true,
+			wantCurrent: 2,
+			wantLongest: 5,
+		},
+		{
+			name:        "no reflections yet",
+			wantQueries: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			reflectionRepo := repository.NewMockReflectionRepository(ctrl)
+			userRepo := repository.NewMockUserRepository(ctrl)
+			svc := &reflectionService{reflectionRepo: reflectionRepo, userRepo: userRepo, analytics: analytics.NewDatabaseAnalytics()}
+
+			user := tt.user
+			user.ID = uuid.New()
+			user.Timezone = "UTC"
+			userRepo.EXPECT().FindByID(gomock.Any(), user.ID).Return(&user, nil)
+
+			var queries []time.Time
+			reflectionRepo.EXPECT().
+				GetCompletionTimes(gomock.Any(), user.ID, gomock.Any()).
+				DoAndReturn(func(ctx context.Context, userID uuid.UUID, since time.Time) ([]time.Time, error) {
+					queries = append(queries, since)
+					var times []time.Time
+					for _, at := range tt.history {
+						if !at.Before(since) {
+							times = append(times, at)
+						}
+					}
+					return times, nil
+				}).
+				AnyTimes()
+			if len(tt.history) > 0 {
+				userRepo.EXPECT().SetStreak(gomock.Any(), user.ID, tt.wantCurrent, tt.wantLongest, gomock.Any()).Return(nil)
+			}
+
+			info, err := svc.refreshStreak(context.Background(), user.ID)
+			if err != nil {
+				t.Fatalf("refreshStreak() error = %v", err)
+			}
+			if info.Current != tt.wantCurrent || info.Longest != tt.wantLongest {
+				t.Errorf("streak %d, longest %d; want %d, %d", info.Current, info.Longest, tt.wantCurrent, tt.wantLongest)
+			}
+			if len(queries) != tt.wantQueries {
+				t.Fatalf("read completion times %d times, want %d", len(queries), tt.wantQueries)
+			}
+			if queries[0].IsZero() != tt.wantSeed {
+				t.Errorf("first read since %v, want the whole history %v", queries[0], tt.wantSeed)
+			}
+			for i := 1; i < len(queries); i++ {
+				if !queries[i].Before(queries[i-1]) {
+					t.Errorf("read %d since %v does not reach further back than %v", i, queries[i], queries[i-1])
+				}
+			}
+		})
+	}
+}
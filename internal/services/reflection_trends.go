@@ -0,0 +1,361 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vyve/vyve-backend/internal/models"
+	"github.com/vyve/vyve-backend/internal/repository"
+)
+
+const (
+	// streakGraceDaysSetting is the User.Settings key holding the number of days a
+	// user may skip without losing their streak
+	streakGraceDaysSetting = "streak_grace_days"
+	maxStreakGraceDays     = 3
+	// streakWindowDays is how many local days of reflections are read to compute the
+	// current streak, before reading further back while the streak reaches that far
+	streakWindowDays = 60
+
+	maxTrendDays = 730
+)
+
+// Trend periods
+const (
+	TrendPeriodDaily   = "daily"
+	TrendPeriodWeekly  = "weekly"
+	TrendPeriodMonthly = "monthly"
+)
+
+// trendDefaults holds the default look-back (days) and moving average window (buckets)
+// for each period
+var trendDefaults = map[string]struct{ days, window int }{
+	TrendPeriodDaily:   {30, 7},
+	TrendPeriodWeekly:  {84, 4},
+	TrendPeriodMonthly: {365, 3},
+}
+
+// moodScores maps the moods offered by the app to a 1-5 scale
+var moodScores = map[string]float64{
+	"awful":     1,
+	"terrible":  1,
+	"bad":       2,
+	"sad":       2,
+	"low":       2,
+	"anxious":   2,
+	"stressed":  2,
+	"tired":     2,
+	"okay":      3,
+	"ok":        3,
+	"neutral":   3,
+	"meh":       3,
+	"good":      4,
+	"calm":      4,
+	"content":   4,
+	"grateful":  4,
+	"happy":     4,
+	"great":     5,
+	"amazing":   5,
+	"excellent": 5,
+	"energized": 5,
+	"joyful":    5,
+}
+
+// StreakInfo describes a user's reflection streak
+type StreakInfo struct {
+	Current          int        `json:"streak_count"`
+	Longest          int        `json:"longest_streak"`
+	ReflectedToday   bool       `json:"reflected_today"`
+	LastReflectionAt *time.Time `json:"last_reflection_at"`
+	GraceDays        int        `json:"grace_days"`
+	// ExpiresAt is when the streak resets unless the user reflects again
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	// runStart is the first day of the run ending at the latest reflection, as a civil day
+	runStart time.Time
+}
+
+// MoodTrendOptions selects the trend series to compute
+type MoodTrendOptions struct {
+	Period string // daily, weekly or monthly
+	Days   int    // how far back to look
+	Window int    // moving average window, in buckets
+}
+
+// MoodTrendBucket holds the aggregated mood and energy of one day, week or month
+type MoodTrendBucket struct {
+	Start               time.Time      `json:"start"`
+	End                 time.Time      `json:"end"`
+	Count               int            `json:"count"`
+	AverageMood         *float64       `json:"average_mood"`
+	AverageEnergy       *float64       `json:"average_energy"`
+	MoodMovingAverage   *float64       `json:"mood_moving_average"`
+	EnergyMovingAverage *float64       `json:"energy_moving_average"`
+	Moods               map[string]int `json:"moods,omitempty"`
+
+	moodSum, energySum     float64
+	moodCount, energyCount int
+}
+
+// MoodTrends is a mood and energy time series
+type MoodTrends struct {
+	Period          string            `json:"period"`
+	Window          int               `json:"window"`
+	Timezone        string            `json:"timezone"`
+	Trends          []MoodTrendBucket `json:"trends"`
+	ReflectionCount int               `json:"reflection_count"`
+	AverageMood     *float64          `json:"average_mood"`
+	AverageEnergy   *float64          `json:"average_energy"`
+	DominantMood    string            `json:"dominant_mood,omitempty"`
+}
+
+// normalize validates the options and fills in defaults
+func (o MoodTrendOptions) normalize() (MoodTrendOptions, error) {
+	if o.Period == "" {
+		o.Period = TrendPeriodDaily
+	}
+	defaults, ok := trendDefaults[o.Period]
+	if !ok {
+		return o, fmt.Errorf("%w: period must be daily, weekly or monthly", repository.ErrInvalidInput)
+	}
+	if o.Days <= 0 {
+		o.Days = defaults.days
+	}
+	if o.Days > maxTrendDays {
+		o.Days = maxTrendDays
+	}
+	if o.Window <= 0 {
+		o.Window = defaults.window
+	}
+	return o, nil
+}
+
+// computeStreak derives the streak from reflection completion times. Each local
+// calendar day with at least one reflection counts once; up to graceDays missed days
+// between two reflection days do not break the streak. Longest only covers the times
+// given.
+func computeStreak(times []time.Time, now time.Time, loc *time.Location, graceDays int) *StreakInfo {
+	info := &StreakInfo{GraceDays: graceDays}
+	if len(times) == 0 {
+		return info
+	}
+
+	latest := times[0]
+	days := make([]time.Time, 0, len(times))
+	seen := make(map[time.Time]bool, len(times))
+	for _, t := range times {
+		if t.After(latest) {
+			latest = t
+		}
+		day := civilDay(t, loc)
+		if !seen[day] {
+			seen[day] = true
+			days = append(days, day)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].After(days[j]) })
+	info.LastReflectionAt = &latest
+
+	// Longest run over the given history, and the run ending at the latest day
+	run, latestRun := 1, 0
+	info.Longest = 1
+	for i := 1; i < len(days); i++ {
+		if dayGap(days[i], days[i-1])-1 <= graceDays {
+			run++
+		} else {
+			if latestRun == 0 {
+				latestRun = run
+				info.runStart = days[i-1]
+			}
+			run = 1
+		}
+		if run > info.Longest {
+			info.Longest = run
+		}
+	}
+	if latestRun == 0 {
+		latestRun = run
+		info.runStart = days[len(days)-1]
+	}
+
+	today := civilDay(now, loc)
+	missed := dayGap(days[0], today) - 1
+	info.ReflectedToday = missed < 0
+	if missed <= graceDays {
+		info.Current = latestRun
+		expiresAt := endOfLocalDay(latest, loc, 1+graceDays)
+		info.ExpiresAt = &expiresAt
+	}
+
+	return info
+}
+
+// streakGraceDays reads the user's grace days setting
+func streakGraceDays(user *models.User) int {
+	var days int
+	switch v := user.Settings[streakGraceDaysSetting].(type) {
+	case float64:
+		days = int(v)
+	case int:
+		days = v
+	case string:
+		days, _ = strconv.Atoi(v)
+	}
+	if days < 0 {
+		return 0
+	}
+	if days > maxStreakGraceDays {
+		return maxStreakGraceDays
+	}
+	return days
+}
+
+// civilDay returns the local calendar date of t as midnight UTC, so that day
+// arithmetic is unaffected by DST transitions
+func civilDay(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// dayGap returns the number of calendar days from a to b
+func dayGap(a, b time.Time) int {
+	return int(math.Round(b.Sub(a).Hours() / 24))
+}
+
+// trendBuckets returns consecutive buckets covering the last days up to and including
+// the current period. Bucket boundaries are local midnights.
+func trendBuckets(period string, days int, now time.Time, loc *time.Location) []MoodTrendBucket {
+	local := now.In(loc)
+	y, m, d := local.AddDate(0, 0, -(days - 1)).Date()
+
+	var start time.Time
+	switch period {
+	case TrendPeriodWeekly:
+		// Weeks start on Monday
+		first := time.Date(y, m, d, 0, 0, 0, 0, loc)
+		offset := (int(first.Weekday()) + 6) % 7
+		start = time.Date(y, m, d-offset, 0, 0, 0, 0, loc)
+	case TrendPeriodMonthly:
+		start = time.Date(y, m, 1, 0, 0, 0, 0, loc)
+	default:
+		start = time.Date(y, m, d, 0, 0, 0, 0, loc)
+	}
+
+	var buckets []MoodTrendBucket
+	for !start.After(local) {
+		sy, sm, sd := start.Date()
+		var end time.Time
+		switch period {
+		case TrendPeriodWeekly:
+			end = time.Date(sy, sm, sd+7, 0, 0, 0, 0, loc)
+		case TrendPeriodMonthly:
+			end = time.Date(sy, sm+1, 1, 0, 0, 0, 0, loc)
+		default:
+			end = time.Date(sy, sm, sd+1, 0, 0, 0, 0, loc)
+		}
+		buckets = append(buckets, MoodTrendBucket{Start: start, End: end})
+		start = end
+	}
+
+	return buckets
+}
+
+// buildMoodTrends aggregates reflections (ordered oldest first) into the buckets and
+// computes count-weighted moving averages over the last window buckets
+func buildMoodTrends(buckets []MoodTrendBucket, reflections []*models.Reflection, window int) *MoodTrends {
+	trends := &MoodTrends{Window: window}
+
+	var moodSum, energySum float64
+	var moodCount, energyCount int
+	moods := make(map[string]int)
+
+	i := 0
+	for _, r := range reflections {
+		for i < len(buckets) && !r.CompletedAt.Before(buckets[i].End) {
+			i++
+		}
+		if i == len(buckets) {
+			break
+		}
+		if r.CompletedAt.Before(buckets[i].Start) {
+			continue
+		}
+
+		b := &buckets[i]
+		b.Count++
+		trends.ReflectionCount++
+
+		if mood := strings.ToLower(strings.TrimSpace(r.Mood)); mood != "" {
+			if b.Moods == nil {
+				b.Moods = make(map[string]int)
+			}
+			b.Moods[mood]++
+			moods[mood]++
+		}
+		if score, ok := moodScore(r.Mood); ok {
+			b.moodSum += score
+			b.moodCount++
+			moodSum += score
+			moodCount++
+		}
+		if r.EnergyLevel > 0 {
+			b.energySum += float64(r.EnergyLevel)
+			b.energyCount++
+			energySum += float64(r.EnergyLevel)
+			energyCount++
+		}
+	}
+
+	for i := range buckets {
+		b := &buckets[i]
+		b.AverageMood = average(b.moodSum, b.moodCount)
+		b.AverageEnergy = average(b.energySum, b.energyCount)
+
+		var wMoodSum, wEnergySum float64
+		var wMoodCount, wEnergyCount int
+		for j := i; j >= 0 && j > i-window; j-- {
+			wMoodSum += buckets[j].moodSum
+			wMoodCount += buckets[j].moodCount
+			wEnergySum += buckets[j].energySum
+			wEnergyCount += buckets[j].energyCount
+		}
+		b.MoodMovingAverage = average(wMoodSum, wMoodCount)
+		b.EnergyMovingAverage = average(wEnergySum, wEnergyCount)
+	}
+
+	trends.Trends = buckets
+	trends.AverageMood = average(moodSum, moodCount)
+	trends.AverageEnergy = average(energySum, energyCount)
+	for mood, count := range moods {
+		if count > moods[trends.DominantMood] || (count == moods[trends.DominantMood] && mood < trends.DominantMood) {
+			trends.DominantMood = mood
+		}
+	}
+
+	return trends
+}
+
+// moodScore converts a mood to the 1-5 scale. Numeric moods are accepted as is.
+func moodScore(mood string) (float64, bool) {
+	mood = strings.ToLower(strings.TrimSpace(mood))
+	if score, ok := moodScores[mood]; ok {
+		return score, true
+	}
+	if score, err := strconv.ParseFloat(mood, 64); err == nil && score >= 1 && score <= 5 {
+		return score, true
+	}
+	return 0, false
+}
+
+// average returns sum/count rounded to two decimals, or nil when there is no data
+func average(sum float64, count int) *float64 {
+	if count == 0 {
+		return nil
+	}
+	avg := math.Round(sum/float64(count)*100) / 100
+	return &avg
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vyve/vyve-backend/internal/models"
+)
+
+func TestComputeStreak(t *testing.T) {
+	la, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// at returns a local time in Los Angeles on a day of October 2026
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 10, day, hour, minute, 0, 0, la)
+	}
+	now := at(14, 10, 0)
+
+	tests := []struct {
+		name        string
+		times       []time.Time
+		loc         *time.Location
+		graceDays   int
+		wantCurrent int
+		wantLongest int
+		wantToday   bool
+	}{
+		{name: "no reflections", loc: la},
+		{name: "reflected today", times: []time.Time{at(14, 8, 0)}, loc: la, wantCurrent: 1, wantLongest: 1, wantToday: true},
+		{name: "run ending yesterday", times: []time.Time{at(13, 20, 0), at(12, 20, 0), at(11, 20, 0)}, loc: la, wantCurrent: 3, wantLongest: 3},
+		{name: "two reflections on one day count once", times: []time.Time{at(14, 9, 0), at(14, 7, 0)}, loc: la, wantCurrent: 1, wantLongest: 1, wantToday: true},
+		// 23:30 and 00:30 in Los Angeles are 06:30 and 07:30 on the same UTC day
+		{name: "either side of local midnight", times: []time.Time{at(14, 0, 30), at(13, 23, 30)}, loc: la, wantCurrent: 2, wantLongest: 2, wantToday: true},
+		{name: "either side of local midnight in UTC", times: []time.Time{at(14, 0, 30), at(13, 23, 30)}, loc: time.UTC, wantCurrent: 1, wantLongest: 1, wantToday: true},
+		{name: "skipped day breaks the run", times: []time.Time{at(14, 8, 0), at(12, 8, 0)}, loc: la, wantCurrent: 1, wantLongest: 1, wantToday: true},
+		{name: "skipped day within grace", times: []time.Time{at(14, 8, 0), at(12, 8, 0)}, loc: la, graceDays: 1, wantCurrent: 2, wantLongest: 2, wantToday: true},
+		{name: "missed more days than the grace", times: []time.Time{at(11, 8, 0), at(10, 8, 0)}, loc: la, graceDays: 1, wantLongest: 2},
+		{name: "missed days within the grace", times: []time.Time{at(11, 8, 0), at(10, 8, 0)}, loc: la, graceDays: 2, wantCurrent: 2, wantLongest: 2},
+		{
+			name:        "longer run before a gap",
+			times:       []time.Time{at(14, 8, 0), at(13, 8, 0), at(9, 8, 0), at(8, 8, 0), at(7, 8, 0), at(6, 8, 0)},
+			loc:         la,
+			wantCurrent: 2,
+			wantLongest: 4,
+			wantToday:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := computeStreak(tt.times, now, tt.loc, tt.graceDays)
+
+			if info.Current != tt.wantCurrent || info.Longest != tt.wantLongest || info.ReflectedToday != tt.wantToday {
+				t.Errorf("streak %d, longest %d, reflected today %v; want %d, %d, %v",
+					info.Current, info.Longest, info.ReflectedToday, tt.wantCurrent, tt.wantLongest, tt.wantToday)
+			}
+			if len(tt.times) == 0 {
+				if info.LastReflectionAt != nil || info.ExpiresAt != nil {
+					t.Errorf("last reflection %v and expiry %v without reflections", info.LastReflectionAt, info.ExpiresAt)
+				}
+				return
+			}
+			if !info.LastReflectionAt.Equal(tt.times[0]) {
+				t.Errorf("last reflection at %v, want %v", info.LastReflectionAt, tt.times[0])
+			}
+			// A live streak lasts until the end of the day after the grace days
+			if tt.wantCurrent > 0 {
+				if want := endOfLocalDay(tt.times[0], tt.loc, 1+tt.graceDays); info.ExpiresAt == nil || !info.ExpiresAt.Equal(want) {
+					t.Errorf("streak expires at %v, want %v", info.ExpiresAt, want)
+				}
+			} else if info.ExpiresAt != nil {
+				t.Errorf("broken streak expires at %v", info.ExpiresAt)
+			}
+		})
+	}
+}
+
+func TestTrendBuckets(t *testing.T) {
+	la, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := func(month time.Month, day int) time.Time {
+		return time.Date(2026, month, day, 0, 0, 0, 0, la)
+	}
+
+	tests := []struct {
+		name       string
+		period     string
+		days       int
+		now        time.Time
+		wantStarts []time.Time
+		wantEnd    time.Time
+	}{
+		{
+			name:       "daily",
+			period:     TrendPeriodDaily,
+			days:       3,
+			now:        time.Date(2026, 10, 14, 10, 0, 0, 0, la),
+			wantStarts: []time.Time{day(10, 12), day(10, 13), day(10, 14)},
+			wantEnd:    day(10, 15),
+		},
+		{
+			// 02:00 UTC is still the previous evening in Los Angeles
+			name:       "daily by the local date",
+			period:     TrendPeriodDaily,
+			days:       2,
+			now:        time.Date(2026, 10, 15, 2, 0, 0, 0, time.UTC),
+			wantStarts: []time.Time{day(10, 13), day(10, 14)},
+			wantEnd:    day(10, 15),
+		},
+		{
+			name:       "weekly from Monday",
+			period:     TrendPeriodWeekly,
+			days:       14,
+			now:        time.Date(2026, 10, 14, 10, 0, 0, 0, la),
+			wantStarts: []time.Time{day(9, 28), day(10, 5), day(10, 12)},
+			wantEnd:    day(10, 19),
+		},
+		{
+			name:       "monthly from the first",
+			period:     TrendPeriodMonthly,
+			days:       40,
+			now:        time.Date(2026, 10, 14, 10, 0, 0, 0, la),
+			wantStarts: []time.Time{day(9, 1), day(10, 1)},
+			wantEnd:    day(11, 1),
+		},
+		{
+			name:       "daily across the end of daylight saving time",
+			period:     TrendPeriodDaily,
+			days:       3,
+			now:        time.Date(2026, 11, 2, 10, 0, 0, 0, la),
+			wantStarts: []time.Time{day(10, 31), day(11, 1), day(11, 2)},
+			wantEnd:    day(11, 3),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buckets := trendBuckets(tt.period, tt.days, tt.now, la)
+			if len(buckets) != len(tt.wantStarts) {
+				t.Fatalf("got %d buckets, want %d", len(buckets), len(tt.wantStarts))
+			}
+			for i, bucket := range buckets {
+				if !bucket.Start.Equal(tt.wantStarts[i]) {
+					t.Errorf("bucket %d starts at %v, want %v", i, bucket.Start, tt.wantStarts[i])
+				}
+				if i > 0 && !buckets[i-1].End.Equal(bucket.Start) {
+					t.Errorf("bucket %d starts at %v, not where the previous one ended", i, bucket.Start)
+				}
+			}
+			if last := buckets[len(buckets)-1]; !last.End.Equal(tt.wantEnd) {
+				t.Errorf("last bucket ends at %v, want %v", last.End, tt.wantEnd)
+			}
+		})
+	}
+
+	// Buckets end at local midnight, so the day the clocks go back is 25 hours long
+	buckets := trendBuckets(TrendPeriodDaily, 3, time.Date(2026, 11, 2, 10, 0, 0, 0, la), la)
+	if length := buckets[1].End.Sub(buckets[1].Start); length != 25*time.Hour {
+		t.Errorf("November 1 bucket is %s long, want 25h", length)
+	}
+}
+
+func TestBuildMoodTrends(t *testing.T) {
+	la, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(day, hour int) time.Time {
+		return time.Date(2026, 10, day, hour, 0, 0, 0, la)
+	}
+	reflection := func(completedAt time.Time, mood string, energy int) *models.Reflection {
+		return &models.Reflection{CompletedAt: completedAt, Mood: mood, EnergyLevel: energy}
+	}
+
+	// Daily buckets for October 12-14, oldest reflection first
+	buckets := trendBuckets(TrendPeriodDaily, 3, at(14, 10), la)
+	reflections := []*models.Reflection{
+		reflection(at(11, 20), "awful", 1), // before the first bucket
+		reflection(at(12, 9), "good", 6),
+		reflection(at(12, 21), "great", 0), // saved before energy was required
+		reflection(at(13, 12), "3", 0),
+		reflection(at(14, 8), "sad", 4),
+		reflection(at(14, 9), " Sad", 8),
+		reflection(at(15, 1), "great", 9), // after the last bucket
+	}
+
+	trends := buildMoodTrends(buckets, reflections, 2)
+
+	tests := []struct {
+		count                  int
+		mood, energy           *float64
+		moodAverage, energyAvg *float64
+	}{
+		{count: 2, mood: ptr(4.5), energy: ptr(6.0), moodAverage: ptr(4.5), energyAvg: ptr(6.0)},
+		// A bucket without energy readings takes the moving average from its neighbour
+		{count: 1, mood: ptr(3.0), energy: nil, moodAverage: ptr(4.0), energyAvg: ptr(6.0)},
+		// The window drops the first day; averages are weighted by reflection count
+		{count: 2, mood: ptr(2.0), energy: ptr(6.0), moodAverage: ptr(2.33), energyAvg: ptr(6.0)},
+	}
+	if len(trends.Trends) != len(tests) {
+		t.Fatalf("got %d buckets, want %d", len(trends.Trends), len(tests))
+	}
+	for i, want := range tests {
+		got := trends.Trends[i]
+		if got.Count != want.count {
+			t.Errorf("bucket %d has %d reflections, want %d", i, got.Count, want.count)
+		}
+		for _, field := range []struct {
+			name      string
+			got, want *float64
+		}{
+			{"average mood", got.AverageMood, want.mood},
+			{"average energy", got.AverageEnergy, want.energy},
+			{"mood moving average", got.MoodMovingAverage, want.moodAverage},
+			{"energy moving average", got.EnergyMovingAverage, want.energyAvg},
+		} {
+			if !sameFloat(field.got, field.want) {
+				t.Errorf("bucket %d %s = %v, want %v", i, field.name, deref(field.got), deref(field.want))
+			}
+		}
+	}
+
+	if trends.ReflectionCount != 5 {
+		t.Errorf("reflection count = %d, want 5", trends.ReflectionCount)
+	}
+	if !sameFloat(trends.AverageMood, ptr(3.2)) || !sameFloat(trends.AverageEnergy, ptr(6.0)) {
+		t.Errorf("average mood %v and energy %v, want 3.2 and 6", deref(trends.AverageMood), deref(trends.AverageEnergy))
+	}
+	if trends.DominantMood != "sad" || trends.Trends[2].Moods["sad"] != 2 {
+		t.Errorf("dominant mood %q with moods %v on the last day, want sad twice", trends.DominantMood, trends.Trends[2].Moods)
+	}
+}
+
+func ptr(v float64) *float64 {
+	return &v
+}
+
+func deref(v *float64) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+func sameFloat(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
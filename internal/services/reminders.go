@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vyve/vyve-backend/internal/models"
+	"github.com/vyve/vyve-backend/internal/repository"
+	"github.com/vyve/vyve-backend/pkg/cache"
+)
+
+const (
+	// User.Settings key of how many reminders a user may get in a day. Their local
+	// reminder hour, 0-23, is reminder_hour, which GetUsersForReminders reads.
+	remindersPerDaySetting   = "reminders_per_day"
+	defaultReminderHour      = 9
+	defaultRemindersPerDay   = 1
+	maxRemindersPerDay       = 5
+	reminderDigestMaxNames   = 3
+	reminderCounterRetention = 48 * time.Hour
+)
+
+// reminderSender sends the daily reminder digests
+type reminderSender struct {
+	userRepo         repository.UserRepository
+	personRepo       repository.PersonRepository
+	consentRepo      repository.ConsentRepository
+	notificationRepo repository.NotificationRepository
+	cache            cache.Cache
+}
+
+// SendDailyReminders is run hourly. Users whose reminder hour has come are sent one push
+// notification listing the people due a reminder, also put in their in-app inbox if
+// they want it there, and the people's next reminders are then scheduled by their
+// reminder schedule or frequency. Users who turned reminder push notifications off get
+// none. A digest queued inside the user's quiet hours is pushed when they end, weekly
+// digests wait until Monday, and no user gets more than their daily number of reminders.
+func SendDailyReminders(repos *repository.Repositories, cache cache.Cache) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Minute)
+	defer cancel()
+
+	sender := &reminderSender{
+		userRepo:         repos.User,
+		personRepo:       repos.Person,
+		consentRepo:      repos.Consent,
+		notificationRepo: repos.Notification,
+		cache:            cache,
+	}
+
+	users, err := sender.userRepo.GetUsersForReminders(ctx, defaultReminderHour)
+	if err != nil {
+		log.Printf("[REMINDER] Failed to load users due reminders: %v", err)
+		return
+	}
+
+	sent, failed := 0, 0
+	for _, user := range users {
+		ok, err := sender.remind(ctx, user, time.Now())
+		if err != nil {
+			log.Printf("[REMINDER] Failed to remind user %s: %v", user.ID, err)
+			failed++
+		} else if ok {
+			sent++
+		}
+	}
+
+	log.Printf("[REMINDER] Sent reminders to %d of %d users (%d failed)", sent, len(users), failed)
+}
+
+// remind sends the user's reminder digest unless their notification preferences or the
+// daily cap hold it back. It reports whether a reminder was sent.
+func (s *reminderSender) remind(ctx context.Context, user *models.User, now time.Time) (bool, error) {
+	loc := userLocation(user.Timezone)
+	local := now.In(loc)
+	allowed, err := notificationAllowed(ctx, s.consentRepo, user, models.ChannelPush, models.NotificationReminder)
+	if err != nil || !allowed {
+		return false, err
+	}
+	if notificationPreferences(user).DigestFrequency == models.DigestWeekly && local.Weekday() != time.Monday {
+		return false, nil
+	}
+
+	people, err := s.personRepo.GetPeopleForReminders(ctx, user.ID)
+	if err != nil {
+		return false, err
+	}
+	if len(people) == 0 {
+		return false, nil
+	}
+
+	// Count the reminder before sending it, so two workers can't both send the last one
+	// of the day
+	counter := fmt.Sprintf("reminders_sent:%s:%s", user.ID, local.Format("2006-01-02"))
+	count, err := s.cache.IncrementWithTTL(ctx, counter, reminderCounterRetention)
+	if err != nil {
+		return false, err
+	}
+	if count > int64(remindersPerDay(user)) {
+		return false, nil
+	}
+
+	if err := s.queue(ctx, user, people, now); err != nil {
+		if _, err := s.cache.Decrement(ctx, counter); err != nil {
+			log.Printf("[REMINDER] Failed to uncount reminder of user %s: %v", user.ID, err)
+		}
+		return false, err
+	}
+
+	for _, person := range people {
+		next := reminderAfter(person, loc, now)
+		if err := s.personRepo.SetNextReminder(ctx, person.ID, next); err != nil {
+			log.Printf("[REMINDER] Failed to schedule next reminder of person %s: %v", person.ID, err)
+		}
+	}
+	return true, nil
+}
+
+// queue queues the digest for the notification worker to push to the user's devices
+func (s *reminderSender) queue(ctx context.Context, user *models.User, people []*models.Person, now time.Time) error {
+	personIDs := make([]string, len(people))
+	for i, person := range people {
+		personIDs[i] = person.ID.String()
+	}
+
+	_, err := queueNotification(ctx, s.notificationRepo, s.consentRepo, user, models.NotificationReminder, models.NotificationPayload{
+		Title:    "Time to reconnect",
+		Body:     reminderDigestBody(people),
+		Priority: "normal",
+		Data: map[string]string{
+			"type":       "reminder",
+			"person_ids": strings.Join(personIDs, ","),
+		},
+	}, now)
+	return err
+}
+
+// reminderDigestBody names the first few people due a reminder and counts the rest
+func reminderDigestBody(people []*models.Person) string {
+	names := make([]string, 0, reminderDigestMaxNames)
+	for _, person := range people {
+		if len(names) == reminderDigestMaxNames {
+			break
+		}
+		names = append(names, person.Name)
+	}
+
+	switch others := len(people) - len(names); {
+	case others == 1:
+		names = append(names, "1 other")
+	case others > 1:
+		names = append(names, fmt.Sprintf("%d others", others))
+	}
+
+	list := names[0]
+	if len(names) > 1 {
+		list = strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+	}
+	return fmt.Sprintf("Reach out to %s today.", list)
+}
+
+// reminderAfter returns a person's first reminder after the given time, or nil once their
+// reminder schedule has ended. Without a schedule, the reminder steps forward by the
+// reminder frequency. Days are added in the user's timezone, so the reminder keeps its
+// local time of day across DST changes.
+func reminderAfter(person *models.Person, loc *time.Location, after time.Time) *time.Time {
+	if person.ReminderSchedule != nil {
+		next, ok := person.ReminderSchedule.Next(after, loc)
+		if !ok {
+			return nil
+		}
+		next = next.UTC()
+		return &next
+	}
+
+	days := reminderInterval(person.ReminderFrequency)
+	next := after.In(loc)
+	if person.NextReminderAt != nil {
+		next = person.NextReminderAt.In(loc)
+	}
+	for !next.After(after) {
+		next = next.AddDate(0, 0, days)
+	}
+	next = next.UTC()
+	return &next
+}
+
+// remindersPerDay reads how many reminders the user may get in a day
+func remindersPerDay(user *models.User) int {
+	count, ok := intSetting(user, remindersPerDaySetting)
+	if !ok || count < 1 {
+		return defaultRemindersPerDay
+	}
+	return min(count, maxRemindersPerDay)
+}
+
+// hourSetting reads a setting holding an hour of the day
+func hourSetting(user *models.User, key string) (int, bool) {
+	hour, ok := intSetting(user, key)
+	if !ok || hour < 0 || hour > 23 {
+		return 0, false
+	}
+	return hour, true
+}
+
+// intSetting reads a whole number setting, which clients may have stored as a string
+func intSetting(user *models.User, key string) (int, bool) {
+	switch v := user.Settings[key].(type) {
+	case float64:
+		return int(v), true
+	case int:
+		return v, true
+	case string:
+		n, err := strconv.Atoi(v)
+		return n, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/vyve/vyve-backend/internal/models"
+	"github.com/vyve/vyve-backend/internal/repository"
+)
+
+// newReminderSender returns a sender for a user with one person due a reminder, and the
+// notifications it queues
+func newReminderSender(t *testing.T, user *models.User) (*reminderSender, *[]*models.Notification) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	person := &models.Person{Base: models.Base{ID: uuid.New()}, UserID: user.ID, Name: "Sam", ReminderFrequency: "weekly"}
+
+	personRepo := repository.NewMockPersonRepository(ctrl)
+	personRepo.EXPECT().GetPeopleForReminders(gomock.Any(), user.ID).Return([]*models.Person{person}, nil).AnyTimes()
+	personRepo.EXPECT().SetNextReminder(gomock.Any(), person.ID, gomock.Any()).Return(nil).AnyTimes()
+
+	var created []*models.Notification
+	notificationRepo := repository.NewMockNotificationRepository(ctrl)
+	notificationRepo.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, notification *models.Notification) error {
+			created = append(created, notification)
+			return nil
+		}).
+		AnyTimes()
+
+	return &reminderSender{
+		personRepo:       personRepo,
+		notificationRepo: notificationRepo,
+		cache:            newTestCache(t),
+	}, &created
+}
+
+func TestRemindDailyCap(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A Wednesday, late enough that the next local day starts an hour later
+	now := time.Date(2026, 10, 14, 23, 0, 0, 0, loc)
+
+	tests := []struct {
+		name     string
+		setting  interface{}
+		wantSent int
+	}{
+		{"default", nil, defaultRemindersPerDay},
+		{"one", 1, 1},
+		{"two, stored as a string", "2", 2},
+		{"at the maximum", float64(maxRemindersPerDay), maxRemindersPerDay},
+		{"above the maximum", 9, maxRemindersPerDay},
+		{"zero", 0, defaultRemindersPerDay},
+		{"not a number", "lots", defaultRemindersPerDay},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &models.User{Base: models.Base{ID: uuid.New()}, Timezone: loc.String(), Settings: models.JSONB{}}
+			if tt.setting != nil {
+				user.Settings[remindersPerDaySetting] = tt.setting
+			}
+			sender, created := newReminderSender(t, user)
+			ctx := context.Background()
+
+			sent := 0
+			for i := 0; i < maxRemindersPerDay+2; i++ {
+				ok, err := sender.remind(ctx, user, now)
+				if err != nil {
+					t.Fatalf("remind() error = %v", err)
+				}
+				if ok {
+					sent++
+				}
+			}
+			if sent != tt.wantSent {
+				t.Errorf("sent %d reminders, want %d", sent, tt.wantSent)
+			}
+			if pushes := countChannel(*created, models.ChannelPush); pushes != tt.wantSent {
+				t.Errorf("queued %d pushes, want %d", pushes, tt.wantSent)
+			}
+
+			// The cap is per local day
+			if ok, err := sender.remind(ctx, user, now.Add(time.Hour)); err != nil || !ok {
+				t.Errorf("remind() the next local day = %v, %v, want a reminder", ok, err)
+			}
+		})
+	}
+}
+
+func TestRemindQuietHours(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	overnight := models.QuietHours{Enabled: true, Start: 22, End: 7}
+	afternoon := models.QuietHours{Enabled: true, Start: 13, End: 15}
+	// Hours past 23 are the next day's
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 10, 14, hour, minute, 0, 0, loc)
+	}
+
+	tests := []struct {
+		name   string
+		quiet  models.QuietHours
+		now    time.Time
+		wantAt time.Time
+	}{
+		{"before overnight quiet hours", overnight, at(21, 59), at(21, 59)},
+		{"overnight quiet hours start", overnight, at(22, 0), at(31, 0)},
+		{"after midnight", overnight, at(24, 30), at(31, 0)},
+		{"last minute of overnight quiet hours", overnight, at(30, 59), at(31, 0)},
+		{"overnight quiet hours end", overnight, at(7, 0), at(7, 0)},
+		{"afternoon quiet hours start", afternoon, at(13, 0), at(15, 0)},
+		{"afternoon quiet hours end", afternoon, at(15, 0), at(15, 0)},
+		{"quiet hours off", models.QuietHours{Start: 22, End: 7}, at(23, 0), at(23, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefs := models.DefaultNotificationPreferences()
+			prefs.QuietHours = tt.quiet
+			user := &models.User{Base: models.Base{ID: uuid.New()}, Timezone: loc.String(), NotificationPreferences: &prefs}
+			sender, created := newReminderSender(t, user)
+
+			// Reminders are queued inside quiet hours too, and pushed when they end
+			ok, err := sender.remind(context.Background(), user, tt.now)
+			if err != nil || !ok {
+				t.Fatalf("remind() = %v, %v, want a reminder", ok, err)
+			}
+			for _, notification := range *created {
+				if notification.Channel != models.ChannelPush {
+					continue
+				}
+				if notification.NextAttemptAt == nil || !notification.NextAttemptAt.Equal(tt.wantAt) {
+					t.Errorf("push due at %v, want %v", notification.NextAttemptAt, tt.wantAt)
+				}
+			}
+			if countChannel(*created, models.ChannelPush) != 1 {
+				t.Errorf("queued %d pushes, want 1", countChannel(*created, models.ChannelPush))
+			}
+		})
+	}
+}
+
+// countChannel counts the notifications queued on a channel
+func countChannel(notifications []*models.Notification, channel string) int {
+	count := 0
+	for _, notification := range notifications {
+		if notification.Channel == channel {
+			count++
+		}
+	}
+	return count
+}
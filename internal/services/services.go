@@ -2,6 +2,8 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"log"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,55 +11,10 @@ import (
 	"github.com/vyve/vyve-backend/internal/models"
 	"github.com/vyve/vyve-backend/internal/repository"
 	"github.com/vyve/vyve-backend/pkg/analytics"
-	"github.com/vyve/vyve-backend/pkg/notifications"
+	"github.com/vyve/vyve-backend/pkg/cache"
+	"github.com/vyve/vyve-backend/pkg/storage"
 )
 
-// ReflectionService handles reflection business logic
-type ReflectionService interface {
-	Create(ctx context.Context, userID uuid.UUID, req CreateReflectionRequest) (*models.Reflection, error)
-	GetToday(ctx context.Context, userID uuid.UUID) (*models.Reflection, error)
-}
-
-type reflectionService struct {
-	reflectionRepo repository.ReflectionRepository
-}
-
-// NewReflectionService creates a new reflection service
-func NewReflectionService(reflectionRepo repository.ReflectionRepository) ReflectionService {
-	return &reflectionService{
-		reflectionRepo: reflectionRepo,
-	}
-}
-
-type CreateReflectionRequest struct {
-	Prompt     string   `json:"prompt"`
-	Responses  []string `json:"responses"`
-	Mood       string   `json:"mood"`
-	Insights   []string `json:"insights"`
-	Intentions []string `json:"intentions"`
-}
-
-func (s *reflectionService) Create(ctx context.Context, userID uuid.UUID, req CreateReflectionRequest) (*models.Reflection, error) {
-	reflection := &models.Reflection{
-		UserID:     userID,
-		Prompt:     req.Prompt,
-		Responses:  req.Responses,
-		Mood:       req.Mood,
-		Insights:   req.Insights,
-		Intentions: req.Intentions,
-	}
-
-	if err := s.reflectionRepo.Create(ctx, reflection); err != nil {
-		return nil, err
-	}
-
-	return reflection, nil
-}
-
-func (s *reflectionService) GetToday(ctx context.Context, userID uuid.UUID) (*models.Reflection, error) {
-	return s.reflectionRepo.GetToday(ctx, userID)
-}
-
 // NudgeService handles nudge business logic
 type NudgeService interface {
 	// List and retrieve
@@ -75,36 +32,65 @@ type NudgeService interface {
 	GenerateNudges(ctx context.Context, userID uuid.UUID) error
 	GenerateForPerson(ctx context.Context, userID, personID uuid.UUID) ([]*models.Nudge, error)
 	GenerateSystemNudges(ctx context.Context, userID uuid.UUID) ([]*models.Nudge, error)
+
+	// User-defined rules
+	ListRules(ctx context.Context, userID uuid.UUID) ([]*models.NudgeRule, error)
+	GetRule(ctx context.Context, userID, ruleID uuid.UUID) (*models.NudgeRule, error)
+	CreateRule(ctx context.Context, userID uuid.UUID, req NudgeRuleRequest) (*models.NudgeRule, error)
+	UpdateRule(ctx context.Context, userID, ruleID uuid.UUID, req NudgeRuleRequest) (*models.NudgeRule, error)
+	DeleteRule(ctx context.Context, userID, ruleID uuid.UUID) error
+	DryRunRule(ctx context.Context, userID uuid.UUID, expression string) ([]*RuleMatch, error)
+
+	// Global rules (admin)
+	ListGlobalRules(ctx context.Context) ([]*models.NudgeRule, error)
+	GetGlobalRule(ctx context.Context, ruleID uuid.UUID) (*models.NudgeRule, error)
+	CreateGlobalRule(ctx context.Context, req NudgeRuleRequest) (*models.NudgeRule, error)
+	UpdateGlobalRule(ctx context.Context, ruleID uuid.UUID, req NudgeRuleRequest) (*models.NudgeRule, error)
+	DeleteGlobalRule(ctx context.Context, ruleID uuid.UUID) error
 }
 
 // Note: NudgeService implementation is in nudge_service.go
 
 // GDPRService handles GDPR compliance
 type GDPRService interface {
-	ExportUserData(ctx context.Context, userID uuid.UUID) (*models.DataExport, error)
+	ExportUserData(ctx context.Context, userID uuid.UUID, format string) (*models.DataExport, error)
 	DeleteAllUserData(ctx context.Context, userID uuid.UUID) error
+	DeleteUserDataAsAdmin(ctx context.Context, actorID, userID uuid.UUID) error
 	AnonymizeUserData(ctx context.Context, userID uuid.UUID) error
 	RecordConsent(ctx context.Context, userID uuid.UUID, consentType string, granted bool) error
 	GetConsents(ctx context.Context, userID uuid.UUID) ([]*models.UserConsent, error)
 	GetLatestExport(ctx context.Context, userID uuid.UUID) (*models.DataExport, error)
 	GetExport(ctx context.Context, userID uuid.UUID, exportID uuid.UUID) (*models.DataExport, error)
+	GetExportDownloadURL(ctx context.Context, userID, exportID uuid.UUID) (string, error)
+	PurgeExpiredExports(ctx context.Context) (int, error)
+	FailStaleExports(ctx context.Context) (int64, error)
 	GetAuditLog(ctx context.Context, userID uuid.UUID) ([]*models.AuditLog, error)
 }
 
 type gdprService struct {
 	repos      *repository.Repositories
+	storage    storage.Storage
+	cache      cache.Cache
 	encryption bool
 }
 
 // NewGDPRService creates a new GDPR service
-func NewGDPRService(repos *repository.Repositories, encryptionConfig config.EncryptionConfig) GDPRService {
+func NewGDPRService(repos *repository.Repositories, storage storage.Storage, cache cache.Cache, encryptionConfig config.EncryptionConfig) GDPRService {
 	return &gdprService{
 		repos:      repos,
+		storage:    storage,
+		cache:      cache,
 		encryption: encryptionConfig.Enabled,
 	}
 }
 
-func (s *gdprService) ExportUserData(ctx context.Context, userID uuid.UUID) (*models.DataExport, error) {
+// ExportUserData queues an export of all user data as a ZIP of JSON or CSV files
+func (s *gdprService) ExportUserData(ctx context.Context, userID uuid.UUID, format string) (*models.DataExport, error) {
+	format, err := validateExportFormat(format)
+	if err != nil {
+		return nil, err
+	}
+
 	// Check for existing pending export
 	existing, err := s.repos.DataExport.GetPending(ctx, userID)
 	if err == nil && existing != nil {
@@ -115,7 +101,7 @@ func (s *gdprService) ExportUserData(ctx context.Context, userID uuid.UUID) (*mo
 	export := &models.DataExport{
 		UserID: userID,
 		Status: "pending",
-		Format: "json",
+		Format: format,
 	}
 
 	if err := s.repos.DataExport.Create(ctx, export); err != nil {
@@ -128,29 +114,103 @@ func (s *gdprService) ExportUserData(ctx context.Context, userID uuid.UUID) (*mo
 	return export, nil
 }
 
+// DeleteAllUserData permanently erases the user's account. Database rows are removed
+// in a single transaction; stored files and sessions are cleaned up afterwards and a
+// tombstone audit entry, which holds no personal data, records the erasure.
 func (s *gdprService) DeleteAllUserData(ctx context.Context, userID uuid.UUID) error {
-	// Note: This is a simplified implementation
-	// In a full implementation, you would need to add DeleteByUser methods
-	// to each repository or iterate and delete records individually
-
-	// For now, just delete the user (which should cascade due to foreign keys)
-	// Or implement soft delete and mark all user data as deleted
-
-	// TODO: Implement proper cascade deletion:
-	// - Delete AI analysis jobs
-	// - Delete relationship analyses and recommendations
-	// - Delete nudges
-	// - Delete interactions
-	// - Delete people
-	// - Delete reflections
-	// - Delete events
-	// - Delete consents
-	// - Delete audit logs
-	// - Delete data exports
-	// - Delete refresh tokens
-
-	// Finally, delete user
-	return s.repos.User.Delete(ctx, userID)
+	return s.eraseUser(ctx, nil, userID)
+}
+
+// DeleteUserDataAsAdmin erases another user's account on behalf of an admin. The
+// tombstone names the admin, so it is kept when the erasure is recorded.
+func (s *gdprService) DeleteUserDataAsAdmin(ctx context.Context, actorID, userID uuid.UUID) error {
+	if actorID == userID {
+		return fmt.Errorf("%w: delete your own account from your account settings", repository.ErrInvalidInput)
+	}
+	return s.eraseUser(ctx, &actorID, userID)
+}
+
+// eraseUser deletes the user's data and writes the tombstone, attributed to the admin
+// who asked for the erasure, if any. The last admin cannot be erased.
+func (s *gdprService) eraseUser(ctx context.Context, actorID *uuid.UUID, userID uuid.UUID) error {
+	// Refuses to erase the last admin, checked in the same transaction
+	deleted, err := s.repos.Account.DeleteAllData(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	// The rows are gone, so cleanup failures are logged rather than returned
+	files := s.deleteUserFiles(ctx, userID)
+	s.purgeSessions(ctx, userID)
+
+	// The tombstone is written only after the transaction, which also deleted the
+	// user's own audit_logs, so it is the one record of the user that survives. It is
+	// not part of the transaction, so failing to write it never keeps data that was
+	// asked to be erased.
+	tombstone := &models.AuditLog{
+		UserID:     actorID,
+		Action:     "user_data_erased",
+		EntityType: "user",
+		EntityID:   userID.String(),
+		Changes: models.JSONB{
+			"rows_deleted":  deleted,
+			"files_deleted": files,
+		},
+		Result: "success",
+	}
+	if err := s.repos.AuditLog.Create(ctx, tombstone); err != nil {
+		log.Printf("[GDPR] Failed to write erasure tombstone for user %s: %v", userID, err)
+	}
+
+	return nil
+}
+
+// userStoragePrefixes returns the storage prefixes holding files stored for a user
+func userStoragePrefixes(userID uuid.UUID) []string {
+	return []string{
+		fmt.Sprintf("avatars/%s/", userID),
+		fmt.Sprintf("avatars/people/%s/", userID),
+		fmt.Sprintf("exports/%s/", userID),
+		fmt.Sprintf("imports/%s/", userID),
+	}
+}
+
+// deleteUserFiles removes the user's avatars, person avatars, exports and imports from storage and
+// returns the number of files deleted
+func (s *gdprService) deleteUserFiles(ctx context.Context, userID uuid.UUID) int {
+	if s.storage == nil {
+		return 0
+	}
+
+	count := 0
+	for _, prefix := range userStoragePrefixes(userID) {
+		keys, err := s.storage.ListObjects(ctx, prefix)
+		if err != nil {
+			log.Printf("[GDPR] Failed to list %s for user %s: %v", prefix, userID, err)
+			continue
+		}
+		for _, key := range keys {
+			if err := s.storage.Delete(ctx, key); err != nil {
+				log.Printf("[GDPR] Failed to delete %s for user %s: %v", key, userID, err)
+				continue
+			}
+			count++
+		}
+	}
+	return count
+}
+
+// purgeSessions removes every cached session of the user
+func (s *gdprService) purgeSessions(ctx context.Context, userID uuid.UUID) {
+	if s.cache == nil {
+		return
+	}
+	if err := s.cache.DeletePattern(ctx, fmt.Sprintf("session:%s:*", userID)); err != nil {
+		log.Printf("[GDPR] Failed to purge sessions for user %s: %v", userID, err)
+	}
+	if err := s.cache.Delete(ctx, fmt.Sprintf("session:%s", userID)); err != nil {
+		log.Printf("[GDPR] Failed to purge session for user %s: %v", userID, err)
+	}
 }
 
 func (s *gdprService) AnonymizeUserData(ctx context.Context, userID uuid.UUID) error {
@@ -168,7 +228,15 @@ func (s *gdprService) AnonymizeUserData(ctx context.Context, userID uuid.UUID) e
 	user.AvatarURL = ""
 	user.PasswordHash = "" // Clear password
 
-	if err := s.repos.User.Update(ctx, user); err != nil {
+	err = s.repos.User.UpdateFields(ctx, user, map[string]interface{}{
+		"username":      user.Username,
+		"email":         user.Email,
+		"display_name":  user.DisplayName,
+		"bio":           user.Bio,
+		"avatar_url":    user.AvatarURL,
+		"password_hash": user.PasswordHash,
+	})
+	if err != nil {
 		return err
 	}
 
@@ -204,54 +272,6 @@ func (s *gdprService) RecordConsent(ctx context.Context, userID uuid.UUID, conse
 	return s.repos.Consent.Create(ctx, consent)
 }
 
-// Helper method to process data export asynchronously
-func (s *gdprService) processDataExport(ctx context.Context, export *models.DataExport) {
-	// Update status to processing
-	export.Status = "processing"
-	s.repos.DataExport.Update(ctx, export)
-
-	// Gather all user data
-	data := make(map[string]interface{})
-
-	// Get user profile
-	user, err := s.repos.User.FindByID(ctx, export.UserID)
-	if err == nil {
-		data["user"] = user
-	}
-
-	// Get people
-	people, err := s.repos.Person.FindByUserID(ctx, export.UserID)
-	if err == nil {
-		data["people"] = people
-	}
-
-	// Get interactions
-	interactions, err := s.repos.Interaction.GetRecent(ctx, export.UserID, 10000)
-	if err == nil {
-		data["interactions"] = interactions
-	}
-
-	// Get consents
-	consents, _ := s.repos.Consent.GetByUser(ctx, export.UserID)
-	data["consents"] = consents
-
-	// In a real implementation, you would:
-	// 1. Serialize data to JSON/CSV
-	// 2. Upload to storage (S3, etc.)
-	// 3. Update export with file URL and size
-	// 4. Set expiration time
-
-	// For now, mark as completed
-	now := time.Now()
-	expiresAt := now.Add(7 * 24 * time.Hour) // Expires in 7 days
-	export.Status = "completed"
-	export.CompletedAt = &now
-	export.ExpiresAt = &expiresAt
-	export.FileURL = "/api/v1/gdpr/export/" + export.ID.String() + "/download"
-
-	s.repos.DataExport.Update(ctx, export)
-}
-
 func (s *gdprService) GetConsents(ctx context.Context, userID uuid.UUID) ([]*models.UserConsent, error) {
 	return s.repos.Consent.GetByUser(ctx, userID)
 }
@@ -280,14 +300,27 @@ func (s *gdprService) GetAuditLog(ctx context.Context, userID uuid.UUID) ([]*mod
 
 // Background worker functions
 
-// SendDailyReminders sends daily reminder notifications
-func SendDailyReminders(userRepo repository.UserRepository, notificationService notifications.NotificationService) {
-	// Stub implementation
-}
+// GenerateNudges runs the system nudge rules for every user active in the last 30 days
+func GenerateNudges(repos *repository.Repositories, analytics analytics.Analytics) {
+	ctx, cancel := context.WithTimeout(context.Background(), 25*time.Minute)
+	defer cancel()
 
-// GenerateNudges generates AI-powered nudges for users
-func GenerateNudges(repos *repository.Repositories, analytics analytics.Analytics, notificationService notifications.NotificationService) {
-	// Stub implementation
+	users, err := repos.User.GetActiveUsers(ctx, time.Now().AddDate(0, 0, -30))
+	if err != nil {
+		log.Printf("[NUDGE] Failed to load active users: %v", err)
+		return
+	}
+
+	nudgeService := NewNudgeService(repos.Nudge, repos.NudgeRule, repos.Person, repos.User, repos.Consent, repos.Notification, analytics)
+	failed := 0
+	for _, user := range users {
+		if err := nudgeService.GenerateNudges(ctx, user.ID); err != nil {
+			log.Printf("[NUDGE] Failed to generate nudges for user %s: %v", user.ID, err)
+			failed++
+		}
+	}
+
+	log.Printf("[NUDGE] Generated system nudges for %d users (%d failed)", len(users)-failed, failed)
 }
 
 // AggregateMetrics aggregates daily metrics
@@ -0,0 +1,298 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vyve/vyve-backend/internal/models"
+	"github.com/vyve/vyve-backend/internal/repository"
+	"github.com/vyve/vyve-backend/pkg/utils"
+)
+
+// suspensionReasonMaxLength bounds the note admins leave when suspending a user
+const suspensionReasonMaxLength = 500
+
+// AdminUserDetails is what admins see of a single user
+type AdminUserDetails struct {
+	User           *models.User           `json:"user"`
+	Providers      []string               `json:"providers"`
+	HasPassword    bool                   `json:"has_password"`
+	MFAEnabled     bool                   `json:"mfa_enabled"`
+	Passkeys       int                    `json:"passkeys"`
+	ActiveSessions int                    `json:"active_sessions"`
+	Stats          map[string]interface{} `json:"stats"`
+}
+
+// AdminUserUpdate holds the profile fields an admin may change. Nil fields are left as
+// they are.
+type AdminUserUpdate struct {
+	Username      *string `json:"username"`
+	Email         *string `json:"email"`
+	EmailVerified *bool   `json:"email_verified"`
+	DisplayName   *string `json:"display_name"`
+	Bio           *string `json:"bio"`
+	Timezone      *string `json:"timezone"`
+	Locale        *string `json:"locale"`
+}
+
+// ListUsers lists users for an admin
+func (s *userService) ListUsers(ctx context.Context, actorID uuid.UUID, opts repository.UserFilterOptions) ([]*models.User, *repository.PaginationResult, error) {
+	users, result, err := s.userRepo.List(ctx, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.auditAdminAction(ctx, actorID, "users_listed", uuid.Nil, userFilterChanges(opts))
+	return users, result, nil
+}
+
+// GetUserDetails returns a user with their sign-in methods, sessions and activity
+func (s *userService) GetUserDetails(ctx context.Context, actorID, userID uuid.UUID) (*AdminUserDetails, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	providers, err := s.userRepo.GetAuthProviders(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	passkeys, err := s.userRepo.ListPasskeys(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := s.userRepo.ListActiveRefreshTokens(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := s.userRepo.GetUserStats(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	details := &AdminUserDetails{
+		User:           user,
+		Providers:      make([]string, len(providers)),
+		HasPassword:    user.PasswordHash != "",
+		MFAEnabled:     user.MFAEnabled(),
+		Passkeys:       len(passkeys),
+		ActiveSessions: len(sessions),
+		Stats:          stats,
+	}
+	for i, provider := range providers {
+		details.Providers[i] = provider.Provider
+	}
+
+	s.auditAdminAction(ctx, actorID, "user_viewed", userID, nil)
+	return details, nil
+}
+
+// AdminUpdateUser changes a user's profile on behalf of an admin. A new email address is
+// unverified, and the user is signed out everywhere since their sessions were granted to
+// the old one.
+func (s *userService) AdminUpdateUser(ctx context.Context, actorID, userID uuid.UUID, update AdminUserUpdate) (*models.User, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the edited columns are written, in the order they're audited
+	fields := make(map[string]interface{})
+	var changed []string
+	set := func(column string, value interface{}) {
+		fields[column] = value
+		changed = append(changed, column)
+	}
+	if update.Username != nil && *update.Username != user.Username {
+		username := strings.TrimSpace(*update.Username)
+		if !utils.IsValidUsername(username) {
+			return nil, fmt.Errorf("%w: invalid username", repository.ErrInvalidInput)
+		}
+		exists, err := s.userRepo.CheckUsernameExists(ctx, username)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, fmt.Errorf("%w: username is taken", repository.ErrUserAlreadyExists)
+		}
+		user.Username = username
+		set("username", username)
+	}
+	if update.Email != nil && !strings.EqualFold(*update.Email, user.Email) {
+		address := strings.ToLower(strings.TrimSpace(*update.Email))
+		if !utils.IsValidEmail(address) {
+			return nil, fmt.Errorf("%w: invalid email address", repository.ErrInvalidInput)
+		}
+		exists, err := s.userRepo.CheckEmailExists(ctx, address)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, fmt.Errorf("%w: email address is taken", repository.ErrUserAlreadyExists)
+		}
+		if update.EmailVerified != nil && *update.EmailVerified {
+			return nil, fmt.Errorf("%w: a new email address must be verified by the user", repository.ErrInvalidInput)
+		}
+		user.Email = address
+		set("email", address)
+		if user.EmailVerified {
+			user.EmailVerified = false
+			set("email_verified", false)
+		}
+	} else if update.EmailVerified != nil && *update.EmailVerified != user.EmailVerified {
+		user.EmailVerified = *update.EmailVerified
+		set("email_verified", user.EmailVerified)
+	}
+	if update.DisplayName != nil && *update.DisplayName != user.DisplayName {
+		user.DisplayName = strings.TrimSpace(*update.DisplayName)
+		set("display_name", user.DisplayName)
+	}
+	if update.Bio != nil && *update.Bio != user.Bio {
+		user.Bio = *update.Bio
+		set("bio", user.Bio)
+	}
+	if update.Timezone != nil && *update.Timezone != user.Timezone {
+		if _, err := time.LoadLocation(*update.Timezone); err != nil || *update.Timezone == "" {
+			return nil, fmt.Errorf("%w: unknown timezone %q", repository.ErrInvalidInput, *update.Timezone)
+		}
+		user.Timezone = *update.Timezone
+		set("timezone", user.Timezone)
+	}
+	if update.Locale != nil && *update.Locale != user.Locale {
+		if *update.Locale == "" {
+			return nil, fmt.Errorf("%w: locale is required", repository.ErrInvalidInput)
+		}
+		user.Locale = *update.Locale
+		set("locale", user.Locale)
+	}
+
+	if len(changed) == 0 {
+		return user, nil
+	}
+	if err := s.userRepo.UpdateFields(ctx, user, fields); err != nil {
+		return nil, err
+	}
+
+	if _, ok := fields["email"]; ok {
+		if err := s.userRepo.RevokeAllUserTokens(ctx, userID); err != nil {
+			return nil, err
+		}
+		if err := s.cache.DeletePattern(ctx, fmt.Sprintf("session:%s:*", userID)); err != nil {
+			log.Printf("[USER] Failed to end sessions of user %s after an email change: %v", userID, err)
+		}
+	}
+
+	// Field names only; the audit log shouldn't become a copy of the profile
+	s.auditAdminAction(ctx, actorID, "user_updated", userID, models.JSONB{"fields": changed})
+	return user, nil
+}
+
+// SuspendUser stops a user from signing in and ends all of their sessions. Admins can't
+// suspend themselves.
+func (s *userService) SuspendUser(ctx context.Context, actorID, userID uuid.UUID, reason string) (*models.User, error) {
+	if actorID == userID {
+		return nil, fmt.Errorf("%w: you cannot suspend your own account", repository.ErrInvalidInput)
+	}
+	reason = strings.TrimSpace(reason)
+	if len(reason) > suspensionReasonMaxLength {
+		return nil, fmt.Errorf("%w: reason is longer than %d characters", repository.ErrInvalidInput, suspensionReasonMaxLength)
+	}
+
+	if _, err := s.userRepo.FindByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	changed, err := s.userRepo.Suspend(ctx, userID, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sign the user out everywhere, even when the suspension was already in place, in
+	// case a session outlived it
+	if err := s.userRepo.RevokeAllUserTokens(ctx, userID); err != nil {
+		return nil, err
+	}
+	if err := s.cache.DeletePattern(ctx, fmt.Sprintf("session:%s:*", userID)); err != nil {
+		log.Printf("[USER] Failed to end sessions of suspended user %s: %v", userID, err)
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		s.auditAdminAction(ctx, actorID, "user_suspended", userID, models.JSONB{"reason": reason})
+	}
+	return user, nil
+}
+
+// UnsuspendUser lets a suspended user sign in again
+func (s *userService) UnsuspendUser(ctx context.Context, actorID, userID uuid.UUID) (*models.User, error) {
+	if _, err := s.userRepo.FindByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	changed, err := s.userRepo.Unsuspend(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		s.auditAdminAction(ctx, actorID, "user_unsuspended", userID, nil)
+	}
+	return user, nil
+}
+
+// auditAdminAction records an admin's action on a user, or on users in general when
+// userID is nil
+func (s *userService) auditAdminAction(ctx context.Context, actorID uuid.UUID, action string, userID uuid.UUID, changes models.JSONB) {
+	entry := &models.AuditLog{
+		UserID:     &actorID,
+		Action:     action,
+		EntityType: "user",
+		Changes:    changes,
+		Result:     "success",
+	}
+	if userID != uuid.Nil {
+		entry.EntityID = userID.String()
+	}
+	if err := s.auditRepo.Create(ctx, entry); err != nil {
+		log.Printf("[USER] Failed to audit %s by admin %s: %v", action, actorID, err)
+	}
+}
+
+// userFilterChanges describes the filters of a user listing for the audit log
+func userFilterChanges(opts repository.UserFilterOptions) models.JSONB {
+	changes := models.JSONB{"page": opts.Page}
+	if opts.Search != "" {
+		changes["search"] = opts.Search
+	}
+	if opts.StartDate != nil {
+		changes["signed_up_after"] = opts.StartDate
+	}
+	if opts.EndDate != nil {
+		changes["signed_up_before"] = opts.EndDate
+	}
+	if opts.ActiveAfter != nil {
+		changes["active_after"] = opts.ActiveAfter
+	}
+	if opts.ActiveBefore != nil {
+		changes["active_before"] = opts.ActiveBefore
+	}
+	if opts.Provider != "" {
+		changes["provider"] = opts.Provider
+	}
+	if opts.Role != "" {
+		changes["role"] = opts.Role
+	}
+	if opts.Suspended != nil {
+		changes["suspended"] = *opts.Suspended
+	}
+	return changes
+}
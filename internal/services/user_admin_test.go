@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/vyve/vyve-backend/internal/models"
+	"github.com/vyve/vyve-backend/internal/repository"
+)
+
+func TestAdminUpdateUser(t *testing.T) {
+	adminID := uuid.New()
+	userID := uuid.New()
+	newEmail := "sam@new.example.com"
+	verified := true
+	bio := "Hello"
+
+	tests := []struct {
+		name        string
+		update      AdminUserUpdate
+		wantErr     error
+		wantFields  map[string]interface{}
+		wantRevoked bool
+	}{
+		{
+			name:       "profile field",
+			update:     AdminUserUpdate{Bio: &bio},
+			wantFields: map[string]interface{}{"bio": bio},
+		},
+		{
+			name:        "email change",
+			update:      AdminUserUpdate{Email: &newEmail},
+			wantFields:  map[string]interface{}{"email": newEmail, "email_verified": false},
+			wantRevoked: true,
+		},
+		{
+			name:    "new email marked verified",
+			update:  AdminUserUpdate{Email: &newEmail, EmailVerified: &verified},
+			wantErr: repository.ErrInvalidInput,
+		},
+		{
+			name:   "nothing changed",
+			update: AdminUserUpdate{EmailVerified: &verified},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			userRepo := repository.NewMockUserRepository(ctrl)
+			userRepo.EXPECT().
+				FindByID(gomock.Any(), userID).
+				Return(&models.User{Base: models.Base{ID: userID}, Email: "sam@example.com", EmailVerified: true}, nil)
+			userRepo.EXPECT().CheckEmailExists(gomock.Any(), newEmail).Return(false, nil).AnyTimes()
+
+			var written []map[string]interface{}
+			userRepo.EXPECT().
+				UpdateFields(gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, user *models.User, fields map[string]interface{}) error {
+					written = append(written, fields)
+					return nil
+				}).
+				AnyTimes()
+			if tt.wantRevoked {
+				userRepo.EXPECT().RevokeAllUserTokens(gomock.Any(), userID).Return(nil)
+			}
+
+			auditRepo := repository.NewMockAuditLogRepository(ctrl)
+			auditRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+			sessions := newTestCache(t)
+			sessionKey := "session:" + userID.String() + ":s1"
+			if err := sessions.Set(context.Background(), sessionKey, "live", time.Hour); err != nil {
+				t.Fatal(err)
+			}
+			svc := &userService{userRepo: userRepo, auditRepo: auditRepo, cache: sessions}
+
+			_, err := svc.AdminUpdateUser(context.Background(), adminID, userID, tt.update)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AdminUpdateUser() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantFields == nil {
+				if len(written) != 0 {
+					t.Errorf("wrote %v, want nothing", written)
+				}
+			} else if len(written) != 1 || !reflect.DeepEqual(written[0], tt.wantFields) {
+				t.Errorf("wrote %v, want only %v", written, tt.wantFields)
+			}
+			live, err := sessions.Exists(context.Background(), sessionKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if live == tt.wantRevoked {
+				t.Errorf("session still live = %v, want sessions ended %v", live, tt.wantRevoked)
+			}
+		})
+	}
+}
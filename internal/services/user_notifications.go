@@ -0,0 +1,297 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vyve/vyve-backend/internal/models"
+	"github.com/vyve/vyve-backend/internal/repository"
+	"github.com/vyve/vyve-backend/pkg/notifications"
+)
+
+const (
+	// User.Settings keys of the quiet hours users could set before notification
+	// preferences existed. They apply until the user saves their preferences.
+	quietHoursStartSetting = "quiet_hours_start"
+	quietHoursEndSetting   = "quiet_hours_end"
+	// marketingConsentType is the UserConsent marketing notifications need
+	marketingConsentType = "marketing"
+	// maxPushTokenLength bounds device tokens, which FCM keeps well under it
+	maxPushTokenLength = 4096
+)
+
+// pushPlatforms are the platforms devices can register push tokens from
+var pushPlatforms = map[string]bool{"ios": true, "android": true, "web": true}
+
+// notificationChannels are the channels notifications are queued on. Nothing is sent by
+// email yet.
+var notificationChannels = []string{models.ChannelPush, models.ChannelInApp}
+
+// errNoPushDevices is returned when a push notification has no device to go to
+var errNoPushDevices = errors.New("no active push devices")
+
+// RegisterPushTokenRequest registers a device for push notifications. A device that
+// sends its ID keeps a single token, so a refreshed token replaces the old one.
+type RegisterPushTokenRequest struct {
+	Token      string                 `json:"token"`
+	Platform   string                 `json:"platform"` // ios, android, web
+	DeviceID   string                 `json:"device_id"`
+	DeviceInfo map[string]interface{} `json:"device_info"`
+}
+
+// NotificationPreferencesUpdate changes a user's notification preferences. Channels map
+// notification types to whether they are wanted; types and fields left out are kept.
+type NotificationPreferencesUpdate struct {
+	Push            map[string]bool    `json:"push"`
+	Email           map[string]bool    `json:"email"`
+	InApp           map[string]bool    `json:"in_app"`
+	QuietHours      *models.QuietHours `json:"quiet_hours"`
+	DigestFrequency *string            `json:"digest_frequency"`
+}
+
+// GetNotificationPreferences returns the user's notification preferences
+func (s *userService) GetNotificationPreferences(ctx context.Context, userID uuid.UUID) (*models.NotificationPreferences, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	prefs := notificationPreferences(user)
+	return &prefs, nil
+}
+
+// UpdateNotificationPreferences changes the user's notification preferences. Marketing
+// can only be turned on once the user has consented to it.
+func (s *userService) UpdateNotificationPreferences(ctx context.Context, userID uuid.UUID, update NotificationPreferencesUpdate) (*models.NotificationPreferences, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	prefs := notificationPreferences(user)
+	for channel, types := range map[string]map[string]bool{
+		models.ChannelPush:  update.Push,
+		models.ChannelEmail: update.Email,
+		models.ChannelInApp: update.InApp,
+	} {
+		for notificationType, enabled := range types {
+			if err := prefs.Set(channel, notificationType, enabled); err != nil {
+				return nil, fmt.Errorf("%w: %v", repository.ErrInvalidInput, err)
+			}
+		}
+	}
+	if update.QuietHours != nil {
+		prefs.QuietHours = *update.QuietHours
+	}
+	if update.DigestFrequency != nil {
+		prefs.DigestFrequency = *update.DigestFrequency
+	}
+	if err := prefs.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", repository.ErrInvalidInput, err)
+	}
+
+	if prefs.Push.Marketing || prefs.Email.Marketing || prefs.InApp.Marketing {
+		consented, err := hasConsent(ctx, s.consentRepo, userID, marketingConsentType)
+		if err != nil {
+			return nil, err
+		}
+		if !consented {
+			return nil, fmt.Errorf("%w: marketing notifications need your marketing consent", repository.ErrConsentRequired)
+		}
+	}
+
+	user.NotificationPreferences = &prefs
+	if err := s.userRepo.UpdateFields(ctx, user, map[string]interface{}{"notification_preferences": &prefs}); err != nil {
+		return nil, err
+	}
+	return &prefs, nil
+}
+
+// SendTestNotification pushes a test notification to all of the user's devices, whatever
+// their preferences, and returns how many devices it reached. It skips the outbox so
+// the answer is known right away.
+func (s *userService) SendTestNotification(ctx context.Context, userID uuid.UUID) (int, error) {
+	if s.notifications == nil {
+		return 0, fmt.Errorf("%w: push notifications", repository.ErrProviderNotConfigured)
+	}
+	sent, err := pushToDevices(ctx, s.userRepo, s.notifications, userID, notifications.Notification{
+		Title:    "Test notification",
+		Body:     "Notifications are working on this device.",
+		Priority: "normal",
+		Data:     map[string]string{"type": "test"},
+	})
+	if errors.Is(err, errNoPushDevices) {
+		return 0, fmt.Errorf("%w: no devices are registered for push notifications", repository.ErrInvalidInput)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if sent == 0 {
+		return 0, fmt.Errorf("%w: no registered device could receive the notification", repository.ErrInvalidInput)
+	}
+	return sent, nil
+}
+
+// ListNotifications lists the user's in-app notifications, newest first
+func (s *userService) ListNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool, page, limit int) ([]*models.Notification, *repository.PaginationResult, error) {
+	return s.notificationRepo.ListInbox(ctx, userID, unreadOnly, page, limit)
+}
+
+// CountUnreadNotifications counts the user's unread in-app notifications
+func (s *userService) CountUnreadNotifications(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return s.notificationRepo.CountUnread(ctx, userID)
+}
+
+// MarkNotificationRead marks one of the user's notifications as read. Apps may also use
+// it to report that a push notification was opened.
+func (s *userService) MarkNotificationRead(ctx context.Context, userID, notificationID uuid.UUID) (*models.Notification, error) {
+	return s.notificationRepo.MarkRead(ctx, userID, notificationID)
+}
+
+// MarkAllNotificationsRead marks all of the user's in-app notifications as read and
+// returns how many were unread
+func (s *userService) MarkAllNotificationsRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return s.notificationRepo.MarkAllRead(ctx, userID)
+}
+
+// NotificationAllowed reports whether the user wants a notification of a type on a
+// channel
+func (s *userService) NotificationAllowed(ctx context.Context, userID uuid.UUID, channel, notificationType string) (bool, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return notificationAllowed(ctx, s.consentRepo, user, channel, notificationType)
+}
+
+// notificationAllowed is the check every sender makes before notifying a user: the user
+// must want the type on the channel, and marketing needs the user's consent. Quiet hours
+// don't hold a notification back here; queueNotification delays its push until they end.
+func notificationAllowed(ctx context.Context, consentRepo repository.ConsentRepository, user *models.User, channel, notificationType string) (bool, error) {
+	prefs := notificationPreferences(user)
+	if !prefs.Allows(channel, notificationType) {
+		return false, nil
+	}
+	if notificationType == models.NotificationMarketing {
+		return hasConsent(ctx, consentRepo, user.ID, marketingConsentType)
+	}
+	return true, nil
+}
+
+// quietHoursEnd returns when the user's quiet hours end if they are in them at the given
+// time
+func quietHoursEnd(user *models.User, now time.Time) (time.Time, bool) {
+	end, quiet := notificationPreferences(user).QuietHours.Until(now.In(userLocation(user.Timezone)))
+	return end.UTC(), quiet
+}
+
+// notificationPreferences returns the user's notification preferences. Users who haven't
+// saved any get the defaults, with the quiet hours they may have put in their settings.
+func notificationPreferences(user *models.User) models.NotificationPreferences {
+	if user.NotificationPreferences != nil {
+		return *user.NotificationPreferences
+	}
+
+	prefs := models.DefaultNotificationPreferences()
+	start, ok := hourSetting(user, quietHoursStartSetting)
+	if !ok {
+		return prefs
+	}
+	end, ok := hourSetting(user, quietHoursEndSetting)
+	if !ok || start == end {
+		return prefs
+	}
+	prefs.QuietHours = models.QuietHours{Enabled: true, Start: start, End: end}
+	return prefs
+}
+
+// pushToDevices sends a notification to all of the user's active devices and returns how
+// many it reached, or errNoPushDevices when there are none. Tokens FCM rejects as
+// unregistered or invalid are deactivated, and the ones it accepts are marked as used.
+func pushToDevices(ctx context.Context, userRepo repository.UserRepository, sender notifications.NotificationService, userID uuid.UUID, notification notifications.Notification) (int, error) {
+	pushTokens, err := userRepo.GetUserPushTokens(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	if len(pushTokens) == 0 {
+		return 0, errNoPushDevices
+	}
+
+	tokens := make([]string, len(pushTokens))
+	for i, t := range pushTokens {
+		tokens[i] = t.Token
+	}
+	result, err := sender.SendBatchNotifications(ctx, tokens, notification)
+	if result == nil {
+		return 0, err
+	}
+
+	if len(result.Invalid) > 0 {
+		if err := userRepo.DeactivatePushTokens(ctx, result.Invalid); err != nil {
+			log.Printf("[PUSH] Failed to deactivate invalid push tokens of user %s: %v", userID, err)
+		} else {
+			log.Printf("[PUSH] Deactivated %d invalid push tokens of user %s", len(result.Invalid), userID)
+		}
+	}
+	if err := userRepo.TouchPushTokens(ctx, result.Delivered); err != nil {
+		log.Printf("[PUSH] Failed to record push token use of user %s: %v", userID, err)
+	}
+	return len(result.Delivered), err
+}
+
+// queueNotification records a notification to the user on each channel they want it on
+// and returns how many channels it was queued on. Push notifications wait in the outbox
+// for the notification worker, until the user's quiet hours end if they are in them, and
+// in-app ones go straight to the inbox.
+func queueNotification(ctx context.Context, notificationRepo repository.NotificationRepository, consentRepo repository.ConsentRepository, user *models.User, notificationType string, payload models.NotificationPayload, now time.Time) (int, error) {
+	queued := 0
+	for _, channel := range notificationChannels {
+		allowed, err := notificationAllowed(ctx, consentRepo, user, channel, notificationType)
+		if err != nil {
+			return queued, err
+		}
+		if !allowed {
+			continue
+		}
+
+		notification := &models.Notification{
+			UserID:        user.ID,
+			Channel:       channel,
+			Type:          notificationType,
+			Payload:       payload,
+			Status:        models.NotificationStatusPending,
+			NextAttemptAt: &now,
+		}
+		switch channel {
+		case models.ChannelPush:
+			if end, quiet := quietHoursEnd(user, now); quiet {
+				notification.NextAttemptAt = &end
+			}
+		case models.ChannelInApp:
+			notification.Status = models.NotificationStatusSent
+			notification.SentAt = &now
+			notification.NextAttemptAt = nil
+		}
+		if err := notificationRepo.Create(ctx, notification); err != nil {
+			return queued, err
+		}
+		queued++
+	}
+	return queued, nil
+}
+
+// hasConsent reports whether the user has granted a consent and not revoked it
+func hasConsent(ctx context.Context, consentRepo repository.ConsentRepository, userID uuid.UUID, consentType string) (bool, error) {
+	consent, err := consentRepo.GetByType(ctx, userID, consentType)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return consent.Granted && consent.RevokedAt == nil, nil
+}
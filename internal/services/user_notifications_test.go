@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/vyve/vyve-backend/internal/models"
+	"github.com/vyve/vyve-backend/internal/repository"
+	"github.com/vyve/vyve-backend/pkg/notifications"
+)
+
+// pushTokenStore keeps push tokens in memory so a test can see which ones were pruned
+// and marked used
+type pushTokenStore struct {
+	*repository.MockUserRepository
+	tokens []*models.PushToken
+}
+
+func (r *pushTokenStore) GetUserPushTokens(ctx context.Context, userID uuid.UUID) ([]*models.PushToken, error) {
+	var tokens []*models.PushToken
+	for _, token := range r.tokens {
+		if token.UserID == userID && token.Active {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens, nil
+}
+
+func (r *pushTokenStore) DeactivatePushTokens(ctx context.Context, tokens []string) error {
+	for _, token := range r.tokens {
+		if slices.Contains(tokens, token.Token) {
+			token.Active = false
+		}
+	}
+	return nil
+}
+
+func (r *pushTokenStore) TouchPushTokens(ctx context.Context, tokens []string) error {
+	now := time.Now()
+	for _, token := range r.tokens {
+		if slices.Contains(tokens, token.Token) {
+			token.LastUsedAt = &now
+		}
+	}
+	return nil
+}
+
+// fakePushSender delivers to every token but the rejected ones, or fails the whole batch
+// with err
+type fakePushSender struct {
+	notifications.NotificationService
+	rejected []string
+	err      error
+	sent     []notifications.Notification
+}
+
+func (s *fakePushSender) SendBatchNotifications(ctx context.Context, tokens []string, notification notifications.Notification) (*notifications.BatchResult, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.sent = append(s.sent, notification)
+	result := &notifications.BatchResult{}
+	for _, token := range tokens {
+		if slices.Contains(s.rejected, token) {
+			result.Invalid = append(result.Invalid, token)
+		} else {
+			result.Delivered = append(result.Delivered, token)
+		}
+	}
+	return result, nil
+}
+
+func TestPushToDevices(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name       string
+		tokens     []string
+		rejected   []string
+		wantSent   int
+		wantErr    error
+		wantActive []string
+	}{
+		{"all delivered", []string{"phone", "tablet"}, nil, 2, nil, []string{"phone", "tablet"}},
+		{"rejected token pruned", []string{"phone", "tablet"}, []string{"tablet"}, 1, nil, []string{"phone"}},
+		{"every token rejected", []string{"phone"}, []string{"phone"}, 0, nil, nil},
+		{"no devices", nil, nil, 0, errNoPushDevices, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := &pushTokenStore{MockUserRepository: repository.NewMockUserRepository(gomock.NewController(t))}
+			for _, token := range tt.tokens {
+				userRepo.tokens = append(userRepo.tokens, &models.PushToken{UserID: userID, Token: token, Active: true})
+			}
+			// Another user's token is left alone
+			other := &models.PushToken{UserID: uuid.New(), Token: "other", Active: true}
+			userRepo.tokens = append(userRepo.tokens, other)
+
+			sent, err := pushToDevices(context.Background(), userRepo, &fakePushSender{rejected: tt.rejected}, userID, notifications.Notification{Title: "Hi"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("pushToDevices() error = %v, want %v", err, tt.wantErr)
+			}
+			if sent != tt.wantSent {
+				t.Errorf("sent to %d devices, want %d", sent, tt.wantSent)
+			}
+
+			active, _ := userRepo.GetUserPushTokens(context.Background(), userID)
+			if len(active) != len(tt.wantActive) {
+				t.Fatalf("%d active tokens left, want %v", len(active), tt.wantActive)
+			}
+			for i, token := range active {
+				if token.Token != tt.wantActive[i] || token.LastUsedAt == nil {
+					t.Errorf("active token %q used at %v, want %q marked used", token.Token, token.LastUsedAt, tt.wantActive[i])
+				}
+			}
+			if !other.Active {
+				t.Error("another user's token was deactivated")
+			}
+		})
+	}
+}
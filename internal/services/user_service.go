@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"fmt"
+	"log"
 	"strings"
 	"time"
 
@@ -10,6 +11,8 @@ import (
 	"github.com/vyve/vyve-backend/internal/models"
 	"github.com/vyve/vyve-backend/internal/repository"
 	"github.com/vyve/vyve-backend/pkg/analytics"
+	"github.com/vyve/vyve-backend/pkg/cache"
+	"github.com/vyve/vyve-backend/pkg/notifications"
 	"github.com/vyve/vyve-backend/pkg/storage"
 )
 
@@ -30,27 +33,60 @@ type UserService interface {
 	GetStats(ctx context.Context, userID uuid.UUID) (map[string]interface{}, error)
 	GetSettings(ctx context.Context, userID uuid.UUID) (map[string]interface{}, error)
 	UpdateSettings(ctx context.Context, userID uuid.UUID, settings map[string]interface{}) error
-	RegisterPushToken(ctx context.Context, userID uuid.UUID, token string, platform string) error
-	DeactivatePushToken(ctx context.Context, token string) error
+	RegisterPushToken(ctx context.Context, userID uuid.UUID, req RegisterPushTokenRequest) (*models.PushToken, error)
+	DeactivatePushToken(ctx context.Context, userID uuid.UUID, token string) error
 	GetPushTokens(ctx context.Context, userID uuid.UUID) ([]*models.PushToken, error)
 
 	// Onboarding related methods
 	GetOnboardingStatus(ctx context.Context, userID uuid.UUID) (*OnboardingStatus, error)
 	UpdateOnboardingStatus(ctx context.Context, userID uuid.UUID, completed bool, currentStep string) (*OnboardingStatus, error)
+
+	// Role management
+	GrantRole(ctx context.Context, actorID, userID uuid.UUID, role string) (*models.User, error)
+	RevokeRole(ctx context.Context, actorID, userID uuid.UUID, role string) (*models.User, error)
+
+	// Admin user management
+	ListUsers(ctx context.Context, actorID uuid.UUID, opts repository.UserFilterOptions) ([]*models.User, *repository.PaginationResult, error)
+	GetUserDetails(ctx context.Context, actorID, userID uuid.UUID) (*AdminUserDetails, error)
+	AdminUpdateUser(ctx context.Context, actorID, userID uuid.UUID, update AdminUserUpdate) (*models.User, error)
+	SuspendUser(ctx context.Context, actorID, userID uuid.UUID, reason string) (*models.User, error)
+	UnsuspendUser(ctx context.Context, actorID, userID uuid.UUID) (*models.User, error)
+
+	// Notification preferences
+	GetNotificationPreferences(ctx context.Context, userID uuid.UUID) (*models.NotificationPreferences, error)
+	UpdateNotificationPreferences(ctx context.Context, userID uuid.UUID, update NotificationPreferencesUpdate) (*models.NotificationPreferences, error)
+	SendTestNotification(ctx context.Context, userID uuid.UUID) (int, error)
+	NotificationAllowed(ctx context.Context, userID uuid.UUID, channel, notificationType string) (bool, error)
+
+	// Notification inbox
+	ListNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool, page, limit int) ([]*models.Notification, *repository.PaginationResult, error)
+	CountUnreadNotifications(ctx context.Context, userID uuid.UUID) (int64, error)
+	MarkNotificationRead(ctx context.Context, userID, notificationID uuid.UUID) (*models.Notification, error)
+	MarkAllNotificationsRead(ctx context.Context, userID uuid.UUID) (int64, error)
 }
 
 type userService struct {
-	userRepo  repository.UserRepository
-	storage   storage.Storage
-	analytics analytics.Analytics
+	userRepo         repository.UserRepository
+	auditRepo        repository.AuditLogRepository
+	consentRepo      repository.ConsentRepository
+	notificationRepo repository.NotificationRepository
+	cache            cache.Cache
+	storage          storage.Storage
+	analytics        analytics.Analytics
+	notifications    notifications.NotificationService
 }
 
 // NewUserService creates a new user service
-func NewUserService(userRepo repository.UserRepository, storage storage.Storage, analyticsService analytics.Analytics) UserService {
+func NewUserService(userRepo repository.UserRepository, auditRepo repository.AuditLogRepository, consentRepo repository.ConsentRepository, notificationRepo repository.NotificationRepository, cache cache.Cache, storage storage.Storage, analyticsService analytics.Analytics, notificationService notifications.NotificationService) UserService {
 	return &userService{
-		userRepo:  userRepo,
-		storage:   storage,
-		analytics: analyticsService,
+		userRepo:         userRepo,
+		auditRepo:        auditRepo,
+		consentRepo:      consentRepo,
+		notificationRepo: notificationRepo,
+		cache:            cache,
+		storage:          storage,
+		analytics:        analyticsService,
+		notifications:    notificationService,
 	}
 }
 
@@ -68,21 +104,30 @@ func (s *userService) Update(ctx context.Context, id uuid.UUID, updates map[stri
 
 	// Apply updates
 	// TODO: Add validation and proper field mapping
+	fields := make(map[string]interface{})
 	if displayName, ok := updates["display_name"].(string); ok {
 		user.DisplayName = displayName
+		fields["display_name"] = displayName
 	}
 	if bio, ok := updates["bio"].(string); ok {
 		user.Bio = bio
+		fields["bio"] = bio
 	}
 	if timezone, ok := updates["timezone"].(string); ok {
 		user.Timezone = timezone
+		fields["timezone"] = timezone
 	}
 	if locale, ok := updates["locale"].(string); ok {
 		user.Locale = locale
+		fields["locale"] = locale
 	}
 
-	if err := s.userRepo.Update(ctx, user); err != nil {
-		return nil, err
+	// Only the profile columns are written, so an admin's concurrent change such as a
+	// suspension isn't overwritten
+	if len(fields) > 0 {
+		if err := s.userRepo.UpdateFields(ctx, user, fields); err != nil {
+			return nil, err
+		}
 	}
 
 	// Track profile update event
@@ -126,7 +171,7 @@ func (s *userService) UploadAvatar(ctx context.Context, userID uuid.UUID, file [
 	}
 
 	user.AvatarURL = url
-	if err := s.userRepo.Update(ctx, user); err != nil {
+	if err := s.userRepo.UpdateFields(ctx, user, map[string]interface{}{"avatar_url": url}); err != nil {
 		return "", err
 	}
 
@@ -167,7 +212,7 @@ func (s *userService) UpdateSettings(ctx context.Context, userID uuid.UUID, sett
 		user.Settings[key] = value
 	}
 
-	err = s.userRepo.Update(ctx, user)
+	err = s.userRepo.UpdateFields(ctx, user, map[string]interface{}{"settings": user.Settings})
 	if err != nil {
 		return err
 	}
@@ -193,21 +238,37 @@ func (s *userService) GetSettings(ctx context.Context, userID uuid.UUID) (map[st
 	return user.Settings, nil
 }
 
-// RegisterPushToken registers a push notification token
-func (s *userService) RegisterPushToken(ctx context.Context, userID uuid.UUID, token string, platform string) error {
-	pushToken := &models.PushToken{
-		UserID:   userID,
-		Token:    token,
-		Platform: platform,
-		Active:   true,
+// RegisterPushToken registers a device for push notifications
+func (s *userService) RegisterPushToken(ctx context.Context, userID uuid.UUID, req RegisterPushTokenRequest) (*models.PushToken, error) {
+	req.Token = strings.TrimSpace(req.Token)
+	req.Platform = strings.ToLower(strings.TrimSpace(req.Platform))
+	req.DeviceID = strings.TrimSpace(req.DeviceID)
+	if req.Token == "" {
+		return nil, fmt.Errorf("%w: token is required", repository.ErrInvalidInput)
+	}
+	if len(req.Token) > maxPushTokenLength {
+		return nil, fmt.Errorf("%w: token is too long", repository.ErrInvalidInput)
+	}
+	if !pushPlatforms[req.Platform] {
+		return nil, fmt.Errorf("%w: platform must be ios, android or web", repository.ErrInvalidInput)
 	}
 
-	return s.userRepo.SavePushToken(ctx, pushToken)
+	pushToken := &models.PushToken{
+		UserID:     userID,
+		Token:      req.Token,
+		Platform:   req.Platform,
+		DeviceID:   req.DeviceID,
+		DeviceInfo: models.JSONB(req.DeviceInfo),
+	}
+	if err := s.userRepo.SavePushToken(ctx, pushToken); err != nil {
+		return nil, err
+	}
+	return pushToken, nil
 }
 
-// DeactivatePushToken deactivates a push notification token
-func (s *userService) DeactivatePushToken(ctx context.Context, token string) error {
-	return s.userRepo.DeactivatePushToken(ctx, token)
+// DeactivatePushToken stops push notifications to one of the user's devices
+func (s *userService) DeactivatePushToken(ctx context.Context, userID uuid.UUID, token string) error {
+	return s.userRepo.DeactivatePushToken(ctx, userID, token)
 }
 
 // GetPushTokens gets user's push tokens
@@ -271,7 +332,11 @@ func (s *userService) UpdateOnboardingStatus(ctx context.Context, userID uuid.UU
 	}
 
 	// Update the user
-	if err := s.userRepo.Update(ctx, user); err != nil {
+	err = s.userRepo.UpdateFields(ctx, user, map[string]interface{}{
+		"onboarding_completed": user.OnboardingCompleted,
+		"onboarding_steps":     user.OnboardingSteps,
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -281,3 +346,71 @@ func (s *userService) UpdateOnboardingStatus(ctx context.Context, userID uuid.UU
 	// Return the updated status
 	return s.GetOnboardingStatus(ctx, userID)
 }
+
+// GrantRole grants a role to a user on behalf of an admin
+func (s *userService) GrantRole(ctx context.Context, actorID, userID uuid.UUID, role string) (*models.User, error) {
+	return s.changeRole(ctx, actorID, userID, role, true)
+}
+
+// RevokeRole revokes a role from a user on behalf of an admin. The last admin cannot
+// lose the admin role.
+func (s *userService) RevokeRole(ctx context.Context, actorID, userID uuid.UUID, role string) (*models.User, error) {
+	return s.changeRole(ctx, actorID, userID, role, false)
+}
+
+// changeRole applies a role change and records it in the audit log. Roles are carried
+// in access tokens, so the user's sessions are ended and the next token refresh picks
+// up the new roles.
+func (s *userService) changeRole(ctx context.Context, actorID, userID uuid.UUID, role string, grant bool) (*models.User, error) {
+	if !models.IsValidRole(role) {
+		return nil, fmt.Errorf("%w: unknown role %q", repository.ErrInvalidInput, role)
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	action := "role_granted"
+	var changed bool
+	if grant {
+		changed, err = s.userRepo.AddRole(ctx, userID, role)
+	} else {
+		action = "role_revoked"
+		// Refuses to remove the last admin
+		changed, err = s.userRepo.RemoveRole(ctx, userID, role)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !changed {
+		return user, nil
+	}
+
+	user, err = s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cache.DeletePattern(ctx, fmt.Sprintf("session:%s:*", userID)); err != nil {
+		log.Printf("[USER] Failed to end sessions of user %s after role change: %v", userID, err)
+	}
+
+	entry := &models.AuditLog{
+		UserID:     &actorID,
+		Action:     action,
+		EntityType: "user",
+		EntityID:   userID.String(),
+		Changes: models.JSONB{
+			"role":  role,
+			"roles": user.Roles,
+		},
+		Result: "success",
+	}
+	if err := s.auditRepo.Create(ctx, entry); err != nil {
+		log.Printf("[USER] Failed to audit %s of %q for user %s: %v", action, role, userID, err)
+	}
+
+	return user, nil
+}
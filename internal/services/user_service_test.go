@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"gorm.io/gorm/schema"
+
+	"github.com/vyve/vyve-backend/internal/models"
+	"github.com/vyve/vyve-backend/internal/repository"
+	"github.com/vyve/vyve-backend/pkg/analytics"
+)
+
+// userRowStore keeps one user row in memory. A full save overwrites the whole row, while
+// UpdateFields writes only the given columns, as the database would.
+type userRowStore struct {
+	*repository.MockUserRepository
+	row models.User
+	// afterRead changes the row once it has been read, as a concurrent save would
+	afterRead func(row *models.User)
+}
+
+func (s *userRowStore) FindByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	if id != s.row.ID {
+		return nil, repository.ErrUserNotFound
+	}
+	user := s.row
+	if s.afterRead != nil {
+		s.afterRead(&s.row)
+	}
+	return &user, nil
+}
+
+func (s *userRowStore) Update(ctx context.Context, user *models.User) error {
+	s.row = *user
+	return nil
+}
+
+func (s *userRowStore) UpdateFields(ctx context.Context, user *models.User, fields map[string]interface{}) error {
+	row := reflect.ValueOf(&s.row).Elem()
+	for i := 0; i < row.NumField(); i++ {
+		column := schema.NamingStrategy{}.ColumnName("", row.Type().Field(i).Name)
+		if value, ok := fields[column]; ok {
+			row.Field(i).Set(reflect.ValueOf(value))
+		}
+	}
+	return nil
+}
+
+func TestSelfServiceSavesKeepSuspension(t *testing.T) {
+	tests := []struct {
+		name  string
+		save  func(svc *userService, userID uuid.UUID) error
+		check func(row *models.User) bool
+	}{
+		{
+			name: "settings",
+			save: func(svc *userService, userID uuid.UUID) error {
+				return svc.UpdateSettings(context.Background(), userID, map[string]interface{}{"theme": "dark"})
+			},
+			check: func(row *models.User) bool { return row.Settings["theme"] == "dark" },
+		},
+		{
+			name: "profile",
+			save: func(svc *userService, userID uuid.UUID) error {
+				_, err := svc.Update(context.Background(), userID, map[string]interface{}{"bio": "Hello"})
+				return err
+			},
+			check: func(row *models.User) bool { return row.Bio == "Hello" },
+		},
+		{
+			name: "notification preferences",
+			save: func(svc *userService, userID uuid.UUID) error {
+				weekly := models.DigestWeekly
+				_, err := svc.UpdateNotificationPreferences(context.Background(), userID, NotificationPreferencesUpdate{DigestFrequency: &weekly})
+				return err
+			},
+			check: func(row *models.User) bool {
+				return row.NotificationPreferences != nil && row.NotificationPreferences.DigestFrequency == models.DigestWeekly
+			},
+		},
+		{
+			name: "onboarding",
+			save: func(svc *userService, userID uuid.UUID) error {
+				_, err := svc.UpdateOnboardingStatus(context.Background(), userID, true, "welcome")
+				return err
+			},
+			check: func(row *models.User) bool { return row.OnboardingCompleted && len(row.OnboardingSteps) == 1 },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			store := &userRowStore{
+				MockUserRepository: repository.NewMockUserRepository(ctrl),
+				row:                models.User{Base: models.Base{ID: uuid.New()}, Email: "sam@example.com"},
+			}
+			// An admin suspends the user while their save is in flight
+			suspendedAt := time.Now()
+			store.afterRead = func(row *models.User) {
+				row.SuspendedAt = &suspendedAt
+				row.SuspensionReason = "spam"
+			}
+			svc := &userService{userRepo: store, analytics: analytics.NewDatabaseAnalytics()}
+
+			if err := tt.save(svc, store.row.ID); err != nil {
+				t.Fatalf("save error = %v", err)
+			}
+			if !tt.check(&store.row) {
+				t.Errorf("the change was not saved: %+v", store.row)
+			}
+			if !store.row.Suspended() || store.row.SuspensionReason != "spam" {
+				t.Errorf("suspension overwritten: suspended at %v for %q", store.row.SuspendedAt, store.row.SuspensionReason)
+			}
+		})
+	}
+}
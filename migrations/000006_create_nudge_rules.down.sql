@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_nudge_rules_updated_at ON nudge_rules;
+DROP TABLE IF EXISTS nudge_rules;
@@ -0,0 +1,18 @@
+-- User-defined nudge rules written in the nudge rule DSL
+CREATE TABLE IF NOT EXISTS nudge_rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    expression TEXT NOT NULL,
+    enabled BOOLEAN DEFAULT true,
+    last_fired_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_nudge_rules_user_id ON nudge_rules(user_id);
+CREATE INDEX IF NOT EXISTS idx_nudge_rules_deleted_at ON nudge_rules(deleted_at);
+
+CREATE TRIGGER update_nudge_rules_updated_at BEFORE UPDATE ON nudge_rules
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_ai_analysis_jobs_heartbeat;
+DROP INDEX IF EXISTS idx_ai_analysis_jobs_queue;
+
+ALTER TABLE ai_analysis_jobs DROP COLUMN IF EXISTS cancelled_at;
+ALTER TABLE ai_analysis_jobs DROP COLUMN IF EXISTS heartbeat_at;
+ALTER TABLE ai_analysis_jobs DROP COLUMN IF EXISTS worker_id;
+ALTER TABLE ai_analysis_jobs DROP COLUMN IF EXISTS next_run_at;
+ALTER TABLE ai_analysis_jobs DROP COLUMN IF EXISTS max_attempts;
+ALTER TABLE ai_analysis_jobs DROP COLUMN IF EXISTS attempts;
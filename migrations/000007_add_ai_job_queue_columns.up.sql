@@ -0,0 +1,20 @@
+-- Turn ai_analysis_jobs into a durable work queue
+
+ALTER TABLE ai_analysis_jobs ADD COLUMN IF NOT EXISTS attempts INTEGER DEFAULT 0;
+ALTER TABLE ai_analysis_jobs ADD COLUMN IF NOT EXISTS max_attempts INTEGER DEFAULT 3;
+ALTER TABLE ai_analysis_jobs ADD COLUMN IF NOT EXISTS next_run_at TIMESTAMP;
+ALTER TABLE ai_analysis_jobs ADD COLUMN IF NOT EXISTS worker_id VARCHAR(255);
+ALTER TABLE ai_analysis_jobs ADD COLUMN IF NOT EXISTS heartbeat_at TIMESTAMP;
+ALTER TABLE ai_analysis_jobs ADD COLUMN IF NOT EXISTS cancelled_at TIMESTAMP;
+
+-- Claim query: pending jobs ordered by priority, then age
+CREATE INDEX IF NOT EXISTS idx_ai_analysis_jobs_queue
+    ON ai_analysis_jobs(priority DESC, created_at ASC)
+    WHERE status = 'pending' AND deleted_at IS NULL;
+
+-- Stale job reclaim
+CREATE INDEX IF NOT EXISTS idx_ai_analysis_jobs_heartbeat
+    ON ai_analysis_jobs(heartbeat_at)
+    WHERE status = 'processing';
+
+COMMENT ON COLUMN ai_analysis_jobs.status IS 'pending, processing, completed, failed, cancelled';
@@ -0,0 +1,40 @@
+-- Background jobs importing people and interactions from uploaded files
+CREATE TABLE IF NOT EXISTS import_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source VARCHAR(50) NOT NULL,
+    file_name VARCHAR(255),
+    file_key TEXT,
+    dry_run BOOLEAN DEFAULT false,
+    status VARCHAR(50) NOT NULL DEFAULT 'pending',
+    total_items INTEGER DEFAULT 0,
+    processed_items INTEGER DEFAULT 0,
+    created_items INTEGER DEFAULT 0,
+    skipped_items INTEGER DEFAULT 0,
+    failed_items INTEGER DEFAULT 0,
+    progress DECIMAL(5,2) DEFAULT 0,
+    result_data JSONB,
+    error TEXT,
+    attempts INTEGER DEFAULT 0,
+    max_attempts INTEGER DEFAULT 3,
+    worker_id VARCHAR(255),
+    heartbeat_at TIMESTAMP,
+    started_at TIMESTAMP,
+    completed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_import_jobs_user_id ON import_jobs(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_import_jobs_deleted_at ON import_jobs(deleted_at);
+
+-- Claim query
+CREATE INDEX IF NOT EXISTS idx_import_jobs_queue
+    ON import_jobs(created_at ASC)
+    WHERE status = 'pending' AND deleted_at IS NULL;
+
+CREATE TRIGGER update_import_jobs_updated_at BEFORE UPDATE ON import_jobs
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON COLUMN import_jobs.status IS 'pending, processing, completed, failed';
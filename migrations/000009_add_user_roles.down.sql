@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_users_roles;
+ALTER TABLE users DROP COLUMN IF EXISTS roles;
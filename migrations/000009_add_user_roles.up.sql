@@ -0,0 +1,4 @@
+-- Roles granted to a user, carried in access tokens and checked by RequireRole
+ALTER TABLE users ADD COLUMN IF NOT EXISTS roles TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_users_roles ON users USING GIN (roles);
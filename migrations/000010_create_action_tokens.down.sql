@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_action_tokens_updated_at ON action_tokens;
+DROP TABLE IF EXISTS action_tokens;
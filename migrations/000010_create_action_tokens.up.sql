@@ -0,0 +1,21 @@
+-- Single-use tokens sent by email (email verification, password reset). Only a
+-- SHA-256 hash of each token is stored.
+CREATE TABLE IF NOT EXISTS action_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    purpose VARCHAR(50) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    email VARCHAR(255),
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_action_tokens_user_purpose ON action_tokens(user_id, purpose);
+
+CREATE TRIGGER update_action_tokens_updated_at BEFORE UPDATE ON action_tokens
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON COLUMN action_tokens.purpose IS 'verify_email, password_reset';
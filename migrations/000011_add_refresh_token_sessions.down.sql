@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_refresh_tokens_user_session;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS signed_in_at;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS device_name;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS device_id;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS session_id;
@@ -0,0 +1,11 @@
+-- Group refresh tokens into sessions so users can list and sign out single devices
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS session_id VARCHAR(64);
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS device_id VARCHAR(255);
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS device_name VARCHAR(255);
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS signed_in_at TIMESTAMP;
+
+-- Tokens issued before sessions were tracked become single-token sessions
+UPDATE refresh_tokens SET session_id = id::text WHERE session_id IS NULL;
+UPDATE refresh_tokens SET signed_in_at = created_at WHERE signed_in_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_session ON refresh_tokens(user_id, session_id);
@@ -0,0 +1,11 @@
+-- Plaintext tokens cannot be recovered from their hashes; existing sessions are revoked
+-- and users have to sign in again.
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS token TEXT;
+UPDATE refresh_tokens SET token = token_hash, revoked = TRUE, revoked_at = COALESCE(revoked_at, NOW()) WHERE token IS NULL;
+ALTER TABLE refresh_tokens ALTER COLUMN token SET NOT NULL;
+ALTER TABLE refresh_tokens ADD CONSTRAINT refresh_tokens_token_key UNIQUE (token);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_token ON refresh_tokens(token);
+
+DROP INDEX IF EXISTS idx_refresh_tokens_token_hash;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS token_hash;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS used_at;
@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS update_mfa_recovery_codes_updated_at ON mfa_recovery_codes;
+DROP TABLE IF EXISTS mfa_recovery_codes;
+
+ALTER TABLE users DROP COLUMN IF EXISTS mfa_enabled_at;
+ALTER TABLE users DROP COLUMN IF EXISTS totp_secret;
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_passkeys_updated_at ON passkeys;
+DROP TABLE IF EXISTS passkeys;
@@ -0,0 +1,26 @@
+-- WebAuthn passkeys. Users may register several, one per device or password manager.
+CREATE TABLE IF NOT EXISTS passkeys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    credential_id TEXT NOT NULL,
+    public_key BYTEA NOT NULL,
+    sign_count BIGINT NOT NULL DEFAULT 0,
+    aaguid VARCHAR(36),
+    transports TEXT[],
+    name VARCHAR(100),
+    backup_eligible BOOLEAN DEFAULT false,
+    backed_up BOOLEAN DEFAULT false,
+    last_used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_passkeys_credential_id ON passkeys(credential_id);
+CREATE INDEX IF NOT EXISTS idx_passkeys_user_id ON passkeys(user_id);
+
+CREATE TRIGGER update_passkeys_updated_at BEFORE UPDATE ON passkeys
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON COLUMN passkeys.public_key IS 'COSE_Key from the authenticator, used to verify sign-in assertions';
+COMMENT ON COLUMN passkeys.sign_count IS 'Authenticator signature counter; a counter that goes backwards suggests a cloned credential';
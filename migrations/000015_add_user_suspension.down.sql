@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_users_suspended_at;
+
+ALTER TABLE users DROP COLUMN IF EXISTS suspension_reason;
+ALTER TABLE users DROP COLUMN IF EXISTS suspended_at;
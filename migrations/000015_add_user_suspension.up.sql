@@ -0,0 +1,7 @@
+-- Suspension of accounts by admins. Suspended users can't sign in.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS suspended_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS suspension_reason TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_users_suspended_at ON users(suspended_at) WHERE suspended_at IS NOT NULL;
+
+COMMENT ON COLUMN users.suspended_at IS 'Set while an admin has suspended the account';
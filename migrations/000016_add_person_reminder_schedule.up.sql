@@ -0,0 +1,5 @@
+-- Structured reminder schedules of people. reminder_frequency stays as an approximation
+-- of the schedule for nudges.
+ALTER TABLE people ADD COLUMN IF NOT EXISTS reminder_schedule JSONB;
+
+COMMENT ON COLUMN people.reminder_schedule IS 'When reminders recur: daily, weekly on weekdays, monthly on a day, every N days, or an RRULE';
@@ -0,0 +1,5 @@
+-- Notification preferences per channel and type, with quiet hours and the digest
+-- frequency. NULL until the user saves theirs; the defaults apply until then.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS notification_preferences JSONB;
+
+COMMENT ON COLUMN users.notification_preferences IS 'Notifications wanted per channel (push, email, in_app) and type, quiet hours and digest frequency';
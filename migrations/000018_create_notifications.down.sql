@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_notifications_updated_at ON notifications;
+DROP TABLE IF EXISTS notifications;
@@ -0,0 +1,39 @@
+-- Notifications sent to users. Push notifications wait here until the notification
+-- worker delivers them, and in-app notifications make up the user's inbox.
+CREATE TABLE IF NOT EXISTS notifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    channel VARCHAR(20) NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER DEFAULT 0,
+    max_attempts INTEGER DEFAULT 5,
+    next_attempt_at TIMESTAMP,
+    error TEXT,
+    sent_at TIMESTAMP,
+    opened_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id ON notifications(user_id, channel, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_notifications_deleted_at ON notifications(deleted_at);
+
+-- Claim query of the notification worker
+CREATE INDEX IF NOT EXISTS idx_notifications_outbox
+    ON notifications(next_attempt_at ASC)
+    WHERE status = 'pending' AND deleted_at IS NULL;
+
+-- Unread count of the inbox
+CREATE INDEX IF NOT EXISTS idx_notifications_unread
+    ON notifications(user_id)
+    WHERE channel = 'in_app' AND opened_at IS NULL AND deleted_at IS NULL;
+
+CREATE TRIGGER update_notifications_updated_at BEFORE UPDATE ON notifications
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON COLUMN notifications.channel IS 'push, email, in_app';
+COMMENT ON COLUMN notifications.status IS 'pending, sent, failed';
+COMMENT ON COLUMN notifications.next_attempt_at IS 'When the worker may next try a pending notification; pushed forward while an attempt is in flight';
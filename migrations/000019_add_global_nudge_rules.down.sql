@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_nudge_rules_global;
+DELETE FROM nudge_rules WHERE user_id IS NULL;
+ALTER TABLE nudge_rules ALTER COLUMN user_id SET NOT NULL;
+COMMENT ON COLUMN nudge_rules.user_id IS NULL;
@@ -0,0 +1,9 @@
+-- Global nudge rules are managed by admins and have no user. They are evaluated for
+-- every user after the user's own rules.
+ALTER TABLE nudge_rules ALTER COLUMN user_id DROP NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_nudge_rules_global
+    ON nudge_rules(created_at ASC)
+    WHERE user_id IS NULL AND enabled = true AND deleted_at IS NULL;
+
+COMMENT ON COLUMN nudge_rules.user_id IS 'Owner of the rule; NULL for global rules managed by admins';
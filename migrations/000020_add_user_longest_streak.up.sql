@@ -0,0 +1,3 @@
+-- Longest reflection streak, kept so streaks can be computed from recent reflections only.
+-- It is seeded from the full history the next time a user's streak is refreshed.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS longest_streak INTEGER NOT NULL DEFAULT 0;
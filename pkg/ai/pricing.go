@@ -0,0 +1,37 @@
+package ai
+
+import "strings"
+
+// modelPricing holds approximate blended USD prices per 1K tokens. Providers only report
+// total tokens, so input and output are priced at a single rate that assumes a typical
+// prompt-heavy analysis call. Keys are matched as model name prefixes, longest first.
+var modelPricing = []struct {
+	prefix string
+	price  float64
+}{
+	{"gpt-4o-mini", 0.0003},
+	{"gpt-4o", 0.005},
+	{"gpt-4-turbo", 0.015},
+	{"gpt-4", 0.04},
+	{"gpt-3.5-turbo", 0.001},
+	{"claude-3-5-haiku", 0.0016},
+	{"claude-3-5-sonnet", 0.006},
+	{"claude-3-haiku", 0.0005},
+	{"claude-3-sonnet", 0.006},
+	{"claude-3-opus", 0.03},
+}
+
+// defaultPricePer1K is used for models that are not in the table
+const defaultPricePer1K = 0.005
+
+// EstimateCost estimates the USD cost of a call from the model name and tokens used
+func EstimateCost(model string, tokens int) float64 {
+	price := defaultPricePer1K
+	for _, p := range modelPricing {
+		if strings.HasPrefix(model, p.prefix) {
+			price = p.price
+			break
+		}
+	}
+	return float64(tokens) / 1000 * price
+}
@@ -62,6 +62,7 @@ const (
 	EventNotificationSent      = "notification_sent"
 	EventNotificationOpened    = "notification_opened"
 	EventDataExported          = "data_exported"
+	EventDataImported          = "data_imported"
 	EventSessionStarted        = "session_started"
 	EventSessionEnded          = "session_ended"
 	EventProfileUpdated        = "profile_updated"
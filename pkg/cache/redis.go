@@ -16,11 +16,14 @@ type Cache interface {
 	Get(ctx context.Context, key string, dest interface{}) error
 	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
 	Delete(ctx context.Context, key string) error
+	GetDel(ctx context.Context, key string, dest interface{}) error
 	DeletePattern(ctx context.Context, pattern string) error
 	Exists(ctx context.Context, key string) (bool, error)
 	Expire(ctx context.Context, key string, expiration time.Duration) error
 	TTL(ctx context.Context, key string) (time.Duration, error)
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error)
 	Increment(ctx context.Context, key string) (int64, error)
+	IncrementWithTTL(ctx context.Context, key string, expiration time.Duration) (int64, error)
 	Decrement(ctx context.Context, key string) (int64, error)
 	
 	// List operations
@@ -123,6 +126,19 @@ func (c *redisCache) Delete(ctx context.Context, key string) error {
 	return c.client.Del(ctx, key).Err()
 }
 
+// GetDel retrieves a value and removes its key in one step, so only one caller gets it
+func (c *redisCache) GetDel(ctx context.Context, key string, dest interface{}) error {
+	val, err := c.client.GetDel(ctx, key).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return ErrCacheMiss
+		}
+		return err
+	}
+	
+	return json.Unmarshal([]byte(val), dest)
+}
+
 // DeletePattern deletes keys matching pattern
 func (c *redisCache) DeletePattern(ctx context.Context, pattern string) error {
 	iter := c.client.Scan(ctx, 0, pattern, 0).Iterator()
@@ -162,11 +178,37 @@ func (c *redisCache) TTL(ctx context.Context, key string) (time.Duration, error)
 	return c.client.TTL(ctx, key).Result()
 }
 
+// SetNX stores a value only if the key does not exist and reports whether it was stored
+func (c *redisCache) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false, err
+	}
+
+	return c.client.SetNX(ctx, key, data, expiration).Result()
+}
+
 // Increment increments a counter
 func (c *redisCache) Increment(ctx context.Context, key string) (int64, error) {
 	return c.client.Incr(ctx, key).Result()
 }
 
+// incrementWithTTL increments a counter and sets its expiration when the counter is new
+// or has none, in a single step
+var incrementWithTTL = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 or redis.call("PTTL", KEYS[1]) == -1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return count
+`)
+
+// IncrementWithTTL increments a counter whose expiration starts with the first
+// increment. The counter can never be left without an expiration.
+func (c *redisCache) IncrementWithTTL(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	return incrementWithTTL.Run(ctx, c.client, []string{key}, expiration.Milliseconds()).Int64()
+}
+
 // Decrement decrements a counter
 func (c *redisCache) Decrement(ctx context.Context, key string) (int64, error) {
 	return c.client.Decr(ctx, key).Result()
@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestCache(t *testing.T) (*redisCache, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return &redisCache{client: client}, mr
+}
+
+func TestIncrementWithTTL(t *testing.T) {
+	c, mr := newTestCache(t)
+	ctx := context.Background()
+
+	for want := int64(1); want <= 3; want++ {
+		got, err := c.IncrementWithTTL(ctx, "counter", time.Minute)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != want {
+			t.Fatalf("IncrementWithTTL() = %d, want %d", got, want)
+		}
+	}
+	if ttl := mr.TTL("counter"); ttl != time.Minute {
+		t.Errorf("TTL = %s, want the window to start with the first increment", ttl)
+	}
+
+	// A counter left without an expiration gets one on its next increment
+	mr.Set("stuck", "7")
+	if _, err := c.IncrementWithTTL(ctx, "stuck", time.Minute); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ttl := mr.TTL("stuck"); ttl != time.Minute {
+		t.Errorf("TTL of stuck counter = %s, want %s", ttl, time.Minute)
+	}
+}
+
+func TestSetNX(t *testing.T) {
+	c, mr := newTestCache(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"first", true},
+		{"while set", false},
+	}
+	for _, tt := range tests {
+		stored, err := c.SetNX(ctx, "cooldown", true, time.Minute)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if stored != tt.want {
+			t.Errorf("%s: SetNX() = %v, want %v", tt.name, stored, tt.want)
+		}
+	}
+	if ttl := mr.TTL("cooldown"); ttl != time.Minute {
+		t.Errorf("TTL = %s, want %s", ttl, time.Minute)
+	}
+
+	mr.FastForward(time.Minute)
+	if stored, err := c.SetNX(ctx, "cooldown", true, time.Minute); err != nil || !stored {
+		t.Errorf("SetNX() after expiry = %v, %v, want true", stored, err)
+	}
+}
@@ -0,0 +1,166 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/vyve/vyve-backend/internal/config"
+)
+
+// Mailer defines the email delivery interface
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Message represents an email with a plain text and an HTML body
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Mailer drivers
+const (
+	DriverSMTP = "smtp"
+	DriverLog  = "log"
+	DriverFile = "file"
+)
+
+// NewMailer creates the mailer selected by cfg.Driver
+func NewMailer(cfg config.EmailConfig) (Mailer, error) {
+	switch cfg.Driver {
+	case DriverSMTP, "":
+		return NewSMTPMailer(cfg)
+	case DriverLog:
+		return NewLogMailer(), nil
+	case DriverFile:
+		return NewFileMailer(cfg.From, cfg.OutboxDir)
+	default:
+		return nil, fmt.Errorf("unknown email driver %q", cfg.Driver)
+	}
+}
+
+// LogMailer writes emails to the application log instead of sending them
+type LogMailer struct{}
+
+// NewLogMailer creates a new log mailer
+func NewLogMailer() Mailer {
+	return &LogMailer{}
+}
+
+// Send logs the recipient, subject and text body of an email
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	log.Printf("[EMAIL] To: %s | Subject: %s\n%s", msg.To, msg.Subject, msg.Text)
+	return nil
+}
+
+// FileMailer writes each email as an .eml file to a directory, which can be opened
+// in a mail client or inspected by tests
+type FileMailer struct {
+	from string
+	dir  string
+}
+
+// NewFileMailer creates a new file mailer writing to dir
+func NewFileMailer(from, dir string) (Mailer, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("email outbox directory is not set")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create email outbox: %w", err)
+	}
+	return &FileMailer{from: from, dir: dir}, nil
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// Send writes the email to <dir>/<timestamp>-<recipient>.eml
+func (m *FileMailer) Send(ctx context.Context, msg Message) error {
+	raw, err := buildMessage(m.from, msg)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().UTC().Format("20060102T150405.000000000"), unsafeFileChars.ReplaceAllString(msg.To, "_"))
+	return os.WriteFile(filepath.Join(m.dir, name), raw, 0o644)
+}
+
+// buildMessage encodes an email as a multipart/alternative MIME message
+func buildMessage(from string, msg Message) ([]byte, error) {
+	if _, err := mail.ParseAddress(msg.To); err != nil {
+		return nil, fmt.Errorf("invalid recipient %q: %w", msg.To, err)
+	}
+
+	boundary, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+	messageID, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+	domain := "localhost"
+	if addr, err := mail.ParseAddress(from); err == nil {
+		if at := strings.LastIndex(addr.Address, "@"); at >= 0 {
+			domain = addr.Address[at+1:]
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@%s>\r\n", messageID, domain)
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", boundary)
+
+	parts := []struct {
+		contentType string
+		body        string
+	}{
+		{"text/plain", msg.Text},
+		{"text/html", msg.HTML},
+	}
+	for _, part := range parts {
+		if part.body == "" {
+			continue
+		}
+		fmt.Fprintf(&buf, "--%s\r\n", boundary)
+		fmt.Fprintf(&buf, "Content-Type: %s; charset=utf-8\r\n", part.contentType)
+		buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+		qp := quotedprintable.NewWriter(&buf)
+		if _, err := qp.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+		buf.WriteString("\r\n")
+	}
+	fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+
+	return buf.Bytes(), nil
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
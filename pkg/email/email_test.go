@@ -0,0 +1,130 @@
+package email
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderLocalizesAndFallsBack(t *testing.T) {
+	data := Data{"Name": "Ana", "Link": "https://example.com/verify?token=a&b", "ExpiresIn": 24}
+
+	msg, err := Render(TemplateVerifyEmail, "es-MX", "ana@example.com", data)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if msg.Subject != translations["es"]["verify_email.subject"] {
+		t.Errorf("subject = %q, want the Spanish subject", msg.Subject)
+	}
+	if !strings.Contains(msg.Text, "Hola Ana:") || !strings.Contains(msg.Text, "24 horas") {
+		t.Errorf("text body is not localized:\n%s", msg.Text)
+	}
+	if !strings.Contains(msg.HTML, "https://example.com/verify?token=a&amp;b") {
+		t.Errorf("HTML body does not contain the escaped link:\n%s", msg.HTML)
+	}
+
+	msg, err = Render(TemplatePasswordReset, "ja", "ana@example.com", data)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if msg.Subject != translations[DefaultLocale]["password_reset.subject"] {
+		t.Errorf("subject = %q, want the default locale subject", msg.Subject)
+	}
+}
+
+func TestRenderAccountLocked(t *testing.T) {
+	data := Data{"Name": "Ana", "Link": "https://example.com/reset?token=abc", "Minutes": 30, "ExpiresIn": 60}
+
+	msg, err := Render(TemplateAccountLocked, "de", "ana@example.com", data)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if !strings.Contains(msg.Text, "30 Minuten") || !strings.Contains(msg.Text, "https://example.com/reset?token=abc") {
+		t.Errorf("text body is missing the lock duration or link:\n%s", msg.Text)
+	}
+}
+
+func TestTranslationsAreComplete(t *testing.T) {
+	for locale, messages := range translations {
+		for key := range translations[DefaultLocale] {
+			if _, ok := messages[key]; !ok {
+				t.Errorf("locale %q is missing %q", locale, key)
+			}
+		}
+	}
+}
+
+func TestFileMailerWritesMIMEMessage(t *testing.T) {
+	dir := t.TempDir()
+	mailer, err := NewFileMailer("Vyve <noreply@vyve.app>", dir)
+	if err != nil {
+		t.Fatalf("NewFileMailer: %v", err)
+	}
+
+	msg, err := Render(TemplatePasswordReset, "fr", "ana@example.com", Data{"Name": "Ana", "Link": "https://example.com/reset", "ExpiresIn": 60})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if err := mailer.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if len(files) != 1 {
+		t.Fatalf("expected one .eml file, got %d", len(files))
+	}
+	f, err := os.Open(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	parsed, err := mail.ReadMessage(f)
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if err != nil || subject != msg.Subject {
+		t.Errorf("subject = %q (%v), want %q", subject, err, msg.Subject)
+	}
+
+	_, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("Content-Type: %v", err)
+	}
+	reader := multipart.NewReader(parsed.Body, params["boundary"])
+	var types []string
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("NextPart: %v", err)
+		}
+		body, _ := io.ReadAll(part)
+		if !strings.Contains(string(body), "https://example.com/reset") {
+			t.Errorf("%s part does not contain the link", part.Header.Get("Content-Type"))
+		}
+		types = append(types, part.Header.Get("Content-Type"))
+	}
+	if len(types) != 2 {
+		t.Errorf("expected text and HTML parts, got %v", types)
+	}
+}
+
+func TestSendRejectsInvalidRecipient(t *testing.T) {
+	mailer, err := NewFileMailer("noreply@vyve.app", t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mailer.Send(context.Background(), Message{To: "not an address", Text: "hi"}); err == nil {
+		t.Error("expected an error for an invalid recipient")
+	}
+}
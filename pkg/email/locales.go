@@ -0,0 +1,102 @@
+package email
+
+// translations holds the email copy per locale. Every locale should define the keys of
+// DefaultLocale; missing keys fall back to it.
+var translations = map[string]map[string]string{
+	"en": {
+		"greeting":      "Hi %s,",
+		"link_fallback": "If the button doesn't work, copy this link into your browser:",
+		"footer":        "You're receiving this email because of your Vyve account.",
+
+		"verify_email.subject": "Confirm your email address",
+		"verify_email.intro":   "Thanks for joining Vyve! Please confirm your email address to finish setting up your account.",
+		"verify_email.action":  "Confirm email",
+		"verify_email.expiry":  "This link expires in %d hours.",
+		"verify_email.ignore":  "If you didn't create a Vyve account, you can safely ignore this email.",
+
+		"password_reset.subject": "Reset your Vyve password",
+		"password_reset.intro":   "We received a request to reset your password. Use the link below to choose a new one.",
+		"password_reset.action":  "Reset password",
+		"password_reset.expiry":  "This link expires in %d minutes and can only be used once.",
+		"password_reset.ignore":  "If you didn't ask to reset your password, you can ignore this email; your password won't change.",
+
+		"account_locked.subject":    "Your Vyve account was locked",
+		"account_locked.intro":      "There were several failed attempts to sign in to your account, so we've paused signing in with your password for %d minutes.",
+		"account_locked.if_not_you": "If this wasn't you, someone may be trying to guess your password. Reset it to keep your account safe.",
+		"account_locked.action":     "Reset password",
+		"account_locked.expiry":     "This link expires in %d minutes and can only be used once.",
+		"account_locked.if_you":     "If it was you, you can try again once the lock ends.",
+	},
+	"es": {
+		"greeting":      "Hola %s:",
+		"link_fallback": "Si el botón no funciona, copia este enlace en tu navegador:",
+		"footer":        "Recibes este correo por tu cuenta de Vyve.",
+
+		"verify_email.subject": "Confirma tu dirección de correo",
+		"verify_email.intro":   "¡Gracias por unirte a Vyve! Confirma tu dirección de correo para terminar de configurar tu cuenta.",
+		"verify_email.action":  "Confirmar correo",
+		"verify_email.expiry":  "Este enlace caduca en %d horas.",
+		"verify_email.ignore":  "Si no creaste una cuenta de Vyve, puedes ignorar este correo.",
+
+		"password_reset.subject": "Restablece tu contraseña de Vyve",
+		"password_reset.intro":   "Recibimos una solicitud para restablecer tu contraseña. Usa el enlace de abajo para elegir una nueva.",
+		"password_reset.action":  "Restablecer contraseña",
+		"password_reset.expiry":  "Este enlace caduca en %d minutos y solo se puede usar una vez.",
+		"password_reset.ignore":  "Si no solicitaste restablecer tu contraseña, ignora este correo; tu contraseña no cambiará.",
+
+		"account_locked.subject":    "Tu cuenta de Vyve se ha bloqueado",
+		"account_locked.intro":      "Hubo varios intentos fallidos de iniciar sesión en tu cuenta, así que hemos pausado el inicio de sesión con contraseña durante %d minutos.",
+		"account_locked.if_not_you": "Si no fuiste tú, alguien podría estar intentando adivinar tu contraseña. Restablécela para proteger tu cuenta.",
+		"account_locked.action":     "Restablecer contraseña",
+		"account_locked.expiry":     "Este enlace caduca en %d minutos y solo se puede usar una vez.",
+		"account_locked.if_you":     "Si fuiste tú, puedes volver a intentarlo cuando termine el bloqueo.",
+	},
+	"fr": {
+		"greeting":      "Bonjour %s,",
+		"link_fallback": "Si le bouton ne fonctionne pas, copiez ce lien dans votre navigateur :",
+		"footer":        "Vous recevez cet e-mail en raison de votre compte Vyve.",
+
+		"verify_email.subject": "Confirmez votre adresse e-mail",
+		"verify_email.intro":   "Merci d'avoir rejoint Vyve ! Confirmez votre adresse e-mail pour terminer la configuration de votre compte.",
+		"verify_email.action":  "Confirmer l'adresse",
+		"verify_email.expiry":  "Ce lien expire dans %d heures.",
+		"verify_email.ignore":  "Si vous n'avez pas créé de compte Vyve, vous pouvez ignorer cet e-mail.",
+
+		"password_reset.subject": "Réinitialisez votre mot de passe Vyve",
+		"password_reset.intro":   "Nous avons reçu une demande de réinitialisation de votre mot de passe. Utilisez le lien ci-dessous pour en choisir un nouveau.",
+		"password_reset.action":  "Réinitialiser le mot de passe",
+		"password_reset.expiry":  "Ce lien expire dans %d minutes et ne peut être utilisé qu'une seule fois.",
+		"password_reset.ignore":  "Si vous n'avez pas demandé de réinitialisation, ignorez cet e-mail ; votre mot de passe ne changera pas.",
+
+		"account_locked.subject":    "Votre compte Vyve a été verrouillé",
+		"account_locked.intro":      "Plusieurs tentatives de connexion à votre compte ont échoué, nous avons donc suspendu la connexion par mot de passe pendant %d minutes.",
+		"account_locked.if_not_you": "Si ce n'était pas vous, quelqu'un essaie peut-être de deviner votre mot de passe. Réinitialisez-le pour protéger votre compte.",
+		"account_locked.action":     "Réinitialiser le mot de passe",
+		"account_locked.expiry":     "Ce lien expire dans %d minutes et ne peut être utilisé qu'une seule fois.",
+		"account_locked.if_you":     "Si c'était vous, vous pourrez réessayer à la fin du verrouillage.",
+	},
+	"de": {
+		"greeting":      "Hallo %s,",
+		"link_fallback": "Falls die Schaltfläche nicht funktioniert, kopiere diesen Link in deinen Browser:",
+		"footer":        "Du erhältst diese E-Mail aufgrund deines Vyve-Kontos.",
+
+		"verify_email.subject": "Bestätige deine E-Mail-Adresse",
+		"verify_email.intro":   "Danke, dass du Vyve beigetreten bist! Bitte bestätige deine E-Mail-Adresse, um dein Konto fertig einzurichten.",
+		"verify_email.action":  "E-Mail bestätigen",
+		"verify_email.expiry":  "Dieser Link läuft in %d Stunden ab.",
+		"verify_email.ignore":  "Wenn du kein Vyve-Konto erstellt hast, kannst du diese E-Mail ignorieren.",
+
+		"password_reset.subject": "Setze dein Vyve-Passwort zurück",
+		"password_reset.intro":   "Wir haben eine Anfrage zum Zurücksetzen deines Passworts erhalten. Über den Link unten kannst du ein neues wählen.",
+		"password_reset.action":  "Passwort zurücksetzen",
+		"password_reset.expiry":  "Dieser Link läuft in %d Minuten ab und kann nur einmal verwendet werden.",
+		"password_reset.ignore":  "Wenn du das nicht angefordert hast, ignoriere diese E-Mail; dein Passwort bleibt unverändert.",
+
+		"account_locked.subject":    "Dein Vyve-Konto wurde gesperrt",
+		"account_locked.intro":      "Es gab mehrere fehlgeschlagene Anmeldeversuche bei deinem Konto, deshalb haben wir die Anmeldung mit Passwort für %d Minuten pausiert.",
+		"account_locked.if_not_you": "Wenn du das nicht warst, versucht vielleicht jemand, dein Passwort zu erraten. Setze es zurück, um dein Konto zu schützen.",
+		"account_locked.action":     "Passwort zurücksetzen",
+		"account_locked.expiry":     "Dieser Link läuft in %d Minuten ab und kann nur einmal verwendet werden.",
+		"account_locked.if_you":     "Wenn du es warst, kannst du es nach Ablauf der Sperre erneut versuchen.",
+	},
+}
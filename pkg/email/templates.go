@@ -0,0 +1,96 @@
+package email
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+// Template identifies a transactional email
+type Template string
+
+// Email templates
+const (
+	TemplateVerifyEmail   Template = "verify_email"
+	TemplatePasswordReset Template = "password_reset"
+	TemplateAccountLocked Template = "account_locked"
+)
+
+// DefaultLocale is used when the user's locale has no translations
+const DefaultLocale = "en"
+
+// Data holds the values a template refers to, e.g. .Name and .Link
+type Data map[string]interface{}
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+// Render renders a localized email for a recipient. Locales such as "pt-BR" fall back
+// to their base language and then to DefaultLocale.
+func Render(tmpl Template, locale, to string, data Data) (Message, error) {
+	locale = resolveLocale(locale)
+	funcs := map[string]interface{}{
+		"t": func(key string, args ...interface{}) string {
+			return translate(locale, key, args...)
+		},
+	}
+
+	name := string(tmpl)
+	htmlTmpl, err := htmltemplate.New("layout.html.tmpl").Funcs(funcs).
+		ParseFS(templateFS, "templates/layout.html.tmpl", "templates/"+name+".html.tmpl")
+	if err != nil {
+		return Message{}, fmt.Errorf("failed to parse %s HTML template: %w", name, err)
+	}
+	textTmpl, err := texttemplate.New("layout.txt.tmpl").Funcs(funcs).
+		ParseFS(templateFS, "templates/layout.txt.tmpl", "templates/"+name+".txt.tmpl")
+	if err != nil {
+		return Message{}, fmt.Errorf("failed to parse %s text template: %w", name, err)
+	}
+
+	var html, text bytes.Buffer
+	if err := htmlTmpl.Execute(&html, data); err != nil {
+		return Message{}, fmt.Errorf("failed to render %s HTML template: %w", name, err)
+	}
+	if err := textTmpl.Execute(&text, data); err != nil {
+		return Message{}, fmt.Errorf("failed to render %s text template: %w", name, err)
+	}
+
+	return Message{
+		To:      to,
+		Subject: translate(locale, name+".subject"),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
+
+// resolveLocale returns the closest locale that has translations
+func resolveLocale(locale string) string {
+	locale = strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+	if _, ok := translations[locale]; ok {
+		return locale
+	}
+	if base, _, found := strings.Cut(locale, "-"); found {
+		if _, ok := translations[base]; ok {
+			return base
+		}
+	}
+	return DefaultLocale
+}
+
+// translate looks up a message, falling back to DefaultLocale, and formats it with args
+func translate(locale, key string, args ...interface{}) string {
+	msg, ok := translations[locale][key]
+	if !ok {
+		msg, ok = translations[DefaultLocale][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
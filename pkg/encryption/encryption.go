@@ -0,0 +1,225 @@
+// Package encryption provides AES-256-GCM encryption for sensitive database fields.
+//
+// Encrypted values are stored as text in the form
+//
+//	enc:<key version>:<base64(nonce || ciphertext)>
+//
+// so that encrypted and legacy plaintext values can be told apart and keys can be
+// rotated: values are always written with the active key, and any configured
+// previous key can still decrypt them until the rows are re-encrypted.
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	cfg "github.com/vyve/vyve-backend/internal/config"
+)
+
+const prefix = "enc:"
+
+var (
+	// ErrInvalidKey is returned when a key is not 32 bytes encoded as base64 or hex
+	ErrInvalidKey = errors.New("encryption key must be 32 bytes, base64 or hex encoded")
+	// ErrMalformed is returned when an encrypted value cannot be parsed
+	ErrMalformed = errors.New("malformed encrypted value")
+	// ErrUnknownKey is returned when a value was encrypted with a key that is not configured
+	ErrUnknownKey = errors.New("value was encrypted with an unknown key")
+)
+
+// Encryptor encrypts and decrypts field values
+type Encryptor interface {
+	Encrypt(plaintext string) (string, error)
+	Decrypt(value string) (string, error)
+}
+
+// Keyring implements Encryptor with AES-256-GCM and versioned keys
+type Keyring struct {
+	active int
+	keys   map[int]cipher.AEAD
+}
+
+// New creates a keyring from the encryption config. It returns nil when encryption
+// is disabled.
+func New(c cfg.EncryptionConfig) (Encryptor, error) {
+	if !c.Enabled {
+		return nil, nil
+	}
+	k, err := NewKeyringFromConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// NewKeyringFromConfig creates a keyring from the encryption config regardless of
+// whether encryption is enabled
+func NewKeyringFromConfig(c cfg.EncryptionConfig) (*Keyring, error) {
+	version := c.KeyVersion
+	if version < 1 {
+		version = 1
+	}
+
+	keys := map[int]string{version: c.Key}
+	for _, pair := range c.PreviousKeys {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		v, key, ok := strings.Cut(pair, ":")
+		n, err := strconv.Atoi(v)
+		if !ok || err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid previous key %q, expected version:key", v)
+		}
+		if n == version {
+			return nil, fmt.Errorf("previous key version %d is the active version", n)
+		}
+		keys[n] = key
+	}
+
+	return NewKeyring(version, keys)
+}
+
+// NewKeyring creates a keyring that encrypts with the active key version and can
+// decrypt with any of the given keys
+func NewKeyring(active int, keys map[int]string) (*Keyring, error) {
+	k := &Keyring{active: active, keys: make(map[int]cipher.AEAD, len(keys))}
+	for version, key := range keys {
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, fmt.Errorf("key version %d: %w", version, err)
+		}
+		k.keys[version] = aead
+	}
+	if _, ok := k.keys[active]; !ok {
+		return nil, fmt.Errorf("active key version %d is not configured", active)
+	}
+	return k, nil
+}
+
+// NewAESGCM creates a keyring with a single key as version 1
+func NewAESGCM(key string) (*Keyring, error) {
+	return NewKeyring(1, map[int]string{1: key})
+}
+
+// ActiveVersion returns the version of the key new values are encrypted with
+func (k *Keyring) ActiveVersion() int {
+	return k.active
+}
+
+// Encrypt encrypts a value with the active key. Empty strings are left as is.
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+
+	aead := k.keys[k.active]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), nil)
+
+	return prefix + strconv.Itoa(k.active) + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a value with the key it was encrypted with. Values that are not
+// encrypted are returned unchanged so rows written before encryption was enabled
+// stay readable.
+func (k *Keyring) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	version, sealed, err := parse(value)
+	if err != nil {
+		return "", err
+	}
+	aead, ok := k.keys[version]
+	if !ok {
+		return "", fmt.Errorf("%w (version %d)", ErrUnknownKey, version)
+	}
+
+	nonceSize := aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return "", ErrMalformed
+	}
+	plaintext, err := aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return "", fmt.Errorf("decrypt: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// NeedsRotation reports whether a stored value is plaintext or encrypted with a key
+// other than the active one
+func (k *Keyring) NeedsRotation(value string) bool {
+	if value == "" {
+		return false
+	}
+	if !IsEncrypted(value) {
+		return true
+	}
+	version, _, err := parse(value)
+	return err != nil || version != k.active
+}
+
+// Rotate re-encrypts a stored value with the active key
+func (k *Keyring) Rotate(value string) (string, error) {
+	plaintext, err := k.Decrypt(value)
+	if err != nil {
+		return "", err
+	}
+	return k.Encrypt(plaintext)
+}
+
+// IsEncrypted reports whether a value looks like an encrypted value
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+func parse(value string) (int, []byte, error) {
+	parts := strings.SplitN(strings.TrimPrefix(value, prefix), ":", 2)
+	if len(parts) != 2 {
+		return 0, nil, ErrMalformed
+	}
+	version, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, nil, ErrMalformed
+	}
+	sealed, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return 0, nil, ErrMalformed
+	}
+	return version, sealed, nil
+}
+
+func newAEAD(key string) (cipher.AEAD, error) {
+	raw, err := decodeKey(key)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func decodeKey(key string) ([]byte, error) {
+	key = strings.TrimSpace(key)
+	if raw, err := base64.StdEncoding.DecodeString(key); err == nil && len(raw) == 32 {
+		return raw, nil
+	}
+	if raw, err := hex.DecodeString(key); err == nil && len(raw) == 32 {
+		return raw, nil
+	}
+	return nil, ErrInvalidKey
+}
@@ -0,0 +1,109 @@
+package encryption
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	cfg "github.com/vyve/vyve-backend/internal/config"
+)
+
+func newKey(t *testing.T) string {
+	t.Helper()
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+func TestRoundTrip(t *testing.T) {
+	e, err := NewAESGCM(newKey(t))
+	if err != nil {
+		t.Fatalf("NewAESGCM: %v", err)
+	}
+
+	encrypted, err := e.Encrypt("I felt heard today")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if !strings.HasPrefix(encrypted, "enc:1:") || strings.Contains(encrypted, "heard") {
+		t.Fatalf("unexpected ciphertext %q", encrypted)
+	}
+
+	again, _ := e.Encrypt("I felt heard today")
+	if again == encrypted {
+		t.Fatal("expected a fresh nonce for every encryption")
+	}
+
+	plaintext, err := e.Decrypt(encrypted)
+	if err != nil || plaintext != "I felt heard today" {
+		t.Fatalf("Decrypt = %q, %v", plaintext, err)
+	}
+
+	// Legacy plaintext rows stay readable
+	if plaintext, err := e.Decrypt("written before encryption"); err != nil || plaintext != "written before encryption" {
+		t.Fatalf("Decrypt(plaintext) = %q, %v", plaintext, err)
+	}
+}
+
+func TestDecryptErrors(t *testing.T) {
+	e, _ := NewAESGCM(newKey(t))
+	other, _ := NewAESGCM(newKey(t))
+
+	encrypted, _ := other.Encrypt("secret")
+	if _, err := e.Decrypt(encrypted); err == nil {
+		t.Fatal("expected an error when decrypting with the wrong key")
+	}
+	if _, err := e.Decrypt("enc:1:not-base64!"); !errors.Is(err, ErrMalformed) {
+		t.Fatalf("expected ErrMalformed, got %v", err)
+	}
+	if _, err := NewAESGCM("too-short"); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected ErrInvalidKey, got %v", err)
+	}
+	if e, err := New(cfg.EncryptionConfig{Enabled: false}); e != nil || err != nil {
+		t.Fatalf("expected no encryptor when disabled, got %v, %v", e, err)
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	oldSecret, newSecret := newKey(t), newKey(t)
+
+	old, err := NewKeyringFromConfig(cfg.EncryptionConfig{Key: oldSecret, KeyVersion: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, _ := old.Encrypt("notes about Sam")
+
+	rotated, err := NewKeyringFromConfig(cfg.EncryptionConfig{
+		Key:          newSecret,
+		KeyVersion:   2,
+		PreviousKeys: []string{"1:" + oldSecret},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if plaintext, err := rotated.Decrypt(stored); err != nil || plaintext != "notes about Sam" {
+		t.Fatalf("Decrypt with previous key = %q, %v", plaintext, err)
+	}
+	if !rotated.NeedsRotation(stored) || !rotated.NeedsRotation("plaintext") || rotated.NeedsRotation("") {
+		t.Fatal("unexpected NeedsRotation result")
+	}
+
+	fresh, err := rotated.Rotate(stored)
+	if err != nil || !strings.HasPrefix(fresh, "enc:2:") || rotated.NeedsRotation(fresh) {
+		t.Fatalf("Rotate = %q, %v", fresh, err)
+	}
+
+	// Once the old key is dropped, old values are reported as such
+	current, err := NewKeyring(2, map[int]string{2: newSecret})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := current.Decrypt(stored); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("expected ErrUnknownKey, got %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package notifications
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -16,7 +17,7 @@ import (
 // NotificationService defines the notification service interface
 type NotificationService interface {
 	SendPushNotification(ctx context.Context, token string, notification Notification) error
-	SendBatchNotifications(ctx context.Context, tokens []string, notification Notification) error
+	SendBatchNotifications(ctx context.Context, tokens []string, notification Notification) (*BatchResult, error)
 	SendTopicNotification(ctx context.Context, topic string, notification Notification) error
 	SubscribeToTopic(ctx context.Context, tokens []string, topic string) error
 	UnsubscribeFromTopic(ctx context.Context, tokens []string, topic string) error
@@ -33,6 +34,25 @@ type Notification struct {
 	Sound    string                 `json:"sound,omitempty"`
 }
 
+// ErrInvalidToken is returned for a device token FCM rejects as unregistered or
+// malformed. The token will never work again and should be deactivated.
+var ErrInvalidToken = errors.New("invalid push token")
+
+// BatchResult reports which tokens a batch notification reached
+type BatchResult struct {
+	// Delivered are the tokens FCM accepted the notification for
+	Delivered []string
+	// Invalid are the tokens FCM rejected as unregistered or malformed
+	Invalid []string
+	// Failed counts the other tokens the notification didn't reach, which may work later
+	Failed int
+}
+
+// isInvalidToken reports whether FCM rejected a message because of its token
+func isInvalidToken(err error) bool {
+	return messaging.IsUnregistered(err) || messaging.IsInvalidArgument(err)
+}
+
 // FCMService implements NotificationService using Firebase Cloud Messaging
 type FCMService struct {
 	client *messaging.Client
@@ -101,6 +121,9 @@ func (f *FCMService) SendPushNotification(ctx context.Context, token string, not
 
 	response, err := f.client.Send(ctx, message)
 	if err != nil {
+		if isInvalidToken(err) {
+			return fmt.Errorf("%w: %v", ErrInvalidToken, err)
+		}
 		return fmt.Errorf("failed to send notification: %w", err)
 	}
 
@@ -108,8 +131,9 @@ func (f *FCMService) SendPushNotification(ctx context.Context, token string, not
 	return nil
 }
 
-// SendBatchNotifications sends notifications to multiple devices
-func (f *FCMService) SendBatchNotifications(ctx context.Context, tokens []string, notification Notification) error {
+// SendBatchNotifications sends notifications to multiple devices and reports which
+// tokens they reached
+func (f *FCMService) SendBatchNotifications(ctx context.Context, tokens []string, notification Notification) (*BatchResult, error) {
 	messages := []*messaging.Message{}
 	
 	for _, token := range tokens {
@@ -140,6 +164,7 @@ func (f *FCMService) SendBatchNotifications(ctx context.Context, tokens []string
 	}
 
 	// Send batch (max 500 messages per batch)
+	result := &BatchResult{}
 	batchSize := 500
 	for i := 0; i < len(messages); i += batchSize {
 		end := i + batchSize
@@ -148,24 +173,28 @@ func (f *FCMService) SendBatchNotifications(ctx context.Context, tokens []string
 		}
 
 		batch := messages[i:end]
-		response, err := f.client.SendAll(ctx, batch)
+		response, err := f.client.SendEach(ctx, batch)
 		if err != nil {
-			return fmt.Errorf("failed to send batch notifications: %w", err)
+			return result, fmt.Errorf("failed to send batch notifications: %w", err)
 		}
 
 		log.Printf("Batch response: %d success, %d failure", response.SuccessCount, response.FailureCount)
-		
-		// Handle failed tokens
-		if response.FailureCount > 0 {
-			for idx, resp := range response.Responses {
-				if !resp.Success {
-					log.Printf("Failed to send to token %s: %v", tokens[i+idx], resp.Error)
-				}
+
+		for idx, resp := range response.Responses {
+			token := tokens[i+idx]
+			switch {
+			case resp.Success:
+				result.Delivered = append(result.Delivered, token)
+			case isInvalidToken(resp.Error):
+				result.Invalid = append(result.Invalid, token)
+			default:
+				log.Printf("Failed to send to token %s: %v", token, resp.Error)
+				result.Failed++
 			}
 		}
 	}
 
-	return nil
+	return result, nil
 }
 
 // SendTopicNotification sends a notification to a topic
@@ -248,9 +277,9 @@ func (m *MockNotificationService) SendPushNotification(ctx context.Context, toke
 }
 
 // SendBatchNotifications mock implementation
-func (m *MockNotificationService) SendBatchNotifications(ctx context.Context, tokens []string, notification Notification) error {
+func (m *MockNotificationService) SendBatchNotifications(ctx context.Context, tokens []string, notification Notification) (*BatchResult, error) {
 	log.Printf("Mock: Sending batch notification to %d devices: %s - %s", len(tokens), notification.Title, notification.Body)
-	return nil
+	return &BatchResult{Delivered: tokens}, nil
 }
 
 // SendTopicNotification mock implementation
@@ -0,0 +1,55 @@
+package oidc
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// appleClientSecretTTL is the lifetime of generated client secrets; Apple allows up to
+// six months
+const appleClientSecretTTL = time.Hour
+
+// AppleClientSecret builds the ES256-signed JWT Sign in with Apple expects as the
+// client secret, from the team ID, the Services ID (clientID) and the key ID and
+// PKCS#8 private key downloaded from the developer portal
+func AppleClientSecret(teamID, clientID, keyID, privateKeyPEM string, now time.Time) (string, error) {
+	key, err := parseApplePrivateKey(privateKeyPEM)
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.RegisteredClaims{
+		Issuer:    teamID,
+		Subject:   clientID,
+		Audience:  jwt.ClaimStrings{IssuerApple},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(appleClientSecretTTL)),
+	})
+	token.Header["kid"] = keyID
+
+	return token.SignedString(key)
+}
+
+// parseApplePrivateKey parses a .p8 key. Keys set through environment variables often
+// have their newlines escaped, so literal "\n" sequences are accepted.
+func parseApplePrivateKey(privateKeyPEM string) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(strings.ReplaceAll(privateKeyPEM, `\n`, "\n")))
+	if block == nil {
+		return nil, fmt.Errorf("apple private key is not PEM encoded")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid apple private key: %w", err)
+	}
+	key, ok := parsed.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("apple private key is not an ECDSA key")
+	}
+	return key, nil
+}
@@ -0,0 +1,212 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+)
+
+// fakeIssuer is an OIDC provider serving discovery and a JWKS with one RSA and one EC key
+type fakeIssuer struct {
+	*httptest.Server
+	rsaKey *rsa.PrivateKey
+	ecKey  *ecdsa.PrivateKey
+}
+
+func newFakeIssuer(t *testing.T) *fakeIssuer {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := &fakeIssuer{rsaKey: rsaKey, ecKey: ecKey}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Metadata{
+			Issuer:                f.URL,
+			AuthorizationEndpoint: f.URL + "/authorize",
+			TokenEndpoint:         f.URL + "/token",
+			JWKSURI:               f.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		b64 := base64.RawURLEncoding.EncodeToString
+		json.NewEncoder(w).Encode(jsonWebKeySet{Keys: []jsonWebKey{
+			{Kty: "RSA", Kid: "rsa", Use: "sig", N: b64(rsaKey.N.Bytes()), E: b64(big.NewInt(int64(rsaKey.E)).Bytes())},
+			{Kty: "EC", Kid: "ec", Crv: "P-256", X: b64(ecKey.X.Bytes()), Y: b64(ecKey.Y.Bytes())},
+		}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("code") != "good-code" || r.FormValue("client_secret") != "secret" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "access",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"id_token":     f.sign(t, "rsa", f.claims(jwt.MapClaims{"aud": r.FormValue("client_id")})),
+		})
+	})
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(f.Close)
+	return f
+}
+
+// sign issues an ID token, signed with the RSA key unless kid is "ec"
+func (f *fakeIssuer) sign(t *testing.T, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	method, key := jwt.SigningMethod(jwt.SigningMethodRS256), interface{}(f.rsaKey)
+	if kid == "ec" {
+		method, key = jwt.SigningMethodES256, f.ecKey
+	}
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	raw, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+func (f *fakeIssuer) claims(overrides jwt.MapClaims) jwt.MapClaims {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":            f.URL,
+		"aud":            "client-id",
+		"sub":            "user-123",
+		"email":          "ana@example.com",
+		"email_verified": "true",
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+	}
+	for k, v := range overrides {
+		claims[k] = v
+	}
+	return claims
+}
+
+func TestVerify(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	provider := NewProvider(issuer.URL, nil)
+	ctx := context.Background()
+
+	for _, kid := range []string{"rsa", "ec"} {
+		token, err := provider.Verify(ctx, issuer.sign(t, kid, issuer.claims(nil)), "client-id")
+		if err != nil {
+			t.Fatalf("%s: Verify: %v", kid, err)
+		}
+		if token.Subject != "user-123" || token.Email != "ana@example.com" || !bool(token.EmailVerified) {
+			t.Errorf("%s: unexpected claims %+v", kid, token)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		kid   string
+		claim jwt.MapClaims
+	}{
+		{"wrong audience", "rsa", jwt.MapClaims{"aud": "someone-else"}},
+		{"wrong issuer", "rsa", jwt.MapClaims{"iss": "https://evil.example.com"}},
+		{"expired", "rsa", jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()}},
+		{"no subject", "ec", jwt.MapClaims{"sub": ""}},
+		{"unknown key", "other", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := issuer.sign(t, tt.kid, issuer.claims(tt.claim))
+			if _, err := provider.Verify(ctx, raw, "client-id"); !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("Verify error = %v, want ErrInvalidToken", err)
+			}
+		})
+	}
+}
+
+func TestExchange(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	provider := NewProvider(issuer.URL, nil)
+	ctx := context.Background()
+
+	endpoint, err := provider.Endpoint(ctx)
+	if err != nil {
+		t.Fatalf("Endpoint: %v", err)
+	}
+	conf := &oauth2.Config{ClientID: "client-id", ClientSecret: "secret", Endpoint: endpoint}
+
+	token, idToken, err := provider.Exchange(ctx, conf, "good-code")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if token.AccessToken != "access" || idToken.Subject != "user-123" {
+		t.Errorf("unexpected tokens %+v %+v", token, idToken)
+	}
+
+	if _, _, err := provider.Exchange(ctx, conf, "bad-code"); err == nil {
+		t.Error("expected an error for a rejected code")
+	}
+}
+
+func TestMetadataRejectsMismatchedIssuer(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	provider := NewProvider(issuer.URL+"/other", nil)
+	if _, err := provider.Metadata(context.Background()); err == nil {
+		t.Error("expected an error for a discovery document naming another issuer")
+	}
+}
+
+func TestAppleClientSecret(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// As it would appear in an environment variable
+	pemKey := strings.ReplaceAll(string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), "\n", `\n`)
+
+	secret, err := AppleClientSecret("TEAM123", "app.vyve.signin", "KEY123", pemKey, time.Now())
+	if err != nil {
+		t.Fatalf("AppleClientSecret: %v", err)
+	}
+
+	var claims jwt.RegisteredClaims
+	token, err := jwt.ParseWithClaims(secret, &claims, func(*jwt.Token) (interface{}, error) {
+		return &key.PublicKey, nil
+	}, jwt.WithValidMethods([]string{"ES256"}), jwt.WithAudience(IssuerApple))
+	if err != nil {
+		t.Fatalf("client secret does not verify: %v", err)
+	}
+	if token.Header["kid"] != "KEY123" || claims.Issuer != "TEAM123" || claims.Subject != "app.vyve.signin" {
+		t.Errorf("unexpected client secret header %v and claims %+v", token.Header, claims)
+	}
+
+	if _, err := AppleClientSecret("TEAM123", "app.vyve.signin", "KEY123", "not a key", time.Now()); err == nil {
+		t.Error("expected an error for an invalid private key")
+	}
+}
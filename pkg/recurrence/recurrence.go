@@ -0,0 +1,507 @@
+// Package recurrence computes the days a reminder recurs on from RFC 5545 recurrence
+// rules. Rules work on calendar days, without times of day: FREQ may be DAILY, WEEKLY,
+// MONTHLY or YEARLY, and BYHOUR, BYMINUTE, BYSECOND, BYWEEKNO and BYYEARDAY are not
+// supported.
+//
+// Days are represented as time.Time values at midnight UTC, so that day arithmetic is
+// unaffected by DST changes. Callers convert them to the user's timezone.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequencies
+const (
+	Daily   = "DAILY"
+	Weekly  = "WEEKLY"
+	Monthly = "MONTHLY"
+	Yearly  = "YEARLY"
+)
+
+const (
+	// MaxInterval bounds INTERVAL, which also bounds how far apart occurrences can be
+	MaxInterval = 1000
+	// maxPeriods is how many periods Next looks through before deciding a rule has no
+	// further occurrences, such as BYMONTH=2;BYMONTHDAY=30
+	maxPeriods = 5000
+)
+
+// ErrInvalidRule is returned for rules that are malformed or use unsupported parts
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+var weekdayCodes = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// WeekdayNum is a BYDAY entry: a weekday, optionally the Nth of the month or year, counted
+// from the end when negative
+type WeekdayNum struct {
+	N   int
+	Day time.Weekday
+}
+
+// Rule is a parsed RRULE
+type Rule struct {
+	Freq       string
+	Interval   int
+	Count      int
+	Until      time.Time // the last day occurrences may fall on; zero for none
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByMonth    []time.Month
+	BySetPos   []int
+	WeekStart  time.Weekday
+}
+
+// Parse parses an RRULE value such as "FREQ=WEEKLY;BYDAY=MO,TH". A leading "RRULE:"
+// is accepted.
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimSpace(s)
+	if len(s) >= 6 && strings.EqualFold(s[:6], "RRULE:") {
+		s = s[6:]
+	}
+	if s == "" {
+		return nil, fmt.Errorf("%w: empty rule", ErrInvalidRule)
+	}
+
+	rule := &Rule{Interval: 1, WeekStart: time.Monday}
+	seen := map[string]bool{}
+	for _, part := range strings.Split(s, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		name = strings.ToUpper(strings.TrimSpace(name))
+		value = strings.ToUpper(strings.TrimSpace(value))
+		if !ok || name == "" || value == "" {
+			return nil, fmt.Errorf("%w: malformed part %q", ErrInvalidRule, part)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%w: %s given twice", ErrInvalidRule, name)
+		}
+		seen[name] = true
+
+		var err error
+		switch name {
+		case "FREQ":
+			rule.Freq = value
+		case "INTERVAL":
+			rule.Interval, err = strconv.Atoi(value)
+		case "COUNT":
+			rule.Count, err = strconv.Atoi(value)
+			if err == nil && rule.Count < 1 {
+				err = errors.New("must be positive")
+			}
+		case "UNTIL":
+			rule.Until, err = parseUntil(value)
+		case "BYDAY":
+			rule.ByDay, err = parseByDay(value)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = parseInts(value, 1, 31)
+		case "BYMONTH":
+			var months []int
+			if months, err = parseInts(value, 1, 12); err == nil {
+				for _, m := range months {
+					if m < 0 {
+						return nil, fmt.Errorf("%w: BYMONTH can't be negative", ErrInvalidRule)
+					}
+					rule.ByMonth = append(rule.ByMonth, time.Month(m))
+				}
+			}
+		case "BYSETPOS":
+			rule.BySetPos, err = parseInts(value, 1, 366)
+		case "WKST":
+			day, ok := weekdayCodes[value]
+			if !ok {
+				err = errors.New("unknown weekday")
+			}
+			rule.WeekStart = day
+		default:
+			return nil, fmt.Errorf("%w: %s is not supported", ErrInvalidRule, name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidRule, name, err)
+		}
+	}
+
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// Validate checks that the rule's parts fit together
+func (r *Rule) Validate() error {
+	switch r.Freq {
+	case Daily, Weekly, Monthly, Yearly:
+	case "":
+		return fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	default:
+		return fmt.Errorf("%w: FREQ=%s is not supported", ErrInvalidRule, r.Freq)
+	}
+	if r.Interval < 1 || r.Interval > MaxInterval {
+		return fmt.Errorf("%w: INTERVAL must be between 1 and %d", ErrInvalidRule, MaxInterval)
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return fmt.Errorf("%w: COUNT and UNTIL can't be combined", ErrInvalidRule)
+	}
+	if r.Freq == Weekly && len(r.ByMonthDay) > 0 {
+		return fmt.Errorf("%w: BYMONTHDAY can't be used with FREQ=WEEKLY", ErrInvalidRule)
+	}
+	for _, day := range r.ByDay {
+		if day.N == 0 {
+			continue
+		}
+		// Ordinal weekdays count within a month; yearly rules need BYMONTH to name it
+		if r.Freq == Daily || r.Freq == Weekly || (r.Freq == Yearly && len(r.ByMonth) == 0) {
+			return fmt.Errorf("%w: numbered BYDAY needs FREQ=MONTHLY, or FREQ=YEARLY with BYMONTH", ErrInvalidRule)
+		}
+		if day.N < -5 || day.N > 5 {
+			return fmt.Errorf("%w: BYDAY number out of range", ErrInvalidRule)
+		}
+	}
+	if r.Freq == Yearly && len(r.ByDay) > 0 && len(r.ByMonth) == 0 {
+		return fmt.Errorf("%w: BYDAY with FREQ=YEARLY needs BYMONTH", ErrInvalidRule)
+	}
+	return nil
+}
+
+// String formats the rule as an RRULE value
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+	}
+	if len(r.ByMonth) > 0 {
+		months := make([]string, len(r.ByMonth))
+		for i, m := range r.ByMonth {
+			months[i] = strconv.Itoa(int(m))
+		}
+		parts = append(parts, "BYMONTH="+strings.Join(months, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		parts = append(parts, "BYMONTHDAY="+joinInts(r.ByMonthDay))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			days[i] = weekdayCode(day.Day)
+			if day.N != 0 {
+				days[i] = strconv.Itoa(day.N) + days[i]
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.BySetPos) > 0 {
+		parts = append(parts, "BYSETPOS="+joinInts(r.BySetPos))
+	}
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+weekdayCode(r.WeekStart))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next returns the first day after the given one the rule recurs on, counting from start,
+// the first day occurrences may fall on. It returns false when the rule has ended.
+func (r *Rule) Next(start, after time.Time) (time.Time, bool) {
+	start, after = Day(start), Day(after)
+
+	first := r.periodStart(start)
+	k := 0
+	// Without COUNT, earlier periods don't matter and can be skipped
+	if r.Count == 0 && after.After(start) {
+		k = max(0, r.periodsBetween(first, after)/r.Interval-1)
+	}
+
+	seen := 0
+	for end := k + maxPeriods; k < end; k++ {
+		for _, day := range r.expand(r.periodAt(first, k), start) {
+			if day.Before(start) {
+				continue
+			}
+			if !r.Until.IsZero() && day.After(r.Until) {
+				return time.Time{}, false
+			}
+			seen++
+			if r.Count > 0 && seen > r.Count {
+				return time.Time{}, false
+			}
+			if day.After(after) {
+				return day, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// Day truncates t to its calendar day, as midnight UTC
+func Day(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// periodStart returns the first day of the period holding day
+func (r *Rule) periodStart(day time.Time) time.Time {
+	switch r.Freq {
+	case Weekly:
+		offset := (int(day.Weekday()) - int(r.WeekStart) + 7) % 7
+		return day.AddDate(0, 0, -offset)
+	case Monthly:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case Yearly:
+		return time.Date(day.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return day
+}
+
+// periodAt returns the first day of the kth period of the rule after the first one
+func (r *Rule) periodAt(first time.Time, k int) time.Time {
+	n := k * r.Interval
+	switch r.Freq {
+	case Weekly:
+		return first.AddDate(0, 0, 7*n)
+	case Monthly:
+		return first.AddDate(0, n, 0)
+	case Yearly:
+		return first.AddDate(n, 0, 0)
+	}
+	return first.AddDate(0, 0, n)
+}
+
+// periodsBetween counts the whole periods from the first one to the one holding day
+func (r *Rule) periodsBetween(first, day time.Time) int {
+	switch r.Freq {
+	case Weekly:
+		return int(day.Sub(first).Hours()/24) / 7
+	case Monthly:
+		return (day.Year()-first.Year())*12 + int(day.Month()-first.Month())
+	case Yearly:
+		return day.Year() - first.Year()
+	}
+	return int(day.Sub(first).Hours() / 24)
+}
+
+// expand lists the days the rule recurs on within the period starting on the given day,
+// in order
+func (r *Rule) expand(period, start time.Time) []time.Time {
+	var days []time.Time
+	switch r.Freq {
+	case Daily:
+		days = []time.Time{period}
+	case Weekly:
+		for i := 0; i < 7; i++ {
+			day := period.AddDate(0, 0, i)
+			if (len(r.ByDay) == 0 && day.Weekday() == start.Weekday()) || r.matchesWeekday(day) {
+				days = append(days, day)
+			}
+		}
+	case Monthly:
+		days = r.expandMonth(period.Year(), period.Month(), start)
+	case Yearly:
+		months := r.ByMonth
+		if len(months) == 0 {
+			months = []time.Month{start.Month()}
+		}
+		for _, month := range sortedMonths(months) {
+			days = append(days, r.expandMonth(period.Year(), month, start)...)
+		}
+	}
+
+	filtered := days[:0]
+	for _, day := range days {
+		if r.Freq != Yearly && len(r.ByMonth) > 0 && !containsMonth(r.ByMonth, day.Month()) {
+			continue
+		}
+		if r.Freq == Daily {
+			if len(r.ByMonthDay) > 0 && !r.matchesMonthDay(day) {
+				continue
+			}
+			if len(r.ByDay) > 0 && !r.matchesWeekday(day) {
+				continue
+			}
+		}
+		filtered = append(filtered, day)
+	}
+	return r.setPositions(filtered)
+}
+
+// expandMonth lists the days of a month matching BYMONTHDAY and BYDAY, or the day of the
+// month of start when neither is given
+func (r *Rule) expandMonth(year int, month time.Month, start time.Time) []time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	length := first.AddDate(0, 1, -1).Day()
+
+	if len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 {
+		if start.Day() > length {
+			return nil
+		}
+		return []time.Time{first.AddDate(0, 0, start.Day()-1)}
+	}
+
+	var days []time.Time
+	for d := 1; d <= length; d++ {
+		day := first.AddDate(0, 0, d-1)
+		if len(r.ByMonthDay) > 0 && !r.matchesMonthDay(day) {
+			continue
+		}
+		if len(r.ByDay) > 0 && !r.matchesMonthWeekday(day, length) {
+			continue
+		}
+		days = append(days, day)
+	}
+	return days
+}
+
+// matchesMonthDay reports whether day is one of BYMONTHDAY, counting negative entries
+// from the end of the month
+func (r *Rule) matchesMonthDay(day time.Time) bool {
+	length := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	for _, d := range r.ByMonthDay {
+		if d == day.Day() || (d < 0 && length+d+1 == day.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesWeekday reports whether day falls on one of the BYDAY weekdays
+func (r *Rule) matchesWeekday(day time.Time) bool {
+	for _, wd := range r.ByDay {
+		if wd.Day == day.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesMonthWeekday reports whether day matches BYDAY within its month, where numbered
+// entries pick the Nth such weekday from the start or end of the month
+func (r *Rule) matchesMonthWeekday(day time.Time, length int) bool {
+	for _, wd := range r.ByDay {
+		if wd.Day != day.Weekday() {
+			continue
+		}
+		switch {
+		case wd.N == 0:
+			return true
+		case wd.N > 0 && (day.Day()-1)/7+1 == wd.N:
+			return true
+		case wd.N < 0 && (length-day.Day())/7+1 == -wd.N:
+			return true
+		}
+	}
+	return false
+}
+
+// setPositions keeps the BYSETPOS entries of a period's days
+func (r *Rule) setPositions(days []time.Time) []time.Time {
+	if len(r.BySetPos) == 0 {
+		return days
+	}
+	var picked []time.Time
+	for _, pos := range r.BySetPos {
+		i := pos - 1
+		if pos < 0 {
+			i = len(days) + pos
+		}
+		if i >= 0 && i < len(days) {
+			picked = append(picked, days[i])
+		}
+	}
+	sort.Slice(picked, func(i, j int) bool { return picked[i].Before(picked[j]) })
+	// Positions may name the same day twice
+	unique := picked[:0]
+	for i, day := range picked {
+		if i == 0 || !day.Equal(picked[i-1]) {
+			unique = append(unique, day)
+		}
+	}
+	return unique
+}
+
+func parseUntil(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, errors.New("expected YYYYMMDD")
+	}
+	return time.Parse("20060102", value[:8])
+}
+
+func parseByDay(value string) ([]WeekdayNum, error) {
+	var days []WeekdayNum
+	for _, entry := range strings.Split(value, ",") {
+		if len(entry) < 2 {
+			return nil, fmt.Errorf("malformed weekday %q", entry)
+		}
+		day, ok := weekdayCodes[entry[len(entry)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("unknown weekday %q", entry)
+		}
+		n := 0
+		if prefix := entry[:len(entry)-2]; prefix != "" {
+			var err error
+			if n, err = strconv.Atoi(prefix); err != nil || n == 0 {
+				return nil, fmt.Errorf("malformed weekday %q", entry)
+			}
+		}
+		days = append(days, WeekdayNum{N: n, Day: day})
+	}
+	return days, nil
+}
+
+// parseInts parses a list of numbers between min and max, or between -max and -min
+func parseInts(value string, lo, hi int) ([]int, error) {
+	var values []int
+	for _, entry := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(entry)
+		if err != nil {
+			return nil, fmt.Errorf("malformed number %q", entry)
+		}
+		if abs := max(n, -n); abs < lo || abs > hi {
+			return nil, fmt.Errorf("%d is out of range", n)
+		}
+		values = append(values, n)
+	}
+	return values, nil
+}
+
+func joinInts(values []int) string {
+	s := make([]string, len(values))
+	for i, v := range values {
+		s[i] = strconv.Itoa(v)
+	}
+	return strings.Join(s, ",")
+}
+
+func weekdayCode(day time.Weekday) string {
+	return strings.ToUpper(day.String()[:2])
+}
+
+func containsMonth(months []time.Month, month time.Month) bool {
+	for _, m := range months {
+		if m == month {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedMonths(months []time.Month) []time.Month {
+	sorted := append([]time.Month(nil), months...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted
+}
@@ -0,0 +1,141 @@
+package recurrence
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func day(s string) time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// occurrences lists the first n days a rule recurs on
+func occurrences(t *testing.T, rule, start string, n int) []string {
+	t.Helper()
+	r, err := Parse(rule)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", rule, err)
+	}
+	var days []string
+	after := day(start).AddDate(0, 0, -1)
+	for len(days) < n {
+		next, ok := r.Next(day(start), after)
+		if !ok {
+			break
+		}
+		days = append(days, next.Format("2006-01-02"))
+		after = next
+	}
+	return days
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		rule  string
+		start string
+		want  []string
+	}{
+		{"FREQ=DAILY", "2024-02-27", []string{"2024-02-27", "2024-02-28", "2024-02-29", "2024-03-01"}},
+		{"FREQ=DAILY;INTERVAL=10", "2024-01-01", []string{"2024-01-01", "2024-01-11", "2024-01-21"}},
+		{"FREQ=DAILY;COUNT=2", "2024-01-01", []string{"2024-01-01", "2024-01-02"}},
+		{"FREQ=DAILY;UNTIL=20240103", "2024-01-01", []string{"2024-01-01", "2024-01-02", "2024-01-03"}},
+		{"FREQ=DAILY;BYDAY=SA,SU", "2024-01-01", []string{"2024-01-06", "2024-01-07", "2024-01-13"}},
+		// 2024-01-03 is a Wednesday
+		{"FREQ=WEEKLY", "2024-01-03", []string{"2024-01-03", "2024-01-10", "2024-01-17"}},
+		{"FREQ=WEEKLY;BYDAY=MO,TH", "2024-01-03", []string{"2024-01-04", "2024-01-08", "2024-01-11"}},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO", "2024-01-01", []string{"2024-01-01", "2024-01-15", "2024-01-29"}},
+		// Months without a 31st are skipped
+		{"FREQ=MONTHLY", "2024-01-31", []string{"2024-01-31", "2024-03-31", "2024-05-31"}},
+		{"FREQ=MONTHLY;BYMONTHDAY=-1", "2024-01-15", []string{"2024-01-31", "2024-02-29", "2024-03-31"}},
+		{"FREQ=MONTHLY;BYMONTHDAY=28,29,30,31;BYSETPOS=-1", "2023-01-01", []string{"2023-01-31", "2023-02-28", "2023-03-31", "2023-04-30"}},
+		{"FREQ=MONTHLY;BYDAY=2TU", "2024-01-01", []string{"2024-01-09", "2024-02-13", "2024-03-12"}},
+		{"FREQ=MONTHLY;BYDAY=-1FR", "2024-01-01", []string{"2024-01-26", "2024-02-23", "2024-03-29"}},
+		{"FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=1", "2024-01-01", []string{"2024-01-01", "2024-02-01", "2024-03-01", "2024-04-01"}},
+		{"FREQ=YEARLY", "2024-02-29", []string{"2024-02-29", "2028-02-29"}},
+		{"FREQ=YEARLY;BYMONTH=5;BYDAY=2SU", "2024-01-01", []string{"2024-05-12", "2025-05-11"}},
+	}
+	for _, tt := range tests {
+		got := occurrences(t, tt.rule, tt.start, len(tt.want))
+		if len(got) != len(tt.want) {
+			t.Errorf("%s from %s = %v, want %v", tt.rule, tt.start, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s from %s = %v, want %v", tt.rule, tt.start, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestNextEnds(t *testing.T) {
+	r, _ := Parse("FREQ=WEEKLY;COUNT=3")
+	if _, ok := r.Next(day("2024-01-01"), day("2024-01-15")); ok {
+		t.Error("Next after the last of COUNT occurrences should be false")
+	}
+
+	r, _ = Parse("FREQ=MONTHLY;UNTIL=20240301T000000Z")
+	if next, ok := r.Next(day("2024-01-10"), day("2024-02-10")); ok {
+		t.Errorf("Next after UNTIL = %s, want none", next)
+	}
+
+	// February never has a 30th
+	r, _ = Parse("FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30")
+	if _, ok := r.Next(day("2024-01-01"), day("2024-01-01")); ok {
+		t.Error("Next of a rule with no occurrences should be false")
+	}
+}
+
+func TestNextSkipsAhead(t *testing.T) {
+	r, _ := Parse("FREQ=DAILY;INTERVAL=3")
+	next, ok := r.Next(day("2000-01-01"), day("2024-06-01"))
+	// 2024-06-01 is 8918 days after 2000-01-01, a multiple of 3 plus 2
+	if !ok || !next.Equal(day("2024-06-02")) {
+		t.Errorf("Next = %s, %v; want 2024-06-02", next.Format("2006-01-02"), ok)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, rule := range []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;BYHOUR=9",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=2;UNTIL=20240101",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=MONTHLY;BYDAY=XX",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ",
+	} {
+		if _, err := Parse(rule); !errors.Is(err, ErrInvalidRule) {
+			t.Errorf("Parse(%q) = %v, want ErrInvalidRule", rule, err)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	for _, rule := range []string{
+		"FREQ=DAILY",
+		"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH",
+		"FREQ=MONTHLY;BYMONTHDAY=28,29,30,31;BYSETPOS=-1",
+		"FREQ=YEARLY;COUNT=5;BYMONTH=5;BYDAY=2SU",
+		"FREQ=WEEKLY;UNTIL=20241231;WKST=SU",
+	} {
+		r, err := Parse("RRULE:" + rule)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", rule, err)
+		}
+		if got := r.String(); got != rule {
+			t.Errorf("String() = %q, want %q", got, rule)
+		}
+	}
+}
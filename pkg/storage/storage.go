@@ -17,9 +17,12 @@ import (
 	cfg "github.com/vyve/vyve-backend/internal/config"
 )
 
+//go:generate mockgen -source=storage.go -destination=mock_storage.go -package=storage
+
 // Storage defines the storage interface
 type Storage interface {
 	Upload(ctx context.Context, key string, data []byte, contentType string) (string, error)
+	UploadStream(ctx context.Context, key string, r io.Reader, size int64, contentType string) (string, error)
 	Download(ctx context.Context, key string) ([]byte, error)
 	Delete(ctx context.Context, key string) error
 	GetURL(key string) string
@@ -75,6 +78,24 @@ func (s *S3Storage) Upload(ctx context.Context, key string, data []byte, content
 	return s.GetURL(key), nil
 }
 
+// UploadStream uploads size bytes read from r to S3
+func (s *S3Storage) UploadStream(ctx context.Context, key string, r io.Reader, size int64, contentType string) (string, error) {
+	input := &s3.PutObjectInput{
+		Bucket:        aws.String(s.bucket),
+		Key:           aws.String(key),
+		Body:          r,
+		ContentLength: aws.Int64(size),
+		ContentType:   aws.String(contentType),
+	}
+
+	_, err := s.client.PutObject(ctx, input)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload to S3: %w", err)
+	}
+
+	return s.GetURL(key), nil
+}
+
 // Download downloads data from S3
 func (s *S3Storage) Download(ctx context.Context, key string) ([]byte, error) {
 	input := &s3.GetObjectInput{
@@ -209,6 +230,18 @@ func (m *MinIOStorage) Upload(ctx context.Context, key string, data []byte, cont
 	return m.GetURL(key), nil
 }
 
+// UploadStream uploads size bytes read from r to MinIO
+func (m *MinIOStorage) UploadStream(ctx context.Context, key string, r io.Reader, size int64, contentType string) (string, error) {
+	_, err := m.client.PutObject(ctx, m.bucket, key, r, size, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to upload to MinIO: %w", err)
+	}
+
+	return m.GetURL(key), nil
+}
+
 // Download downloads data from MinIO
 func (m *MinIOStorage) Download(ctx context.Context, key string) ([]byte, error) {
 	object, err := m.client.GetObject(ctx, m.bucket, key, minio.GetObjectOptions{})
@@ -0,0 +1,91 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA-1 test key of RFC 6238, "12345678901234567890", in base32
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode(t *testing.T) {
+	// RFC 6238 appendix B, truncated to 6 digits
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, tt := range tests {
+		got, err := Code(rfcSecret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("Code at %d = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+
+	step, ok := Validate(rfcSecret, "005924", now)
+	if !ok || step != Step(now) {
+		t.Fatalf("Validate = %d, %v; want %d, true", step, ok, Step(now))
+	}
+
+	// The previous code is still accepted, the one before that is not
+	previous, _ := Code(rfcSecret, now.Add(-Period))
+	if step, ok := Validate(rfcSecret, previous, now); !ok || step != Step(now)-1 {
+		t.Errorf("previous code: Validate = %d, %v", step, ok)
+	}
+	stale, _ := Code(rfcSecret, now.Add(-2*Period))
+	if _, ok := Validate(rfcSecret, stale, now); ok {
+		t.Error("code from two periods ago was accepted")
+	}
+
+	for _, code := range []string{"", "005925", "00592", "0059244", "abcdef"} {
+		if _, ok := Validate(rfcSecret, code, now); ok {
+			t.Errorf("Validate accepted %q", code)
+		}
+	}
+	if _, ok := Validate("not base32!", "005924", now); ok {
+		t.Error("Validate accepted a code for an invalid secret")
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secret) != 32 {
+		t.Errorf("secret length = %d, want 32", len(secret))
+	}
+
+	code, err := Code(secret, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := Validate(secret, code, time.Now()); !ok {
+		t.Error("code for a generated secret does not validate")
+	}
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := ProvisioningURI(rfcSecret, "Vyve", "ana@example.com")
+	if !strings.HasPrefix(uri, "otpauth://totp/Vyve:ana@example.com?") {
+		t.Errorf("unexpected label in %s", uri)
+	}
+	for _, param := range []string{"secret=" + rfcSecret, "issuer=Vyve", "digits=6", "period=30"} {
+		if !strings.Contains(uri, param) {
+			t.Errorf("%s is missing %s", uri, param)
+		}
+	}
+}
@@ -124,6 +124,49 @@ func SlugifyString(s string) string {
 	return s
 }
 
+// userAgentPlatforms and userAgentClients map User-Agent markers to readable names, in
+// match order (e.g. Edge and Opera also claim to be Chrome)
+var (
+	userAgentPlatforms = [][2]string{
+		{"iPhone", "iPhone"}, {"iPad", "iPad"}, {"Android", "Android"},
+		{"Windows", "Windows"}, {"Macintosh", "macOS"}, {"CrOS", "ChromeOS"},
+		{"Linux", "Linux"}, {"Darwin", "iOS"},
+	}
+	userAgentClients = [][2]string{
+		{"Edg/", "Edge"}, {"OPR/", "Opera"}, {"Firefox/", "Firefox"},
+		{"CriOS/", "Chrome"}, {"Chrome/", "Chrome"}, {"Safari/", "Safari"},
+		{"okhttp/", "Vyve app"}, {"CFNetwork/", "Vyve app"}, {"Dart/", "Vyve app"},
+	}
+)
+
+// DescribeUserAgent returns a short device label such as "Chrome on macOS"
+func DescribeUserAgent(userAgent string) string {
+	var platform, client string
+	for _, p := range userAgentPlatforms {
+		if strings.Contains(userAgent, p[0]) {
+			platform = p[1]
+			break
+		}
+	}
+	for _, c := range userAgentClients {
+		if strings.Contains(userAgent, c[0]) {
+			client = c[1]
+			break
+		}
+	}
+
+	switch {
+	case client != "" && platform != "":
+		return client + " on " + platform
+	case client != "":
+		return client
+	case platform != "":
+		return platform
+	default:
+		return "Unknown device"
+	}
+}
+
 // ParseDuration parses duration string (e.g., "1h", "30m", "7d")
 func ParseDuration(duration string) (time.Duration, error) {
 	// Handle days specially
@@ -0,0 +1,24 @@
+package utils
+
+import "testing"
+
+func TestDescribeUserAgent(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		want      string
+	}{
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36", "Chrome on macOS"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36 Edg/124.0.0.0", "Edge on Windows"},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1", "Safari on iPhone"},
+		{"Mozilla/5.0 (X11; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0", "Firefox on Linux"},
+		{"Vyve/1.4 CFNetwork/1494.0.7 Darwin/23.4.0", "Vyve app on iOS"},
+		{"okhttp/4.12.0", "Vyve app"},
+		{"", "Unknown device"},
+	}
+
+	for _, tt := range tests {
+		if got := DescribeUserAgent(tt.userAgent); got != tt.want {
+			t.Errorf("DescribeUserAgent(%q) = %q, want %q", tt.userAgent, got, tt.want)
+		}
+	}
+}